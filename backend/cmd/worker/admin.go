@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// queueAdmin is the part of the manager behind the admin endpoints
+type queueAdmin interface {
+	ListQueueControls(ctx context.Context) ([]workerqueue.QueueControl, error)
+	PauseQueue(ctx context.Context, queue string) error
+	ResumeQueue(ctx context.Context, queue string) error
+	SetQueueMaxWorkers(ctx context.Context, queue string, maxWorkers int) (*workerqueue.QueueControl, error)
+
+	ListFailedJobs(ctx context.Context, filter workerqueue.FailedJobFilter) ([]workerqueue.FailedJob, error)
+	RetryJob(ctx context.Context, id int64) (*workerqueue.FailedJob, error)
+	RetryJobs(ctx context.Context, ids []int64) (int, error)
+	DeleteJobs(ctx context.Context, ids []int64) (int, error)
+}
+
+var _ queueAdmin = (*workerqueue.Manager)(nil)
+
+// maxWorkersRequest is the body of a max workers update
+type maxWorkersRequest struct {
+	MaxWorkers *int `json:"maxWorkers"`
+}
+
+// jobIDsRequest is the body of a bulk retry or delete
+type jobIDsRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+// newAdminHandler serves queue controls and failed job recovery under /admin/, requiring token
+// as a bearer token
+func newAdminHandler(admin queueAdmin, token string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /admin/queues", func(w http.ResponseWriter, r *http.Request) {
+		controls, err := admin.ListQueueControls(r.Context())
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, controls)
+	})
+	mux.HandleFunc("POST /admin/queues/{queue}/pause", func(w http.ResponseWriter, r *http.Request) {
+		if err := admin.PauseQueue(r.Context(), r.PathValue("queue")); err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /admin/queues/{queue}/resume", func(w http.ResponseWriter, r *http.Request) {
+		if err := admin.ResumeQueue(r.Context(), r.PathValue("queue")); err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("PUT /admin/queues/{queue}/max-workers", func(w http.ResponseWriter, r *http.Request) {
+		var req maxWorkersRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MaxWorkers == nil {
+			http.Error(w, "body must be {\"maxWorkers\": <int>}", http.StatusBadRequest)
+			return
+		}
+		control, err := admin.SetQueueMaxWorkers(r.Context(), r.PathValue("queue"), *req.MaxWorkers)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, control)
+	})
+
+	mux.HandleFunc("GET /admin/jobs/failed", func(w http.ResponseWriter, r *http.Request) {
+		filter, err := failedJobFilterFromQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jobs, err := admin.ListFailedJobs(r.Context(), filter)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, jobs)
+	})
+	mux.HandleFunc("POST /admin/jobs/{id}/retry", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid job id", http.StatusBadRequest)
+			return
+		}
+		job, err := admin.RetryJob(r.Context(), id)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, job)
+	})
+	mux.HandleFunc("POST /admin/jobs/retry", func(w http.ResponseWriter, r *http.Request) {
+		ids, ok := decodeJobIDs(w, r)
+		if !ok {
+			return
+		}
+		retried, err := admin.RetryJobs(r.Context(), ids)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"retried": retried})
+	})
+	mux.HandleFunc("POST /admin/jobs/delete", func(w http.ResponseWriter, r *http.Request) {
+		ids, ok := decodeJobIDs(w, r)
+		if !ok {
+			return
+		}
+		deleted, err := admin.DeleteJobs(r.Context(), ids)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"deleted": deleted})
+	})
+
+	return requireBearerToken(token, mux)
+}
+
+// failedJobFilterFromQuery reads a failed job filter from repeatable kind, queue and state
+// parameters, RFC 3339 since and until, and limit
+func failedJobFilterFromQuery(query url.Values) (workerqueue.FailedJobFilter, error) {
+	filter := workerqueue.FailedJobFilter{
+		Kinds:  query["kind"],
+		Queues: query["queue"],
+	}
+	for _, state := range query["state"] {
+		filter.States = append(filter.States, rivertype.JobState(state))
+	}
+
+	var err error
+	if filter.Since, err = parseQueryTime(query, "since"); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = parseQueryTime(query, "until"); err != nil {
+		return filter, err
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			return filter, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+	return filter, nil
+}
+
+func parseQueryTime(query url.Values, key string) (time.Time, error) {
+	value := query.Get(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: must be RFC 3339", key, value)
+	}
+	return t, nil
+}
+
+// decodeJobIDs reads a jobIDsRequest body, writing a bad request response when it is invalid
+func decodeJobIDs(w http.ResponseWriter, r *http.Request) ([]int64, bool) {
+	var req jobIDsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) == 0 {
+		http.Error(w, "body must be {\"ids\": [<job id>, ...]}", http.StatusBadRequest)
+		return nil, false
+	}
+	return req.IDs, true
+}
+
+// requireBearerToken rejects requests whose Authorization header does not carry token
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeAdminError maps manager errors to HTTP status codes
+func writeAdminError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, workerqueue.ErrUnknownQueue), errors.Is(err, river.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, workerqueue.ErrInvalidMaxWorkers):
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
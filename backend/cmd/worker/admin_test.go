@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kongflow/backend/internal/services/metrics"
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockQueueAdmin mocks the manager behind the admin endpoints
+type MockQueueAdmin struct {
+	mock.Mock
+}
+
+func (m *MockQueueAdmin) ListQueueControls(ctx context.Context) ([]workerqueue.QueueControl, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]workerqueue.QueueControl), args.Error(1)
+}
+
+func (m *MockQueueAdmin) PauseQueue(ctx context.Context, queue string) error {
+	return m.Called(ctx, queue).Error(0)
+}
+
+func (m *MockQueueAdmin) ResumeQueue(ctx context.Context, queue string) error {
+	return m.Called(ctx, queue).Error(0)
+}
+
+func (m *MockQueueAdmin) SetQueueMaxWorkers(ctx context.Context, queue string, maxWorkers int) (*workerqueue.QueueControl, error) {
+	args := m.Called(ctx, queue, maxWorkers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*workerqueue.QueueControl), args.Error(1)
+}
+
+func (m *MockQueueAdmin) ListFailedJobs(ctx context.Context, filter workerqueue.FailedJobFilter) ([]workerqueue.FailedJob, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]workerqueue.FailedJob), args.Error(1)
+}
+
+func (m *MockQueueAdmin) RetryJob(ctx context.Context, id int64) (*workerqueue.FailedJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*workerqueue.FailedJob), args.Error(1)
+}
+
+func (m *MockQueueAdmin) RetryJobs(ctx context.Context, ids []int64) (int, error) {
+	args := m.Called(ctx, ids)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQueueAdmin) DeleteJobs(ctx context.Context, ids []int64) (int, error) {
+	args := m.Called(ctx, ids)
+	return args.Int(0), args.Error(1)
+}
+
+const testAdminToken = "admin-token"
+
+func serveAdmin(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminHandler_RequiresToken(t *testing.T) {
+	admin := &MockQueueAdmin{}
+	handler := newAdminHandler(admin, testAdminToken)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/queues/execution/pause", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	admin.AssertNotCalled(t, "PauseQueue", mock.Anything, mock.Anything)
+}
+
+func TestAdminHandler_PauseAndResumeQueue(t *testing.T) {
+	admin := &MockQueueAdmin{}
+	handler := newAdminHandler(admin, testAdminToken)
+
+	admin.On("PauseQueue", mock.Anything, "execution").Return(nil)
+	admin.On("ResumeQueue", mock.Anything, "execution").Return(nil)
+	admin.On("PauseQueue", mock.Anything, "missing").Return(fmt.Errorf("%w: missing", workerqueue.ErrUnknownQueue))
+
+	assert.Equal(t, http.StatusNoContent, serveAdmin(handler, http.MethodPost, "/admin/queues/execution/pause", "").Code)
+	assert.Equal(t, http.StatusNoContent, serveAdmin(handler, http.MethodPost, "/admin/queues/execution/resume", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveAdmin(handler, http.MethodPost, "/admin/queues/missing/pause", "").Code)
+	admin.AssertExpectations(t)
+}
+
+func TestAdminHandler_SetQueueMaxWorkers(t *testing.T) {
+	admin := &MockQueueAdmin{}
+	handler := newAdminHandler(admin, testAdminToken)
+
+	admin.On("SetQueueMaxWorkers", mock.Anything, "events", 2).
+		Return(&workerqueue.QueueControl{Queue: "events", ConfiguredMaxWorkers: 5, MaxWorkers: 2}, nil)
+	admin.On("SetQueueMaxWorkers", mock.Anything, "events", 50).
+		Return(nil, fmt.Errorf("%w: 50 is outside 0-5 for queue events", workerqueue.ErrInvalidMaxWorkers))
+
+	rec := serveAdmin(handler, http.MethodPut, "/admin/queues/events/max-workers", `{"maxWorkers": 2}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"queue":"events","paused":false,"configuredMaxWorkers":5,"maxWorkers":2,"updatedAt":"0001-01-01T00:00:00Z"}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, serveAdmin(handler, http.MethodPut, "/admin/queues/events/max-workers", `{"maxWorkers": 50}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serveAdmin(handler, http.MethodPut, "/admin/queues/events/max-workers", `{}`).Code)
+	admin.AssertExpectations(t)
+}
+
+func TestAdminHandler_ListQueueControls(t *testing.T) {
+	admin := &MockQueueAdmin{}
+	handler := newAdminHandler(admin, testAdminToken)
+
+	admin.On("ListQueueControls", mock.Anything).Return([]workerqueue.QueueControl{
+		{Queue: "default", Paused: true, ConfiguredMaxWorkers: 10, MaxWorkers: 10},
+	}, nil)
+
+	rec := serveAdmin(handler, http.MethodGet, "/admin/queues", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"paused":true`)
+}
+
+func TestAdminHandler_ListFailedJobs(t *testing.T) {
+	admin := &MockQueueAdmin{}
+	handler := newAdminHandler(admin, testAdminToken)
+
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	admin.On("ListFailedJobs", mock.Anything, workerqueue.FailedJobFilter{
+		Kinds:  []string{"startRun", "deliverEvent"},
+		States: []rivertype.JobState{rivertype.JobStateDiscarded},
+		Since:  since,
+		Limit:  20,
+	}).Return([]workerqueue.FailedJob{{ID: 7, Kind: "startRun"}}, nil)
+
+	rec := serveAdmin(handler, http.MethodGet, "/admin/jobs/failed?kind=startRun&kind=deliverEvent&state=discarded&since=2026-10-01T00:00:00Z&limit=20", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":7`)
+
+	assert.Equal(t, http.StatusBadRequest, serveAdmin(handler, http.MethodGet, "/admin/jobs/failed?since=yesterday", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serveAdmin(handler, http.MethodGet, "/admin/jobs/failed?limit=-1", "").Code)
+	admin.AssertExpectations(t)
+}
+
+func TestAdminHandler_RetryAndDeleteJobs(t *testing.T) {
+	admin := &MockQueueAdmin{}
+	handler := newAdminHandler(admin, testAdminToken)
+
+	admin.On("RetryJob", mock.Anything, int64(7)).Return(&workerqueue.FailedJob{ID: 7, State: rivertype.JobStateAvailable}, nil)
+	admin.On("RetryJob", mock.Anything, int64(8)).Return(nil, fmt.Errorf("failed to retry job 8: %w", river.ErrNotFound))
+	admin.On("RetryJobs", mock.Anything, []int64{7, 9}).Return(2, nil)
+	admin.On("DeleteJobs", mock.Anything, []int64{7}).Return(1, nil)
+
+	assert.Equal(t, http.StatusOK, serveAdmin(handler, http.MethodPost, "/admin/jobs/7/retry", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveAdmin(handler, http.MethodPost, "/admin/jobs/8/retry", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serveAdmin(handler, http.MethodPost, "/admin/jobs/abc/retry", "").Code)
+
+	rec := serveAdmin(handler, http.MethodPost, "/admin/jobs/retry", `{"ids": [7, 9]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"retried": 2}`, rec.Body.String())
+
+	rec = serveAdmin(handler, http.MethodPost, "/admin/jobs/delete", `{"ids": [7]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"deleted": 1}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, serveAdmin(handler, http.MethodPost, "/admin/jobs/delete", `{"ids": []}`).Code)
+	admin.AssertExpectations(t)
+}
+
+func TestNewHandler_AdminEndpointsNeedToken(t *testing.T) {
+	handler := newHandler(nil, metrics.New(), "")
+
+	rec := serveAdmin(handler, http.MethodGet, "/admin/queues", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
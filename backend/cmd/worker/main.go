@@ -0,0 +1,168 @@
+// Package main runs the KongFlow worker process, aligned with trigger.dev worker.server.ts
+// SIGTERM or SIGINT drains the worker: running jobs finish up to WORKER_DRAIN_TIMEOUT and jobs
+// that have not started are snoozed for other workers
+// WORKER_TENANT_CONCURRENCY opts in to limiting how many jobs of one tenant run at once per queue
+// ENCRYPTION_KEY (16, 24 or 32 bytes) encrypts integration connection credentials in the secret store
+// WORKER_ADMIN_TOKEN enables the /admin/ queue and failed job endpoints, authenticated with it as a bearer token
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kongflow/backend/internal/database"
+	"kongflow/backend/internal/services/email"
+	"kongflow/backend/internal/services/metrics"
+	"kongflow/backend/internal/services/tracing"
+	"kongflow/backend/internal/services/worker"
+	"kongflow/backend/internal/services/workerqueue"
+)
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	if err := run(logger); err != nil {
+		logger.Error("Worker exited with error", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	provider, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName:  "kongflow-worker",
+		OTLPEndpoint: os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		OTLPInsecure: os.Getenv("OTEL_EXPORTER_OTLP_INSECURE") == "true",
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = provider.Shutdown(shutdownCtx)
+	}()
+
+	pool, err := newPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	config := workerqueue.DefaultConfig()
+	if timeout, err := time.ParseDuration(os.Getenv("WORKER_DRAIN_TIMEOUT")); err == nil {
+		config.DrainTimeout = timeout
+	}
+	if concurrency, err := strconv.Atoi(os.Getenv("WORKER_TENANT_CONCURRENCY")); err == nil {
+		config.TenantConcurrency = concurrency
+	}
+
+	emailSvc, err := newEmailService()
+	if err != nil {
+		return err
+	}
+
+	workerMetrics := metrics.New()
+	w, err := worker.Bootstrap(worker.Options{
+		Config:       config,
+		Pool:         pool,
+		Logger:       logger,
+		DeadLetters:  true,
+		Metrics:      workerMetrics,
+		EmailService: emailSvc,
+		AppOrigin:    os.Getenv("APP_ORIGIN"),
+		SecretKey:    []byte(os.Getenv("ENCRYPTION_KEY")),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := w.Manager.EnsureRiverTables(ctx); err != nil {
+		return err
+	}
+	if err := w.Manager.Start(ctx); err != nil {
+		return err
+	}
+
+	server := &http.Server{Addr: metricsAddr(), Handler: newHandler(w.Manager, workerMetrics, os.Getenv("WORKER_ADMIN_TOKEN"))}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("Shutdown signal received, draining worker")
+
+	// Drain with a fresh context: the signal context is already cancelled
+	drainErr := w.Manager.Drain(context.Background())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+
+	return drainErr
+}
+
+// newPool connects to DATABASE_URL, falling back to the local development database
+func newPool(ctx context.Context) (*pgxpool.Pool, error) {
+	if databaseURL := os.Getenv("DATABASE_URL"); databaseURL != "" {
+		return pgxpool.New(ctx, databaseURL)
+	}
+	return database.NewPool(ctx, database.NewDefaultConfig())
+}
+
+// newEmailService sends run failure and missing connection emails through Resend when RESEND_API_KEY is set
+func newEmailService() (email.EmailService, error) {
+	apiKey := os.Getenv("RESEND_API_KEY")
+	if apiKey == "" {
+		return nil, nil
+	}
+	templateEngine, err := email.NewTemplateEngine()
+	if err != nil {
+		return nil, err
+	}
+	return email.New(email.NewResendProvider(apiKey), templateEngine, email.EmailConfig{
+		ResendAPIKey:  apiKey,
+		FromEmail:     os.Getenv("FROM_EMAIL"),
+		ReplyToEmail:  os.Getenv("REPLY_TO_EMAIL"),
+		ImagesBaseURL: os.Getenv("APP_ORIGIN"),
+	}), nil
+}
+
+// metricsAddr returns the listen address for the metrics, health and admin endpoints
+func metricsAddr() string {
+	if addr := os.Getenv("METRICS_ADDR"); addr != "" {
+		return addr
+	}
+	return ":9090"
+}
+
+// newHandler serves Prometheus metrics, the worker health check and, when adminToken is set,
+// the admin endpoints
+func newHandler(manager *workerqueue.Manager, workerMetrics *metrics.Metrics, adminToken string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", workerMetrics.Handler())
+	if adminToken != "" {
+		mux.Handle("/admin/", newAdminHandler(manager, adminToken))
+	}
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if !manager.Health(r.Context()).Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
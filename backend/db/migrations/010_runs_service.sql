@@ -0,0 +1,66 @@
+-- 010_runs_service.sql
+-- Runs Service 数据库表结构，对齐 trigger.dev JobRun 模型
+
+-- 作业运行状态枚举，对齐 trigger.dev JobRunStatus
+CREATE TYPE job_run_status AS ENUM (
+    'PENDING',
+    'QUEUED',
+    'PREPROCESSING',
+    'STARTED',
+    'SUCCESS',
+    'FAILURE',
+    'ABORTED'
+);
+
+-- JobRun 作业运行表
+CREATE TABLE job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL,
+    version_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    environment_id UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    project_id UUID NOT NULL,
+    endpoint_id UUID NOT NULL,
+    queue_id UUID NOT NULL,
+    external_account_id UUID,
+
+    status job_run_status NOT NULL DEFAULT 'PENDING',
+    properties JSONB,
+    output JSONB,
+    is_test BOOLEAN NOT NULL DEFAULT false,
+    preprocess BOOLEAN NOT NULL DEFAULT false,
+
+    queued_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (version_id) REFERENCES job_versions(id) ON DELETE CASCADE,
+    FOREIGN KEY (event_id) REFERENCES event_records(id) ON DELETE CASCADE,
+    FOREIGN KEY (environment_id) REFERENCES runtime_environments(id) ON DELETE CASCADE,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (endpoint_id) REFERENCES endpoints(id) ON DELETE CASCADE,
+    FOREIGN KEY (queue_id) REFERENCES job_queues(id) ON DELETE RESTRICT
+);
+
+-- 运行索引
+CREATE INDEX idx_job_runs_job ON job_runs(job_id);
+CREATE INDEX idx_job_runs_version ON job_runs(version_id);
+CREATE INDEX idx_job_runs_event ON job_runs(event_id);
+CREATE INDEX idx_job_runs_environment_status ON job_runs(environment_id, status);
+CREATE INDEX idx_job_runs_created_at ON job_runs(created_at DESC);
+
+CREATE TRIGGER update_job_runs_updated_at BEFORE UPDATE ON job_runs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- 注释说明
+COMMENT ON TABLE job_runs IS 'JobRun 作业运行表，对齐 trigger.dev JobRun 模型';
+COMMENT ON COLUMN job_runs.event_id IS '触发运行的事件记录ID (event_records.id)';
+COMMENT ON COLUMN job_runs.status IS '运行状态，对齐 trigger.dev JobRunStatus';
+COMMENT ON COLUMN job_runs.properties IS '运行展示属性，由端点预处理返回';
+COMMENT ON COLUMN job_runs.output IS '运行输出 JSON';
+COMMENT ON COLUMN job_runs.preprocess IS '是否需要在执行前调用端点预处理';
@@ -0,0 +1,59 @@
+-- 011_run_tasks.sql
+-- 运行任务日志，对齐 trigger.dev Task 模型，支持可恢复的多步执行
+
+-- 扩展运行状态：执行中 / 等待任务
+ALTER TYPE job_run_status ADD VALUE IF NOT EXISTS 'EXECUTING';
+ALTER TYPE job_run_status ADD VALUE IF NOT EXISTS 'WAITING';
+
+-- 执行次数，每次调用端点 EXECUTE_JOB 递增
+ALTER TABLE job_runs
+ADD COLUMN IF NOT EXISTS execution_count INTEGER NOT NULL DEFAULT 0;
+
+-- 任务状态枚举，对齐 trigger.dev TaskStatus
+CREATE TYPE task_status AS ENUM (
+    'PENDING',
+    'WAITING',
+    'RUNNING',
+    'COMPLETED',
+    'ERRORED',
+    'CANCELED'
+);
+
+-- Task 运行任务表
+CREATE TABLE tasks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL,
+    parent_id UUID,
+    idempotency_key TEXT NOT NULL,
+    display_key TEXT,
+    name TEXT NOT NULL,
+    icon TEXT,
+    status task_status NOT NULL DEFAULT 'PENDING',
+    noop BOOLEAN NOT NULL DEFAULT false,
+    params JSONB,
+    output JSONB,
+    error TEXT,
+    delay_until TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(run_id, idempotency_key),
+    FOREIGN KEY (run_id) REFERENCES job_runs(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- 任务索引
+CREATE INDEX idx_tasks_run_status ON tasks(run_id, status);
+CREATE INDEX idx_tasks_parent ON tasks(parent_id) WHERE parent_id IS NOT NULL;
+
+CREATE TRIGGER update_tasks_updated_at BEFORE UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- 注释说明
+COMMENT ON TABLE tasks IS 'Task 运行任务表，对齐 trigger.dev Task 模型';
+COMMENT ON COLUMN tasks.idempotency_key IS '任务幂等键，在运行内唯一';
+COMMENT ON COLUMN tasks.output IS '任务输出 JSON，后续执行时回传给端点';
+COMMENT ON COLUMN tasks.delay_until IS '延迟任务的恢复时间';
+COMMENT ON COLUMN job_runs.execution_count IS '端点执行调用次数';
@@ -0,0 +1,13 @@
+-- 012_run_queue_slots.sql
+-- 队列并发控制，对齐 trigger.dev JobQueue.jobCount / maxJobs
+
+-- 运行是否占用所属队列的并发槽位，保证槽位释放幂等
+ALTER TABLE job_runs
+ADD COLUMN IF NOT EXISTS holds_queue_slot BOOLEAN NOT NULL DEFAULT false;
+
+-- 按排队时间查找等待槽位的运行
+CREATE INDEX idx_job_runs_queue_waiting ON job_runs(queue_id, queued_at)
+    WHERE status = 'QUEUED' AND holds_queue_slot = false;
+
+-- 注释说明
+COMMENT ON COLUMN job_runs.holds_queue_slot IS '运行是否占用队列并发槽位 (job_queues.job_count)';
@@ -0,0 +1,18 @@
+-- 013_job_status.sql
+-- 作业生命周期状态：启用、暂停、归档
+
+-- PAUSED 作业继续接收事件，但运行保持 QUEUED；ARCHIVED 作业不再匹配事件
+CREATE TYPE job_status AS ENUM ('ENABLED', 'PAUSED', 'ARCHIVED');
+
+ALTER TABLE jobs
+ADD COLUMN IF NOT EXISTS status job_status NOT NULL DEFAULT 'ENABLED',
+ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS status_changed_by VARCHAR(255);
+
+-- 列表默认过滤归档作业
+CREATE INDEX idx_jobs_project_status ON jobs(project_id, status);
+
+-- 注释说明
+COMMENT ON COLUMN jobs.status IS '作业生命周期状态 (ENABLED/PAUSED/ARCHIVED)';
+COMMENT ON COLUMN jobs.status_changed_at IS '最近一次状态变更时间';
+COMMENT ON COLUMN jobs.status_changed_by IS '最近一次状态变更的操作者';
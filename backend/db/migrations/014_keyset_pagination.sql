@@ -0,0 +1,21 @@
+-- 014_keyset_pagination.sql
+-- 作业与事件记录列表的键集分页，按 (created_at, id) 倒序
+
+-- 作业列表：项目内按创建时间分页
+CREATE INDEX IF NOT EXISTS idx_jobs_project_created_id
+    ON jobs(project_id, created_at DESC, id DESC);
+
+-- 作业 slug 前缀过滤
+CREATE INDEX IF NOT EXISTS idx_jobs_project_slug_prefix
+    ON jobs(project_id, slug text_pattern_ops);
+
+-- 事件记录列表：按环境或项目分页
+CREATE INDEX IF NOT EXISTS idx_event_records_environment_created_id
+    ON event_records(environment_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_event_records_project_created_id
+    ON event_records(project_id, created_at DESC, id DESC);
+
+-- 事件记录按名称过滤后分页
+CREATE INDEX IF NOT EXISTS idx_event_records_environment_name_created_id
+    ON event_records(environment_id, name, created_at DESC, id DESC);
@@ -0,0 +1,29 @@
+-- 015_job_version_changelog.sql
+-- 作业版本定义快照与变更日志
+
+-- 注册时的作业定义快照（事件规范、触发器、队列、集成），用于检测同版本重复注册时的内容漂移
+ALTER TABLE job_versions
+ADD COLUMN IF NOT EXISTS definition JSONB;
+
+-- 作业版本变更日志
+CREATE TABLE job_version_changelogs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL,
+    version_id UUID NOT NULL,
+    version VARCHAR(100) NOT NULL,
+    previous_version VARCHAR(100),
+    changes JSONB NOT NULL,
+    overridden BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (version_id) REFERENCES job_versions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_job_version_changelogs_job ON job_version_changelogs(job_id, created_at DESC);
+
+-- 注释说明
+COMMENT ON COLUMN job_versions.definition IS '注册时的作业定义快照';
+COMMENT ON COLUMN job_version_changelogs.previous_version IS '对比的上一版本，同版本覆盖时与 version 相同';
+COMMENT ON COLUMN job_version_changelogs.changes IS '变更字段列表 [{field, previous, current}]';
+COMMENT ON COLUMN job_version_changelogs.overridden IS '是否为同一版本的强制覆盖注册';
@@ -0,0 +1,66 @@
+-- 016_integrations.sql
+-- 集成与集成连接，对齐 trigger.dev Integration / IntegrationConnection / MissingConnection 模型
+
+-- 组织内的集成，作业注册时按 IntegrationConf 自动创建
+CREATE TABLE integrations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    identifier VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    UNIQUE(organization_id, slug),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+-- 集成连接，凭证加密保存在 SecretStore 中，此处只保存引用
+CREATE TABLE integration_connections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    integration_id UUID NOT NULL UNIQUE,
+    organization_id UUID NOT NULL,
+    secret_key TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (integration_id) REFERENCES integrations(id) ON DELETE CASCADE,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+-- 作业版本所需的集成，key 为作业定义中 integrations 的键
+CREATE TABLE job_version_integrations (
+    job_version_id UUID NOT NULL,
+    integration_id UUID NOT NULL,
+    key VARCHAR(100) NOT NULL,
+
+    PRIMARY KEY (job_version_id, key),
+    FOREIGN KEY (job_version_id) REFERENCES job_versions(id) ON DELETE CASCADE,
+    FOREIGN KEY (integration_id) REFERENCES integrations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_job_version_integrations_integration ON job_version_integrations(integration_id);
+
+-- 缺失的连接，每个集成一条，连接建立后标记为已解决
+CREATE TABLE missing_connections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    integration_id UUID NOT NULL UNIQUE,
+    organization_id UUID NOT NULL,
+    notified_at TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (integration_id) REFERENCES integrations(id) ON DELETE CASCADE,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+-- 运行所需集成未连接时保持等待，对齐 trigger.dev WAITING_ON_CONNECTIONS
+ALTER TYPE job_run_status ADD VALUE IF NOT EXISTS 'WAITING_ON_CONNECTIONS' AFTER 'PENDING';
+
+-- 注释说明
+COMMENT ON TABLE integrations IS '集成表，对齐 trigger.dev Integration 模型';
+COMMENT ON COLUMN integrations.identifier IS '集成服务标识，如 github、slack';
+COMMENT ON COLUMN integration_connections.secret_key IS 'SecretStore 中加密凭证的键';
+COMMENT ON TABLE missing_connections IS '缺失的集成连接，用于挂起运行并发送连接提醒';
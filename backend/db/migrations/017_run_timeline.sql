@@ -0,0 +1,41 @@
+-- 017_run_timeline.sql
+-- 运行查询与时间线：执行记录、任务错误堆栈和运行列表索引
+
+-- 运行执行记录，每次调用端点执行动作一条
+CREATE TABLE job_run_executions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL,
+    number INTEGER NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'EXECUTING',
+    error JSONB,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    UNIQUE(run_id, number),
+    FOREIGN KEY (run_id) REFERENCES job_runs(id) ON DELETE CASCADE
+);
+
+-- 任务错误保存端点返回的完整 ErrorWithStack，已有的错误消息迁移为 {"message": ...}
+ALTER TABLE tasks
+ALTER COLUMN error TYPE JSONB
+USING CASE WHEN error IS NULL THEN NULL ELSE jsonb_build_object('message', error) END;
+
+-- 运行列表：项目内按作业、环境分页
+CREATE INDEX IF NOT EXISTS idx_job_runs_project_created_id
+    ON job_runs(project_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_created_id
+    ON job_runs(job_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_environment_created_id
+    ON job_runs(environment_id, created_at DESC, id DESC);
+
+-- 注释说明
+COMMENT ON TABLE job_run_executions IS '运行执行记录，用于运行时间线';
+COMMENT ON COLUMN job_run_executions.number IS '运行的第几次执行，对应 job_runs.execution_count';
+COMMENT ON COLUMN job_run_executions.reason IS '执行原因，如 EXECUTE_JOB、PREPROCESS';
+COMMENT ON COLUMN job_run_executions.status IS '执行结果：EXECUTING、SUCCESS、ERROR、RESUME_WITH_TASK、YIELD_EXECUTION 或 FAILED（端点不可达）';
+COMMENT ON COLUMN job_run_executions.error IS '端点返回的 ErrorWithStack';
+COMMENT ON COLUMN tasks.error IS '端点返回的 ErrorWithStack';
@@ -0,0 +1,15 @@
+-- 018_run_cancellation.sql
+-- 运行取消与重新运行
+
+-- 被操作员取消的运行
+ALTER TYPE job_run_status ADD VALUE IF NOT EXISTS 'CANCELED';
+
+-- 重新运行关联到原始运行
+ALTER TABLE job_runs
+ADD COLUMN IF NOT EXISTS rerun_of_id UUID REFERENCES job_runs(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_rerun_of ON job_runs(rerun_of_id)
+    WHERE rerun_of_id IS NOT NULL;
+
+-- 注释说明
+COMMENT ON COLUMN job_runs.rerun_of_id IS '重新运行时指向原始运行';
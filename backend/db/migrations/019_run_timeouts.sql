@@ -0,0 +1,18 @@
+-- 019_run_timeouts.sql
+-- 运行最长持续时间
+
+-- 作业注册时声明的运行最长持续时间（秒），为空时使用环境默认值
+ALTER TABLE job_versions
+ADD COLUMN IF NOT EXISTS max_run_duration_seconds INTEGER CHECK (max_run_duration_seconds > 0);
+
+-- 环境级默认运行最长持续时间（秒），为空时不限制
+ALTER TABLE runtime_environments
+ADD COLUMN IF NOT EXISTS default_max_run_duration_seconds INTEGER CHECK (default_max_run_duration_seconds > 0);
+
+-- 超时巡检只扫描执行中和等待中的运行
+CREATE INDEX IF NOT EXISTS idx_job_runs_active_started ON job_runs(started_at)
+    WHERE status IN ('EXECUTING', 'WAITING');
+
+-- 注释说明
+COMMENT ON COLUMN job_versions.max_run_duration_seconds IS '运行最长持续时间（秒），从首次执行开始计算';
+COMMENT ON COLUMN runtime_environments.default_max_run_duration_seconds IS '作业未声明时使用的运行最长持续时间（秒）';
@@ -0,0 +1,35 @@
+-- 020_run_failure_notifications.sql
+-- 运行失败邮件通知：组织成员、用户通知偏好与按作业去重
+
+-- 组织成员，对齐 trigger.dev OrgMember
+CREATE TABLE IF NOT EXISTS org_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL DEFAULT 'MEMBER' CHECK (role IN ('ADMIN', 'MEMBER')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_members_user ON org_members(user_id);
+
+-- 用户通知偏好，没有记录时使用默认值
+CREATE TABLE IF NOT EXISTS user_notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    run_failure_emails BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- 作业失败通知去重，每个作业在时间窗口内只通知一次
+CREATE TABLE IF NOT EXISTS job_failure_notifications (
+    job_id UUID PRIMARY KEY REFERENCES jobs(id) ON DELETE CASCADE,
+    run_id UUID REFERENCES job_runs(id) ON DELETE SET NULL,
+    notified_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- 注释说明
+COMMENT ON COLUMN user_notification_preferences.run_failure_emails IS '是否接收生产环境运行失败邮件';
+COMMENT ON COLUMN job_failure_notifications.run_id IS '最近一次发送通知的运行';
+COMMENT ON COLUMN job_failure_notifications.notified_at IS '最近一次发送通知的时间';
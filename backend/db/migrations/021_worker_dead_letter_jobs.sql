@@ -0,0 +1,20 @@
+-- 021_worker_dead_letter_jobs.sql
+-- 死信队列：耗尽重试次数被丢弃的 River 作业，保留原始参数以便重放
+
+CREATE TABLE IF NOT EXISTS worker_dead_letter_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL UNIQUE,
+    kind TEXT NOT NULL,
+    queue TEXT NOT NULL,
+    args JSONB NOT NULL,
+    errors JSONB NOT NULL DEFAULT '[]',
+    attempt INTEGER NOT NULL,
+    max_attempts INTEGER NOT NULL,
+    discarded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    replayed_at TIMESTAMP WITH TIME ZONE,
+    replayed_job_id BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_worker_dead_letter_jobs_kind ON worker_dead_letter_jobs(kind, discarded_at DESC);
+CREATE INDEX IF NOT EXISTS idx_worker_dead_letter_jobs_pending ON worker_dead_letter_jobs(discarded_at DESC) WHERE replayed_at IS NULL;
@@ -0,0 +1,15 @@
+-- 022_worker_rate_limits.sql
+-- 作业限流令牌桶：按作业类型和参数派生的键（如端点、组织）限制执行速率
+
+CREATE TABLE IF NOT EXISTS worker_rate_limit_buckets (
+    kind TEXT NOT NULL,
+    key TEXT NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (kind, key)
+);
+
+COMMENT ON TABLE worker_rate_limit_buckets IS '作业限流令牌桶，每次执行消耗一个令牌，令牌按速率随时间补充';
+COMMENT ON COLUMN worker_rate_limit_buckets.key IS '由作业参数字段派生的限流键，未配置时为空字符串';
+COMMENT ON COLUMN worker_rate_limit_buckets.tokens IS '上次更新时剩余的令牌数';
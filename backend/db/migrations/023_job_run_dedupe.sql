@@ -0,0 +1,6 @@
+-- 023_job_run_dedupe.sql
+-- 同一事件对同一作业版本只创建一个运行，调度任务重试时不会重复创建
+
+-- 重跑沿用原运行的事件与版本，不受此约束
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_runs_event_version ON job_runs(event_id, version_id)
+    WHERE rerun_of_id IS NULL;
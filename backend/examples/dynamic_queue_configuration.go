@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -26,13 +25,15 @@ type UserTaskArgs struct {
 func demonstrateDynamicQueueConfiguration() {
 	logger := slog.Default()
 
+	// 任务由 Kind 对应的 River worker 处理，Kind 需要先通过 workerqueue.RegisterTask 注册
+
 	// 1. 静态队列配置（向后兼容）
 	staticTaskCatalog := workerqueue.TaskCatalog{
 		"legacyTask": workerqueue.TaskDefinition{
 			QueueName:   workerqueue.StaticQueueName("legacy-queue"), // ✅ 静态队列名称
 			Priority:    1,
 			MaxAttempts: 3,
-			Handler:     handleLegacyTask,
+			Kind:        "legacy_task",
 		},
 	}
 
@@ -47,7 +48,7 @@ func demonstrateDynamicQueueConfiguration() {
 				return "default-runs" // 回退队列
 			}),
 			MaxAttempts: 1,
-			Handler:     handlePerformRunExecution,
+			Kind:        "perform_run_execution_v2",
 		},
 
 		// 按项目 ID 分配队列 - 实现项目级别隔离
@@ -59,7 +60,7 @@ func demonstrateDynamicQueueConfiguration() {
 				return "default-project-runs"
 			}),
 			MaxAttempts: 3,
-			Handler:     handleStartQueuedRuns,
+			Kind:        "start_queued_runs",
 		},
 
 		// 按用户等级和地理位置分配队列 - 实现多维度路由
@@ -79,7 +80,7 @@ func demonstrateDynamicQueueConfiguration() {
 				return "default-user-tasks"
 			}),
 			MaxAttempts: 5,
-			Handler:     handleUserTask,
+			Kind:        "user_task",
 		},
 	}
 
@@ -89,7 +90,7 @@ func demonstrateDynamicQueueConfiguration() {
 		"indexEndpoint": workerqueue.TaskDefinition{
 			QueueName:   workerqueue.StaticQueueName("internal-queue"),
 			MaxAttempts: 7,
-			Handler:     handleIndexEndpoint,
+			Kind:        "index_endpoint",
 		},
 
 		// 动态队列：适用于复杂业务逻辑
@@ -108,7 +109,7 @@ func demonstrateDynamicQueueConfiguration() {
 				return "small-payload-queue"
 			}),
 			MaxAttempts: 3,
-			Handler:     handleSmartTask,
+			Kind:        "smart_task",
 		},
 	}
 
@@ -159,37 +160,6 @@ func demonstrateQueueResolution(catalog workerqueue.TaskCatalog, logger *slog.Lo
 	}
 }
 
-// 示例处理器函数
-func handleLegacyTask(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Println("Processing legacy task in static queue:", job.Queue)
-	return nil
-}
-
-func handlePerformRunExecution(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Printf("Processing run execution in queue: %s\n", job.Queue)
-	return nil
-}
-
-func handleStartQueuedRuns(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Printf("Starting queued runs in queue: %s\n", job.Queue)
-	return nil
-}
-
-func handleUserTask(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Printf("Processing user task in queue: %s\n", job.Queue)
-	return nil
-}
-
-func handleIndexEndpoint(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Printf("Indexing endpoint in queue: %s\n", job.Queue)
-	return nil
-}
-
-func handleSmartTask(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Printf("Processing smart task in queue: %s\n", job.Queue)
-	return nil
-}
-
 // 业务场景示例
 func businessScenarioExamples() {
 	logger := slog.Default()
@@ -215,7 +185,7 @@ func businessScenarioExamples() {
 				return "default-customers"
 			}),
 			MaxAttempts: 5,
-			Handler:     handleCustomerData,
+			Kind:        "process_customer_data",
 		},
 	}
 
@@ -232,7 +202,7 @@ func businessScenarioExamples() {
 				return "gdpr:default:data-processing"
 			}),
 			MaxAttempts: 3,
-			Handler:     handleGDPRData,
+			Kind:        "process_gdpr_data",
 		},
 	}
 
@@ -255,7 +225,7 @@ func businessScenarioExamples() {
 				return "analytics:default"
 			}),
 			MaxAttempts: 3,
-			Handler:     handleAnalytics,
+			Kind:        "process_analytics",
 		},
 	}
 
@@ -264,22 +234,6 @@ func businessScenarioExamples() {
 	logger.Info("Workload priority catalog", "tasks", len(workloadPriorityCatalog))
 }
 
-// 示例处理器
-func handleCustomerData(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Printf("Processing customer data in queue: %s\n", job.Queue)
-	return nil
-}
-
-func handleGDPRData(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Printf("Processing GDPR data in queue: %s\n", job.Queue)
-	return nil
-}
-
-func handleAnalytics(ctx context.Context, payload json.RawMessage, job workerqueue.JobContext) error {
-	fmt.Printf("Processing analytics in queue: %s\n", job.Queue)
-	return nil
-}
-
 func main() {
 	fmt.Println("🚀 KongFlow 动态队列配置示例")
 	fmt.Println("=====================================")
@@ -3,6 +3,7 @@ package apiauth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -15,15 +16,53 @@ const (
 	unifiedAuthResultKey contextKey = "unified_auth_result"
 )
 
+// AuthMetrics records rejected API requests
+// Implemented by the metrics package; a nil AuthMetrics records nothing
+type AuthMetrics interface {
+	AuthFailed(reason string)
+}
+
 // AuthMiddleware provides HTTP middleware for API authentication
 type AuthMiddleware struct {
 	service APIAuthService
+	metrics AuthMetrics
 }
 
 // NewAuthMiddleware creates a new authentication middleware
 func NewAuthMiddleware(service APIAuthService) *AuthMiddleware {
+	return NewAuthMiddlewareWithMetrics(service, nil)
+}
+
+// NewAuthMiddlewareWithMetrics creates a new authentication middleware that records failures
+func NewAuthMiddlewareWithMetrics(service APIAuthService, metrics AuthMetrics) *AuthMiddleware {
 	return &AuthMiddleware{
 		service: service,
+		metrics: metrics,
+	}
+}
+
+// recordFailure records a rejected request when metrics are configured
+func (m *AuthMiddleware) recordFailure(reason AuthFailureReason) {
+	if m.metrics == nil {
+		return
+	}
+	if reason == "" {
+		reason = AuthFailureInternalError
+	}
+	m.metrics.AuthFailed(string(reason))
+}
+
+// authRequestFailureReason classifies an error returned by AuthenticateRequest
+func authRequestFailureReason(err error) AuthFailureReason {
+	switch {
+	case errors.Is(err, ErrMissingAuthorizationHeader):
+		return AuthFailureMissingHeader
+	case errors.Is(err, ErrInvalidAuthorizationFormat):
+		return AuthFailureInvalidFormat
+	case errors.Is(err, ErrAuthenticationFailed):
+		return AuthFailureNoMethodMatched
+	default:
+		return AuthFailureInternalError
 	}
 }
 
@@ -33,11 +72,13 @@ func (m *AuthMiddleware) RequireAPIKey(opts *AuthOptions) func(http.Handler) htt
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			result, err := m.service.AuthenticateAPIRequest(r.Context(), r, opts)
 			if err != nil {
+				m.recordFailure(AuthFailureInternalError)
 				http.Error(w, "Authentication error", http.StatusInternalServerError)
 				return
 			}
 
 			if !result.Success {
+				m.recordFailure(result.FailureReason)
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusUnauthorized)
 				json.NewEncoder(w).Encode(map[string]string{
@@ -59,6 +100,7 @@ func (m *AuthMiddleware) RequireAuth(config *AuthConfig) func(http.Handler) http
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			result, err := m.service.AuthenticateRequest(r.Context(), r, config)
 			if err != nil {
+				m.recordFailure(authRequestFailureReason(err))
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusUnauthorized)
 				json.NewEncoder(w).Encode(map[string]string{
@@ -0,0 +1,64 @@
+package apiauth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingAuthMetrics records failure reasons passed to AuthFailed
+type recordingAuthMetrics struct {
+	reasons []string
+}
+
+func (m *recordingAuthMetrics) AuthFailed(reason string) {
+	m.reasons = append(m.reasons, reason)
+}
+
+// Test that rejected requests are recorded with their failure reason
+func TestAuthMiddlewareRecordsFailureReasons(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("FindEnvironmentByAPIKey", mock.Anything, "tr_unknown_key").Return(nil, errors.New("not found"))
+
+	metrics := &recordingAuthMetrics{}
+	middleware := NewAuthMiddlewareWithMetrics(&service{repo: repo, jwtSecret: []byte("test-secret")}, metrics)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	apiKeyHandler := middleware.RequireAPIKey(&AuthOptions{})(next)
+	authHandler := middleware.RequireAuth(&AuthConfig{})(next)
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		authHeader string
+		expected   AuthFailureReason
+	}{
+		{"Missing Header", apiKeyHandler, "", AuthFailureMissingHeader},
+		{"Not Bearer", apiKeyHandler, "Basic abc", AuthFailureInvalidFormat},
+		{"Public Key Not Allowed", apiKeyHandler, "Bearer pk_test_123", AuthFailureKeyTypeNotAllowed},
+		{"Unknown Private Key", apiKeyHandler, "Bearer tr_unknown_key", AuthFailureEnvironmentMissing},
+		{"No Method Matched", authHandler, "Bearer tr_unknown_key", AuthFailureNoMethodMatched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics.reasons = nil
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Equal(t, []string{string(tt.expected)}, metrics.reasons)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package apiauth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -10,6 +11,13 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Errors returned by AuthenticateRequest
+var (
+	ErrMissingAuthorizationHeader = errors.New("missing authorization header")
+	ErrInvalidAuthorizationFormat = errors.New("invalid authorization format")
+	ErrAuthenticationFailed       = errors.New("authentication failed with all configured methods")
+)
+
 // service implements APIAuthService interface - trigger.dev apiAuth.server.ts alignment
 type service struct {
 	repo      Repository
@@ -30,8 +38,9 @@ func (s *service) AuthenticateAPIRequest(ctx context.Context, req *http.Request,
 	authHeader := req.Header.Get("Authorization")
 	if authHeader == "" {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "missing authorization header",
+			Success:       false,
+			Error:         "missing authorization header",
+			FailureReason: AuthFailureMissingHeader,
 		}, nil
 	}
 
@@ -43,8 +52,9 @@ func (s *service) AuthenticateAuthorizationHeader(ctx context.Context, authoriza
 	// Validate Bearer format
 	if !strings.HasPrefix(authorization, "Bearer ") {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "invalid authorization format, expected 'Bearer {token}'",
+			Success:       false,
+			Error:         "invalid authorization format, expected 'Bearer {token}'",
+			FailureReason: AuthFailureInvalidFormat,
 		}, nil
 	}
 
@@ -52,8 +62,9 @@ func (s *service) AuthenticateAuthorizationHeader(ctx context.Context, authoriza
 	apiKey := strings.TrimPrefix(authorization, "Bearer ")
 	if apiKey == "" {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "empty token in authorization header",
+			Success:       false,
+			Error:         "empty token in authorization header",
+			FailureReason: AuthFailureInvalidFormat,
 		}, nil
 	}
 
@@ -66,23 +77,26 @@ func (s *service) authenticateAPIKey(ctx context.Context, apiKey string, opts *A
 	keyType := getAPIKeyType(apiKey)
 	if keyType == "" {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "invalid API key format",
+			Success:       false,
+			Error:         "invalid API key format",
+			FailureReason: AuthFailureInvalidKey,
 		}, nil
 	}
 
 	// Check if key type is allowed
 	if !opts.AllowPublicKey && keyType == APIKeyTypePublic {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "public API keys are not allowed for this request",
+			Success:       false,
+			Error:         "public API keys are not allowed for this request",
+			FailureReason: AuthFailureKeyTypeNotAllowed,
 		}, nil
 	}
 
 	if !opts.AllowJWT && keyType == APIKeyTypePublicJWT {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "public JWT API keys are not allowed for this request",
+			Success:       false,
+			Error:         "public JWT API keys are not allowed for this request",
+			FailureReason: AuthFailureKeyTypeNotAllowed,
 		}, nil
 	}
 
@@ -96,8 +110,9 @@ func (s *service) authenticateAPIKey(ctx context.Context, apiKey string, opts *A
 		return s.authenticateJWTKey(ctx, apiKey)
 	default:
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "unsupported API key type",
+			Success:       false,
+			Error:         "unsupported API key type",
+			FailureReason: AuthFailureInvalidKey,
 		}, nil
 	}
 }
@@ -106,11 +121,11 @@ func (s *service) authenticateAPIKey(ctx context.Context, apiKey string, opts *A
 func (s *service) AuthenticateRequest(ctx context.Context, req *http.Request, config *AuthConfig) (*UnifiedAuthResult, error) {
 	authHeader := req.Header.Get("Authorization")
 	if authHeader == "" {
-		return nil, fmt.Errorf("missing authorization header")
+		return nil, ErrMissingAuthorizationHeader
 	}
 
 	if !strings.HasPrefix(authHeader, "Bearer ") {
-		return nil, fmt.Errorf("invalid authorization format")
+		return nil, ErrInvalidAuthorizationFormat
 	}
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
@@ -158,7 +173,7 @@ func (s *service) AuthenticateRequest(ctx context.Context, req *http.Request, co
 		}
 	}
 
-	return nil, fmt.Errorf("authentication failed with all configured methods")
+	return nil, ErrAuthenticationFailed
 }
 
 // GenerateJWTToken generates JWT token for environment - trigger.dev alignment
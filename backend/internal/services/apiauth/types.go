@@ -38,6 +38,20 @@ const (
 	AuthTypeAPIKey                  AuthenticationType = "apiKey"
 )
 
+// AuthFailureReason classifies why API authentication failed
+type AuthFailureReason string
+
+const (
+	AuthFailureMissingHeader      AuthFailureReason = "missing_header"        // no Authorization header
+	AuthFailureInvalidFormat      AuthFailureReason = "invalid_format"        // not a Bearer token
+	AuthFailureInvalidKey         AuthFailureReason = "invalid_key"           // unrecognised or malformed API key
+	AuthFailureKeyTypeNotAllowed  AuthFailureReason = "key_type_not_allowed"  // key type disallowed by AuthOptions
+	AuthFailureEnvironmentMissing AuthFailureReason = "environment_not_found" // no environment for the key
+	AuthFailureInvalidJWT         AuthFailureReason = "invalid_jwt"           // bad signature or claims
+	AuthFailureNoMethodMatched    AuthFailureReason = "no_method_matched"     // every method in AuthConfig failed
+	AuthFailureInternalError      AuthFailureReason = "internal_error"        // authentication could not complete
+)
+
 // AuthOptions configures API key authentication behavior - trigger.dev alignment
 type AuthOptions struct {
 	AllowPublicKey bool   `json:"allowPublicKey"`
@@ -67,14 +81,15 @@ type RuntimeEnvironment struct {
 
 // AuthenticationResult represents API key authentication outcome - trigger.dev alignment
 type AuthenticationResult struct {
-	Success     bool                `json:"success"`
-	Error       string              `json:"error,omitempty"`
-	APIKey      string              `json:"apiKey"`
-	Type        APIKeyType          `json:"type"`
-	Environment *RuntimeEnvironment `json:"environment"`
-	Scopes      []string            `json:"scopes,omitempty"`
-	OneTimeUse  bool                `json:"oneTimeUse,omitempty"`
-	Realtime    bool                `json:"realtime,omitempty"`
+	Success       bool                `json:"success"`
+	Error         string              `json:"error,omitempty"`
+	FailureReason AuthFailureReason   `json:"failureReason,omitempty"`
+	APIKey        string              `json:"apiKey"`
+	Type          APIKeyType          `json:"type"`
+	Environment   *RuntimeEnvironment `json:"environment"`
+	Scopes        []string            `json:"scopes,omitempty"`
+	OneTimeUse    bool                `json:"oneTimeUse,omitempty"`
+	Realtime      bool                `json:"realtime,omitempty"`
 }
 
 // UnifiedAuthResult represents unified authentication result supporting multiple auth types
@@ -50,8 +50,9 @@ func (s *service) authenticatePublicKey(ctx context.Context, apiKey string, bran
 	// Validate format
 	if err := validateAPIKeyFormat(apiKey); err != nil {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   err.Error(),
+			Success:       false,
+			Error:         err.Error(),
+			FailureReason: AuthFailureInvalidKey,
 		}, nil
 	}
 
@@ -59,8 +60,9 @@ func (s *service) authenticatePublicKey(ctx context.Context, apiKey string, bran
 	env, err := s.repo.FindEnvironmentByPublicAPIKey(ctx, apiKey, &branchName)
 	if err != nil {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "invalid public API key or environment not found",
+			Success:       false,
+			Error:         "invalid public API key or environment not found",
+			FailureReason: AuthFailureEnvironmentMissing,
 		}, nil
 	}
 
@@ -77,8 +79,9 @@ func (s *service) authenticatePrivateKey(ctx context.Context, apiKey string, bra
 	// Validate format
 	if err := validateAPIKeyFormat(apiKey); err != nil {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   err.Error(),
+			Success:       false,
+			Error:         err.Error(),
+			FailureReason: AuthFailureInvalidKey,
 		}, nil
 	}
 
@@ -86,8 +89,9 @@ func (s *service) authenticatePrivateKey(ctx context.Context, apiKey string, bra
 	env, err := s.repo.FindEnvironmentByAPIKey(ctx, apiKey)
 	if err != nil {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "invalid private API key or environment not found",
+			Success:       false,
+			Error:         "invalid private API key or environment not found",
+			FailureReason: AuthFailureEnvironmentMissing,
 		}, nil
 	}
 
@@ -112,8 +116,9 @@ func (s *service) authenticateJWTKey(ctx context.Context, jwtToken string) (*Aut
 
 	if err != nil {
 		return &AuthenticationResult{
-			Success: false,
-			Error:   "invalid JWT token",
+			Success:       false,
+			Error:         "invalid JWT token",
+			FailureReason: AuthFailureInvalidJWT,
 		}, nil
 	}
 
@@ -123,16 +128,18 @@ func (s *service) authenticateJWTKey(ctx context.Context, jwtToken string) (*Aut
 		envID, ok := claims["sub"].(string)
 		if !ok {
 			return &AuthenticationResult{
-				Success: false,
-				Error:   "invalid JWT claims: missing subject",
+				Success:       false,
+				Error:         "invalid JWT claims: missing subject",
+				FailureReason: AuthFailureInvalidJWT,
 			}, nil
 		}
 
 		// Validate environment ID format
 		if _, err := uuid.Parse(envID); err != nil {
 			return &AuthenticationResult{
-				Success: false,
-				Error:   "invalid environment ID in JWT",
+				Success:       false,
+				Error:         "invalid environment ID in JWT",
+				FailureReason: AuthFailureInvalidJWT,
 			}, nil
 		}
 
@@ -140,8 +147,9 @@ func (s *service) authenticateJWTKey(ctx context.Context, jwtToken string) (*Aut
 		env, err := s.repo.FindEnvironmentByAPIKey(ctx, envID)
 		if err != nil {
 			return &AuthenticationResult{
-				Success: false,
-				Error:   "environment not found for JWT",
+				Success:       false,
+				Error:         "environment not found for JWT",
+				FailureReason: AuthFailureEnvironmentMissing,
 			}, nil
 		}
 
@@ -174,8 +182,9 @@ func (s *service) authenticateJWTKey(ctx context.Context, jwtToken string) (*Aut
 	}
 
 	return &AuthenticationResult{
-		Success: false,
-		Error:   "invalid JWT token claims",
+		Success:       false,
+		Error:         "invalid JWT token claims",
+		FailureReason: AuthFailureInvalidJWT,
 	}, nil
 }
 
@@ -108,6 +108,24 @@ func (m *MockQueries) UpdateProject(ctx context.Context, arg shared.UpdateProjec
 func (m *MockQueries) UpdateRuntimeEnvironment(ctx context.Context, arg shared.UpdateRuntimeEnvironmentParams) (shared.RuntimeEnvironments, error) {
 	return shared.RuntimeEnvironments{}, nil
 }
+func (m *MockQueries) UpdateRuntimeEnvironmentMaxRunDuration(ctx context.Context, arg shared.UpdateRuntimeEnvironmentMaxRunDurationParams) (shared.RuntimeEnvironments, error) {
+	return shared.RuntimeEnvironments{}, nil
+}
+func (m *MockQueries) GetUserNotificationPreferences(ctx context.Context, userID pgtype.UUID) (shared.UserNotificationPreferences, error) {
+	return shared.UserNotificationPreferences{}, nil
+}
+func (m *MockQueries) UpsertUserNotificationPreferences(ctx context.Context, arg shared.UpsertUserNotificationPreferencesParams) (shared.UserNotificationPreferences, error) {
+	return shared.UserNotificationPreferences{}, nil
+}
+func (m *MockQueries) UpsertOrgMember(ctx context.Context, arg shared.UpsertOrgMemberParams) (shared.OrgMembers, error) {
+	return shared.OrgMembers{}, nil
+}
+func (m *MockQueries) DeleteOrgMember(ctx context.Context, arg shared.DeleteOrgMemberParams) error {
+	return nil
+}
+func (m *MockQueries) ListOrgMembersByOrganization(ctx context.Context, organizationID pgtype.UUID) ([]shared.OrgMembers, error) {
+	return nil, nil
+}
 func (m *MockQueries) UpdateUser(ctx context.Context, arg shared.UpdateUserParams) (shared.Users, error) {
 	return shared.Users{}, nil
 }
@@ -207,6 +207,35 @@ func TestTemplateRendering_Quality(t *testing.T) {
 	if !contains(html, "Hi there,") {
 		t.Error("Welcome template should contain 'Hi there,' when no name provided")
 	}
+
+	// Test workflow failed template with rendered failure reason
+	workflowFailedData := WorkflowFailedEmailData{
+		UserName:          "Alice",
+		WorkflowName:      "Sync Stripe customers",
+		FailureReason:     "Stripe API returned 500",
+		FailureReasonHTML: "<p>Stripe API returned <code>500</code></p>",
+		WorkflowLink:      "https://kongflow.dev/orgs/acme/projects/web/jobs/sync/runs/run_1",
+		RunID:             "run_1",
+	}
+
+	html, err = templateEngine.RenderTemplate(string(EmailTypeWorkflowFailed), workflowFailedData)
+	if err != nil {
+		t.Fatalf("Failed to render workflow failed template: %v", err)
+	}
+
+	expectedElements = []string{
+		"Hi Alice,",
+		`Your workflow "Sync Stripe customers" has failed`,
+		"<p>Stripe API returned <code>500</code></p>",
+		"Run ID: run_1",
+		"https://kongflow.dev/orgs/acme/projects/web/jobs/sync/runs/run_1",
+	}
+
+	for _, element := range expectedElements {
+		if !contains(html, element) {
+			t.Errorf("Workflow failed template missing expected element: %s", element)
+		}
+	}
 }
 
 // Helper function
@@ -49,12 +49,12 @@ func (t *templateEngine) RenderTemplate(templateName string, data interface{}) (
 func (t *templateEngine) loadTemplates() error {
 	// Template mapping from email type to template file
 	templateFiles := map[string]string{
-		string(EmailTypeMagicLink): "templates/magic_link.html",
-		string(EmailTypeWelcome):   "templates/welcome.html",
-		string(EmailTypeInvite):    "templates/invite.html",
+		string(EmailTypeMagicLink):          "templates/magic_link.html",
+		string(EmailTypeWelcome):            "templates/welcome.html",
+		string(EmailTypeInvite):             "templates/invite.html",
+		string(EmailTypeConnectIntegration): "templates/connect_integration.html",
+		string(EmailTypeWorkflowFailed):     "templates/workflow_failed.html",
 		// Placeholder templates for remaining types (will be implemented later)
-		string(EmailTypeConnectIntegration):  "",
-		string(EmailTypeWorkflowFailed):      "",
 		string(EmailTypeWorkflowIntegration): "",
 	}
 
@@ -5,6 +5,7 @@ package email
 import (
 	"context"
 	"encoding/json"
+	"html/template"
 	"time"
 
 	"kongflow/backend/internal/services/workerqueue"
@@ -54,15 +55,21 @@ type InviteEmailData struct {
 
 // ConnectIntegrationEmailData represents data for integration connection emails
 type ConnectIntegrationEmailData struct {
+	UserName        string `json:"userName,omitempty"`
 	IntegrationName string `json:"integrationName" validate:"required"`
 	ConnectLink     string `json:"connectLink" validate:"required,url"`
 }
 
 // WorkflowFailedEmailData represents data for workflow failure notifications
 type WorkflowFailedEmailData struct {
+	UserName      string `json:"userName,omitempty"`
 	WorkflowName  string `json:"workflowName" validate:"required"`
 	FailureReason string `json:"failureReason" validate:"required"`
-	WorkflowLink  string `json:"workflowLink" validate:"required,url"`
+	// FailureReasonHTML is the failure reason rendered from markdown, shown instead of FailureReason when set
+	FailureReasonHTML template.HTML `json:"failureReasonHtml,omitempty"`
+	WorkflowLink      string        `json:"workflowLink" validate:"required,url"`
+	RunID             string        `json:"runId,omitempty"`
+	FailedAt          string        `json:"failedAt,omitempty"`
 }
 
 // WorkflowIntegrationEmailData represents data for workflow integration emails
@@ -7,10 +7,20 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracerName 端点 API 调用 span 的 tracer 名称
+const tracerName = "kongflow/backend/internal/services/endpointapi"
+
 // NewClient 创建新的 EndpointApi 客户端
 func NewClient(apiKey, url, endpointID string, logger Logger) *Client {
 	return &Client{
@@ -33,6 +43,11 @@ func NewClientWithHTTPClient(apiKey, url, endpointID string, httpClient HTTPClie
 	}
 }
 
+// SetMetrics 设置调用指标记录器
+func (c *Client) SetMetrics(metrics Metrics) {
+	c.metrics = metrics
+}
+
 // buildRequest 构建 HTTP 请求
 func (c *Client) buildRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
 	url := c.url
@@ -53,17 +68,54 @@ func (c *Client) buildRequest(ctx context.Context, method, path string, body io.
 
 // safeFetch 安全执行 HTTP 请求，对齐 trigger.dev 的 safeFetch 行为
 func (c *Client) safeFetch(req *http.Request) (*http.Response, error) {
+	action := req.Header.Get("x-trigger-action")
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), "endpointapi "+action,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("endpoint.id", c.endpointID),
+			attribute.String("endpoint.action", action),
+			attribute.String("http.request.method", req.Method),
+		),
+	)
+	defer span.End()
+
+	// 注入 traceparent 头，端点可继续同一条 trace
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
+	c.observeRequest(req, resp, time.Since(start))
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "could not connect to endpoint")
 		c.logger.Debug("Error while trying to connect to endpoint", map[string]interface{}{
 			"url":   req.URL.String(),
 			"error": err.Error(),
 		})
 		return nil, err
 	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
 	return resp, nil
 }
 
+// observeRequest 按动作记录调用状态和耗时
+func (c *Client) observeRequest(req *http.Request, resp *http.Response, duration time.Duration) {
+	if c.metrics == nil {
+		return
+	}
+
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	c.metrics.ObserveEndpointRequest(req.Header.Get("x-trigger-action"), status, duration)
+}
+
 // Ping 检测端点连接 (对齐 trigger.dev ping 方法)
 func (c *Client) Ping(ctx context.Context) (*PongResponse, error) {
 	req, err := c.buildRequest(ctx, "POST", "", nil)
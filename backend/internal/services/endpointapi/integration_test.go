@@ -8,6 +8,12 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TestClient_Integration_Ping 集成测试 Ping 方法
@@ -505,3 +511,97 @@ func TestClient_Integration_DeliverHttpSourceRequest_NoDynamicID(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.True(t, result.Success)
 }
+
+// TestClient_Integration_PreprocessRunRequest_Abort 测试预处理响应中的 abort 和 properties
+func TestClient_Integration_PreprocessRunRequest_Abort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"abort": true, "properties": [{"label": "Reason", "text": "duplicate"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL, "test-endpoint", &MockLogger{})
+
+	result, err := client.PreprocessRunRequest(context.Background(), &PreprocessRunBody{ID: "run-123"})
+	assert.NoError(t, err)
+
+	var response PreprocessRunResponse
+	assert.NoError(t, result.Parse(&response))
+	assert.True(t, response.Abort)
+	assert.Len(t, response.Properties, 1)
+	assert.Equal(t, "Reason", response.Properties[0].Label)
+	assert.Equal(t, "duplicate", response.Properties[0].Text)
+}
+
+// recordingMetrics 记录端点调用指标
+type recordingMetrics struct {
+	actions  []string
+	statuses []string
+}
+
+func (m *recordingMetrics) ObserveEndpointRequest(action, status string, duration time.Duration) {
+	m.actions = append(m.actions, action)
+	m.statuses = append(m.statuses, status)
+}
+
+// TestClient_Integration_Metrics 测试按动作记录调用状态
+func TestClient_Integration_Metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+	}))
+
+	metrics := &recordingMetrics{}
+	client := NewClient("test-key", server.URL, "test-endpoint", &MockLogger{})
+	client.SetMetrics(metrics)
+
+	_, err := client.Ping(context.Background())
+	assert.NoError(t, err)
+
+	// 服务器关闭后连接失败记录为 error
+	server.Close()
+	_, err = client.IndexEndpoint(context.Background())
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"PING", "INDEX_ENDPOINT"}, metrics.actions)
+	assert.Equal(t, []string{"401", "error"}, metrics.statuses)
+}
+
+// TestClient_Integration_TraceContext 测试调用 span 与 traceparent 头注入
+func TestClient_Integration_TraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL, "test-endpoint", &MockLogger{})
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "runs.PerformRunExecution")
+	_, err := client.Ping(ctx)
+	parent.End()
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	call := spans[0]
+	assert.Equal(t, "endpointapi PING", call.Name())
+	assert.Equal(t, trace.SpanKindClient, call.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), call.Parent().SpanID())
+
+	// 端点收到的 traceparent 指向调用 span
+	assert.Contains(t, traceparent, call.SpanContext().TraceID().String())
+	assert.Contains(t, traceparent, call.SpanContext().SpanID().String())
+}
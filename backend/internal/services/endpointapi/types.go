@@ -16,6 +16,12 @@ type Logger interface {
 	Error(msg string, fields map[string]interface{})
 }
 
+// Metrics 记录端点 API 调用，由 metrics 包实现；为 nil 时不记录
+type Metrics interface {
+	// ObserveEndpointRequest 记录一次调用的动作 (x-trigger-action)、状态码 (连接失败时为 "error") 和耗时
+	ObserveEndpointRequest(action, status string, duration time.Duration)
+}
+
 // Client 对应 trigger.dev 的 EndpointApi 类
 type Client struct {
 	apiKey     string
@@ -23,6 +29,7 @@ type Client struct {
 	endpointID string
 	httpClient HTTPClient
 	logger     Logger
+	metrics    Metrics
 }
 
 // 请求/响应类型 (严格对齐 trigger.dev)
@@ -78,18 +85,60 @@ type DeliverEventResponse struct {
 }
 
 // RunJobBody executeJobRequest 方法的请求体
+// Tasks 为此前执行中已完成的任务，端点据此跳过已执行的步骤
 type RunJobBody struct {
 	ID      string                 `json:"id"`
 	Payload map[string]interface{} `json:"payload"`
 	Context map[string]interface{} `json:"context"`
 	JobRun  map[string]interface{} `json:"jobRun"`
+	Tasks   []CachedTask           `json:"tasks,omitempty"`
 }
 
+// RunJobResponse 状态值 (对齐 trigger.dev RunJobResponseSchema)
+const (
+	RunJobStatusSuccess        = "SUCCESS"
+	RunJobStatusError          = "ERROR"
+	RunJobStatusResumeWithTask = "RESUME_WITH_TASK"
+	RunJobStatusYieldExecution = "YIELD_EXECUTION"
+)
+
 // RunJobResponse executeJobRequest 方法的响应类型
+// SUCCESS 携带 Output；ERROR 携带 Error；RESUME_WITH_TASK 携带等待中的 Task；
+// YIELD_EXECUTION 表示端点让出执行，需携带已完成任务立即重新执行
 type RunJobResponse struct {
-	ID      string `json:"id"`
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
+	ID      string          `json:"id"`
+	Status  string          `json:"status"`
+	Message string          `json:"message,omitempty"`
+	Output  interface{}     `json:"output,omitempty"`
+	Error   *ErrorWithStack `json:"error,omitempty"`
+	Task    *ServerTask     `json:"task,omitempty"`
+	Tasks   []ServerTask    `json:"tasks,omitempty"`
+}
+
+// ServerTask 端点报告的任务 (对齐 trigger.dev ServerTaskSchema)
+type ServerTask struct {
+	ID             string          `json:"id,omitempty"`
+	IdempotencyKey string          `json:"idempotencyKey"`
+	DisplayKey     string          `json:"displayKey,omitempty"`
+	Name           string          `json:"name"`
+	Icon           string          `json:"icon,omitempty"`
+	Status         string          `json:"status"`
+	Noop           bool            `json:"noop"`
+	DelayUntil     *time.Time      `json:"delayUntil,omitempty"`
+	Params         interface{}     `json:"params,omitempty"`
+	Output         interface{}     `json:"output,omitempty"`
+	Error          *ErrorWithStack `json:"error,omitempty"`
+	ParentID       string          `json:"parentId,omitempty"`
+}
+
+// CachedTask 回传给端点的已完成任务 (对齐 trigger.dev CachedTaskSchema)
+type CachedTask struct {
+	ID             string      `json:"id"`
+	IdempotencyKey string      `json:"idempotencyKey"`
+	Status         string      `json:"status"`
+	Noop           bool        `json:"noop"`
+	Output         interface{} `json:"output,omitempty"`
+	ParentID       string      `json:"parentId,omitempty"`
 }
 
 // PreprocessRunBody preprocessRunRequest 方法的请求体
@@ -100,10 +149,20 @@ type PreprocessRunBody struct {
 }
 
 // PreprocessRunResponse preprocessRunRequest 方法的响应类型
+// Abort/Properties 对齐 trigger.dev PreprocessRunResponseSchema
 type PreprocessRunResponse struct {
-	Success bool                   `json:"success"`
-	Data    map[string]interface{} `json:"data,omitempty"`
-	Error   string                 `json:"error,omitempty"`
+	Success    bool                   `json:"success"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Abort      bool                   `json:"abort"`
+	Properties []DisplayProperty      `json:"properties,omitempty"`
+}
+
+// DisplayProperty 运行展示属性 (对齐 trigger.dev DisplayPropertySchema)
+type DisplayProperty struct {
+	Label string `json:"label"`
+	Text  string `json:"text"`
+	URL   string `json:"url,omitempty"`
 }
 
 // RegisterTriggerBody initializeTrigger 方法的响应类型
@@ -195,7 +195,16 @@ func SetupEndpointQueueTestHarness(t *testing.T) *EndpointQueueTestHarness {
 	config.EventsMaxWorkers = 1
 	config.MaintenanceMaxWorkers = 1
 
-	manager, err := workerqueue.NewManagerWithIndexer(config, testDB.Pool, logger, testEmailSender, mockIndexer)
+	manager, err := workerqueue.NewManagerWithOptions(config, testDB.Pool, logger, workerqueue.ManagerOptions{
+		EmailSender: testEmailSender,
+		Indexer:     mockIndexer,
+		RegisterTasks: func(registry *workerqueue.TaskRegistry) error {
+			if err := workerqueue.RegisterTask(registry, &RegisterJobMockWorker{logger: logger}, workerqueue.JobOptions{}); err != nil {
+				return err
+			}
+			return workerqueue.RegisterTask(registry, &RegisterSourceMockWorker{logger: logger}, workerqueue.JobOptions{})
+		},
+	})
 	require.NoError(t, err)
 
 	// Ensure River tables are created
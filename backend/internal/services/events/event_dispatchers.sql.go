@@ -109,13 +109,22 @@ func (q *Queries) DeleteEventDispatcher(ctx context.Context, id pgtype.UUID) err
 }
 
 const findEventDispatchers = `-- name: FindEventDispatchers :many
-SELECT id, event, source, payload_filter, context_filter, manual, dispatchable_id, dispatchable, enabled, environment_id, created_at, updated_at FROM event_dispatchers
-WHERE environment_id = $1
-    AND event = $2
-    AND source = $3
-    AND ($4::BOOLEAN = false OR enabled = true)
-    AND ($5::BOOLEAN IS NULL OR manual = $5)
-ORDER BY created_at ASC
+SELECT event_dispatchers.id, event_dispatchers.event, event_dispatchers.source, event_dispatchers.payload_filter, event_dispatchers.context_filter, event_dispatchers.manual, event_dispatchers.dispatchable_id, event_dispatchers.dispatchable, event_dispatchers.enabled, event_dispatchers.environment_id, event_dispatchers.created_at, event_dispatchers.updated_at FROM event_dispatchers
+WHERE event_dispatchers.environment_id = $1
+    AND event_dispatchers.event = $2
+    AND event_dispatchers.source = $3
+    AND ($4::BOOLEAN = false OR event_dispatchers.enabled = true)
+    AND ($5::BOOLEAN IS NULL OR event_dispatchers.manual = $5)
+    AND NOT EXISTS (
+        SELECT 1 FROM jobs j
+        WHERE j.status = 'ARCHIVED'
+            AND j.id IN (
+                SELECT v.job_id FROM job_versions v WHERE v.id::TEXT = event_dispatchers.dispatchable_id
+                UNION ALL
+                SELECT a.job_id FROM job_aliases a WHERE a.id::TEXT = event_dispatchers.dispatchable_id
+            )
+    )
+ORDER BY event_dispatchers.created_at ASC
 `
 
 type FindEventDispatchersParams struct {
@@ -127,6 +136,7 @@ type FindEventDispatchersParams struct {
 }
 
 // 查找匹配的事件调度器，对齐 trigger.dev DeliverEventService 逻辑
+// 已归档作业的版本和别名调度器不再匹配事件
 func (q *Queries) FindEventDispatchers(ctx context.Context, arg FindEventDispatchersParams) ([]EventDispatchers, error) {
 	rows, err := q.db.Query(ctx, findEventDispatchers,
 		arg.EnvironmentID,
@@ -261,6 +261,159 @@ func (q *Queries) ListEventRecords(ctx context.Context, arg ListEventRecordsPara
 	return items, nil
 }
 
+const listEventRecordsPage = `-- name: ListEventRecordsPage :many
+SELECT id, event_id, name, source, payload, context, timestamp, environment_id, organization_id, project_id, is_test, created_at, updated_at, external_account_id, deliver_at, delivered_at FROM event_records
+WHERE ($1::UUID IS NULL OR environment_id = $1::UUID)
+    AND ($2::UUID IS NULL OR project_id = $2::UUID)
+    AND ($3::TEXT IS NULL OR name = $3::TEXT)
+    AND ($4::TEXT IS NULL OR source = $4::TEXT)
+    AND ($5::BOOLEAN IS NULL OR is_test = $5::BOOLEAN)
+    AND ($6::TIMESTAMPTZ IS NULL OR created_at >= $6::TIMESTAMPTZ)
+    AND ($7::TIMESTAMPTZ IS NULL OR created_at < $7::TIMESTAMPTZ)
+    AND ($8::TIMESTAMPTZ IS NULL
+        OR (created_at, id) < ($8::TIMESTAMPTZ, $9::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $10
+`
+
+type ListEventRecordsPageParams struct {
+	EnvironmentID   pgtype.UUID        `json:"environment_id"`
+	ProjectID       pgtype.UUID        `json:"project_id"`
+	Name            pgtype.Text        `json:"name"`
+	Source          pgtype.Text        `json:"source"`
+	IsTest          pgtype.Bool        `json:"is_test"`
+	CreatedAfter    pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore   pgtype.Timestamptz `json:"created_before"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+// 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+func (q *Queries) ListEventRecordsPage(ctx context.Context, arg ListEventRecordsPageParams) ([]EventRecords, error) {
+	rows, err := q.db.Query(ctx, listEventRecordsPage,
+		arg.EnvironmentID,
+		arg.ProjectID,
+		arg.Name,
+		arg.Source,
+		arg.IsTest,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventRecords
+	for rows.Next() {
+		var i EventRecords
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.Name,
+			&i.Source,
+			&i.Payload,
+			&i.Context,
+			&i.Timestamp,
+			&i.EnvironmentID,
+			&i.OrganizationID,
+			&i.ProjectID,
+			&i.IsTest,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExternalAccountID,
+			&i.DeliverAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventRecordsPageReverse = `-- name: ListEventRecordsPageReverse :many
+SELECT id, event_id, name, source, payload, context, timestamp, environment_id, organization_id, project_id, is_test, created_at, updated_at, external_account_id, deliver_at, delivered_at FROM event_records
+WHERE ($1::UUID IS NULL OR environment_id = $1::UUID)
+    AND ($2::UUID IS NULL OR project_id = $2::UUID)
+    AND ($3::TEXT IS NULL OR name = $3::TEXT)
+    AND ($4::TEXT IS NULL OR source = $4::TEXT)
+    AND ($5::BOOLEAN IS NULL OR is_test = $5::BOOLEAN)
+    AND ($6::TIMESTAMPTZ IS NULL OR created_at >= $6::TIMESTAMPTZ)
+    AND ($7::TIMESTAMPTZ IS NULL OR created_at < $7::TIMESTAMPTZ)
+    AND (created_at, id) > ($8::TIMESTAMPTZ, $9::UUID)
+ORDER BY created_at ASC, id ASC
+LIMIT $10
+`
+
+type ListEventRecordsPageReverseParams struct {
+	EnvironmentID   pgtype.UUID        `json:"environment_id"`
+	ProjectID       pgtype.UUID        `json:"project_id"`
+	Name            pgtype.Text        `json:"name"`
+	Source          pgtype.Text        `json:"source"`
+	IsTest          pgtype.Bool        `json:"is_test"`
+	CreatedAfter    pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore   pgtype.Timestamptz `json:"created_before"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+// 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+func (q *Queries) ListEventRecordsPageReverse(ctx context.Context, arg ListEventRecordsPageReverseParams) ([]EventRecords, error) {
+	rows, err := q.db.Query(ctx, listEventRecordsPageReverse,
+		arg.EnvironmentID,
+		arg.ProjectID,
+		arg.Name,
+		arg.Source,
+		arg.IsTest,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventRecords
+	for rows.Next() {
+		var i EventRecords
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.Name,
+			&i.Source,
+			&i.Payload,
+			&i.Context,
+			&i.Timestamp,
+			&i.EnvironmentID,
+			&i.OrganizationID,
+			&i.ProjectID,
+			&i.IsTest,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExternalAccountID,
+			&i.DeliverAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPendingEventRecords = `-- name: ListPendingEventRecords :many
 SELECT id, event_id, name, source, payload, context, timestamp, environment_id, organization_id, project_id, is_test, created_at, updated_at, external_account_id, deliver_at, delivered_at FROM event_records
 WHERE delivered_at IS NULL 
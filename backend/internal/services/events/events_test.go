@@ -75,3 +75,20 @@ func TestUUIDHelperFunctions(t *testing.T) {
 	_, err = stringToPgUUID(invalidUUID)
 	assert.Error(t, err, "Invalid UUID string should return error")
 }
+
+func TestDedupeDispatchers(t *testing.T) {
+	dispatcher := func(dispatchable string) EventDispatchers {
+		return EventDispatchers{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Dispatchable: []byte(dispatchable)}
+	}
+
+	version := dispatcher(`{"type":"JOB_VERSION","id":"v2"}`)
+	oldVersion := dispatcher(`{"type":"JOB_VERSION","id":"v1"}`)
+	latest := dispatcher(`{"type":"JOB_ALIAS","id":"a1","name":"latest","versionId":"v2"}`)
+	stable := dispatcher(`{"type":"JOB_ALIAS","id":"a2","name":"stable","versionId":"v2"}`)
+	trigger := dispatcher(`{"type":"DYNAMIC_TRIGGER","id":"t1"}`)
+
+	deduped := dedupeDispatchers([]EventDispatchers{version, oldVersion, latest, stable, trigger})
+
+	// 同一版本只保留一个调度器，别名调度器优先
+	assert.Equal(t, []EventDispatchers{oldVersion, latest, trigger}, deduped)
+}
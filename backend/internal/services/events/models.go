@@ -5,8 +5,6 @@
 package events
 
 import (
-	"time"
-
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -57,67 +55,3 @@ type EventRecords struct {
 	// 实际投递时间，NULL表示未投递
 	DeliveredAt pgtype.Timestamptz `json:"delivered_at"`
 }
-
-// API 请求和响应类型定义，对齐 trigger.dev
-
-// SendEventRequest 发送事件请求，对齐 trigger.dev RawEvent
-type SendEventRequest struct {
-	ID        string                 `json:"id" validate:"required"`
-	Name      string                 `json:"name" validate:"required"`
-	Source    string                 `json:"source,omitempty"`
-	Payload   map[string]interface{} `json:"payload,omitempty"`
-	Context   map[string]interface{} `json:"context,omitempty"`
-	Timestamp *time.Time             `json:"timestamp,omitempty"`
-}
-
-// SendEventOptions 发送事件选项，对齐 trigger.dev SendEventOptions
-type SendEventOptions struct {
-	AccountID    *string    `json:"accountId,omitempty"`
-	DeliverAt    *time.Time `json:"deliverAt,omitempty"`
-	DeliverAfter *int       `json:"deliverAfter,omitempty"` // 秒数
-	Test         *bool      `json:"test,omitempty"`         // 显式指定是否为测试事件
-}
-
-// EventRecordResponse 事件记录响应
-type EventRecordResponse struct {
-	ID        string                 `json:"id"`
-	EventID   string                 `json:"eventId"`
-	Name      string                 `json:"name"`
-	Source    string                 `json:"source"`
-	Payload   map[string]interface{} `json:"payload"`
-	Context   map[string]interface{} `json:"context"`
-	Timestamp time.Time              `json:"timestamp"`
-	DeliverAt *time.Time             `json:"deliverAt,omitempty"`
-	IsTest    bool                   `json:"isTest"`
-	CreatedAt time.Time              `json:"createdAt"`
-}
-
-// EventFilter 事件过滤器，对齐 trigger.dev EventFilter
-type EventFilter struct {
-	Payload map[string]interface{} `json:"payload"`
-	Context map[string]interface{} `json:"context"`
-}
-
-// ListEventRecordsResponse 事件记录列表响应
-type ListEventRecordsResponse struct {
-	Records []EventRecordResponse `json:"records"`
-	Total   int64                 `json:"total"`
-}
-
-// EventDispatcherResponse 事件调度器响应
-type EventDispatcherResponse struct {
-	ID            string                 `json:"id"`
-	Event         string                 `json:"event"`
-	Source        string                 `json:"source"`
-	PayloadFilter map[string]interface{} `json:"payloadFilter"`
-	ContextFilter map[string]interface{} `json:"contextFilter"`
-	Manual        bool                   `json:"manual"`
-	Enabled       bool                   `json:"enabled"`
-	CreatedAt     string                 `json:"createdAt"`
-}
-
-// ListEventDispatchersResponse 事件调度器列表响应
-type ListEventDispatchersResponse struct {
-	Dispatchers []EventDispatcherResponse `json:"dispatchers"`
-	Total       int64                     `json:"total"`
-}
@@ -22,12 +22,17 @@ type Querier interface {
 	DeleteEventDispatcher(ctx context.Context, id pgtype.UUID) error
 	DeleteEventRecord(ctx context.Context, id pgtype.UUID) error
 	// 查找匹配的事件调度器，对齐 trigger.dev DeliverEventService 逻辑
+	// 已归档作业的版本和别名调度器不再匹配事件
 	FindEventDispatchers(ctx context.Context, arg FindEventDispatchersParams) ([]EventDispatchers, error)
 	GetEventDispatcherByID(ctx context.Context, id pgtype.UUID) (EventDispatchers, error)
 	GetEventRecordByEventID(ctx context.Context, arg GetEventRecordByEventIDParams) (EventRecords, error)
 	GetEventRecordByID(ctx context.Context, id pgtype.UUID) (EventRecords, error)
 	ListEventDispatchers(ctx context.Context, arg ListEventDispatchersParams) ([]EventDispatchers, error)
 	ListEventRecords(ctx context.Context, arg ListEventRecordsParams) ([]EventRecords, error)
+	// 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+	ListEventRecordsPage(ctx context.Context, arg ListEventRecordsPageParams) ([]EventRecords, error)
+	// 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+	ListEventRecordsPageReverse(ctx context.Context, arg ListEventRecordsPageReverseParams) ([]EventRecords, error)
 	// 获取待投递的事件记录，用于调度
 	ListPendingEventRecords(ctx context.Context, arg ListPendingEventRecordsParams) ([]EventRecords, error)
 	UpdateEventDispatcherEnabled(ctx context.Context, arg UpdateEventDispatcherEnabledParams) error
@@ -22,13 +22,23 @@ WHERE id = $1;
 
 -- name: FindEventDispatchers :many
 -- 查找匹配的事件调度器，对齐 trigger.dev DeliverEventService 逻辑
-SELECT * FROM event_dispatchers
-WHERE environment_id = $1
-    AND event = $2
-    AND source = $3
-    AND ($4::BOOLEAN = false OR enabled = true)
-    AND ($5::BOOLEAN IS NULL OR manual = $5)
-ORDER BY created_at ASC;
+-- 已归档作业的版本和别名调度器不再匹配事件
+SELECT event_dispatchers.* FROM event_dispatchers
+WHERE event_dispatchers.environment_id = $1
+    AND event_dispatchers.event = $2
+    AND event_dispatchers.source = $3
+    AND ($4::BOOLEAN = false OR event_dispatchers.enabled = true)
+    AND ($5::BOOLEAN IS NULL OR event_dispatchers.manual = $5)
+    AND NOT EXISTS (
+        SELECT 1 FROM jobs j
+        WHERE j.status = 'ARCHIVED'
+            AND j.id IN (
+                SELECT v.job_id FROM job_versions v WHERE v.id::TEXT = event_dispatchers.dispatchable_id
+                UNION ALL
+                SELECT a.job_id FROM job_aliases a WHERE a.id::TEXT = event_dispatchers.dispatchable_id
+            )
+    )
+ORDER BY event_dispatchers.created_at ASC;
 
 -- name: ListEventDispatchers :many
 SELECT * FROM event_dispatchers
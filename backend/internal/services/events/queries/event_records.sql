@@ -64,4 +64,32 @@ ORDER BY deliver_at ASC
 LIMIT $2;
 
 -- name: DeleteEventRecord :exec
-DELETE FROM event_records WHERE id = $1;
\ No newline at end of file
+DELETE FROM event_records WHERE id = $1;
+-- name: ListEventRecordsPage :many
+-- 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+SELECT * FROM event_records
+WHERE (sqlc.narg(environment_id)::UUID IS NULL OR environment_id = sqlc.narg(environment_id)::UUID)
+    AND (sqlc.narg(project_id)::UUID IS NULL OR project_id = sqlc.narg(project_id)::UUID)
+    AND (sqlc.narg(name)::TEXT IS NULL OR name = sqlc.narg(name)::TEXT)
+    AND (sqlc.narg(source)::TEXT IS NULL OR source = sqlc.narg(source)::TEXT)
+    AND (sqlc.narg(is_test)::BOOLEAN IS NULL OR is_test = sqlc.narg(is_test)::BOOLEAN)
+    AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+    AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+    AND (sqlc.narg(cursor_created_at)::TIMESTAMPTZ IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at)::TIMESTAMPTZ, sqlc.narg(cursor_id)::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListEventRecordsPageReverse :many
+-- 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+SELECT * FROM event_records
+WHERE (sqlc.narg(environment_id)::UUID IS NULL OR environment_id = sqlc.narg(environment_id)::UUID)
+    AND (sqlc.narg(project_id)::UUID IS NULL OR project_id = sqlc.narg(project_id)::UUID)
+    AND (sqlc.narg(name)::TEXT IS NULL OR name = sqlc.narg(name)::TEXT)
+    AND (sqlc.narg(source)::TEXT IS NULL OR source = sqlc.narg(source)::TEXT)
+    AND (sqlc.narg(is_test)::BOOLEAN IS NULL OR is_test = sqlc.narg(is_test)::BOOLEAN)
+    AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+    AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+    AND (created_at, id) > (sqlc.arg(cursor_created_at)::TIMESTAMPTZ, sqlc.arg(cursor_id)::UUID)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(page_size);
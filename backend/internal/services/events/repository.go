@@ -17,8 +17,11 @@ type Repository interface {
 	GetEventRecordByEventID(ctx context.Context, params GetEventRecordByEventIDParams) (EventRecords, error)
 	UpdateEventRecordDeliveredAt(ctx context.Context, params UpdateEventRecordDeliveredAtParams) error
 	ListEventRecords(ctx context.Context, params ListEventRecordsParams) ([]EventRecords, error)
+	ListEventRecordsPage(ctx context.Context, params ListEventRecordsPageParams) ([]EventRecords, error)
+	ListEventRecordsPageReverse(ctx context.Context, params ListEventRecordsPageReverseParams) ([]EventRecords, error)
 	CountEventRecords(ctx context.Context, params CountEventRecordsParams) (int64, error)
 	ListPendingEventRecords(ctx context.Context, params ListPendingEventRecordsParams) ([]EventRecords, error)
+	DeleteEventRecord(ctx context.Context, id pgtype.UUID) error
 
 	// EventDispatcher 操作
 	GetEventDispatcherByID(ctx context.Context, id pgtype.UUID) (EventDispatchers, error)
@@ -62,6 +65,10 @@ func (r *repository) GetEventRecordByEventID(ctx context.Context, params GetEven
 	return r.queries.GetEventRecordByEventID(ctx, params)
 }
 
+func (r *repository) DeleteEventRecord(ctx context.Context, id pgtype.UUID) error {
+	return r.queries.DeleteEventRecord(ctx, id)
+}
+
 func (r *repository) UpdateEventRecordDeliveredAt(ctx context.Context, params UpdateEventRecordDeliveredAtParams) error {
 	return r.queries.UpdateEventRecordDeliveredAt(ctx, params)
 }
@@ -70,6 +77,14 @@ func (r *repository) ListEventRecords(ctx context.Context, params ListEventRecor
 	return r.queries.ListEventRecords(ctx, params)
 }
 
+func (r *repository) ListEventRecordsPage(ctx context.Context, params ListEventRecordsPageParams) ([]EventRecords, error) {
+	return r.queries.ListEventRecordsPage(ctx, params)
+}
+
+func (r *repository) ListEventRecordsPageReverse(ctx context.Context, params ListEventRecordsPageReverseParams) ([]EventRecords, error) {
+	return r.queries.ListEventRecordsPageReverse(ctx, params)
+}
+
 func (r *repository) CountEventRecords(ctx context.Context, params CountEventRecordsParams) (int64, error) {
 	return r.queries.CountEventRecords(ctx, params)
 }
@@ -11,11 +11,16 @@ import (
 
 	"kongflow/backend/internal/services/apiauth"
 	"kongflow/backend/internal/services/events/queue"
+	"kongflow/backend/internal/services/pagination"
+	"kongflow/backend/internal/services/runs"
+	"kongflow/backend/internal/services/workerqueue"
 	"kongflow/backend/internal/shared"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // determineIfTestEvent 根据环境和选项确定事件是否为测试事件
@@ -60,23 +65,49 @@ type Service interface {
 
 	// 事件查询
 	GetEventRecord(ctx context.Context, id string) (*EventRecordResponse, error)
-	ListEventRecords(ctx context.Context, params ListEventRecordsParams) (*ListEventRecordsResponse, error)
+	ListEventRecords(ctx context.Context, req ListEventRecordsRequest) (*ListEventRecordsResponse, error)
+
+	// 删除事件记录，用于撤销未能创建运行的测试事件
+	DeleteEventRecord(ctx context.Context, id string) error
 
 	// 调度器管理
 	GetEventDispatcher(ctx context.Context, id string) (*EventDispatcherResponse, error)
 	ListEventDispatchers(ctx context.Context, params ListEventDispatchersParams) (*ListEventDispatchersResponse, error)
 }
 
+// 确保 Service 可直接作为 workerqueue 的事件处理器
+var _ workerqueue.EventProcessor = (Service)(nil)
+
+// Metrics 事件摄取与分发指标，由 metrics 包实现；为 nil 时不记录
+type Metrics interface {
+	// EventIngested 记录一次事件摄取结果 ("success" 或 "error")
+	EventIngested(outcome string)
+
+	// EventDelivered 记录事件从计划投递时间到完成分发的延迟
+	EventDelivered(latency time.Duration)
+
+	// DispatchersMatched 记录一次分发匹配到的调度器数量
+	DispatchersMatched(count int)
+}
+
 // service 实现
 type service struct {
 	repo          Repository
 	sharedQueries *shared.Queries
 	queueSvc      queue.QueueService
+	runsSvc       runs.Service
+	metrics       Metrics
 	logger        *slog.Logger
 }
 
 // NewService 创建服务实例
-func NewService(repo Repository, sharedQueries *shared.Queries, queueSvc queue.QueueService, logger *slog.Logger) Service {
+// runsSvc 用于为匹配的作业版本创建运行，为 nil 时跳过运行创建
+func NewService(repo Repository, sharedQueries *shared.Queries, queueSvc queue.QueueService, runsSvc runs.Service, logger *slog.Logger) Service {
+	return NewServiceWithMetrics(repo, sharedQueries, queueSvc, runsSvc, nil, logger)
+}
+
+// NewServiceWithMetrics 创建记录摄取与分发指标的服务实例
+func NewServiceWithMetrics(repo Repository, sharedQueries *shared.Queries, queueSvc queue.QueueService, runsSvc runs.Service, metrics Metrics, logger *slog.Logger) Service {
 	if logger == nil {
 		logger = slog.Default()
 	}
@@ -84,6 +115,8 @@ func NewService(repo Repository, sharedQueries *shared.Queries, queueSvc queue.Q
 		repo:          repo,
 		sharedQueries: sharedQueries,
 		queueSvc:      queueSvc,
+		runsSvc:       runsSvc,
+		metrics:       metrics,
 		logger:        logger,
 	}
 }
@@ -91,6 +124,18 @@ func NewService(repo Repository, sharedQueries *shared.Queries, queueSvc queue.Q
 // IngestSendEvent 事件摄取，对齐 trigger.dev IngestSendEvent.call
 func (s *service) IngestSendEvent(ctx context.Context, env *apiauth.AuthenticatedEnvironment,
 	event *SendEventRequest, opts *SendEventOptions) (*EventRecordResponse, error) {
+	ctx, span := startSpan(ctx, "events.IngestSendEvent",
+		attribute.String("event.name", event.Name),
+		attribute.String("event.source", event.Source),
+	)
+	response, err := s.ingestSendEvent(ctx, env, event, opts)
+	endSpan(span, err)
+	return response, err
+}
+
+// ingestSendEvent 创建事件记录并在同一事务中入队 deliverEvent 作业
+func (s *service) ingestSendEvent(ctx context.Context, env *apiauth.AuthenticatedEnvironment,
+	event *SendEventRequest, opts *SendEventOptions) (*EventRecordResponse, error) {
 
 	logger := s.logger.With("operation", "ingest_send_event", "event_name", event.Name)
 	logger.Info("Ingesting event", "event_name", event.Name, "environment_id", env.Environment.ID)
@@ -187,6 +232,11 @@ func (s *service) IngestSendEvent(ctx context.Context, env *apiauth.Authenticate
 
 		eventRecord = record
 
+		if opts != nil && opts.SkipDelivery {
+			logger.Debug("Skipping event delivery", "event_id", eventID)
+			return nil
+		}
+
 		// 触发事件分发作业，对齐 trigger.dev
 		// workerQueue.enqueue("deliverEvent", { id: eventLog.id }, { runAt: eventLog.deliverAt, tx })
 		payloadStr, err := json.Marshal(map[string]interface{}{
@@ -212,6 +262,14 @@ func (s *service) IngestSendEvent(ctx context.Context, env *apiauth.Authenticate
 		return nil
 	})
 
+	if s.metrics != nil {
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+		}
+		s.metrics.EventIngested(outcome)
+	}
+
 	if err != nil {
 		logger.Error("Failed to ingest event", "error", err)
 		return nil, err
@@ -224,6 +282,14 @@ func (s *service) IngestSendEvent(ctx context.Context, env *apiauth.Authenticate
 
 // DeliverEvent 事件分发，对齐 trigger.dev DeliverEventService.call
 func (s *service) DeliverEvent(ctx context.Context, eventID string) error {
+	ctx, span := startSpan(ctx, "events.DeliverEvent", attribute.String("event.id", eventID))
+	err := s.deliverEvent(ctx, eventID)
+	endSpan(span, err)
+	return err
+}
+
+// deliverEvent 匹配事件调度器并为每个匹配入队 invokeDispatcher 作业
+func (s *service) deliverEvent(ctx context.Context, eventID string) error {
 	logger := s.logger.With("operation", "deliver_event", "event_id", eventID)
 	logger.Info("Delivering event")
 
@@ -234,14 +300,19 @@ func (s *service) DeliverEvent(ctx context.Context, eventID string) error {
 		return fmt.Errorf("invalid event ID format: %w", err)
 	}
 
+	// 分发成功后记录延迟和匹配数
+	var deliverAt time.Time
+	var matched int
+
 	// 在事务中处理事件分发
-	return s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+	err = s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
 		// 获取事件记录
 		eventRecord, err := txRepo.GetEventRecordByID(ctx, pgUUID)
 		if err != nil {
 			logger.Error("Failed to get event record", "error", err)
 			return fmt.Errorf("failed to get event record: %w", err)
 		}
+		deliverAt = eventRecord.DeliverAt.Time
 
 		// 查找可能的事件调度器
 		findParams := FindEventDispatchersParams{
@@ -268,7 +339,11 @@ func (s *service) DeliverEvent(ctx context.Context, eventID string) error {
 			}
 		}
 
-		if len(matchingDispatchers) == 0 {
+		// 别名调度器与作业版本调度器可能指向同一版本，只为该版本创建一个运行
+		matchingDispatchers = dedupeDispatchers(matchingDispatchers)
+
+		matched = len(matchingDispatchers)
+		if matched == 0 {
 			logger.Debug("No matching event dispatchers")
 			return nil
 		}
@@ -323,10 +398,33 @@ func (s *service) DeliverEvent(ctx context.Context, eventID string) error {
 		logger.Info("Event delivered successfully", "matching_dispatchers", len(matchingDispatchers))
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("event.matched_dispatchers", matched))
+	if s.metrics != nil {
+		s.metrics.DispatchersMatched(matched)
+		if !deliverAt.IsZero() {
+			s.metrics.EventDelivered(time.Since(deliverAt))
+		}
+	}
+	return nil
 }
 
 // InvokeDispatcher 调度器调用，对齐 trigger.dev InvokeDispatcherService.call
 func (s *service) InvokeDispatcher(ctx context.Context, dispatcherID string, eventRecordID string) error {
+	ctx, span := startSpan(ctx, "events.InvokeDispatcher",
+		attribute.String("dispatcher.id", dispatcherID),
+		attribute.String("event.id", eventRecordID),
+	)
+	err := s.invokeDispatcher(ctx, dispatcherID, eventRecordID)
+	endSpan(span, err)
+	return err
+}
+
+// invokeDispatcher 按调度器类型调用作业版本或动态触发器
+func (s *service) invokeDispatcher(ctx context.Context, dispatcherID string, eventRecordID string) error {
 	logger := s.logger.With("operation", "invoke_dispatcher", "dispatcher_id", dispatcherID, "event_record_id", eventRecordID)
 	logger.Info("Invoking dispatcher")
 
@@ -389,6 +487,15 @@ func (s *service) InvokeDispatcher(ctx context.Context, dispatcherID string, eve
 	case "JOB_VERSION":
 		return s.invokeJobVersion(ctx, dispatchableID, eventRecord, logger)
 
+	case "JOB_ALIAS":
+		// 别名调度器在别名移动时同步 versionId，直接投递到别名当前指向的版本
+		versionID, ok := dispatchable["versionId"].(string)
+		if !ok {
+			logger.Error("Missing alias version ID", "alias_id", dispatchableID)
+			return fmt.Errorf("missing version ID for job alias %s", dispatchableID)
+		}
+		return s.invokeJobVersion(ctx, versionID, eventRecord, logger.With("job_alias_id", dispatchableID))
+
 	case "DYNAMIC_TRIGGER":
 		return s.invokeDynamicTrigger(ctx, dispatchableID, eventRecord, logger)
 
@@ -402,29 +509,28 @@ func (s *service) InvokeDispatcher(ctx context.Context, dispatcherID string, eve
 func (s *service) invokeJobVersion(ctx context.Context, jobVersionID string, eventRecord EventRecords, logger *slog.Logger) error {
 	logger.Info("Invoking job version", "job_version_id", jobVersionID)
 
-	// TODO: 集成Jobs服务
-	// 1. 通过jobVersionID查找JobVersion
-	// 2. 调用CreateRunService创建作业运行
-	//
-	// 示例代码结构:
-	// jobVersion, err := jobsService.GetJobVersionByID(ctx, jobVersionID)
-	// if err != nil {
-	//     return fmt.Errorf("failed to get job version: %w", err)
-	// }
-	//
-	// createRunParams := CreateRunParams{
-	//     EventID: eventRecord.ID.String(),
-	//     Job: jobVersion.Job,
-	//     Version: jobVersion,
-	//     Environment: environment,
-	// }
-	//
-	// run, err := runsService.CreateRun(ctx, createRunParams)
-	// if err != nil {
-	//     return fmt.Errorf("failed to create run: %w", err)
-	// }
+	if s.runsSvc == nil {
+		logger.Warn("Runs service not configured, skipping run creation", "job_version_id", jobVersionID)
+		return nil
+	}
+
+	req := &runs.CreateRunRequest{
+		EventRecordID: uuid.UUID(eventRecord.ID.Bytes).String(),
+		VersionID:     jobVersionID,
+		IsTest:        eventRecord.IsTest,
+	}
+	if eventRecord.ExternalAccountID.Valid {
+		accountID := uuid.UUID(eventRecord.ExternalAccountID.Bytes).String()
+		req.ExternalAccountID = &accountID
+	}
+
+	run, err := s.runsSvc.CreateRun(ctx, req)
+	if err != nil {
+		logger.Error("Failed to create run", "job_version_id", jobVersionID, "error", err)
+		return fmt.Errorf("failed to create run: %w", err)
+	}
 
-	logger.Info("Job version invocation completed (placeholder)", "job_version_id", jobVersionID)
+	logger.Info("Job version invoked", "job_version_id", jobVersionID, "run_id", run.ID)
 	return nil
 }
 
@@ -488,28 +594,79 @@ func (s *service) GetEventRecord(ctx context.Context, id string) (*EventRecordRe
 	return convertEventRecordToResponse(eventRecord), nil
 }
 
-// ListEventRecords 列出事件记录
-func (s *service) ListEventRecords(ctx context.Context, params ListEventRecordsParams) (*ListEventRecordsResponse, error) {
+// DeleteEventRecord 删除事件记录，记录不存在时不报错
+func (s *service) DeleteEventRecord(ctx context.Context, id string) error {
+	pgUUID, err := stringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteEventRecord(ctx, pgUUID); err != nil {
+		s.logger.Error("Failed to delete event record", "event_id", id, "error", err)
+		return fmt.Errorf("failed to delete event record: %w", err)
+	}
+	return nil
+}
+
+// ListEventRecords 按游标分页列出事件记录
+func (s *service) ListEventRecords(ctx context.Context, req ListEventRecordsRequest) (*ListEventRecordsResponse, error) {
 	logger := s.logger.With("operation", "list_event_records")
 
-	events, err := s.repo.ListEventRecords(ctx, params)
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := pagination.NormalizeLimit(req.Limit)
+
+	params := ListEventRecordsPageParams{
+		Name:          optionalText(req.Name),
+		Source:        optionalText(req.Source),
+		CreatedAfter:  optionalTimestamptz(req.CreatedAfter),
+		CreatedBefore: optionalTimestamptz(req.CreatedBefore),
+		PageSize:      limit + 1,
+	}
+	if req.IsTest != nil {
+		params.IsTest = pgtype.Bool{Bool: *req.IsTest, Valid: true}
+	}
+	if req.EnvironmentID != "" {
+		if params.EnvironmentID, err = stringToPgUUID(req.EnvironmentID); err != nil {
+			return nil, fmt.Errorf("invalid environment ID: %w", err)
+		}
+	}
+	if req.ProjectID != "" {
+		if params.ProjectID, err = stringToPgUUID(req.ProjectID); err != nil {
+			return nil, fmt.Errorf("invalid project ID: %w", err)
+		}
+	}
+	if cursor != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuidToPgUUID(cursor.ID)
+	}
+
+	var events []EventRecords
+	if cursor.Backward() {
+		events, err = s.repo.ListEventRecordsPageReverse(ctx, ListEventRecordsPageReverseParams(params))
+	} else {
+		events, err = s.repo.ListEventRecordsPage(ctx, params)
+	}
 	if err != nil {
 		logger.Error("Failed to list event records", "error", err)
 		return nil, err
 	}
 
-	// 暂时跳过总数计算，等待参数类型匹配修复
-	// total, err := s.repo.CountEventRecords(ctx, params)
-	total := int64(len(events))
+	events, next, prev := pagination.Page(events, limit, cursor, func(e EventRecords) (time.Time, uuid.UUID) {
+		return e.CreatedAt.Time, e.ID.Bytes
+	})
 
-	var eventResponses []EventRecordResponse
+	eventResponses := make([]EventRecordResponse, 0, len(events))
 	for _, event := range events {
 		eventResponses = append(eventResponses, *convertEventRecordToResponse(event))
 	}
 
 	return &ListEventRecordsResponse{
-		Records: eventResponses,
-		Total:   total,
+		Records:    eventResponses,
+		NextCursor: next,
+		PrevCursor: prev,
 	}, nil
 }
 
@@ -578,6 +735,18 @@ func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
 	}
 }
 
+// optionalText 空字符串视为未设置的过滤条件
+func optionalText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func optionalTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
 // calculateDeliverAt 计算延迟投递时间，对齐 trigger.dev calculateDeliverAt
 func (s *service) calculateDeliverAt(opts *SendEventOptions) *time.Time {
 	if opts == nil {
@@ -598,6 +767,56 @@ func (s *service) calculateDeliverAt(opts *SendEventOptions) *time.Time {
 	return nil
 }
 
+// dedupeDispatchers 去除投递到同一作业版本的重复调度器，别名调度器优先保留
+func dedupeDispatchers(dispatchers []EventDispatchers) []EventDispatchers {
+	keep := make([]bool, len(dispatchers))
+	seen := make(map[string]bool, len(dispatchers))
+	for _, aliasPass := range []bool{true, false} {
+		for i, dispatcher := range dispatchers {
+			dispatchableType, versionID := dispatcherTarget(dispatcher)
+			if (dispatchableType == "JOB_ALIAS") != aliasPass {
+				continue
+			}
+			if versionID != "" {
+				if seen[versionID] {
+					continue
+				}
+				seen[versionID] = true
+			}
+			keep[i] = true
+		}
+	}
+
+	deduped := make([]EventDispatchers, 0, len(dispatchers))
+	for i, dispatcher := range dispatchers {
+		if keep[i] {
+			deduped = append(deduped, dispatcher)
+		}
+	}
+	return deduped
+}
+
+// dispatcherTarget 返回调度器的可调度对象类型及其投递到的作业版本ID，非作业调度器的版本ID为空
+func dispatcherTarget(dispatcher EventDispatchers) (string, string) {
+	var dispatchable struct {
+		Type      string `json:"type"`
+		ID        string `json:"id"`
+		VersionID string `json:"versionId"`
+	}
+	if json.Unmarshal(dispatcher.Dispatchable, &dispatchable) != nil {
+		return "", ""
+	}
+
+	switch dispatchable.Type {
+	case "JOB_VERSION":
+		return dispatchable.Type, dispatchable.ID
+	case "JOB_ALIAS":
+		return dispatchable.Type, dispatchable.VersionID
+	default:
+		return dispatchable.Type, ""
+	}
+}
+
 // evaluateEventRule 评估事件过滤规则，对齐 trigger.dev evaluateEventRule
 func (s *service) evaluateEventRule(dispatcher EventDispatchers, eventRecord EventRecords) bool {
 	// 如果没有过滤器，则匹配所有事件
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestServiceSpans 测试事件处理步骤创建的 span 及错误状态
+func TestServiceSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	svc := NewService(nil, nil, nil, nil, nil)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "workerqueue.work deliver_event")
+	assert.Error(t, svc.DeliverEvent(ctx, "not-a-uuid"))
+	assert.Error(t, svc.InvokeDispatcher(ctx, "not-a-uuid", "also-not-a-uuid"))
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	deliver, invoke := spans[0], spans[1]
+	assert.Equal(t, "events.DeliverEvent", deliver.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), deliver.Parent().SpanID())
+	assert.Contains(t, deliver.Attributes(), attribute.String("event.id", "not-a-uuid"))
+	assert.Equal(t, codes.Error, deliver.Status().Code)
+
+	assert.Equal(t, "events.InvokeDispatcher", invoke.Name())
+	assert.Contains(t, invoke.Attributes(), attribute.String("dispatcher.id", "not-a-uuid"))
+	assert.Equal(t, codes.Error, invoke.Status().Code)
+}
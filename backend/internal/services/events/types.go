@@ -0,0 +1,85 @@
+package events
+
+import (
+	"time"
+)
+
+// API 请求和响应类型定义，对齐 trigger.dev
+
+// SendEventRequest 发送事件请求，对齐 trigger.dev RawEvent
+type SendEventRequest struct {
+	ID        string                 `json:"id" validate:"required"`
+	Name      string                 `json:"name" validate:"required"`
+	Source    string                 `json:"source,omitempty"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+	Context   map[string]interface{} `json:"context,omitempty"`
+	Timestamp *time.Time             `json:"timestamp,omitempty"`
+}
+
+// SendEventOptions 发送事件选项，对齐 trigger.dev SendEventOptions
+type SendEventOptions struct {
+	AccountID    *string    `json:"accountId,omitempty"`
+	DeliverAt    *time.Time `json:"deliverAt,omitempty"`
+	DeliverAfter *int       `json:"deliverAfter,omitempty"` // 秒数
+	Test         *bool      `json:"test,omitempty"`         // 显式指定是否为测试事件
+	// SkipDelivery 只记录事件不加入 deliverEvent，调用方自行创建运行（如作业测试）
+	SkipDelivery bool `json:"-"`
+}
+
+// EventRecordResponse 事件记录响应
+type EventRecordResponse struct {
+	ID        string                 `json:"id"`
+	EventID   string                 `json:"eventId"`
+	Name      string                 `json:"name"`
+	Source    string                 `json:"source"`
+	Payload   map[string]interface{} `json:"payload"`
+	Context   map[string]interface{} `json:"context"`
+	Timestamp time.Time              `json:"timestamp"`
+	DeliverAt *time.Time             `json:"deliverAt,omitempty"`
+	IsTest    bool                   `json:"isTest"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// EventFilter 事件过滤器，对齐 trigger.dev EventFilter
+type EventFilter struct {
+	Payload map[string]interface{} `json:"payload"`
+	Context map[string]interface{} `json:"context"`
+}
+
+// ListEventRecordsRequest 事件记录列表查询，按 (createdAt, id) 倒序的游标分页
+type ListEventRecordsRequest struct {
+	EnvironmentID string     `json:"environmentId,omitempty"`
+	ProjectID     string     `json:"projectId,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	Source        string     `json:"source,omitempty"`
+	IsTest        *bool      `json:"isTest,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`  // 包含
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"` // 不包含
+	Cursor        string     `json:"cursor,omitempty"`
+	Limit         int32      `json:"limit,omitempty"`
+}
+
+// ListEventRecordsResponse 事件记录列表响应
+type ListEventRecordsResponse struct {
+	Records    []EventRecordResponse `json:"records"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+	PrevCursor string                `json:"prevCursor,omitempty"`
+}
+
+// EventDispatcherResponse 事件调度器响应
+type EventDispatcherResponse struct {
+	ID            string                 `json:"id"`
+	Event         string                 `json:"event"`
+	Source        string                 `json:"source"`
+	PayloadFilter map[string]interface{} `json:"payloadFilter"`
+	ContextFilter map[string]interface{} `json:"contextFilter"`
+	Manual        bool                   `json:"manual"`
+	Enabled       bool                   `json:"enabled"`
+	CreatedAt     string                 `json:"createdAt"`
+}
+
+// ListEventDispatchersResponse 事件调度器列表响应
+type ListEventDispatchersResponse struct {
+	Dispatchers []EventDispatcherResponse `json:"dispatchers"`
+	Total       int64                     `json:"total"`
+}
@@ -0,0 +1,265 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: integrations.sql
+
+package integrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createIntegration = `-- name: CreateIntegration :one
+
+INSERT INTO integrations (organization_id, slug, title, identifier)
+VALUES ($1, $2, $3, $4)
+RETURNING id, organization_id, slug, title, identifier, created_at, updated_at
+`
+
+type CreateIntegrationParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Slug           string      `json:"slug"`
+	Title          string      `json:"title"`
+	Identifier     string      `json:"identifier"`
+}
+
+// integrations.sql
+// Integration 集成与连接相关查询
+func (q *Queries) CreateIntegration(ctx context.Context, arg CreateIntegrationParams) (Integrations, error) {
+	row := q.db.QueryRow(ctx, createIntegration,
+		arg.OrganizationID,
+		arg.Slug,
+		arg.Title,
+		arg.Identifier,
+	)
+	var i Integrations
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Slug,
+		&i.Title,
+		&i.Identifier,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteIntegrationConnection = `-- name: DeleteIntegrationConnection :exec
+DELETE FROM integration_connections WHERE integration_id = $1
+`
+
+func (q *Queries) DeleteIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteIntegrationConnection, integrationID)
+	return err
+}
+
+const getIntegrationByID = `-- name: GetIntegrationByID :one
+SELECT id, organization_id, slug, title, identifier, created_at, updated_at FROM integrations WHERE id = $1
+`
+
+func (q *Queries) GetIntegrationByID(ctx context.Context, id pgtype.UUID) (Integrations, error) {
+	row := q.db.QueryRow(ctx, getIntegrationByID, id)
+	var i Integrations
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Slug,
+		&i.Title,
+		&i.Identifier,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getIntegrationBySlug = `-- name: GetIntegrationBySlug :one
+SELECT id, organization_id, slug, title, identifier, created_at, updated_at FROM integrations WHERE organization_id = $1 AND slug = $2
+`
+
+type GetIntegrationBySlugParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Slug           string      `json:"slug"`
+}
+
+func (q *Queries) GetIntegrationBySlug(ctx context.Context, arg GetIntegrationBySlugParams) (Integrations, error) {
+	row := q.db.QueryRow(ctx, getIntegrationBySlug, arg.OrganizationID, arg.Slug)
+	var i Integrations
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Slug,
+		&i.Title,
+		&i.Identifier,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getIntegrationConnection = `-- name: GetIntegrationConnection :one
+SELECT id, integration_id, organization_id, secret_key, expires_at, created_at, updated_at FROM integration_connections WHERE integration_id = $1
+`
+
+func (q *Queries) GetIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) (IntegrationConnections, error) {
+	row := q.db.QueryRow(ctx, getIntegrationConnection, integrationID)
+	var i IntegrationConnections
+	err := row.Scan(
+		&i.ID,
+		&i.IntegrationID,
+		&i.OrganizationID,
+		&i.SecretKey,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listConnectionsExpiringBefore = `-- name: ListConnectionsExpiringBefore :many
+SELECT id, integration_id, organization_id, secret_key, expires_at, created_at, updated_at FROM integration_connections
+WHERE expires_at IS NOT NULL AND expires_at <= $1
+ORDER BY expires_at
+`
+
+// 在指定时间前过期的连接，供维护任务刷新令牌
+func (q *Queries) ListConnectionsExpiringBefore(ctx context.Context, expiresAt pgtype.Timestamptz) ([]IntegrationConnections, error) {
+	rows, err := q.db.Query(ctx, listConnectionsExpiringBefore, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IntegrationConnections
+	for rows.Next() {
+		var i IntegrationConnections
+		if err := rows.Scan(
+			&i.ID,
+			&i.IntegrationID,
+			&i.OrganizationID,
+			&i.SecretKey,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIntegrationsByOrganization = `-- name: ListIntegrationsByOrganization :many
+SELECT id, organization_id, slug, title, identifier, created_at, updated_at FROM integrations
+WHERE organization_id = $1
+ORDER BY slug
+`
+
+func (q *Queries) ListIntegrationsByOrganization(ctx context.Context, organizationID pgtype.UUID) ([]Integrations, error) {
+	rows, err := q.db.Query(ctx, listIntegrationsByOrganization, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Integrations
+	for rows.Next() {
+		var i Integrations
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Slug,
+			&i.Title,
+			&i.Identifier,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationMemberEmails = `-- name: ListOrganizationMemberEmails :many
+SELECT u.email FROM org_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = $1
+ORDER BY u.email
+`
+
+// 组织成员的邮箱，接收缺失连接提醒
+func (q *Queries) ListOrganizationMemberEmails(ctx context.Context, organizationID pgtype.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listOrganizationMemberEmails, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resolveMissingConnection = `-- name: ResolveMissingConnection :exec
+UPDATE missing_connections
+SET resolved_at = NOW(), updated_at = NOW()
+WHERE integration_id = $1 AND resolved_at IS NULL
+`
+
+func (q *Queries) ResolveMissingConnection(ctx context.Context, integrationID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, resolveMissingConnection, integrationID)
+	return err
+}
+
+const upsertIntegrationConnection = `-- name: UpsertIntegrationConnection :one
+INSERT INTO integration_connections (integration_id, organization_id, secret_key, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (integration_id)
+DO UPDATE SET
+    secret_key = EXCLUDED.secret_key,
+    expires_at = EXCLUDED.expires_at,
+    updated_at = NOW()
+RETURNING id, integration_id, organization_id, secret_key, expires_at, created_at, updated_at
+`
+
+type UpsertIntegrationConnectionParams struct {
+	IntegrationID  pgtype.UUID        `json:"integration_id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	SecretKey      string             `json:"secret_key"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) UpsertIntegrationConnection(ctx context.Context, arg UpsertIntegrationConnectionParams) (IntegrationConnections, error) {
+	row := q.db.QueryRow(ctx, upsertIntegrationConnection,
+		arg.IntegrationID,
+		arg.OrganizationID,
+		arg.SecretKey,
+		arg.ExpiresAt,
+	)
+	var i IntegrationConnections
+	err := row.Scan(
+		&i.ID,
+		&i.IntegrationID,
+		&i.OrganizationID,
+		&i.SecretKey,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
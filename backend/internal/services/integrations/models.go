@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package integrations
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type IntegrationConnections struct {
+	ID             pgtype.UUID `json:"id"`
+	IntegrationID  pgtype.UUID `json:"integration_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	// SecretStore 中加密凭证的键
+	SecretKey string             `json:"secret_key"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// 集成表，对齐 trigger.dev Integration 模型
+type Integrations struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Slug           string      `json:"slug"`
+	Title          string      `json:"title"`
+	// 集成服务标识，如 github、slack
+	Identifier string             `json:"identifier"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
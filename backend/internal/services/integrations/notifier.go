@@ -0,0 +1,82 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"kongflow/backend/internal/services/email"
+	"kongflow/backend/internal/services/runs"
+
+	"github.com/google/uuid"
+)
+
+// RecipientResolver 查找组织内接收连接提醒的邮箱地址
+type RecipientResolver func(ctx context.Context, organizationID string) ([]string, error)
+
+// MemberRecipients 将组织的全部成员作为连接提醒的收件人
+func MemberRecipients(repo Repository) RecipientResolver {
+	return func(ctx context.Context, organizationID string) ([]string, error) {
+		id, err := uuid.Parse(organizationID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid organization id: %w", err)
+		}
+		return repo.ListOrganizationMemberEmails(ctx, uuidToPgUUID(id))
+	}
+}
+
+// EmailNotifier 通过 connect_integration 邮件提醒组织连接缺失的集成
+type EmailNotifier struct {
+	emailSvc   email.EmailService
+	recipients RecipientResolver
+	appOrigin  string
+}
+
+var _ runs.ConnectionNotifier = (*EmailNotifier)(nil)
+
+// NewEmailNotifier 创建邮件通知器，appOrigin 用于生成连接链接
+func NewEmailNotifier(emailSvc email.EmailService, recipients RecipientResolver, appOrigin string) *EmailNotifier {
+	return &EmailNotifier{
+		emailSvc:   emailSvc,
+		recipients: recipients,
+		appOrigin:  strings.TrimRight(appOrigin, "/"),
+	}
+}
+
+// NotifyMissingConnection 向组织的每个收件人发送连接提醒
+// 单个收件人发送失败时继续发送其余收件人，任一失败都返回错误，由调用方撤销通知标记后重试
+func (n *EmailNotifier) NotifyMissingConnection(ctx context.Context, connection runs.MissingConnection) error {
+	recipients, err := n.recipients(ctx, connection.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve recipients: %w", err)
+	}
+
+	data, err := json.Marshal(email.ConnectIntegrationEmailData{
+		IntegrationName: connection.Title,
+		ConnectLink:     ConnectLink(n.appOrigin, connection.OrganizationID, connection.Slug),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal connect integration data: %w", err)
+	}
+
+	var errs []error
+	for _, to := range recipients {
+		if err := n.emailSvc.ScheduleEmail(ctx, email.DeliverEmail{
+			Email: string(email.EmailTypeConnectIntegration),
+			To:    to,
+			Data:  data,
+		}, nil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send connect integration email to %s: %w", to, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ConnectLink 集成连接页面的链接
+func ConnectLink(appOrigin, organizationID, slug string) string {
+	return fmt.Sprintf("%s/orgs/%s/integrations/%s/connect",
+		strings.TrimRight(appOrigin, "/"), url.PathEscape(organizationID), url.PathEscape(slug))
+}
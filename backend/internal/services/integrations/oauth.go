@@ -0,0 +1,353 @@
+package integrations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"kongflow/backend/internal/services/secretstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// oauthStateTTL 授权 state 的有效期
+const oauthStateTTL = 10 * time.Minute
+
+var (
+	// ErrOAuthClientNotConfigured 集成未配置 OAuth2 客户端
+	ErrOAuthClientNotConfigured = errors.New("oauth client not configured for integration")
+	// ErrInvalidOAuthState 回调 state 不存在、已使用或已过期
+	ErrInvalidOAuthState = errors.New("invalid or expired oauth state")
+)
+
+// OAuthClientConfig 集成的 OAuth2 客户端凭证，加密保存在 SecretStore 中
+type OAuthClientConfig struct {
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"`
+	AuthorizeURL string   `json:"authorizeUrl"`
+	TokenURL     string   `json:"tokenUrl"`
+	RedirectURL  string   `json:"redirectUrl"`
+	Scopes       []string `json:"scopes,omitempty"`
+}
+
+// OAuthCredentials 保存在连接中的 OAuth2 令牌
+type OAuthCredentials struct {
+	Type         string     `json:"type"`
+	AccessToken  string     `json:"accessToken"`
+	RefreshToken string     `json:"refreshToken,omitempty"`
+	TokenType    string     `json:"tokenType,omitempty"`
+	Scopes       []string   `json:"scopes,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+}
+
+// oauthState 授权跳转时保存的 state，回调时校验并消费
+type oauthState struct {
+	IntegrationID uuid.UUID `json:"integrationId"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// oauthTokenResponse 令牌端点响应
+type oauthTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	Scope            string `json:"scope"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// SetOAuthClient 保存集成的 OAuth2 客户端凭证
+func (s *service) SetOAuthClient(ctx context.Context, integrationID uuid.UUID, config OAuthClientConfig) error {
+	if config.ClientID == "" || config.AuthorizeURL == "" || config.TokenURL == "" || config.RedirectURL == "" {
+		return fmt.Errorf("invalid oauth client: client id, authorize url, token url and redirect url are required")
+	}
+	if s.secrets == nil || !s.secrets.Encrypted() {
+		return ErrSecretsNotEncrypted
+	}
+
+	if _, err := s.repo.GetIntegrationByID(ctx, uuidToPgUUID(integrationID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrIntegrationNotFound
+		}
+		return fmt.Errorf("failed to get integration: %w", err)
+	}
+
+	if err := s.secrets.SetSecret(ctx, oauthClientSecretKey(integrationID), config); err != nil {
+		return fmt.Errorf("failed to store oauth client: %w", err)
+	}
+	return nil
+}
+
+// AuthorizeURL 生成授权跳转地址，state 保存在 SecretStore 中用于 CSRF 校验
+func (s *service) AuthorizeURL(ctx context.Context, integrationID uuid.UUID) (string, error) {
+	config, err := s.oauthClient(ctx, integrationID)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := generateOAuthState()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate oauth state: %w", err)
+	}
+
+	if err := s.secrets.SetSecret(ctx, oauthStateSecretKey(state), oauthState{
+		IntegrationID: integrationID,
+		ExpiresAt:     time.Now().Add(oauthStateTTL),
+	}); err != nil {
+		return "", fmt.Errorf("failed to store oauth state: %w", err)
+	}
+
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {config.ClientID},
+		"redirect_uri":  {config.RedirectURL},
+		"state":         {state},
+	}
+	if len(config.Scopes) > 0 {
+		params.Set("scope", strings.Join(config.Scopes, " "))
+	}
+
+	separator := "?"
+	if strings.Contains(config.AuthorizeURL, "?") {
+		separator = "&"
+	}
+	return config.AuthorizeURL + separator + params.Encode(), nil
+}
+
+// HandleOAuthCallback 校验 state 并用授权码换取令牌，随后建立连接
+func (s *service) HandleOAuthCallback(ctx context.Context, state, code string) (*IntegrationResponse, error) {
+	if state == "" || code == "" {
+		return nil, fmt.Errorf("invalid callback: state and code are required")
+	}
+	if s.secrets == nil {
+		return nil, ErrSecretsNotEncrypted
+	}
+
+	// state 只能使用一次
+	var saved oauthState
+	stateKey := oauthStateSecretKey(state)
+	if err := s.secrets.GetSecret(ctx, stateKey, &saved); err != nil {
+		return nil, ErrInvalidOAuthState
+	}
+	if err := s.secrets.DeleteSecret(ctx, stateKey); err != nil {
+		return nil, fmt.Errorf("failed to consume oauth state: %w", err)
+	}
+	if time.Now().After(saved.ExpiresAt) {
+		return nil, ErrInvalidOAuthState
+	}
+
+	config, err := s.oauthClient(ctx, saved.IntegrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.requestToken(ctx, config, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {config.RedirectURL},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storeOAuthCredentials(ctx, saved.IntegrationID, credentials)
+}
+
+// RefreshExpiringConnections 刷新在 window 内过期的 OAuth2 连接，返回刷新成功的数量
+// 单个连接刷新失败只记录日志，不影响其它连接
+func (s *service) RefreshExpiringConnections(ctx context.Context, window time.Duration) (int, error) {
+	logger := s.logger.With("operation", "refresh_expiring_connections")
+
+	connections, err := s.repo.ListConnectionsExpiringBefore(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(window),
+		Valid: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expiring connections: %w", err)
+	}
+
+	refreshed := 0
+	for _, connection := range connections {
+		integrationID := uuid.UUID(connection.IntegrationID.Bytes)
+		if err := s.refreshConnection(ctx, integrationID, connection); err != nil {
+			logger.Warn("Failed to refresh connection", "integration_id", integrationID.String(), "error", err)
+			continue
+		}
+		refreshed++
+	}
+
+	if refreshed > 0 {
+		logger.Info("Refreshed integration connections", "count", refreshed)
+	}
+	return refreshed, nil
+}
+
+// ConnectionAuth 实现 runs.ConnectionAuthProvider，返回发送给端点的连接认证信息
+func (s *service) ConnectionAuth(ctx context.Context, integrationID string) (map[string]interface{}, error) {
+	id, err := uuid.Parse(integrationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integration id: %w", err)
+	}
+
+	var credentials OAuthCredentials
+	if err := s.GetConnectionCredentials(ctx, id, &credentials); err != nil {
+		return nil, err
+	}
+
+	auth := map[string]interface{}{
+		"type":        "oauth2",
+		"accessToken": credentials.AccessToken,
+	}
+	if len(credentials.Scopes) > 0 {
+		auth["scopes"] = credentials.Scopes
+	}
+	return auth, nil
+}
+
+// refreshConnection 使用 refresh token 轮换单个连接的令牌
+func (s *service) refreshConnection(ctx context.Context, integrationID uuid.UUID, connection IntegrationConnections) error {
+	var current OAuthCredentials
+	if err := s.secrets.GetSecret(ctx, connection.SecretKey, &current); err != nil {
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+	if current.RefreshToken == "" {
+		return fmt.Errorf("connection has no refresh token")
+	}
+
+	config, err := s.oauthClient(ctx, integrationID)
+	if err != nil {
+		return err
+	}
+
+	credentials, err := s.requestToken(ctx, config, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {current.RefreshToken},
+	})
+	if err != nil {
+		return err
+	}
+
+	// 提供方未轮换 refresh token 时沿用原值
+	if credentials.RefreshToken == "" {
+		credentials.RefreshToken = current.RefreshToken
+	}
+	if len(credentials.Scopes) == 0 {
+		credentials.Scopes = current.Scopes
+	}
+
+	_, err = s.storeOAuthCredentials(ctx, integrationID, credentials)
+	return err
+}
+
+// storeOAuthCredentials 以连接凭证的形式保存令牌
+func (s *service) storeOAuthCredentials(ctx context.Context, integrationID uuid.UUID, credentials *OAuthCredentials) (*IntegrationResponse, error) {
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal oauth credentials: %w", err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal oauth credentials: %w", err)
+	}
+
+	return s.ConnectIntegration(ctx, ConnectIntegrationRequest{
+		IntegrationID: integrationID,
+		Credentials:   values,
+		ExpiresAt:     credentials.ExpiresAt,
+	})
+}
+
+// oauthClient 读取集成的 OAuth2 客户端凭证
+func (s *service) oauthClient(ctx context.Context, integrationID uuid.UUID) (*OAuthClientConfig, error) {
+	if s.secrets == nil || !s.secrets.Encrypted() {
+		return nil, ErrSecretsNotEncrypted
+	}
+
+	var config OAuthClientConfig
+	if err := s.secrets.GetSecret(ctx, oauthClientSecretKey(integrationID), &config); err != nil {
+		if errors.Is(err, secretstore.ErrSecretNotFound) {
+			return nil, ErrOAuthClientNotConfigured
+		}
+		return nil, fmt.Errorf("failed to load oauth client: %w", err)
+	}
+	return &config, nil
+}
+
+// requestToken 向令牌端点发起请求，对齐 auth.GitHubStrategy exchangeCodeForToken
+func (s *service) requestToken(ctx context.Context, config *OAuthClientConfig, data url.Values) (*OAuthCredentials, error) {
+	data.Set("client_id", config.ClientID)
+	data.Set("client_secret", config.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokenResp oauthTokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || tokenResp.Error != "" {
+		return nil, fmt.Errorf("token request failed with status %d: %s %s",
+			resp.StatusCode, tokenResp.Error, tokenResp.ErrorDescription)
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("no access token in response")
+	}
+
+	credentials := &OAuthCredentials{
+		Type:         "oauth2",
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		TokenType:    tokenResp.TokenType,
+		Scopes:       strings.Fields(tokenResp.Scope),
+	}
+	if tokenResp.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+		credentials.ExpiresAt = &expiresAt
+	}
+	return credentials, nil
+}
+
+// generateOAuthState 生成随机 state 用于 CSRF 防护
+func generateOAuthState() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// oauthClientSecretKey OAuth2 客户端凭证在 SecretStore 中的键
+func oauthClientSecretKey(integrationID uuid.UUID) string {
+	return fmt.Sprintf("integration-oauth-client:%s", integrationID)
+}
+
+// oauthStateSecretKey 授权 state 在 SecretStore 中的键
+func oauthStateSecretKey(state string) string {
+	return fmt.Sprintf("integration-oauth-state:%s", state)
+}
@@ -0,0 +1,176 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// oauthProvider 基于 httptest 的本地 OAuth2 提供方
+type oauthProvider struct {
+	server        *httptest.Server
+	code          string
+	refreshTokens []string
+	issued        int
+}
+
+func newOAuthProvider(t *testing.T) *oauthProvider {
+	p := &oauthProvider{code: "auth-code"}
+	p.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != p.code {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+		case "refresh_token":
+			p.refreshTokens = append(p.refreshTokens, r.PostForm.Get("refresh_token"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		p.issued++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("access-%d", p.issued),
+			"refresh_token": fmt.Sprintf("refresh-%d", p.issued),
+			"token_type":    "bearer",
+			"scope":         "repo user",
+			"expires_in":    60,
+		})
+	}))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *oauthProvider) clientConfig() OAuthClientConfig {
+	return OAuthClientConfig{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		AuthorizeURL: p.server.URL + "/authorize",
+		TokenURL:     p.server.URL + "/token",
+		RedirectURL:  "https://app.kongflow.dev/oauth/callback",
+		Scopes:       []string{"repo", "user"},
+	}
+}
+
+func TestService_OAuthFlow(t *testing.T) {
+	ctx := context.Background()
+	provider := newOAuthProvider(t)
+	mockRepo := new(MockRepository)
+	mockRuns := new(MockRunsService)
+	secrets, _ := newEncryptedSecrets(t)
+	svc := NewService(mockRepo, secrets, mockRuns, nil)
+
+	integrationID := uuid.New()
+	integration := Integrations{
+		ID:             uuidToPgUUID(integrationID),
+		OrganizationID: uuidToPgUUID(uuid.New()),
+		Slug:           "github",
+		Title:          "GitHub",
+		Identifier:     "github",
+	}
+	secretKey := connectionSecretKey(integrationID)
+
+	mockRepo.On("GetIntegrationByID", ctx, integration.ID).Return(integration, nil)
+	mockRepo.On("UpsertIntegrationConnection", ctx, mock.MatchedBy(func(p UpsertIntegrationConnectionParams) bool {
+		return p.SecretKey == secretKey && p.ExpiresAt.Valid
+	})).Return(IntegrationConnections{IntegrationID: integration.ID, SecretKey: secretKey}, nil)
+	mockRepo.On("ResolveMissingConnection", ctx, integration.ID).Return(nil)
+	mockRuns.On("ResumeConnectionRuns", ctx, integrationID.String()).Return(nil)
+
+	require.NoError(t, svc.SetOAuthClient(ctx, integrationID, provider.clientConfig()))
+
+	// 授权跳转
+	authorizeURL, err := svc.AuthorizeURL(ctx, integrationID)
+	require.NoError(t, err)
+	parsed, err := url.Parse(authorizeURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/authorize", parsed.Path)
+	assert.Equal(t, "client-id", parsed.Query().Get("client_id"))
+	assert.Equal(t, "repo user", parsed.Query().Get("scope"))
+	state := parsed.Query().Get("state")
+	require.Len(t, state, 64)
+
+	// 回调换取令牌
+	resp, err := svc.HandleOAuthCallback(ctx, state, provider.code)
+	require.NoError(t, err)
+	assert.True(t, resp.Connected)
+
+	// state 只能使用一次
+	_, err = svc.HandleOAuthCallback(ctx, state, provider.code)
+	assert.ErrorIs(t, err, ErrInvalidOAuthState)
+
+	mockRepo.On("GetIntegrationConnection", ctx, integration.ID).
+		Return(IntegrationConnections{IntegrationID: integration.ID, SecretKey: secretKey}, nil)
+
+	auth, err := svc.ConnectionAuth(ctx, integrationID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "oauth2", auth["type"])
+	assert.Equal(t, "access-1", auth["accessToken"])
+	assert.Equal(t, []string{"repo", "user"}, auth["scopes"])
+
+	// 维护任务在过期前轮换令牌
+	mockRepo.On("ListConnectionsExpiringBefore", ctx, mock.Anything).
+		Return([]IntegrationConnections{{IntegrationID: integration.ID, SecretKey: secretKey}}, nil)
+
+	refreshed, err := svc.RefreshExpiringConnections(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, refreshed)
+	assert.Equal(t, []string{"refresh-1"}, provider.refreshTokens)
+
+	var credentials OAuthCredentials
+	require.NoError(t, svc.GetConnectionCredentials(ctx, integrationID, &credentials))
+	assert.Equal(t, "access-2", credentials.AccessToken)
+	assert.Equal(t, "refresh-2", credentials.RefreshToken)
+
+	mockRepo.AssertExpectations(t)
+	mockRuns.AssertExpectations(t)
+}
+
+func TestService_HandleOAuthCallback_InvalidCode(t *testing.T) {
+	ctx := context.Background()
+	provider := newOAuthProvider(t)
+	mockRepo := new(MockRepository)
+	secrets, _ := newEncryptedSecrets(t)
+	svc := NewService(mockRepo, secrets, nil, nil)
+
+	integrationID := uuid.New()
+	mockRepo.On("GetIntegrationByID", ctx, uuidToPgUUID(integrationID)).
+		Return(Integrations{ID: uuidToPgUUID(integrationID)}, nil)
+	require.NoError(t, svc.SetOAuthClient(ctx, integrationID, provider.clientConfig()))
+
+	authorizeURL, err := svc.AuthorizeURL(ctx, integrationID)
+	require.NoError(t, err)
+	parsed, err := url.Parse(authorizeURL)
+	require.NoError(t, err)
+
+	_, err = svc.HandleOAuthCallback(ctx, parsed.Query().Get("state"), "wrong-code")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_grant")
+	mockRepo.AssertNotCalled(t, "UpsertIntegrationConnection", mock.Anything, mock.Anything)
+}
+
+func TestService_AuthorizeURL_ClientNotConfigured(t *testing.T) {
+	secrets, _ := newEncryptedSecrets(t)
+	svc := NewService(new(MockRepository), secrets, nil, nil)
+
+	_, err := svc.AuthorizeURL(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrOAuthClientNotConfigured)
+}
@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package integrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Querier interface {
+	// integrations.sql
+	// Integration 集成与连接相关查询
+	CreateIntegration(ctx context.Context, arg CreateIntegrationParams) (Integrations, error)
+	DeleteIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) error
+	GetIntegrationByID(ctx context.Context, id pgtype.UUID) (Integrations, error)
+	GetIntegrationBySlug(ctx context.Context, arg GetIntegrationBySlugParams) (Integrations, error)
+	GetIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) (IntegrationConnections, error)
+	// 在指定时间前过期的连接，供维护任务刷新令牌
+	ListConnectionsExpiringBefore(ctx context.Context, expiresAt pgtype.Timestamptz) ([]IntegrationConnections, error)
+	ListIntegrationsByOrganization(ctx context.Context, organizationID pgtype.UUID) ([]Integrations, error)
+	// 组织成员的邮箱，接收缺失连接提醒
+	ListOrganizationMemberEmails(ctx context.Context, organizationID pgtype.UUID) ([]string, error)
+	ResolveMissingConnection(ctx context.Context, integrationID pgtype.UUID) error
+	UpsertIntegrationConnection(ctx context.Context, arg UpsertIntegrationConnectionParams) (IntegrationConnections, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,52 @@
+-- integrations.sql
+-- Integration 集成与连接相关查询
+
+-- name: CreateIntegration :one
+INSERT INTO integrations (organization_id, slug, title, identifier)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetIntegrationByID :one
+SELECT * FROM integrations WHERE id = $1;
+
+-- name: GetIntegrationBySlug :one
+SELECT * FROM integrations WHERE organization_id = $1 AND slug = $2;
+
+-- name: ListIntegrationsByOrganization :many
+SELECT * FROM integrations
+WHERE organization_id = $1
+ORDER BY slug;
+
+-- name: ListOrganizationMemberEmails :many
+-- 组织成员的邮箱，接收缺失连接提醒
+SELECT u.email FROM org_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = $1
+ORDER BY u.email;
+
+-- name: UpsertIntegrationConnection :one
+INSERT INTO integration_connections (integration_id, organization_id, secret_key, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (integration_id)
+DO UPDATE SET
+    secret_key = EXCLUDED.secret_key,
+    expires_at = EXCLUDED.expires_at,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetIntegrationConnection :one
+SELECT * FROM integration_connections WHERE integration_id = $1;
+
+-- name: DeleteIntegrationConnection :exec
+DELETE FROM integration_connections WHERE integration_id = $1;
+
+-- name: ResolveMissingConnection :exec
+UPDATE missing_connections
+SET resolved_at = NOW(), updated_at = NOW()
+WHERE integration_id = $1 AND resolved_at IS NULL;
+
+-- name: ListConnectionsExpiringBefore :many
+-- 在指定时间前过期的连接，供维护任务刷新令牌
+SELECT * FROM integration_connections
+WHERE expires_at IS NOT NULL AND expires_at <= $1
+ORDER BY expires_at;
@@ -0,0 +1,114 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository 集成仓储接口，遵循 jobs 服务的模式
+type Repository interface {
+	// Integration operations
+	CreateIntegration(ctx context.Context, params CreateIntegrationParams) (Integrations, error)
+	GetIntegrationByID(ctx context.Context, id pgtype.UUID) (Integrations, error)
+	GetIntegrationBySlug(ctx context.Context, params GetIntegrationBySlugParams) (Integrations, error)
+	ListIntegrationsByOrganization(ctx context.Context, organizationID pgtype.UUID) ([]Integrations, error)
+
+	// IntegrationConnection operations
+	UpsertIntegrationConnection(ctx context.Context, params UpsertIntegrationConnectionParams) (IntegrationConnections, error)
+	GetIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) (IntegrationConnections, error)
+	DeleteIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) error
+	ResolveMissingConnection(ctx context.Context, integrationID pgtype.UUID) error
+	ListConnectionsExpiringBefore(ctx context.Context, before pgtype.Timestamptz) ([]IntegrationConnections, error)
+
+	// Notification recipients
+	ListOrganizationMemberEmails(ctx context.Context, organizationID pgtype.UUID) ([]string, error)
+
+	// Transaction support
+	WithTx(ctx context.Context, fn func(Repository) error) error
+}
+
+// repository 仓储实现
+type repository struct {
+	db      *pgxpool.Pool
+	queries *Queries
+}
+
+// NewRepository 创建新的仓储实例
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &repository{
+		db:      db,
+		queries: New(db),
+	}
+}
+
+// WithTx 在事务中执行操作
+func (r *repository) WithTx(ctx context.Context, fn func(Repository) error) (err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	txRepo := &repository{
+		db:      r.db,
+		queries: r.queries.WithTx(tx),
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		} else if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	return fn(txRepo)
+}
+
+// Integration operations implementation
+func (r *repository) CreateIntegration(ctx context.Context, params CreateIntegrationParams) (Integrations, error) {
+	return r.queries.CreateIntegration(ctx, params)
+}
+
+func (r *repository) GetIntegrationByID(ctx context.Context, id pgtype.UUID) (Integrations, error) {
+	return r.queries.GetIntegrationByID(ctx, id)
+}
+
+func (r *repository) GetIntegrationBySlug(ctx context.Context, params GetIntegrationBySlugParams) (Integrations, error) {
+	return r.queries.GetIntegrationBySlug(ctx, params)
+}
+
+func (r *repository) ListIntegrationsByOrganization(ctx context.Context, organizationID pgtype.UUID) ([]Integrations, error) {
+	return r.queries.ListIntegrationsByOrganization(ctx, organizationID)
+}
+
+// IntegrationConnection operations implementation
+func (r *repository) UpsertIntegrationConnection(ctx context.Context, params UpsertIntegrationConnectionParams) (IntegrationConnections, error) {
+	return r.queries.UpsertIntegrationConnection(ctx, params)
+}
+
+func (r *repository) GetIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) (IntegrationConnections, error) {
+	return r.queries.GetIntegrationConnection(ctx, integrationID)
+}
+
+func (r *repository) DeleteIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) error {
+	return r.queries.DeleteIntegrationConnection(ctx, integrationID)
+}
+
+func (r *repository) ResolveMissingConnection(ctx context.Context, integrationID pgtype.UUID) error {
+	return r.queries.ResolveMissingConnection(ctx, integrationID)
+}
+
+func (r *repository) ListConnectionsExpiringBefore(ctx context.Context, before pgtype.Timestamptz) ([]IntegrationConnections, error) {
+	return r.queries.ListConnectionsExpiringBefore(ctx, before)
+}
+
+// Notification recipients implementation
+func (r *repository) ListOrganizationMemberEmails(ctx context.Context, organizationID pgtype.UUID) ([]string, error) {
+	return r.queries.ListOrganizationMemberEmails(ctx, organizationID)
+}
@@ -0,0 +1,277 @@
+// Package integrations 管理组织内的集成及其连接，对齐 trigger.dev Integration / IntegrationConnection
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"kongflow/backend/internal/services/runs"
+	"kongflow/backend/internal/services/secretstore"
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Service 集成服务接口
+type Service interface {
+	// 集成管理
+	CreateIntegration(ctx context.Context, req CreateIntegrationRequest) (*IntegrationResponse, error)
+	GetIntegration(ctx context.Context, organizationID uuid.UUID, slug string) (*IntegrationResponse, error)
+	ListIntegrations(ctx context.Context, organizationID uuid.UUID) ([]IntegrationResponse, error)
+
+	// 连接管理 - 连接建立后恢复等待该连接的运行
+	ConnectIntegration(ctx context.Context, req ConnectIntegrationRequest) (*IntegrationResponse, error)
+	DisconnectIntegration(ctx context.Context, integrationID uuid.UUID) error
+	GetConnectionCredentials(ctx context.Context, integrationID uuid.UUID, target interface{}) error
+
+	// OAuth2 连接流程 - 授权跳转、回调换取令牌、过期前轮换令牌
+	SetOAuthClient(ctx context.Context, integrationID uuid.UUID, config OAuthClientConfig) error
+	AuthorizeURL(ctx context.Context, integrationID uuid.UUID) (string, error)
+	HandleOAuthCallback(ctx context.Context, state, code string) (*IntegrationResponse, error)
+	RefreshExpiringConnections(ctx context.Context, window time.Duration) (int, error)
+
+	// 运行执行时提供连接认证信息
+	ConnectionAuth(ctx context.Context, integrationID string) (map[string]interface{}, error)
+}
+
+// 确保集成服务可作为运行的连接认证提供者和维护任务的连接刷新器
+var (
+	_ runs.ConnectionAuthProvider     = (Service)(nil)
+	_ workerqueue.ConnectionRefresher = (Service)(nil)
+)
+
+// service 集成服务实现
+type service struct {
+	repo       Repository
+	secrets    *secretstore.Service
+	runsSvc    runs.Service
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+// NewService 创建集成服务，secrets 需由 secretstore.NewEncryptedService 创建
+// runsSvc 为 nil 时连接建立后不恢复等待中的运行
+func NewService(repo Repository, secrets *secretstore.Service, runsSvc runs.Service, logger *slog.Logger) Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &service{
+		repo:       repo,
+		secrets:    secrets,
+		runsSvc:    runsSvc,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		logger:     logger,
+	}
+}
+
+// CreateIntegration 创建集成
+func (s *service) CreateIntegration(ctx context.Context, req CreateIntegrationRequest) (*IntegrationResponse, error) {
+	if req.Slug == "" || req.Title == "" || req.Identifier == "" {
+		return nil, fmt.Errorf("invalid request: slug, title and identifier are required")
+	}
+
+	integration, err := s.repo.CreateIntegration(ctx, CreateIntegrationParams{
+		OrganizationID: uuidToPgUUID(req.OrganizationID),
+		Slug:           req.Slug,
+		Title:          req.Title,
+		Identifier:     req.Identifier,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create integration: %w", err)
+	}
+
+	return convertIntegrationToResponse(integration, nil), nil
+}
+
+// GetIntegration 根据 slug 获取集成及其连接状态
+func (s *service) GetIntegration(ctx context.Context, organizationID uuid.UUID, slug string) (*IntegrationResponse, error) {
+	integration, err := s.repo.GetIntegrationBySlug(ctx, GetIntegrationBySlugParams{
+		OrganizationID: uuidToPgUUID(organizationID),
+		Slug:           slug,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIntegrationNotFound
+		}
+		return nil, fmt.Errorf("failed to get integration: %w", err)
+	}
+
+	connection, err := s.getConnection(ctx, integration.ID)
+	if err != nil {
+		return nil, err
+	}
+	return convertIntegrationToResponse(integration, connection), nil
+}
+
+// ListIntegrations 列出组织内的集成及其连接状态
+func (s *service) ListIntegrations(ctx context.Context, organizationID uuid.UUID) ([]IntegrationResponse, error) {
+	integrations, err := s.repo.ListIntegrationsByOrganization(ctx, uuidToPgUUID(organizationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list integrations: %w", err)
+	}
+
+	responses := make([]IntegrationResponse, 0, len(integrations))
+	for _, integration := range integrations {
+		connection, err := s.getConnection(ctx, integration.ID)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *convertIntegrationToResponse(integration, connection))
+	}
+	return responses, nil
+}
+
+// ConnectIntegration 加密保存连接凭证，解决缺失连接并恢复等待该连接的运行
+func (s *service) ConnectIntegration(ctx context.Context, req ConnectIntegrationRequest) (*IntegrationResponse, error) {
+	logger := s.logger.With("operation", "connect_integration", "integration_id", req.IntegrationID.String())
+
+	if len(req.Credentials) == 0 {
+		return nil, fmt.Errorf("invalid request: credentials are required")
+	}
+	if s.secrets == nil || !s.secrets.Encrypted() {
+		return nil, ErrSecretsNotEncrypted
+	}
+
+	integration, err := s.repo.GetIntegrationByID(ctx, uuidToPgUUID(req.IntegrationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIntegrationNotFound
+		}
+		return nil, fmt.Errorf("failed to get integration: %w", err)
+	}
+
+	secretKey := connectionSecretKey(req.IntegrationID)
+	if err := s.secrets.SetSecret(ctx, secretKey, req.Credentials); err != nil {
+		return nil, fmt.Errorf("failed to store connection credentials: %w", err)
+	}
+
+	var connection IntegrationConnections
+	err = s.repo.WithTx(ctx, func(txRepo Repository) error {
+		var err error
+		connection, err = txRepo.UpsertIntegrationConnection(ctx, UpsertIntegrationConnectionParams{
+			IntegrationID:  integration.ID,
+			OrganizationID: integration.OrganizationID,
+			SecretKey:      secretKey,
+			ExpiresAt:      timeToPgTimestamptz(req.ExpiresAt),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upsert integration connection: %w", err)
+		}
+
+		if err := txRepo.ResolveMissingConnection(ctx, integration.ID); err != nil {
+			return fmt.Errorf("failed to resolve missing connection: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to connect integration", "error", err)
+		return nil, err
+	}
+
+	// 连接已保存，恢复失败只记录日志，重新连接时会再次尝试
+	if s.runsSvc != nil {
+		if err := s.runsSvc.ResumeConnectionRuns(ctx, req.IntegrationID.String()); err != nil {
+			logger.Error("Failed to resume runs waiting on connection", "error", err)
+		}
+	}
+
+	logger.Info("Integration connected", "integration", integration.Slug)
+	return convertIntegrationToResponse(integration, &connection), nil
+}
+
+// DisconnectIntegration 删除连接及其凭证
+func (s *service) DisconnectIntegration(ctx context.Context, integrationID uuid.UUID) error {
+	connection, err := s.repo.GetIntegrationConnection(ctx, uuidToPgUUID(integrationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotConnected
+		}
+		return fmt.Errorf("failed to get integration connection: %w", err)
+	}
+
+	// 先删除凭证再删除连接记录：凭证删除对缺失的键是幂等的，
+	// 删除连接记录失败时重试仍能找到连接，不会遗留无主的凭证
+	if s.secrets != nil {
+		if err := s.secrets.DeleteSecret(ctx, connection.SecretKey); err != nil {
+			return fmt.Errorf("failed to delete connection credentials: %w", err)
+		}
+	}
+
+	if err := s.repo.DeleteIntegrationConnection(ctx, connection.IntegrationID); err != nil {
+		return fmt.Errorf("failed to delete integration connection: %w", err)
+	}
+	return nil
+}
+
+// GetConnectionCredentials 解密连接凭证到 target
+func (s *service) GetConnectionCredentials(ctx context.Context, integrationID uuid.UUID, target interface{}) error {
+	connection, err := s.getConnection(ctx, uuidToPgUUID(integrationID))
+	if err != nil {
+		return err
+	}
+	if connection == nil {
+		return ErrNotConnected
+	}
+	if connection.ExpiresAt.Valid && !connection.ExpiresAt.Time.After(time.Now()) {
+		return ErrConnectionExpired
+	}
+	if s.secrets == nil {
+		return ErrSecretsNotEncrypted
+	}
+
+	return s.secrets.GetSecret(ctx, connection.SecretKey, target)
+}
+
+// getConnection 获取集成的连接，未连接时返回 nil
+func (s *service) getConnection(ctx context.Context, integrationID pgtype.UUID) (*IntegrationConnections, error) {
+	connection, err := s.repo.GetIntegrationConnection(ctx, integrationID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get integration connection: %w", err)
+	}
+	return &connection, nil
+}
+
+// connectionSecretKey 连接凭证在 SecretStore 中的键
+func connectionSecretKey(integrationID uuid.UUID) string {
+	return fmt.Sprintf("integration-connection:%s", integrationID)
+}
+
+func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: u, Valid: true}
+}
+
+func timeToPgTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func convertIntegrationToResponse(integration Integrations, connection *IntegrationConnections) *IntegrationResponse {
+	response := &IntegrationResponse{
+		ID:             integration.ID.Bytes,
+		OrganizationID: integration.OrganizationID.Bytes,
+		Slug:           integration.Slug,
+		Title:          integration.Title,
+		Identifier:     integration.Identifier,
+		CreatedAt:      integration.CreatedAt.Time,
+		UpdatedAt:      integration.UpdatedAt.Time,
+	}
+	if connection != nil {
+		response.Connected = !connection.ExpiresAt.Valid || connection.ExpiresAt.Time.After(time.Now())
+		if connection.ExpiresAt.Valid {
+			expiresAt := connection.ExpiresAt.Time
+			response.ConnectionExpiresAt = &expiresAt
+		}
+	}
+	return response
+}
@@ -0,0 +1,300 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"kongflow/backend/internal/services/email"
+	"kongflow/backend/internal/services/runs"
+	"kongflow/backend/internal/services/secretstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository 模拟集成仓储
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateIntegration(ctx context.Context, params CreateIntegrationParams) (Integrations, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(Integrations), args.Error(1)
+}
+
+func (m *MockRepository) GetIntegrationByID(ctx context.Context, id pgtype.UUID) (Integrations, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Integrations), args.Error(1)
+}
+
+func (m *MockRepository) GetIntegrationBySlug(ctx context.Context, params GetIntegrationBySlugParams) (Integrations, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(Integrations), args.Error(1)
+}
+
+func (m *MockRepository) ListIntegrationsByOrganization(ctx context.Context, organizationID pgtype.UUID) ([]Integrations, error) {
+	args := m.Called(ctx, organizationID)
+	return args.Get(0).([]Integrations), args.Error(1)
+}
+
+func (m *MockRepository) UpsertIntegrationConnection(ctx context.Context, params UpsertIntegrationConnectionParams) (IntegrationConnections, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(IntegrationConnections), args.Error(1)
+}
+
+func (m *MockRepository) GetIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) (IntegrationConnections, error) {
+	args := m.Called(ctx, integrationID)
+	return args.Get(0).(IntegrationConnections), args.Error(1)
+}
+
+func (m *MockRepository) DeleteIntegrationConnection(ctx context.Context, integrationID pgtype.UUID) error {
+	args := m.Called(ctx, integrationID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ResolveMissingConnection(ctx context.Context, integrationID pgtype.UUID) error {
+	args := m.Called(ctx, integrationID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListConnectionsExpiringBefore(ctx context.Context, before pgtype.Timestamptz) ([]IntegrationConnections, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).([]IntegrationConnections), args.Error(1)
+}
+
+func (m *MockRepository) ListOrganizationMemberEmails(ctx context.Context, organizationID pgtype.UUID) ([]string, error) {
+	args := m.Called(ctx, organizationID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepository) WithTx(ctx context.Context, fn func(Repository) error) error {
+	return fn(m)
+}
+
+// MockRunsService 模拟 runs 服务，仅覆盖集成服务用到的方法
+type MockRunsService struct {
+	mock.Mock
+	runs.Service
+}
+
+func (m *MockRunsService) ResumeConnectionRuns(ctx context.Context, integrationID string) error {
+	args := m.Called(ctx, integrationID)
+	return args.Error(0)
+}
+
+// MockEmailService 模拟邮件服务，仅覆盖通知器用到的方法
+type MockEmailService struct {
+	mock.Mock
+	email.EmailService
+}
+
+func (m *MockEmailService) ScheduleEmail(ctx context.Context, data email.DeliverEmail, delay *time.Duration) error {
+	args := m.Called(ctx, data, delay)
+	return args.Error(0)
+}
+
+// memorySecrets 内存版 SecretStore 仓储
+type memorySecrets map[string][]byte
+
+func (m memorySecrets) GetSecret(ctx context.Context, key string) (*secretstore.SecretStore, error) {
+	value, ok := m[key]
+	if !ok {
+		return nil, secretstore.ErrSecretNotFound
+	}
+	return &secretstore.SecretStore{Key: key, Value: value}, nil
+}
+
+func (m memorySecrets) UpsertSecret(ctx context.Context, key string, value []byte) error {
+	m[key] = value
+	return nil
+}
+
+func (m memorySecrets) DeleteSecret(ctx context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func newEncryptedSecrets(t *testing.T) (*secretstore.Service, memorySecrets) {
+	store := memorySecrets{}
+	secrets, err := secretstore.NewEncryptedService(store, bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	return secrets, store
+}
+
+func TestService_ConnectIntegration(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	mockRuns := new(MockRunsService)
+	secrets, store := newEncryptedSecrets(t)
+	svc := NewService(mockRepo, secrets, mockRuns, nil)
+
+	integrationID := uuid.New()
+	orgID := uuid.New()
+	integration := Integrations{
+		ID:             uuidToPgUUID(integrationID),
+		OrganizationID: uuidToPgUUID(orgID),
+		Slug:           "github",
+		Title:          "GitHub",
+		Identifier:     "github",
+	}
+	secretKey := connectionSecretKey(integrationID)
+
+	mockRepo.On("GetIntegrationByID", ctx, integration.ID).Return(integration, nil)
+	mockRepo.On("UpsertIntegrationConnection", ctx, mock.MatchedBy(func(p UpsertIntegrationConnectionParams) bool {
+		return p.IntegrationID == integration.ID && p.SecretKey == secretKey && !p.ExpiresAt.Valid
+	})).Return(IntegrationConnections{IntegrationID: integration.ID, SecretKey: secretKey}, nil)
+	mockRepo.On("ResolveMissingConnection", ctx, integration.ID).Return(nil)
+	mockRuns.On("ResumeConnectionRuns", ctx, integrationID.String()).Return(nil)
+
+	resp, err := svc.ConnectIntegration(ctx, ConnectIntegrationRequest{
+		IntegrationID: integrationID,
+		Credentials:   map[string]interface{}{"access_token": "gho_secret"},
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Connected)
+
+	// 凭证以密文保存
+	require.Contains(t, store, secretKey)
+	assert.NotContains(t, string(store[secretKey]), "gho_secret")
+
+	mockRepo.On("GetIntegrationConnection", ctx, integration.ID).
+		Return(IntegrationConnections{IntegrationID: integration.ID, SecretKey: secretKey}, nil)
+
+	var credentials map[string]string
+	require.NoError(t, svc.GetConnectionCredentials(ctx, integrationID, &credentials))
+	assert.Equal(t, "gho_secret", credentials["access_token"])
+
+	mockRepo.AssertExpectations(t)
+	mockRuns.AssertExpectations(t)
+}
+
+func TestService_ConnectIntegration_RequiresEncryptedSecrets(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := NewService(mockRepo, secretstore.NewService(memorySecrets{}), nil, nil)
+
+	_, err := svc.ConnectIntegration(context.Background(), ConnectIntegrationRequest{
+		IntegrationID: uuid.New(),
+		Credentials:   map[string]interface{}{"access_token": "gho_secret"},
+	})
+	assert.ErrorIs(t, err, ErrSecretsNotEncrypted)
+	mockRepo.AssertNotCalled(t, "GetIntegrationByID", mock.Anything, mock.Anything)
+}
+
+func TestService_GetConnectionCredentials_Expired(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	secrets, _ := newEncryptedSecrets(t)
+	svc := NewService(mockRepo, secrets, nil, nil)
+
+	integrationID := uuid.New()
+	mockRepo.On("GetIntegrationConnection", ctx, uuidToPgUUID(integrationID)).Return(IntegrationConnections{
+		SecretKey: connectionSecretKey(integrationID),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	}, nil)
+
+	var credentials map[string]string
+	err := svc.GetConnectionCredentials(ctx, integrationID, &credentials)
+	assert.ErrorIs(t, err, ErrConnectionExpired)
+}
+
+func TestService_DisconnectIntegration_DeletesCredentialsFirst(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	secrets, store := newEncryptedSecrets(t)
+	svc := NewService(mockRepo, secrets, nil, nil)
+
+	integrationID := uuid.New()
+	secretKey := connectionSecretKey(integrationID)
+	store[secretKey] = []byte("sealed")
+	connection := IntegrationConnections{IntegrationID: uuidToPgUUID(integrationID), SecretKey: secretKey}
+
+	mockRepo.On("GetIntegrationConnection", ctx, connection.IntegrationID).Return(connection, nil)
+	mockRepo.On("DeleteIntegrationConnection", ctx, connection.IntegrationID).Return(errors.New("connection lost")).Once()
+
+	// 连接记录删除失败时凭证已删除，连接仍在，可以重试
+	err := svc.DisconnectIntegration(ctx, integrationID)
+	assert.ErrorContains(t, err, "connection lost")
+	assert.NotContains(t, store, secretKey)
+
+	// 重试时凭证已不存在，仍能删除连接记录
+	mockRepo.On("DeleteIntegrationConnection", ctx, connection.IntegrationID).Return(nil).Once()
+	require.NoError(t, svc.DisconnectIntegration(ctx, integrationID))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEmailNotifier_NotifyMissingConnection(t *testing.T) {
+	ctx := context.Background()
+	mockEmail := new(MockEmailService)
+	notifier := NewEmailNotifier(mockEmail, func(ctx context.Context, organizationID string) ([]string, error) {
+		return []string{"owner@example.com"}, nil
+	}, "https://app.kongflow.dev/")
+
+	mockEmail.On("ScheduleEmail", ctx, mock.MatchedBy(func(data email.DeliverEmail) bool {
+		var payload email.ConnectIntegrationEmailData
+		if err := json.Unmarshal(data.Data, &payload); err != nil {
+			return false
+		}
+		return data.Email == string(email.EmailTypeConnectIntegration) &&
+			data.To == "owner@example.com" &&
+			payload.IntegrationName == "GitHub" &&
+			payload.ConnectLink == "https://app.kongflow.dev/orgs/org_1/integrations/github/connect"
+	}), (*time.Duration)(nil)).Return(nil)
+
+	err := notifier.NotifyMissingConnection(ctx, runs.MissingConnection{
+		IntegrationID:  "int_1",
+		OrganizationID: "org_1",
+		Slug:           "github",
+		Title:          "GitHub",
+	})
+	require.NoError(t, err)
+	mockEmail.AssertExpectations(t)
+}
+
+func TestEmailNotifier_NotifyMissingConnection_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	mockEmail := new(MockEmailService)
+	notifier := NewEmailNotifier(mockEmail, func(ctx context.Context, organizationID string) ([]string, error) {
+		return []string{"owner@example.com", "dev@example.com"}, nil
+	}, "https://app.kongflow.dev")
+
+	mockEmail.On("ScheduleEmail", ctx, mock.MatchedBy(func(data email.DeliverEmail) bool {
+		return data.To == "owner@example.com"
+	}), (*time.Duration)(nil)).Return(assert.AnError)
+	mockEmail.On("ScheduleEmail", ctx, mock.MatchedBy(func(data email.DeliverEmail) bool {
+		return data.To == "dev@example.com"
+	}), (*time.Duration)(nil)).Return(nil)
+
+	// 第一个收件人失败时仍发送给其余收件人，并返回错误以便调用方重试
+	err := notifier.NotifyMissingConnection(ctx, runs.MissingConnection{
+		IntegrationID:  "int_1",
+		OrganizationID: "org_1",
+		Slug:           "github",
+		Title:          "GitHub",
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	mockEmail.AssertExpectations(t)
+}
+
+func TestMemberRecipients(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	orgID := uuid.New()
+
+	mockRepo.On("ListOrganizationMemberEmails", ctx, uuidToPgUUID(orgID)).Return([]string{"dev@example.com", "owner@example.com"}, nil)
+
+	recipients, err := MemberRecipients(mockRepo)(ctx, orgID.String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev@example.com", "owner@example.com"}, recipients)
+
+	_, err = MemberRecipients(mockRepo)(ctx, "org_1")
+	assert.ErrorContains(t, err, "invalid organization id")
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,47 @@
+package integrations
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrIntegrationNotFound 集成不存在
+	ErrIntegrationNotFound = errors.New("integration not found")
+	// ErrNotConnected 集成尚未连接
+	ErrNotConnected = errors.New("integration not connected")
+	// ErrConnectionExpired 集成连接已过期
+	ErrConnectionExpired = errors.New("integration connection expired")
+	// ErrSecretsNotEncrypted 连接凭证只能保存在加密的 SecretStore 中
+	ErrSecretsNotEncrypted = errors.New("secret store must be encrypted to hold connection credentials")
+)
+
+// CreateIntegrationRequest 创建集成请求
+type CreateIntegrationRequest struct {
+	OrganizationID uuid.UUID `json:"organization_id" validate:"required"`
+	Slug           string    `json:"slug" validate:"required"`
+	Title          string    `json:"title" validate:"required"`
+	Identifier     string    `json:"identifier" validate:"required"`
+}
+
+// ConnectIntegrationRequest 连接集成请求，Credentials 加密保存在 SecretStore 中
+type ConnectIntegrationRequest struct {
+	IntegrationID uuid.UUID              `json:"integration_id" validate:"required"`
+	Credentials   map[string]interface{} `json:"credentials" validate:"required"`
+	ExpiresAt     *time.Time             `json:"expires_at,omitempty"`
+}
+
+// IntegrationResponse 集成响应
+type IntegrationResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	OrganizationID      uuid.UUID  `json:"organization_id"`
+	Slug                string     `json:"slug"`
+	Title               string     `json:"title"`
+	Identifier          string     `json:"identifier"`
+	Connected           bool       `json:"connected"`
+	ConnectionExpiresAt *time.Time `json:"connection_expires_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
@@ -1,11 +1,18 @@
 package jobs
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+
+	"kongflow/backend/internal/services/apiauth"
+	"kongflow/backend/internal/shared"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -90,26 +97,21 @@ func (s *service) validateRegisterJobRequest(req RegisterJobRequest) error {
 
 // 作业管理辅助方法
 
-// upsertJob 创建或更新作业
-func (s *service) upsertJob(ctx context.Context, repo Repository, req RegisterJobRequest, endpointID uuid.UUID) (Jobs, error) {
-	// 根据项目ID和组织ID来构造参数
-	// 这里需要从 endpoint 获取相关信息，简化处理先使用默认值
-	orgID := uuid.New()     // TODO: 从 endpoint 获取实际的 organization_id
-	projectID := uuid.New() // TODO: 从 endpoint 获取实际的 project_id
-
+// upsertJob 创建或更新作业，作业归属端点所在的组织和项目
+func (s *service) upsertJob(ctx context.Context, repo Repository, req RegisterJobRequest, endpoint GetEndpointScopeRow) (Jobs, error) {
 	params := UpsertJobParams{
 		Slug:           req.ID,
 		Title:          req.Name,
 		Internal:       req.Internal,
-		OrganizationID: uuidToPgUUID(orgID),
-		ProjectID:      uuidToPgUUID(projectID),
+		OrganizationID: endpoint.OrganizationID,
+		ProjectID:      endpoint.ProjectID,
 	}
 
 	return repo.UpsertJob(ctx, params)
 }
 
-// upsertJobQueue 创建或更新作业队列
-func (s *service) upsertJobQueue(ctx context.Context, repo Repository, queueConfig *QueueConfig, endpointID uuid.UUID) (JobQueues, error) {
+// upsertJobQueue 在端点所在环境中创建或更新作业队列
+func (s *service) upsertJobQueue(ctx context.Context, repo Repository, queueConfig *QueueConfig, endpoint GetEndpointScopeRow) (JobQueues, error) {
 	// 确定队列名称和最大并发数
 	queueName := DefaultQueueName
 	maxConcurrent := DefaultMaxConcurrentRuns
@@ -123,12 +125,9 @@ func (s *service) upsertJobQueue(ctx context.Context, repo Repository, queueConf
 		}
 	}
 
-	// TODO: 从 endpoint 获取实际的 environment_id
-	environmentID := uuid.New()
-
 	params := UpsertJobQueueParams{
 		Name:          queueName,
-		EnvironmentID: uuidToPgUUID(environmentID),
+		EnvironmentID: endpoint.EnvironmentID,
 		JobCount:      0,
 		MaxJobs:       int32(maxConcurrent),
 	}
@@ -136,8 +135,8 @@ func (s *service) upsertJobQueue(ctx context.Context, repo Repository, queueConf
 	return repo.UpsertJobQueue(ctx, params)
 }
 
-// upsertJobVersion 创建或更新作业版本
-func (s *service) upsertJobVersion(ctx context.Context, repo Repository, job Jobs, req RegisterJobRequest, endpointID uuid.UUID, queueID pgtype.UUID) (JobVersions, error) {
+// upsertJobVersion 在端点所在环境中创建或更新作业版本
+func (s *service) upsertJobVersion(ctx context.Context, repo Repository, job Jobs, req RegisterJobRequest, endpoint GetEndpointScopeRow, queueID pgtype.UUID) (JobVersions, error) {
 	// 构造事件规范
 	eventSpec := map[string]interface{}{
 		"name":   req.Event.Name,
@@ -164,24 +163,247 @@ func (s *service) upsertJobVersion(ctx context.Context, repo Repository, job Job
 		startPosition = JobStartPositionLATEST
 	}
 
-	// TODO: 从 endpoint 获取实际的环境信息
-	environmentID := uuid.New()
+	definition := newJobVersionDefinition(req)
+	definitionJson, err := json.Marshal(definition)
+	if err != nil {
+		return JobVersions{}, fmt.Errorf("failed to marshal job definition: %w", err)
+	}
+
+	// 与已注册的同一版本或最新版本对比定义，检测漂移
+	var previousVersion string
+	var changes []JobVersionChange
+	existing, err := repo.GetJobVersionByJobAndVersion(ctx, GetJobVersionByJobAndVersionParams{
+		JobID:         job.ID,
+		Version:       req.Version,
+		EnvironmentID: endpoint.EnvironmentID,
+	})
+	switch {
+	case err == nil:
+		if changes, err = diffJobVersionDefinition(existing.Definition, definition); err != nil {
+			return JobVersions{}, err
+		}
+		if len(changes) > 0 && !req.Override {
+			return JobVersions{}, fmt.Errorf("%w: version %s changed %s",
+				ErrJobVersionDrift, req.Version, strings.Join(changedFields(changes), ", "))
+		}
+		previousVersion = existing.Version
+	case errors.Is(err, pgx.ErrNoRows):
+		latest, err := repo.GetLatestJobVersion(ctx, GetLatestJobVersionParams{
+			JobID:         job.ID,
+			EnvironmentID: endpoint.EnvironmentID,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return JobVersions{}, fmt.Errorf("failed to get latest job version: %w", err)
+		}
+		if err == nil {
+			if changes, err = diffJobVersionDefinition(latest.Definition, definition); err != nil {
+				return JobVersions{}, err
+			}
+			previousVersion = latest.Version
+		}
+	default:
+		return JobVersions{}, fmt.Errorf("failed to get job version: %w", err)
+	}
 
 	params := UpsertJobVersionParams{
 		JobID:              job.ID,
 		Version:            req.Version,
 		EventSpecification: eventSpecJson,
 		Properties:         propertiesJson,
-		EndpointID:         uuidToPgUUID(endpointID),
-		EnvironmentID:      uuidToPgUUID(environmentID),
+		EndpointID:         endpoint.ID,
+		EnvironmentID:      endpoint.EnvironmentID,
 		OrganizationID:     job.OrganizationID,
 		ProjectID:          job.ProjectID,
 		QueueID:            queueID,
 		StartPosition:      startPosition,
 		PreprocessRuns:     req.PreprocessRuns,
+		Definition:         definitionJson,
+	}
+	if req.MaxRunDuration > 0 {
+		params.MaxRunDurationSeconds = pgtype.Int4{Int32: int32(req.MaxRunDuration), Valid: true}
+	}
+
+	jobVersion, err := repo.UpsertJobVersion(ctx, params)
+	if err != nil {
+		return JobVersions{}, err
+	}
+
+	if len(changes) > 0 {
+		changesJson, err := json.Marshal(changes)
+		if err != nil {
+			return JobVersions{}, fmt.Errorf("failed to marshal job version changes: %w", err)
+		}
+		if _, err := repo.CreateJobVersionChangelog(ctx, CreateJobVersionChangelogParams{
+			JobID:           job.ID,
+			VersionID:       jobVersion.ID,
+			Version:         jobVersion.Version,
+			PreviousVersion: pgtype.Text{String: previousVersion, Valid: true},
+			Changes:         changesJson,
+			Overridden:      previousVersion == req.Version,
+		}); err != nil {
+			return JobVersions{}, fmt.Errorf("failed to record job version changelog: %w", err)
+		}
+	}
+
+	return jobVersion, nil
+}
+
+// jobVersionDefinition 作业定义快照，只包含影响事件匹配与执行的部分
+type jobVersionDefinition struct {
+	Event        jobEventDefinition         `json:"event"`
+	Trigger      jobTriggerDefinition       `json:"trigger"`
+	Queue        QueueConfig                `json:"queue"`
+	Integrations map[string]IntegrationConf `json:"integrations,omitempty"`
+	// MaxRunDuration 运行最长持续时间（秒），0 表示使用环境默认值
+	MaxRunDuration int `json:"maxRunDuration,omitempty"`
+}
+
+type jobEventDefinition struct {
+	Name   string `json:"name"`
+	Source string `json:"source,omitempty"`
+}
+
+type jobTriggerDefinition struct {
+	Type     string            `json:"type"`
+	Rule     *TriggerRule      `json:"rule,omitempty"`
+	Schedule *ScheduleMetadata `json:"schedule,omitempty"`
+}
+
+// newJobVersionDefinition 从注册请求构造定义快照，队列使用与 upsertJobQueue 相同的默认值
+func newJobVersionDefinition(req RegisterJobRequest) jobVersionDefinition {
+	queue := QueueConfig{Name: DefaultQueueName, MaxConcurrent: DefaultMaxConcurrentRuns}
+	if req.Queue != nil {
+		if req.Queue.Name != "" {
+			queue.Name = req.Queue.Name
+		}
+		if req.Queue.MaxConcurrent > 0 {
+			queue.MaxConcurrent = req.Queue.MaxConcurrent
+		}
+	}
+
+	definition := jobVersionDefinition{
+		Event: jobEventDefinition{Name: req.Event.Name, Source: req.Event.Source},
+		Trigger: jobTriggerDefinition{
+			Type:     req.Trigger.Type,
+			Rule:     req.Trigger.Rule,
+			Schedule: req.Trigger.Schedule,
+		},
+		Queue:          queue,
+		MaxRunDuration: req.MaxRunDuration,
 	}
+	if len(req.Integrations) > 0 {
+		definition.Integrations = req.Integrations
+	}
+	return definition
+}
+
+// diffJobVersionDefinition 对比已存储的定义快照，按字段返回变更
+// 旧版本没有快照时无法对比，视为无变更
+func diffJobVersionDefinition(stored []byte, current jobVersionDefinition) ([]JobVersionChange, error) {
+	if len(stored) == 0 {
+		return nil, nil
+	}
+
+	var previous jobVersionDefinition
+	if err := json.Unmarshal(stored, &previous); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stored job definition: %w", err)
+	}
+
+	fields := []struct {
+		name              string
+		previous, current interface{}
+	}{
+		{"event", previous.Event, current.Event},
+		{"trigger", previous.Trigger, current.Trigger},
+		{"queue", previous.Queue, current.Queue},
+		{"integrations", previous.Integrations, current.Integrations},
+		{"maxRunDuration", previous.MaxRunDuration, current.MaxRunDuration},
+	}
+
+	var changes []JobVersionChange
+	for _, field := range fields {
+		previousJson, err := json.Marshal(field.previous)
+		if err != nil {
+			return nil, err
+		}
+		currentJson, err := json.Marshal(field.current)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(previousJson, currentJson) {
+			changes = append(changes, JobVersionChange{
+				Field:    field.name,
+				Previous: previousJson,
+				Current:  currentJson,
+			})
+		}
+	}
+	return changes, nil
+}
+
+func changedFields(changes []JobVersionChange) []string {
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
 
-	return repo.UpsertJobVersion(ctx, params)
+// convertChangelogToResponse 转换版本变更日志
+func convertChangelogToResponse(changelog JobVersionChangelogs) JobVersionChangelogResponse {
+	var changes []JobVersionChange
+	_ = json.Unmarshal(changelog.Changes, &changes)
+
+	return JobVersionChangelogResponse{
+		ID:              pgUUIDToUUID(changelog.ID),
+		VersionID:       pgUUIDToUUID(changelog.VersionID),
+		Version:         changelog.Version,
+		PreviousVersion: changelog.PreviousVersion.String,
+		Changes:         changes,
+		Overridden:      changelog.Overridden,
+		CreatedAt:       changelog.CreatedAt.Time,
+	}
+}
+
+// linkJobVersionIntegrations 按作业定义创建或更新组织内的集成，并重建版本与集成的关联
+func (s *service) linkJobVersionIntegrations(ctx context.Context, repo Repository, job Jobs, jobVersionID pgtype.UUID, integrations map[string]IntegrationConf) error {
+	if err := repo.DeleteJobVersionIntegrations(ctx, jobVersionID); err != nil {
+		return fmt.Errorf("failed to delete job version integrations: %w", err)
+	}
+
+	for key, conf := range integrations {
+		if conf.ID == "" {
+			return fmt.Errorf("integration %q is missing an id", key)
+		}
+
+		title := conf.Metadata.Name
+		if title == "" {
+			title = conf.ID
+		}
+		identifier := conf.Metadata.ID
+		if identifier == "" {
+			identifier = conf.ID
+		}
+
+		integration, err := repo.UpsertIntegration(ctx, UpsertIntegrationParams{
+			OrganizationID: job.OrganizationID,
+			Slug:           conf.ID,
+			Title:          title,
+			Identifier:     identifier,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upsert integration %s: %w", conf.ID, err)
+		}
+
+		if err := repo.CreateJobVersionIntegration(ctx, CreateJobVersionIntegrationParams{
+			JobVersionID:  jobVersionID,
+			IntegrationID: integration.ID,
+			Key:           key,
+		}); err != nil {
+			return fmt.Errorf("failed to link integration %s: %w", conf.ID, err)
+		}
+	}
+	return nil
 }
 
 // manageEventExamples 管理事件示例
@@ -239,36 +461,270 @@ func (s *service) manageEventExamples(ctx context.Context, repo Repository, jobV
 	return nil
 }
 
-// manageJobAlias 管理作业别名
+// manageJobAlias 管理作业别名，注册的版本为最新版本时移动 latest 别名
 func (s *service) manageJobAlias(ctx context.Context, repo Repository, job Jobs, jobVersion JobVersions, endpointID uuid.UUID) error {
-	// 检查是否有更新的版本
-	// TODO: 从 jobVersion 获取实际的 environment_id
-	environmentID := uuid.New()
-
 	laterCount, err := repo.CountLaterJobVersions(ctx, CountLaterJobVersionsParams{
-		JobID:         job.ID,
-		EnvironmentID: uuidToPgUUID(environmentID),
+		JobID:         jobVersion.JobID,
+		EnvironmentID: jobVersion.EnvironmentID,
 		Version:       jobVersion.Version,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to count later job versions: %w", err)
 	}
 
-	// 如果没有更新的版本，更新 latest 别名
-	if laterCount == 0 {
-		params := UpsertJobAliasParams{
-			JobID:         job.ID,
-			VersionID:     jobVersion.ID,
-			EnvironmentID: uuidToPgUUID(environmentID),
-			Name:          LatestAliasName,
-			Value:         jobVersion.Version,
+	// 存在更新的版本，latest 保持不变
+	if laterCount > 0 {
+		return nil
+	}
+
+	latest, err := repo.GetJobAliasByName(ctx, GetJobAliasByNameParams{
+		JobID:         jobVersion.JobID,
+		EnvironmentID: jobVersion.EnvironmentID,
+		Name:          LatestAliasName,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to get latest alias: %w", err)
+	}
+
+	// latest 被回滚后，重新注册回滚前已存在的版本不会移动 latest
+	if err == nil && latest.VersionID != jobVersion.ID && latest.UpdatedAt.Valid &&
+		jobVersion.CreatedAt.Valid && jobVersion.CreatedAt.Time.Before(latest.UpdatedAt.Time) {
+		return nil
+	}
+
+	_, err = s.moveJobAlias(ctx, repo, jobVersion.JobID, jobVersion.EnvironmentID, LatestAliasName, jobVersion)
+	return err
+}
+
+// 别名管理辅助方法
+
+// validateAliasName 验证别名名称
+func validateAliasName(name string) error {
+	if name == "" {
+		return fmt.Errorf("alias name is required")
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("alias name must be at most 100 characters")
+	}
+	return nil
+}
+
+// moveJobAlias 将别名指向指定版本，并同步以该别名为目标的事件调度器
+func (s *service) moveJobAlias(ctx context.Context, repo Repository, jobID, environmentID pgtype.UUID, name string, version JobVersions) (JobAliases, error) {
+	if version.JobID != jobID {
+		return JobAliases{}, fmt.Errorf("job version %s does not belong to job %s",
+			pgUUIDToUUID(version.ID), pgUUIDToUUID(jobID))
+	}
+	if version.EnvironmentID != environmentID {
+		return JobAliases{}, fmt.Errorf("job version %s does not belong to environment %s",
+			pgUUIDToUUID(version.ID), pgUUIDToUUID(environmentID))
+	}
+
+	alias, err := repo.UpsertJobAlias(ctx, UpsertJobAliasParams{
+		JobID:         jobID,
+		VersionID:     version.ID,
+		EnvironmentID: environmentID,
+		Name:          name,
+		Value:         version.Version,
+	})
+	if err != nil {
+		return JobAliases{}, fmt.Errorf("failed to upsert job alias: %w", err)
+	}
+
+	event, source, dispatchable, err := aliasDispatcherSpec(alias, version)
+	if err != nil {
+		return JobAliases{}, err
+	}
+
+	if _, err := repo.UpdateJobAliasEventDispatchers(ctx, UpdateJobAliasEventDispatchersParams{
+		DispatchableID: pgUUIDToUUID(alias.ID).String(),
+		Event:          event,
+		Source:         source,
+		Dispatchable:   dispatchable,
+	}); err != nil {
+		return JobAliases{}, fmt.Errorf("failed to update alias event dispatchers: %w", err)
+	}
+
+	return alias, nil
+}
+
+// upsertAliasDispatcher 为别名创建事件调度器
+func (s *service) upsertAliasDispatcher(ctx context.Context, repo Repository, alias JobAliases, version JobVersions) error {
+	event, source, dispatchable, err := aliasDispatcherSpec(alias, version)
+	if err != nil {
+		return err
+	}
+
+	if _, err := repo.UpsertJobAliasEventDispatcher(ctx, UpsertJobAliasEventDispatcherParams{
+		Event:          event,
+		Source:         source,
+		DispatchableID: pgUUIDToUUID(alias.ID).String(),
+		Dispatchable:   dispatchable,
+		EnvironmentID:  alias.EnvironmentID,
+	}); err != nil {
+		return fmt.Errorf("failed to upsert alias event dispatcher: %w", err)
+	}
+	return nil
+}
+
+// aliasDispatcherSpec 根据别名指向的版本构造调度器的事件、来源和可调度对象
+func aliasDispatcherSpec(alias JobAliases, version JobVersions) (string, string, []byte, error) {
+	event, source, err := eventNameAndSource(version)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	dispatchable, err := json.Marshal(map[string]interface{}{
+		"type":      JobAliasDispatchableType,
+		"id":        pgUUIDToUUID(alias.ID).String(),
+		"name":      alias.Name,
+		"versionId": pgUUIDToUUID(version.ID).String(),
+	})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to marshal dispatchable: %w", err)
+	}
+
+	return event, source, dispatchable, nil
+}
+
+// eventNameAndSource 从版本的事件规范中解析事件名称和来源，来源缺省为 trigger.dev
+func eventNameAndSource(version JobVersions) (string, string, error) {
+	eventSpec := jsonbToMap(version.EventSpecification)
+	name, _ := eventSpec["name"].(string)
+	if name == "" {
+		return "", "", fmt.Errorf("invalid event specification: missing name")
+	}
+	source, _ := eventSpec["source"].(string)
+	if source == "" {
+		source = DefaultEventSource
+	}
+	return name, source, nil
+}
+
+// convertEnvironmentToAuthenticated 转换环境查询结果为认证环境，对齐 apiauth 仓储的转换
+func convertEnvironmentToAuthenticated(env shared.GetEnvironmentWithProjectAndOrgRow) *apiauth.AuthenticatedEnvironment {
+	return &apiauth.AuthenticatedEnvironment{
+		Environment: apiauth.RuntimeEnvironment{
+			ID:             env.ID,
+			Slug:           env.Slug,
+			APIKey:         env.ApiKey,
+			Type:           apiauth.EnvironmentType(env.Type),
+			OrganizationID: env.OrganizationID,
+			ProjectID:      env.ProjectID,
+			OrgMemberID:    env.OrgMemberID,
+			CreatedAt:      env.CreatedAt.Time,
+			UpdatedAt:      env.UpdatedAt.Time,
+		},
+		ProjectID:   env.ProjectID_2,
+		ProjectSlug: env.ProjectSlug,
+		ProjectName: env.ProjectName,
+		OrgID:       env.OrgID,
+		OrgSlug:     env.OrgSlug,
+		OrgTitle:    env.OrgTitle,
+	}
+}
+
+// resolveTestPayload 解析作业测试的 payload
+// 指定事件示例时以示例 payload 为基础，请求的 payload 作为覆盖项深度合并
+func (s *service) resolveTestPayload(ctx context.Context, version JobVersions, req TestJobRequest) (map[string]interface{}, error) {
+	var example EventExamples
+	var err error
+
+	switch {
+	case req.ExampleID != uuid.Nil:
+		example, err = s.repo.GetEventExampleByID(ctx, uuidToPgUUID(req.ExampleID))
+	case req.ExampleSlug != "":
+		example, err = s.repo.GetEventExampleBySlug(ctx, GetEventExampleBySlugParams{
+			JobVersionID: version.ID,
+			Slug:         req.ExampleSlug,
+		})
+	default:
+		if req.Payload == nil {
+			return make(map[string]interface{}), nil
 		}
+		return req.Payload, nil
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("event example not found")
+		}
+		return nil, fmt.Errorf("failed to get event example: %w", err)
+	}
+	if example.JobVersionID != version.ID {
+		return nil, fmt.Errorf("event example %s does not belong to job version %s",
+			pgUUIDToUUID(example.ID), pgUUIDToUUID(version.ID))
+	}
 
-		_, err := repo.UpsertJobAlias(ctx, params)
-		if err != nil {
-			return fmt.Errorf("failed to upsert job alias: %w", err)
+	payload := jsonbToMap(example.Payload)
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	return mergePayload(payload, req.Payload), nil
+}
+
+// mergePayload 将 overrides 深度合并到 base，嵌套对象逐层合并，其他值直接覆盖
+func mergePayload(base, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		baseMap, baseIsMap := merged[key].(map[string]interface{})
+		overrideMap, overrideIsMap := value.(map[string]interface{})
+		if baseIsMap && overrideIsMap {
+			merged[key] = mergePayload(baseMap, overrideMap)
+			continue
 		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// convertEventExampleToResponse 转换事件示例记录为响应格式
+func convertEventExampleToResponse(example EventExamples) EventExampleResponse {
+	return EventExampleResponse{
+		ID:           pgUUIDToUUID(example.ID),
+		JobVersionID: pgUUIDToUUID(example.JobVersionID),
+		Slug:         example.Slug,
+		Name:         example.Name,
+		Icon:         example.Icon.String,
+		Payload:      jsonbToMap(example.Payload),
+		CreatedAt:    example.CreatedAt.Time,
+		UpdatedAt:    example.UpdatedAt.Time,
 	}
+}
 
-	return nil
+// convertJobToResponse 转换作业记录为响应格式
+func convertJobToResponse(job Jobs) *JobResponse {
+	resp := &JobResponse{
+		ID:              pgUUIDToUUID(job.ID),
+		Slug:            job.Slug,
+		Title:           job.Title,
+		Internal:        job.Internal,
+		OrganizationID:  pgUUIDToUUID(job.OrganizationID),
+		ProjectID:       pgUUIDToUUID(job.ProjectID),
+		Status:          job.Status,
+		StatusChangedBy: job.StatusChangedBy.String,
+		CreatedAt:       job.CreatedAt.Time,
+		UpdatedAt:       job.UpdatedAt.Time,
+	}
+	if job.StatusChangedAt.Valid {
+		changedAt := job.StatusChangedAt.Time
+		resp.StatusChangedAt = &changedAt
+	}
+	return resp
+}
+
+// convertAliasToResponse 转换别名记录为响应格式
+func convertAliasToResponse(alias JobAliases) *JobAliasResponse {
+	return &JobAliasResponse{
+		ID:            pgUUIDToUUID(alias.ID),
+		JobID:         pgUUIDToUUID(alias.JobID),
+		VersionID:     pgUUIDToUUID(alias.VersionID),
+		EnvironmentID: pgUUIDToUUID(alias.EnvironmentID),
+		Name:          alias.Name,
+		Version:       alias.Value,
+		CreatedAt:     alias.CreatedAt.Time,
+		UpdatedAt:     alias.UpdatedAt.Time,
+	}
 }
@@ -8,6 +8,7 @@ import (
 
 	"kongflow/backend/internal/services/apiauth"
 	"kongflow/backend/internal/services/events"
+	"kongflow/backend/internal/services/runs"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -19,8 +20,10 @@ import (
 func TestJobsEventsIntegration(t *testing.T) {
 	// 创建模拟服务
 	mockRepo := &MockRepository{}
+	mockShared := &MockSharedQueries{}
 	mockEvents := &MockEventsService{}
-	service := NewService(mockRepo, mockEvents, slog.Default())
+	mockRuns := &MockRunsService{}
+	service := NewService(mockRepo, mockShared, mockEvents, mockRuns, slog.Default())
 
 	// 准备测试数据
 	versionID := uuid.New()
@@ -46,12 +49,14 @@ func TestJobsEventsIntegration(t *testing.T) {
 
 	// 模拟事件记录响应
 	expectedEventRecord := &events.EventRecordResponse{
-		ID:      "event-record-123",
+		ID:      uuid.New().String(),
 		EventID: uuid.New().String(),
 	}
 
-	// 设置 Repository 期望
+	// 设置 Repository 与环境查询期望
 	mockRepo.On("GetJobVersionByID", mock.Anything, uuidToPgUUID(versionID)).Return(testVersion, nil)
+	mockShared.On("GetEnvironmentWithProjectAndOrg", mock.Anything, uuidToPgUUID(environmentID)).
+		Return(createTestEnvironment(environmentID), nil)
 
 	// 设置 Events service 期望 - 验证传递的参数
 	mockEvents.On("IngestSendEvent",
@@ -70,11 +75,19 @@ func TestJobsEventsIntegration(t *testing.T) {
 				req.Context["test"] == true
 		}),
 		mock.MatchedBy(func(opts *events.SendEventOptions) bool {
-			// 验证事件选项
-			return opts != nil && opts.DeliverAt == nil // 测试事件立即投递
+			// 验证事件选项：测试事件立即记录，运行直接创建而不经过调度器
+			return opts != nil && opts.DeliverAt == nil && opts.SkipDelivery
 		}),
 	).Return(expectedEventRecord, nil)
 
+	// 设置 Runs service 期望 - 运行固定到请求的版本
+	runID := uuid.New()
+	mockRuns.On("CreateRun", mock.Anything, mock.MatchedBy(func(req *runs.CreateRunRequest) bool {
+		return req.EventRecordID == expectedEventRecord.ID &&
+			req.VersionID == versionID.String() &&
+			req.IsTest
+	})).Return(&runs.RunResponse{ID: runID.String(), Status: runs.JobRunStatusPENDING}, nil)
+
 	// 构建测试请求
 	request := TestJobRequest{
 		VersionID:     versionID,
@@ -91,22 +104,26 @@ func TestJobsEventsIntegration(t *testing.T) {
 	// 验证结果
 	require.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.NotEmpty(t, result.RunID)
+	assert.Equal(t, runID, result.RunID)
 	assert.NotEmpty(t, result.EventID)
 	assert.Equal(t, "pending", result.Status)
 	assert.Equal(t, "Test job submitted successfully", result.Message)
 
 	// 验证所有 mock 期望都被满足
 	mockRepo.AssertExpectations(t)
+	mockShared.AssertExpectations(t)
 	mockEvents.AssertExpectations(t)
+	mockRuns.AssertExpectations(t)
 }
 
 // TestJobsEventsIntegration_EventCreationError 测试事件创建失败的情况
 func TestJobsEventsIntegration_EventCreationError(t *testing.T) {
 	// 创建模拟服务
 	mockRepo := &MockRepository{}
+	mockShared := &MockSharedQueries{}
 	mockEvents := &MockEventsService{}
-	service := NewService(mockRepo, mockEvents, slog.Default())
+	mockRuns := &MockRunsService{}
+	service := NewService(mockRepo, mockShared, mockEvents, mockRuns, slog.Default())
 
 	// 准备测试数据
 	versionID := uuid.New()
@@ -121,8 +138,10 @@ func TestJobsEventsIntegration_EventCreationError(t *testing.T) {
 		}),
 	}
 
-	// 设置 Repository 期望
+	// 设置 Repository 与环境查询期望
 	mockRepo.On("GetJobVersionByID", mock.Anything, uuidToPgUUID(versionID)).Return(testVersion, nil)
+	mockShared.On("GetEnvironmentWithProjectAndOrg", mock.Anything, uuidToPgUUID(environmentID)).
+		Return(createTestEnvironment(environmentID), nil)
 
 	// 设置 Events service 返回错误
 	mockEvents.On("IngestSendEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
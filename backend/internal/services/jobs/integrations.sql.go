@@ -0,0 +1,78 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: integrations.sql
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createJobVersionIntegration = `-- name: CreateJobVersionIntegration :exec
+INSERT INTO job_version_integrations (job_version_id, integration_id, key)
+VALUES ($1, $2, $3)
+`
+
+type CreateJobVersionIntegrationParams struct {
+	JobVersionID  pgtype.UUID `json:"job_version_id"`
+	IntegrationID pgtype.UUID `json:"integration_id"`
+	Key           string      `json:"key"`
+}
+
+func (q *Queries) CreateJobVersionIntegration(ctx context.Context, arg CreateJobVersionIntegrationParams) error {
+	_, err := q.db.Exec(ctx, createJobVersionIntegration, arg.JobVersionID, arg.IntegrationID, arg.Key)
+	return err
+}
+
+const deleteJobVersionIntegrations = `-- name: DeleteJobVersionIntegrations :exec
+DELETE FROM job_version_integrations WHERE job_version_id = $1
+`
+
+func (q *Queries) DeleteJobVersionIntegrations(ctx context.Context, jobVersionID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteJobVersionIntegrations, jobVersionID)
+	return err
+}
+
+const upsertIntegration = `-- name: UpsertIntegration :one
+
+INSERT INTO integrations (organization_id, slug, title, identifier)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (organization_id, slug)
+DO UPDATE SET
+    title = EXCLUDED.title,
+    identifier = EXCLUDED.identifier,
+    updated_at = NOW()
+RETURNING id, organization_id, slug, title, identifier, created_at, updated_at
+`
+
+type UpsertIntegrationParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Slug           string      `json:"slug"`
+	Title          string      `json:"title"`
+	Identifier     string      `json:"identifier"`
+}
+
+// integrations.sql
+// 作业版本所需集成的关联
+func (q *Queries) UpsertIntegration(ctx context.Context, arg UpsertIntegrationParams) (Integrations, error) {
+	row := q.db.QueryRow(ctx, upsertIntegration,
+		arg.OrganizationID,
+		arg.Slug,
+		arg.Title,
+		arg.Identifier,
+	)
+	var i Integrations
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Slug,
+		&i.Title,
+		&i.Identifier,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -60,6 +60,15 @@ func (q *Queries) DeleteJobAlias(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const deleteJobAliasEventDispatchers = `-- name: DeleteJobAliasEventDispatchers :exec
+DELETE FROM event_dispatchers WHERE dispatchable_id = $1
+`
+
+func (q *Queries) DeleteJobAliasEventDispatchers(ctx context.Context, dispatchableID string) error {
+	_, err := q.db.Exec(ctx, deleteJobAliasEventDispatchers, dispatchableID)
+	return err
+}
+
 const deleteJobAliasesByJob = `-- name: DeleteJobAliasesByJob :exec
 DELETE FROM job_aliases WHERE job_id = $1
 `
@@ -160,6 +169,33 @@ func (q *Queries) ListJobAliasesByJob(ctx context.Context, arg ListJobAliasesByJ
 	return items, nil
 }
 
+const updateJobAliasEventDispatchers = `-- name: UpdateJobAliasEventDispatchers :execrows
+UPDATE event_dispatchers
+SET event = $2, source = $3, dispatchable = $4, updated_at = NOW()
+WHERE dispatchable_id = $1
+`
+
+type UpdateJobAliasEventDispatchersParams struct {
+	DispatchableID string `json:"dispatchable_id"`
+	Event          string `json:"event"`
+	Source         string `json:"source"`
+	Dispatchable   []byte `json:"dispatchable"`
+}
+
+// 别名移动后同步调度器的事件规范和目标版本
+func (q *Queries) UpdateJobAliasEventDispatchers(ctx context.Context, arg UpdateJobAliasEventDispatchersParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateJobAliasEventDispatchers,
+		arg.DispatchableID,
+		arg.Event,
+		arg.Source,
+		arg.Dispatchable,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const upsertJobAlias = `-- name: UpsertJobAlias :one
 INSERT INTO job_aliases (
     job_id, version_id, environment_id, name, value
@@ -201,3 +237,40 @@ func (q *Queries) UpsertJobAlias(ctx context.Context, arg UpsertJobAliasParams)
 	)
 	return i, err
 }
+
+const upsertJobAliasEventDispatcher = `-- name: UpsertJobAliasEventDispatcher :one
+
+INSERT INTO event_dispatchers (
+    event, source, manual, dispatchable_id, dispatchable, enabled, environment_id
+) VALUES ($1, $2, false, $3, $4, true, $5)
+ON CONFLICT (dispatchable_id, environment_id)
+DO UPDATE SET
+    event = EXCLUDED.event,
+    source = EXCLUDED.source,
+    dispatchable = EXCLUDED.dispatchable,
+    enabled = true,
+    updated_at = NOW()
+RETURNING id
+`
+
+type UpsertJobAliasEventDispatcherParams struct {
+	Event          string      `json:"event"`
+	Source         string      `json:"source"`
+	DispatchableID string      `json:"dispatchable_id"`
+	Dispatchable   []byte      `json:"dispatchable"`
+	EnvironmentID  pgtype.UUID `json:"environment_id"`
+}
+
+// JobAlias 事件调度器，dispatchable 类型为 JOB_ALIAS，dispatchable_id 为别名ID
+func (q *Queries) UpsertJobAliasEventDispatcher(ctx context.Context, arg UpsertJobAliasEventDispatcherParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, upsertJobAliasEventDispatcher,
+		arg.Event,
+		arg.Source,
+		arg.DispatchableID,
+		arg.Dispatchable,
+		arg.EnvironmentID,
+	)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
@@ -35,25 +35,27 @@ const createJobVersion = `-- name: CreateJobVersion :one
 INSERT INTO job_versions (
     job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    start_position, preprocess_runs, definition, max_run_duration_seconds
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 RETURNING id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 `
 
 type CreateJobVersionParams struct {
-	JobID              pgtype.UUID      `json:"job_id"`
-	Version            string           `json:"version"`
-	EventSpecification []byte           `json:"event_specification"`
-	Properties         []byte           `json:"properties"`
-	EndpointID         pgtype.UUID      `json:"endpoint_id"`
-	EnvironmentID      pgtype.UUID      `json:"environment_id"`
-	OrganizationID     pgtype.UUID      `json:"organization_id"`
-	ProjectID          pgtype.UUID      `json:"project_id"`
-	QueueID            pgtype.UUID      `json:"queue_id"`
-	StartPosition      JobStartPosition `json:"start_position"`
-	PreprocessRuns     bool             `json:"preprocess_runs"`
+	JobID                 pgtype.UUID      `json:"job_id"`
+	Version               string           `json:"version"`
+	EventSpecification    []byte           `json:"event_specification"`
+	Properties            []byte           `json:"properties"`
+	EndpointID            pgtype.UUID      `json:"endpoint_id"`
+	EnvironmentID         pgtype.UUID      `json:"environment_id"`
+	OrganizationID        pgtype.UUID      `json:"organization_id"`
+	ProjectID             pgtype.UUID      `json:"project_id"`
+	QueueID               pgtype.UUID      `json:"queue_id"`
+	StartPosition         JobStartPosition `json:"start_position"`
+	PreprocessRuns        bool             `json:"preprocess_runs"`
+	Definition            []byte           `json:"definition"`
+	MaxRunDurationSeconds pgtype.Int4      `json:"max_run_duration_seconds"`
 }
 
 // job_versions.sql
@@ -71,6 +73,8 @@ func (q *Queries) CreateJobVersion(ctx context.Context, arg CreateJobVersionPara
 		arg.QueueID,
 		arg.StartPosition,
 		arg.PreprocessRuns,
+		arg.Definition,
+		arg.MaxRunDurationSeconds,
 	)
 	var i JobVersions
 	err := row.Scan(
@@ -88,6 +92,47 @@ func (q *Queries) CreateJobVersion(ctx context.Context, arg CreateJobVersionPara
 		&i.PreprocessRuns,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Definition,
+		&i.MaxRunDurationSeconds,
+	)
+	return i, err
+}
+
+const createJobVersionChangelog = `-- name: CreateJobVersionChangelog :one
+INSERT INTO job_version_changelogs (
+    job_id, version_id, version, previous_version, changes, overridden
+) VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, job_id, version_id, version, previous_version, changes, overridden, created_at
+`
+
+type CreateJobVersionChangelogParams struct {
+	JobID           pgtype.UUID `json:"job_id"`
+	VersionID       pgtype.UUID `json:"version_id"`
+	Version         string      `json:"version"`
+	PreviousVersion pgtype.Text `json:"previous_version"`
+	Changes         []byte      `json:"changes"`
+	Overridden      bool        `json:"overridden"`
+}
+
+func (q *Queries) CreateJobVersionChangelog(ctx context.Context, arg CreateJobVersionChangelogParams) (JobVersionChangelogs, error) {
+	row := q.db.QueryRow(ctx, createJobVersionChangelog,
+		arg.JobID,
+		arg.VersionID,
+		arg.Version,
+		arg.PreviousVersion,
+		arg.Changes,
+		arg.Overridden,
+	)
+	var i JobVersionChangelogs
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.Version,
+		&i.PreviousVersion,
+		&i.Changes,
+		&i.Overridden,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -104,7 +149,7 @@ func (q *Queries) DeleteJobVersion(ctx context.Context, id pgtype.UUID) error {
 const getJobVersionByID = `-- name: GetJobVersionByID :one
 SELECT id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 FROM job_versions 
 WHERE id = $1
 `
@@ -127,6 +172,8 @@ func (q *Queries) GetJobVersionByID(ctx context.Context, id pgtype.UUID) (JobVer
 		&i.PreprocessRuns,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Definition,
+		&i.MaxRunDurationSeconds,
 	)
 	return i, err
 }
@@ -134,7 +181,7 @@ func (q *Queries) GetJobVersionByID(ctx context.Context, id pgtype.UUID) (JobVer
 const getJobVersionByJobAndVersion = `-- name: GetJobVersionByJobAndVersion :one
 SELECT id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 FROM job_versions 
 WHERE job_id = $1 AND version = $2 AND environment_id = $3
 `
@@ -163,6 +210,8 @@ func (q *Queries) GetJobVersionByJobAndVersion(ctx context.Context, arg GetJobVe
 		&i.PreprocessRuns,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Definition,
+		&i.MaxRunDurationSeconds,
 	)
 	return i, err
 }
@@ -170,7 +219,7 @@ func (q *Queries) GetJobVersionByJobAndVersion(ctx context.Context, arg GetJobVe
 const getLatestJobVersion = `-- name: GetLatestJobVersion :one
 SELECT id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 FROM job_versions 
 WHERE job_id = $1 AND environment_id = $2
 ORDER BY created_at DESC
@@ -200,14 +249,102 @@ func (q *Queries) GetLatestJobVersion(ctx context.Context, arg GetLatestJobVersi
 		&i.PreprocessRuns,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Definition,
+		&i.MaxRunDurationSeconds,
+	)
+	return i, err
+}
+
+const getPreviousJobVersion = `-- name: GetPreviousJobVersion :one
+SELECT id, job_id, version, event_specification, properties,
+    endpoint_id, environment_id, organization_id, project_id, queue_id,
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
+FROM job_versions
+WHERE job_id = $1 AND environment_id = $2 AND created_at < (
+    SELECT created_at FROM job_versions AS cur
+    WHERE cur.job_id = $1 AND cur.environment_id = $2 AND cur.version = $3
+)
+ORDER BY created_at DESC, id DESC
+LIMIT 1
+`
+
+type GetPreviousJobVersionParams struct {
+	JobID         pgtype.UUID `json:"job_id"`
+	EnvironmentID pgtype.UUID `json:"environment_id"`
+	Version       string      `json:"version"`
+}
+
+// 查找指定版本之前注册的最近版本，用于回滚 latest 别名
+// 按注册时间而非版本字符串排序，避免 "1.10.0" 被排在 "1.9.0" 之前
+func (q *Queries) GetPreviousJobVersion(ctx context.Context, arg GetPreviousJobVersionParams) (JobVersions, error) {
+	row := q.db.QueryRow(ctx, getPreviousJobVersion, arg.JobID, arg.EnvironmentID, arg.Version)
+	var i JobVersions
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.Version,
+		&i.EventSpecification,
+		&i.Properties,
+		&i.EndpointID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.QueueID,
+		&i.StartPosition,
+		&i.PreprocessRuns,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Definition,
+		&i.MaxRunDurationSeconds,
 	)
 	return i, err
 }
 
+const listJobVersionChangelogs = `-- name: ListJobVersionChangelogs :many
+SELECT id, job_id, version_id, version, previous_version, changes, overridden, created_at FROM job_version_changelogs
+WHERE job_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type ListJobVersionChangelogsParams struct {
+	JobID pgtype.UUID `json:"job_id"`
+	Limit int32       `json:"limit"`
+}
+
+func (q *Queries) ListJobVersionChangelogs(ctx context.Context, arg ListJobVersionChangelogsParams) ([]JobVersionChangelogs, error) {
+	rows, err := q.db.Query(ctx, listJobVersionChangelogs, arg.JobID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobVersionChangelogs
+	for rows.Next() {
+		var i JobVersionChangelogs
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.VersionID,
+			&i.Version,
+			&i.PreviousVersion,
+			&i.Changes,
+			&i.Overridden,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listJobVersionsByJob = `-- name: ListJobVersionsByJob :many
 SELECT id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 FROM job_versions 
 WHERE job_id = $1
 ORDER BY created_at DESC
@@ -244,6 +381,8 @@ func (q *Queries) ListJobVersionsByJob(ctx context.Context, arg ListJobVersionsB
 			&i.PreprocessRuns,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Definition,
+			&i.MaxRunDurationSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -261,7 +400,7 @@ SET properties = $2, updated_at = NOW()
 WHERE id = $1
 RETURNING id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 `
 
 type UpdateJobVersionPropertiesParams struct {
@@ -287,6 +426,8 @@ func (q *Queries) UpdateJobVersionProperties(ctx context.Context, arg UpdateJobV
 		&i.PreprocessRuns,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Definition,
+		&i.MaxRunDurationSeconds,
 	)
 	return i, err
 }
@@ -295,8 +436,8 @@ const upsertJobVersion = `-- name: UpsertJobVersion :one
 INSERT INTO job_versions (
     job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    start_position, preprocess_runs, definition, max_run_duration_seconds
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 ON CONFLICT (job_id, version, environment_id) 
 DO UPDATE SET 
     event_specification = EXCLUDED.event_specification,
@@ -305,24 +446,28 @@ DO UPDATE SET
     queue_id = EXCLUDED.queue_id,
     start_position = EXCLUDED.start_position,
     preprocess_runs = EXCLUDED.preprocess_runs,
+    definition = EXCLUDED.definition,
+    max_run_duration_seconds = EXCLUDED.max_run_duration_seconds,
     updated_at = NOW()
 RETURNING id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 `
 
 type UpsertJobVersionParams struct {
-	JobID              pgtype.UUID      `json:"job_id"`
-	Version            string           `json:"version"`
-	EventSpecification []byte           `json:"event_specification"`
-	Properties         []byte           `json:"properties"`
-	EndpointID         pgtype.UUID      `json:"endpoint_id"`
-	EnvironmentID      pgtype.UUID      `json:"environment_id"`
-	OrganizationID     pgtype.UUID      `json:"organization_id"`
-	ProjectID          pgtype.UUID      `json:"project_id"`
-	QueueID            pgtype.UUID      `json:"queue_id"`
-	StartPosition      JobStartPosition `json:"start_position"`
-	PreprocessRuns     bool             `json:"preprocess_runs"`
+	JobID                 pgtype.UUID      `json:"job_id"`
+	Version               string           `json:"version"`
+	EventSpecification    []byte           `json:"event_specification"`
+	Properties            []byte           `json:"properties"`
+	EndpointID            pgtype.UUID      `json:"endpoint_id"`
+	EnvironmentID         pgtype.UUID      `json:"environment_id"`
+	OrganizationID        pgtype.UUID      `json:"organization_id"`
+	ProjectID             pgtype.UUID      `json:"project_id"`
+	QueueID               pgtype.UUID      `json:"queue_id"`
+	StartPosition         JobStartPosition `json:"start_position"`
+	PreprocessRuns        bool             `json:"preprocess_runs"`
+	Definition            []byte           `json:"definition"`
+	MaxRunDurationSeconds pgtype.Int4      `json:"max_run_duration_seconds"`
 }
 
 func (q *Queries) UpsertJobVersion(ctx context.Context, arg UpsertJobVersionParams) (JobVersions, error) {
@@ -338,6 +483,8 @@ func (q *Queries) UpsertJobVersion(ctx context.Context, arg UpsertJobVersionPara
 		arg.QueueID,
 		arg.StartPosition,
 		arg.PreprocessRuns,
+		arg.Definition,
+		arg.MaxRunDurationSeconds,
 	)
 	var i JobVersions
 	err := row.Scan(
@@ -355,6 +502,8 @@ func (q *Queries) UpsertJobVersion(ctx context.Context, arg UpsertJobVersionPara
 		&i.PreprocessRuns,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Definition,
+		&i.MaxRunDurationSeconds,
 	)
 	return i, err
 }
@@ -12,11 +12,18 @@ import (
 )
 
 const countJobsByProject = `-- name: CountJobsByProject :one
-SELECT COUNT(*) FROM jobs WHERE project_id = $1
+SELECT COUNT(*) FROM jobs
+WHERE project_id = $1
+    AND ($2::BOOLEAN OR status <> 'ARCHIVED')
 `
 
-func (q *Queries) CountJobsByProject(ctx context.Context, projectID pgtype.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, countJobsByProject, projectID)
+type CountJobsByProjectParams struct {
+	ProjectID       pgtype.UUID `json:"project_id"`
+	IncludeArchived bool        `json:"include_archived"`
+}
+
+func (q *Queries) CountJobsByProject(ctx context.Context, arg CountJobsByProjectParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countJobsByProject, arg.ProjectID, arg.IncludeArchived)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -27,7 +34,8 @@ const createJob = `-- name: CreateJob :one
 INSERT INTO jobs (
     slug, title, internal, organization_id, project_id
 ) VALUES ($1, $2, $3, $4, $5)
-RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at
+RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 `
 
 type CreateJobParams struct {
@@ -58,6 +66,9 @@ func (q *Queries) CreateJob(ctx context.Context, arg CreateJobParams) (Jobs, err
 		&i.ProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.StatusChangedBy,
 	)
 	return i, err
 }
@@ -71,8 +82,35 @@ func (q *Queries) DeleteJob(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const getEndpointScope = `-- name: GetEndpointScope :one
+SELECT id, environment_id, organization_id, project_id
+FROM endpoints
+WHERE id = $1
+`
+
+type GetEndpointScopeRow struct {
+	ID             pgtype.UUID `json:"id"`
+	EnvironmentID  pgtype.UUID `json:"environment_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	ProjectID      pgtype.UUID `json:"project_id"`
+}
+
+// 获取端点所属的环境、组织和项目，注册作业时据此归属作业、队列和版本
+func (q *Queries) GetEndpointScope(ctx context.Context, id pgtype.UUID) (GetEndpointScopeRow, error) {
+	row := q.db.QueryRow(ctx, getEndpointScope, id)
+	var i GetEndpointScopeRow
+	err := row.Scan(
+		&i.ID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+	)
+	return i, err
+}
+
 const getJobByID = `-- name: GetJobByID :one
-SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 FROM jobs 
 WHERE id = $1
 `
@@ -89,12 +127,16 @@ func (q *Queries) GetJobByID(ctx context.Context, id pgtype.UUID) (Jobs, error)
 		&i.ProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.StatusChangedBy,
 	)
 	return i, err
 }
 
 const getJobBySlug = `-- name: GetJobBySlug :one
-SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 FROM jobs 
 WHERE project_id = $1 AND slug = $2
 `
@@ -116,12 +158,16 @@ func (q *Queries) GetJobBySlug(ctx context.Context, arg GetJobBySlugParams) (Job
 		&i.ProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.StatusChangedBy,
 	)
 	return i, err
 }
 
 const listJobsByOrganization = `-- name: ListJobsByOrganization :many
-SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 FROM jobs 
 WHERE organization_id = $1
 ORDER BY created_at DESC
@@ -152,6 +198,9 @@ func (q *Queries) ListJobsByOrganization(ctx context.Context, arg ListJobsByOrga
 			&i.ProjectID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.StatusChangedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -164,21 +213,94 @@ func (q *Queries) ListJobsByOrganization(ctx context.Context, arg ListJobsByOrga
 }
 
 const listJobsByProject = `-- name: ListJobsByProject :many
-SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 FROM jobs 
 WHERE project_id = $1
+    AND ($4::BOOLEAN OR status <> 'ARCHIVED')
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
 `
 
 type ListJobsByProjectParams struct {
-	ProjectID pgtype.UUID `json:"project_id"`
-	Limit     int32       `json:"limit"`
-	Offset    int32       `json:"offset"`
+	ProjectID       pgtype.UUID `json:"project_id"`
+	Limit           int32       `json:"limit"`
+	Offset          int32       `json:"offset"`
+	IncludeArchived bool        `json:"include_archived"`
 }
 
 func (q *Queries) ListJobsByProject(ctx context.Context, arg ListJobsByProjectParams) ([]Jobs, error) {
-	rows, err := q.db.Query(ctx, listJobsByProject, arg.ProjectID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listJobsByProject,
+		arg.ProjectID,
+		arg.Limit,
+		arg.Offset,
+		arg.IncludeArchived,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Jobs
+	for rows.Next() {
+		var i Jobs
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Title,
+			&i.Internal,
+			&i.OrganizationID,
+			&i.ProjectID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.StatusChangedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobsPage = `-- name: ListJobsPage :many
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
+FROM jobs
+WHERE project_id = $1
+    AND ($2::BOOLEAN OR status <> 'ARCHIVED')
+    AND ($3::TEXT IS NULL OR slug LIKE $3::TEXT || '%')
+    AND ($4::BOOLEAN IS NULL OR internal = $4::BOOLEAN)
+    AND ($5::TIMESTAMPTZ IS NULL
+        OR (created_at, id) < ($5::TIMESTAMPTZ, $6::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $7
+`
+
+type ListJobsPageParams struct {
+	ProjectID       pgtype.UUID        `json:"project_id"`
+	IncludeArchived bool               `json:"include_archived"`
+	SlugPrefix      pgtype.Text        `json:"slug_prefix"`
+	Internal        pgtype.Bool        `json:"internal"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+// 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+func (q *Queries) ListJobsPage(ctx context.Context, arg ListJobsPageParams) ([]Jobs, error) {
+	rows, err := q.db.Query(ctx, listJobsPage,
+		arg.ProjectID,
+		arg.IncludeArchived,
+		arg.SlugPrefix,
+		arg.Internal,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +317,73 @@ func (q *Queries) ListJobsByProject(ctx context.Context, arg ListJobsByProjectPa
 			&i.ProjectID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.StatusChangedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobsPageReverse = `-- name: ListJobsPageReverse :many
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
+FROM jobs
+WHERE project_id = $1
+    AND ($2::BOOLEAN OR status <> 'ARCHIVED')
+    AND ($3::TEXT IS NULL OR slug LIKE $3::TEXT || '%')
+    AND ($4::BOOLEAN IS NULL OR internal = $4::BOOLEAN)
+    AND (created_at, id) > ($5::TIMESTAMPTZ, $6::UUID)
+ORDER BY created_at ASC, id ASC
+LIMIT $7
+`
+
+type ListJobsPageReverseParams struct {
+	ProjectID       pgtype.UUID        `json:"project_id"`
+	IncludeArchived bool               `json:"include_archived"`
+	SlugPrefix      pgtype.Text        `json:"slug_prefix"`
+	Internal        pgtype.Bool        `json:"internal"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+// 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+func (q *Queries) ListJobsPageReverse(ctx context.Context, arg ListJobsPageReverseParams) ([]Jobs, error) {
+	rows, err := q.db.Query(ctx, listJobsPageReverse,
+		arg.ProjectID,
+		arg.IncludeArchived,
+		arg.SlugPrefix,
+		arg.Internal,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Jobs
+	for rows.Next() {
+		var i Jobs
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Title,
+			&i.Internal,
+			&i.OrganizationID,
+			&i.ProjectID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.StatusChangedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -210,7 +399,8 @@ const updateJob = `-- name: UpdateJob :one
 UPDATE jobs 
 SET title = $2, internal = $3, updated_at = NOW()
 WHERE id = $1
-RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at
+RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 `
 
 type UpdateJobParams struct {
@@ -231,6 +421,43 @@ func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) (Jobs, err
 		&i.ProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.StatusChangedBy,
+	)
+	return i, err
+}
+
+const updateJobStatus = `-- name: UpdateJobStatus :one
+UPDATE jobs
+SET status = $2, status_changed_at = NOW(), status_changed_by = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
+`
+
+type UpdateJobStatusParams struct {
+	ID              pgtype.UUID `json:"id"`
+	Status          JobStatus   `json:"status"`
+	StatusChangedBy pgtype.Text `json:"status_changed_by"`
+}
+
+// 变更作业生命周期状态，并记录操作者与时间
+func (q *Queries) UpdateJobStatus(ctx context.Context, arg UpdateJobStatusParams) (Jobs, error) {
+	row := q.db.QueryRow(ctx, updateJobStatus, arg.ID, arg.Status, arg.StatusChangedBy)
+	var i Jobs
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Internal,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.StatusChangedBy,
 	)
 	return i, err
 }
@@ -244,7 +471,8 @@ DO UPDATE SET
     title = EXCLUDED.title,
     internal = EXCLUDED.internal,
     updated_at = NOW()
-RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at
+RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 `
 
 type UpsertJobParams struct {
@@ -273,6 +501,9 @@ func (q *Queries) UpsertJob(ctx context.Context, arg UpsertJobParams) (Jobs, err
 		&i.ProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.StatusChangedBy,
 	)
 	return i, err
 }
@@ -53,6 +53,49 @@ func (ns NullJobStartPosition) Value() (driver.Value, error) {
 	return string(ns.JobStartPosition), nil
 }
 
+type JobStatus string
+
+const (
+	JobStatusENABLED  JobStatus = "ENABLED"
+	JobStatusPAUSED   JobStatus = "PAUSED"
+	JobStatusARCHIVED JobStatus = "ARCHIVED"
+)
+
+func (e *JobStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = JobStatus(s)
+	case string:
+		*e = JobStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for JobStatus: %T", src)
+	}
+	return nil
+}
+
+type NullJobStatus struct {
+	JobStatus JobStatus `json:"job_status"`
+	Valid     bool      `json:"valid"` // Valid is true if JobStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullJobStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.JobStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.JobStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullJobStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.JobStatus), nil
+}
+
 // 事件示例表，存储作业的事件示例数据
 type EventExamples struct {
 	ID           pgtype.UUID        `json:"id"`
@@ -65,6 +108,18 @@ type EventExamples struct {
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 }
 
+// 集成表，对齐 trigger.dev Integration 模型
+type Integrations struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Slug           string      `json:"slug"`
+	Title          string      `json:"title"`
+	// 集成服务标识，如 github、slack
+	Identifier string             `json:"identifier"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
 // Job 别名表，提供版本别名功能
 type JobAliases struct {
 	ID            pgtype.UUID        `json:"id"`
@@ -88,6 +143,20 @@ type JobQueues struct {
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 }
 
+type JobVersionChangelogs struct {
+	ID        pgtype.UUID `json:"id"`
+	JobID     pgtype.UUID `json:"job_id"`
+	VersionID pgtype.UUID `json:"version_id"`
+	Version   string      `json:"version"`
+	// 对比的上一版本，同版本覆盖时与 version 相同
+	PreviousVersion pgtype.Text `json:"previous_version"`
+	// 变更字段列表 [{field, previous, current}]
+	Changes []byte `json:"changes"`
+	// 是否为同一版本的强制覆盖注册
+	Overridden bool               `json:"overridden"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 // Job 版本表，管理作业的不同版本
 type JobVersions struct {
 	ID      pgtype.UUID `json:"id"`
@@ -108,6 +177,10 @@ type JobVersions struct {
 	PreprocessRuns bool               `json:"preprocess_runs"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	// 注册时的作业定义快照
+	Definition []byte `json:"definition"`
+	// 运行最长持续时间（秒），从首次执行开始计算
+	MaxRunDurationSeconds pgtype.Int4 `json:"max_run_duration_seconds"`
 }
 
 // Job 作业主体表，对齐 trigger.dev 的 Job 模型
@@ -122,4 +195,10 @@ type Jobs struct {
 	ProjectID      pgtype.UUID        `json:"project_id"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	// 作业生命周期状态 (ENABLED/PAUSED/ARCHIVED)
+	Status JobStatus `json:"status"`
+	// 最近一次状态变更时间
+	StatusChangedAt pgtype.Timestamptz `json:"status_changed_at"`
+	// 最近一次状态变更的操作者
+	StatusChangedBy pgtype.Text `json:"status_changed_by"`
 }
@@ -11,7 +11,7 @@ import (
 )
 
 type Querier interface {
-	CountJobsByProject(ctx context.Context, projectID pgtype.UUID) (int64, error)
+	CountJobsByProject(ctx context.Context, arg CountJobsByProjectParams) (int64, error)
 	CountLaterJobVersions(ctx context.Context, arg CountLaterJobVersionsParams) (int64, error)
 	// event_examples.sql
 	// EventExample 事件示例相关查询
@@ -28,15 +28,21 @@ type Querier interface {
 	// job_versions.sql
 	// JobVersion 作业版本相关查询
 	CreateJobVersion(ctx context.Context, arg CreateJobVersionParams) (JobVersions, error)
+	CreateJobVersionChangelog(ctx context.Context, arg CreateJobVersionChangelogParams) (JobVersionChangelogs, error)
+	CreateJobVersionIntegration(ctx context.Context, arg CreateJobVersionIntegrationParams) error
 	DecrementJobCount(ctx context.Context, id pgtype.UUID) (JobQueues, error)
 	DeleteEventExample(ctx context.Context, id pgtype.UUID) error
 	DeleteEventExamplesByJobVersion(ctx context.Context, jobVersionID pgtype.UUID) error
 	DeleteEventExamplesNotInList(ctx context.Context, arg DeleteEventExamplesNotInListParams) error
 	DeleteJob(ctx context.Context, id pgtype.UUID) error
 	DeleteJobAlias(ctx context.Context, id pgtype.UUID) error
+	DeleteJobAliasEventDispatchers(ctx context.Context, dispatchableID string) error
 	DeleteJobAliasesByJob(ctx context.Context, jobID pgtype.UUID) error
 	DeleteJobQueue(ctx context.Context, id pgtype.UUID) error
 	DeleteJobVersion(ctx context.Context, id pgtype.UUID) error
+	DeleteJobVersionIntegrations(ctx context.Context, jobVersionID pgtype.UUID) error
+	// 获取端点所属的环境、组织和项目，注册作业时据此归属作业、队列和版本
+	GetEndpointScope(ctx context.Context, id pgtype.UUID) (GetEndpointScopeRow, error)
 	GetEventExampleByID(ctx context.Context, id pgtype.UUID) (EventExamples, error)
 	GetEventExampleBySlug(ctx context.Context, arg GetEventExampleBySlugParams) (EventExamples, error)
 	GetJobAliasByID(ctx context.Context, id pgtype.UUID) (JobAliases, error)
@@ -48,19 +54,35 @@ type Querier interface {
 	GetJobVersionByID(ctx context.Context, id pgtype.UUID) (JobVersions, error)
 	GetJobVersionByJobAndVersion(ctx context.Context, arg GetJobVersionByJobAndVersionParams) (JobVersions, error)
 	GetLatestJobVersion(ctx context.Context, arg GetLatestJobVersionParams) (JobVersions, error)
+	// 查找指定版本之前的最近版本，用于回滚 latest 别名
+	GetPreviousJobVersion(ctx context.Context, arg GetPreviousJobVersionParams) (JobVersions, error)
 	IncrementJobCount(ctx context.Context, id pgtype.UUID) (JobQueues, error)
 	ListEventExamplesByJobVersion(ctx context.Context, jobVersionID pgtype.UUID) ([]EventExamples, error)
 	ListJobAliasesByJob(ctx context.Context, arg ListJobAliasesByJobParams) ([]JobAliases, error)
 	ListJobQueuesByEnvironment(ctx context.Context, arg ListJobQueuesByEnvironmentParams) ([]JobQueues, error)
+	ListJobVersionChangelogs(ctx context.Context, arg ListJobVersionChangelogsParams) ([]JobVersionChangelogs, error)
 	ListJobVersionsByJob(ctx context.Context, arg ListJobVersionsByJobParams) ([]JobVersions, error)
 	ListJobsByOrganization(ctx context.Context, arg ListJobsByOrganizationParams) ([]Jobs, error)
 	ListJobsByProject(ctx context.Context, arg ListJobsByProjectParams) ([]Jobs, error)
+	// 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+	ListJobsPage(ctx context.Context, arg ListJobsPageParams) ([]Jobs, error)
+	// 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+	ListJobsPageReverse(ctx context.Context, arg ListJobsPageReverseParams) ([]Jobs, error)
 	UpdateJob(ctx context.Context, arg UpdateJobParams) (Jobs, error)
+	// 别名移动后同步调度器的事件规范和目标版本
+	UpdateJobAliasEventDispatchers(ctx context.Context, arg UpdateJobAliasEventDispatchersParams) (int64, error)
 	UpdateJobQueueCounts(ctx context.Context, arg UpdateJobQueueCountsParams) (JobQueues, error)
+	// 变更作业生命周期状态，并记录操作者与时间
+	UpdateJobStatus(ctx context.Context, arg UpdateJobStatusParams) (Jobs, error)
 	UpdateJobVersionProperties(ctx context.Context, arg UpdateJobVersionPropertiesParams) (JobVersions, error)
 	UpsertEventExample(ctx context.Context, arg UpsertEventExampleParams) (EventExamples, error)
+	// integrations.sql
+	// 作业版本所需集成的关联
+	UpsertIntegration(ctx context.Context, arg UpsertIntegrationParams) (Integrations, error)
 	UpsertJob(ctx context.Context, arg UpsertJobParams) (Jobs, error)
 	UpsertJobAlias(ctx context.Context, arg UpsertJobAliasParams) (JobAliases, error)
+	// JobAlias 事件调度器，dispatchable 类型为 JOB_ALIAS，dispatchable_id 为别名ID
+	UpsertJobAliasEventDispatcher(ctx context.Context, arg UpsertJobAliasEventDispatcherParams) (pgtype.UUID, error)
 	UpsertJobQueue(ctx context.Context, arg UpsertJobQueueParams) (JobQueues, error)
 	UpsertJobVersion(ctx context.Context, arg UpsertJobVersionParams) (JobVersions, error)
 }
@@ -0,0 +1,19 @@
+-- integrations.sql
+-- 作业版本所需集成的关联
+
+-- name: UpsertIntegration :one
+INSERT INTO integrations (organization_id, slug, title, identifier)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (organization_id, slug)
+DO UPDATE SET
+    title = EXCLUDED.title,
+    identifier = EXCLUDED.identifier,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: CreateJobVersionIntegration :exec
+INSERT INTO job_version_integrations (job_version_id, integration_id, key)
+VALUES ($1, $2, $3);
+
+-- name: DeleteJobVersionIntegrations :exec
+DELETE FROM job_version_integrations WHERE job_version_id = $1;
@@ -38,4 +38,28 @@ ORDER BY name;
 DELETE FROM job_aliases WHERE id = $1;
 
 -- name: DeleteJobAliasesByJob :exec
-DELETE FROM job_aliases WHERE job_id = $1;
\ No newline at end of file
+DELETE FROM job_aliases WHERE job_id = $1;
+
+-- JobAlias 事件调度器，dispatchable 类型为 JOB_ALIAS，dispatchable_id 为别名ID
+
+-- name: UpsertJobAliasEventDispatcher :one
+INSERT INTO event_dispatchers (
+    event, source, manual, dispatchable_id, dispatchable, enabled, environment_id
+) VALUES ($1, $2, false, $3, $4, true, $5)
+ON CONFLICT (dispatchable_id, environment_id)
+DO UPDATE SET
+    event = EXCLUDED.event,
+    source = EXCLUDED.source,
+    dispatchable = EXCLUDED.dispatchable,
+    enabled = true,
+    updated_at = NOW()
+RETURNING id;
+
+-- name: UpdateJobAliasEventDispatchers :execrows
+-- 别名移动后同步调度器的事件规范和目标版本
+UPDATE event_dispatchers
+SET event = $2, source = $3, dispatchable = $4, updated_at = NOW()
+WHERE dispatchable_id = $1;
+
+-- name: DeleteJobAliasEventDispatchers :exec
+DELETE FROM event_dispatchers WHERE dispatchable_id = $1;
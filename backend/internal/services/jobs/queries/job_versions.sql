@@ -5,23 +5,23 @@
 INSERT INTO job_versions (
     job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    start_position, preprocess_runs, definition, max_run_duration_seconds
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 RETURNING id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at;
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds;
 
 -- name: GetJobVersionByID :one
 SELECT id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 FROM job_versions 
 WHERE id = $1;
 
 -- name: GetJobVersionByJobAndVersion :one
 SELECT id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 FROM job_versions 
 WHERE job_id = $1 AND version = $2 AND environment_id = $3;
 
@@ -29,8 +29,8 @@ WHERE job_id = $1 AND version = $2 AND environment_id = $3;
 INSERT INTO job_versions (
     job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    start_position, preprocess_runs, definition, max_run_duration_seconds
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 ON CONFLICT (job_id, version, environment_id) 
 DO UPDATE SET 
     event_specification = EXCLUDED.event_specification,
@@ -39,15 +39,17 @@ DO UPDATE SET
     queue_id = EXCLUDED.queue_id,
     start_position = EXCLUDED.start_position,
     preprocess_runs = EXCLUDED.preprocess_runs,
+    definition = EXCLUDED.definition,
+    max_run_duration_seconds = EXCLUDED.max_run_duration_seconds,
     updated_at = NOW()
 RETURNING id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at;
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds;
 
 -- name: ListJobVersionsByJob :many
 SELECT id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 FROM job_versions 
 WHERE job_id = $1
 ORDER BY created_at DESC
@@ -56,7 +58,7 @@ LIMIT $2 OFFSET $3;
 -- name: GetLatestJobVersion :one
 SELECT id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
 FROM job_versions 
 WHERE job_id = $1 AND environment_id = $2
 ORDER BY created_at DESC
@@ -73,7 +75,33 @@ SET properties = $2, updated_at = NOW()
 WHERE id = $1
 RETURNING id, job_id, version, event_specification, properties,
     endpoint_id, environment_id, organization_id, project_id, queue_id,
-    start_position, preprocess_runs, created_at, updated_at;
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds;
 
 -- name: DeleteJobVersion :exec
-DELETE FROM job_versions WHERE id = $1;
\ No newline at end of file
+DELETE FROM job_versions WHERE id = $1;
+
+-- name: GetPreviousJobVersion :one
+-- 查找指定版本之前注册的最近版本，用于回滚 latest 别名
+-- 按注册时间而非版本字符串排序，避免 "1.10.0" 被排在 "1.9.0" 之前
+SELECT id, job_id, version, event_specification, properties,
+    endpoint_id, environment_id, organization_id, project_id, queue_id,
+    start_position, preprocess_runs, created_at, updated_at, definition, max_run_duration_seconds
+FROM job_versions
+WHERE job_id = $1 AND environment_id = $2 AND created_at < (
+    SELECT created_at FROM job_versions AS cur
+    WHERE cur.job_id = $1 AND cur.environment_id = $2 AND cur.version = $3
+)
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: CreateJobVersionChangelog :one
+INSERT INTO job_version_changelogs (
+    job_id, version_id, version, previous_version, changes, overridden
+) VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListJobVersionChangelogs :many
+SELECT * FROM job_version_changelogs
+WHERE job_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
@@ -5,15 +5,24 @@
 INSERT INTO jobs (
     slug, title, internal, organization_id, project_id
 ) VALUES ($1, $2, $3, $4, $5)
-RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at;
+RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by;
+
+-- name: GetEndpointScope :one
+-- 获取端点所属的环境、组织和项目，注册作业时据此归属作业、队列和版本
+SELECT id, environment_id, organization_id, project_id
+FROM endpoints
+WHERE id = $1;
 
 -- name: GetJobByID :one
-SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 FROM jobs 
 WHERE id = $1;
 
 -- name: GetJobBySlug :one
-SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 FROM jobs 
 WHERE project_id = $1 AND slug = $2;
 
@@ -26,30 +35,72 @@ DO UPDATE SET
     title = EXCLUDED.title,
     internal = EXCLUDED.internal,
     updated_at = NOW()
-RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at;
+RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by;
 
 -- name: ListJobsByProject :many
-SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 FROM jobs 
 WHERE project_id = $1
+    AND (sqlc.arg(include_archived)::BOOLEAN OR status <> 'ARCHIVED')
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: CountJobsByProject :one
-SELECT COUNT(*) FROM jobs WHERE project_id = $1;
+SELECT COUNT(*) FROM jobs
+WHERE project_id = $1
+    AND (sqlc.arg(include_archived)::BOOLEAN OR status <> 'ARCHIVED');
 
 -- name: UpdateJob :one
 UPDATE jobs 
 SET title = $2, internal = $3, updated_at = NOW()
 WHERE id = $1
-RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at;
+RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by;
 
 -- name: DeleteJob :exec
 DELETE FROM jobs WHERE id = $1;
 
 -- name: ListJobsByOrganization :many
-SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
 FROM jobs 
 WHERE organization_id = $1
 ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
\ No newline at end of file
+LIMIT $2 OFFSET $3;
+
+-- name: UpdateJobStatus :one
+-- 变更作业生命周期状态，并记录操作者与时间
+UPDATE jobs
+SET status = $2, status_changed_at = NOW(), status_changed_by = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by;
+
+-- name: ListJobsPage :many
+-- 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
+FROM jobs
+WHERE project_id = sqlc.arg(project_id)
+    AND (sqlc.arg(include_archived)::BOOLEAN OR status <> 'ARCHIVED')
+    AND (sqlc.narg(slug_prefix)::TEXT IS NULL OR slug LIKE sqlc.narg(slug_prefix)::TEXT || '%')
+    AND (sqlc.narg(internal)::BOOLEAN IS NULL OR internal = sqlc.narg(internal)::BOOLEAN)
+    AND (sqlc.narg(cursor_created_at)::TIMESTAMPTZ IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at)::TIMESTAMPTZ, sqlc.narg(cursor_id)::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListJobsPageReverse :many
+-- 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+SELECT id, slug, title, internal, organization_id, project_id, created_at, updated_at,
+    status, status_changed_at, status_changed_by
+FROM jobs
+WHERE project_id = sqlc.arg(project_id)
+    AND (sqlc.arg(include_archived)::BOOLEAN OR status <> 'ARCHIVED')
+    AND (sqlc.narg(slug_prefix)::TEXT IS NULL OR slug LIKE sqlc.narg(slug_prefix)::TEXT || '%')
+    AND (sqlc.narg(internal)::BOOLEAN IS NULL OR internal = sqlc.narg(internal)::BOOLEAN)
+    AND (created_at, id) > (sqlc.arg(cursor_created_at)::TIMESTAMPTZ, sqlc.arg(cursor_id)::UUID)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(page_size);
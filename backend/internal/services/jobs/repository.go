@@ -16,9 +16,13 @@ type Repository interface {
 	GetJobBySlug(ctx context.Context, projectID pgtype.UUID, slug string) (Jobs, error)
 	UpsertJob(ctx context.Context, params UpsertJobParams) (Jobs, error)
 	ListJobsByProject(ctx context.Context, params ListJobsByProjectParams) ([]Jobs, error)
-	CountJobsByProject(ctx context.Context, projectID pgtype.UUID) (int64, error)
+	ListJobsPage(ctx context.Context, params ListJobsPageParams) ([]Jobs, error)
+	ListJobsPageReverse(ctx context.Context, params ListJobsPageReverseParams) ([]Jobs, error)
+	CountJobsByProject(ctx context.Context, params CountJobsByProjectParams) (int64, error)
 	UpdateJob(ctx context.Context, params UpdateJobParams) (Jobs, error)
+	UpdateJobStatus(ctx context.Context, params UpdateJobStatusParams) (Jobs, error)
 	DeleteJob(ctx context.Context, id pgtype.UUID) error
+	GetEndpointScope(ctx context.Context, endpointID pgtype.UUID) (GetEndpointScopeRow, error)
 
 	// JobVersion operations
 	CreateJobVersion(ctx context.Context, params CreateJobVersionParams) (JobVersions, error)
@@ -30,6 +34,8 @@ type Repository interface {
 	CountLaterJobVersions(ctx context.Context, params CountLaterJobVersionsParams) (int64, error)
 	UpdateJobVersionProperties(ctx context.Context, params UpdateJobVersionPropertiesParams) (JobVersions, error)
 	DeleteJobVersion(ctx context.Context, id pgtype.UUID) error
+	CreateJobVersionChangelog(ctx context.Context, params CreateJobVersionChangelogParams) (JobVersionChangelogs, error)
+	ListJobVersionChangelogs(ctx context.Context, params ListJobVersionChangelogsParams) ([]JobVersionChangelogs, error)
 
 	// JobQueue operations
 	CreateJobQueue(ctx context.Context, params CreateJobQueueParams) (JobQueues, error)
@@ -50,6 +56,15 @@ type Repository interface {
 	ListJobAliasesByJob(ctx context.Context, params ListJobAliasesByJobParams) ([]JobAliases, error)
 	DeleteJobAlias(ctx context.Context, id pgtype.UUID) error
 	DeleteJobAliasesByJob(ctx context.Context, jobID pgtype.UUID) error
+	GetPreviousJobVersion(ctx context.Context, params GetPreviousJobVersionParams) (JobVersions, error)
+	UpsertJobAliasEventDispatcher(ctx context.Context, params UpsertJobAliasEventDispatcherParams) (pgtype.UUID, error)
+	UpdateJobAliasEventDispatchers(ctx context.Context, params UpdateJobAliasEventDispatchersParams) (int64, error)
+	DeleteJobAliasEventDispatchers(ctx context.Context, dispatchableID string) error
+
+	// Integration operations
+	UpsertIntegration(ctx context.Context, params UpsertIntegrationParams) (Integrations, error)
+	CreateJobVersionIntegration(ctx context.Context, params CreateJobVersionIntegrationParams) error
+	DeleteJobVersionIntegrations(ctx context.Context, jobVersionID pgtype.UUID) error
 
 	// EventExample operations
 	CreateEventExample(ctx context.Context, params CreateEventExampleParams) (EventExamples, error)
@@ -80,7 +95,7 @@ func NewRepository(db *pgxpool.Pool) Repository {
 }
 
 // WithTx 在事务中执行操作
-func (r *repository) WithTx(ctx context.Context, fn func(Repository) error) error {
+func (r *repository) WithTx(ctx context.Context, fn func(Repository) error) (err error) {
 	conn, err := r.db.Acquire(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to acquire connection: %w", err)
@@ -131,18 +146,34 @@ func (r *repository) UpsertJob(ctx context.Context, params UpsertJobParams) (Job
 	return r.queries.UpsertJob(ctx, params)
 }
 
+func (r *repository) GetEndpointScope(ctx context.Context, endpointID pgtype.UUID) (GetEndpointScopeRow, error) {
+	return r.queries.GetEndpointScope(ctx, endpointID)
+}
+
 func (r *repository) ListJobsByProject(ctx context.Context, params ListJobsByProjectParams) ([]Jobs, error) {
 	return r.queries.ListJobsByProject(ctx, params)
 }
 
-func (r *repository) CountJobsByProject(ctx context.Context, projectID pgtype.UUID) (int64, error) {
-	return r.queries.CountJobsByProject(ctx, projectID)
+func (r *repository) ListJobsPage(ctx context.Context, params ListJobsPageParams) ([]Jobs, error) {
+	return r.queries.ListJobsPage(ctx, params)
+}
+
+func (r *repository) ListJobsPageReverse(ctx context.Context, params ListJobsPageReverseParams) ([]Jobs, error) {
+	return r.queries.ListJobsPageReverse(ctx, params)
+}
+
+func (r *repository) CountJobsByProject(ctx context.Context, params CountJobsByProjectParams) (int64, error) {
+	return r.queries.CountJobsByProject(ctx, params)
 }
 
 func (r *repository) UpdateJob(ctx context.Context, params UpdateJobParams) (Jobs, error) {
 	return r.queries.UpdateJob(ctx, params)
 }
 
+func (r *repository) UpdateJobStatus(ctx context.Context, params UpdateJobStatusParams) (Jobs, error) {
+	return r.queries.UpdateJobStatus(ctx, params)
+}
+
 func (r *repository) DeleteJob(ctx context.Context, id pgtype.UUID) error {
 	return r.queries.DeleteJob(ctx, id)
 }
@@ -184,6 +215,14 @@ func (r *repository) DeleteJobVersion(ctx context.Context, id pgtype.UUID) error
 	return r.queries.DeleteJobVersion(ctx, id)
 }
 
+func (r *repository) CreateJobVersionChangelog(ctx context.Context, params CreateJobVersionChangelogParams) (JobVersionChangelogs, error) {
+	return r.queries.CreateJobVersionChangelog(ctx, params)
+}
+
+func (r *repository) ListJobVersionChangelogs(ctx context.Context, params ListJobVersionChangelogsParams) ([]JobVersionChangelogs, error) {
+	return r.queries.ListJobVersionChangelogs(ctx, params)
+}
+
 // JobQueue operations implementation
 func (r *repository) CreateJobQueue(ctx context.Context, params CreateJobQueueParams) (JobQueues, error) {
 	return r.queries.CreateJobQueue(ctx, params)
@@ -250,7 +289,35 @@ func (r *repository) DeleteJobAliasesByJob(ctx context.Context, jobID pgtype.UUI
 	return r.queries.DeleteJobAliasesByJob(ctx, jobID)
 }
 
+func (r *repository) GetPreviousJobVersion(ctx context.Context, params GetPreviousJobVersionParams) (JobVersions, error) {
+	return r.queries.GetPreviousJobVersion(ctx, params)
+}
+
+func (r *repository) UpsertJobAliasEventDispatcher(ctx context.Context, params UpsertJobAliasEventDispatcherParams) (pgtype.UUID, error) {
+	return r.queries.UpsertJobAliasEventDispatcher(ctx, params)
+}
+
+func (r *repository) UpdateJobAliasEventDispatchers(ctx context.Context, params UpdateJobAliasEventDispatchersParams) (int64, error) {
+	return r.queries.UpdateJobAliasEventDispatchers(ctx, params)
+}
+
+func (r *repository) DeleteJobAliasEventDispatchers(ctx context.Context, dispatchableID string) error {
+	return r.queries.DeleteJobAliasEventDispatchers(ctx, dispatchableID)
+}
+
 // EventExample operations implementation
+func (r *repository) UpsertIntegration(ctx context.Context, params UpsertIntegrationParams) (Integrations, error) {
+	return r.queries.UpsertIntegration(ctx, params)
+}
+
+func (r *repository) CreateJobVersionIntegration(ctx context.Context, params CreateJobVersionIntegrationParams) error {
+	return r.queries.CreateJobVersionIntegration(ctx, params)
+}
+
+func (r *repository) DeleteJobVersionIntegrations(ctx context.Context, jobVersionID pgtype.UUID) error {
+	return r.queries.DeleteJobVersionIntegrations(ctx, jobVersionID)
+}
+
 func (r *repository) CreateEventExample(ctx context.Context, params CreateEventExampleParams) (EventExamples, error) {
 	return r.queries.CreateEventExample(ctx, params)
 }
@@ -168,7 +168,10 @@ func (suite *JobsRepositoryTestSuite) createTestQueue(ctx context.Context, name
 
 // countJobs 计算项目中的 Job 数量
 func (suite *JobsRepositoryTestSuite) countJobs(ctx context.Context) int64 {
-	count, err := suite.repo.CountJobsByProject(ctx, suite.goUUIDToPgtype(suite.testProjectID))
+	count, err := suite.repo.CountJobsByProject(ctx, CountJobsByProjectParams{
+		ProjectID:       suite.goUUIDToPgtype(suite.testProjectID),
+		IncludeArchived: true,
+	})
 	require.NoError(suite.T(), err)
 	return count
 }
@@ -235,7 +238,10 @@ func (suite *JobsRepositoryTestSuite) TestJobCRUDOperations() {
 	assert.Equal(suite.T(), updated.ID, jobs[0].ID)
 
 	// 6. 计数查询
-	count, err := suite.repo.CountJobsByProject(ctx, suite.goUUIDToPgtype(suite.testProjectID))
+	count, err := suite.repo.CountJobsByProject(ctx, CountJobsByProjectParams{
+		ProjectID:       suite.goUUIDToPgtype(suite.testProjectID),
+		IncludeArchived: true,
+	})
 	require.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(1), count)
 
@@ -248,7 +254,10 @@ func (suite *JobsRepositoryTestSuite) TestJobCRUDOperations() {
 	assert.Error(suite.T(), err, "Job should be deleted")
 
 	// 验证计数更新
-	count, err = suite.repo.CountJobsByProject(ctx, suite.goUUIDToPgtype(suite.testProjectID))
+	count, err = suite.repo.CountJobsByProject(ctx, CountJobsByProjectParams{
+		ProjectID:       suite.goUUIDToPgtype(suite.testProjectID),
+		IncludeArchived: true,
+	})
 	require.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(0), count)
 }
@@ -2,14 +2,22 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"strings"
 	"time"
 
-	"kongflow/backend/internal/services/apiauth"
 	"kongflow/backend/internal/services/events"
+	"kongflow/backend/internal/services/pagination"
+	"kongflow/backend/internal/services/runs"
+	"kongflow/backend/internal/shared"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // 常量定义，对齐 trigger.dev
@@ -17,8 +25,15 @@ const (
 	DefaultMaxConcurrentRuns = 100
 	DefaultQueueName         = "default"
 	LatestAliasName          = "latest"
+	DefaultEventSource       = "trigger.dev"
+
+	// JobAliasDispatchableType 以别名为目标的事件调度器类型
+	JobAliasDispatchableType = "JOB_ALIAS"
 )
 
+// ErrJobVersionDrift 同一版本以不同内容重复注册，且未设置 Override
+var ErrJobVersionDrift = errors.New("job version already registered with different definition")
+
 // Service Jobs 服务接口，严格对齐 trigger.dev 的功能
 type Service interface {
 	// 核心作业管理 - 对齐 RegisterJobService
@@ -28,14 +43,29 @@ type Service interface {
 	ListJobs(ctx context.Context, params ListJobsParams) (*ListJobsResponse, error)
 	DeleteJob(ctx context.Context, id uuid.UUID) error
 
+	// 作业生命周期 - 启用、暂停、归档
+	SetJobStatus(ctx context.Context, id uuid.UUID, req SetJobStatusRequest) (*JobResponse, error)
+
 	// 作业版本管理
 	GetJobVersion(ctx context.Context, id uuid.UUID) (*JobVersionResponse, error)
 	ListJobVersions(ctx context.Context, jobID uuid.UUID) (*ListJobVersionsResponse, error)
+	ListEventExamples(ctx context.Context, versionID uuid.UUID) ([]EventExampleResponse, error)
+	ListJobVersionChangelog(ctx context.Context, jobID uuid.UUID) ([]JobVersionChangelogResponse, error)
+
+	// 作业别名管理 - 版本提升与回滚
+	SetJobAlias(ctx context.Context, req SetJobAliasRequest) (*JobAliasResponse, error)
+	PromoteJobAlias(ctx context.Context, req PromoteJobAliasRequest) (*JobAliasResponse, error)
+	RollbackLatest(ctx context.Context, req RollbackLatestRequest) (*JobAliasResponse, error)
+	ListJobAliases(ctx context.Context, jobID, environmentID uuid.UUID) ([]JobAliasResponse, error)
+	DeleteJobAlias(ctx context.Context, jobID, environmentID uuid.UUID, name string) error
 
 	// 作业队列管理
 	GetJobQueue(ctx context.Context, environmentID uuid.UUID, name string) (*JobQueueResponse, error)
 	CreateJobQueue(ctx context.Context, req CreateJobQueueRequest) (*JobQueueResponse, error)
 
+	// 环境运行限制 - 未设置 maxRunDuration 的作业版本使用环境默认值
+	SetEnvironmentMaxRunDuration(ctx context.Context, req SetEnvironmentMaxRunDurationRequest) (*EnvironmentMaxRunDurationResponse, error)
+
 	// 作业测试 - 对齐 TestJobService
 	TestJob(ctx context.Context, req TestJobRequest) (*TestJobResponse, error)
 }
@@ -52,6 +82,10 @@ type RegisterJobRequest struct {
 	Integrations   map[string]IntegrationConf `json:"integrations,omitempty"`
 	StartPosition  string                     `json:"startPosition,omitempty"`
 	PreprocessRuns bool                       `json:"preprocessRuns"`
+	// MaxRunDuration 运行最长持续时间（秒），为 0 时使用环境默认值
+	MaxRunDuration int `json:"maxRunDuration,omitempty" validate:"min=0"`
+	// Override 允许以不同的定义覆盖已注册的同一版本，覆盖会记录到变更日志
+	Override bool `json:"override,omitempty"`
 }
 
 // EventSpecification 事件规范
@@ -110,20 +144,62 @@ type EventExampleData struct {
 	Payload map[string]interface{} `json:"payload,omitempty"`
 }
 
-// ListJobsParams 列表查询参数
+// ListJobsParams 列表查询参数，按 (created_at, id) 倒序的游标分页
 type ListJobsParams struct {
 	ProjectID uuid.UUID `json:"project_id"`
 	Limit     int32     `json:"limit"`
-	Offset    int32     `json:"offset"`
+	// Cursor 上一次响应返回的 next_cursor 或 prev_cursor，为空时返回第一页
+	Cursor string `json:"cursor,omitempty"`
+	// SlugPrefix 按 slug 前缀过滤
+	SlugPrefix string `json:"slug_prefix,omitempty"`
+	// Internal 按 internal 标记过滤，为空时不过滤
+	Internal *bool `json:"internal,omitempty"`
+	// IncludeArchived 是否包含已归档的作业，默认不包含
+	IncludeArchived bool `json:"include_archived,omitempty"`
+}
+
+// SetJobStatusRequest 作业状态变更请求
+type SetJobStatusRequest struct {
+	Status    JobStatus `json:"status" validate:"required"`
+	ChangedBy string    `json:"changed_by" validate:"required"`
 }
 
 // TestJobRequest 作业测试请求
+// 指定 ExampleID 或 ExampleSlug 时使用该事件示例的 payload，Payload 作为覆盖项深度合并
 type TestJobRequest struct {
 	EnvironmentID uuid.UUID              `json:"environment_id" validate:"required"`
 	VersionID     uuid.UUID              `json:"version_id" validate:"required"`
+	ExampleID     uuid.UUID              `json:"example_id,omitempty"`
+	ExampleSlug   string                 `json:"example_slug,omitempty"`
 	Payload       map[string]interface{} `json:"payload,omitempty"`
 }
 
+// SetJobAliasRequest 创建或移动作业别名请求
+type SetJobAliasRequest struct {
+	JobID         uuid.UUID `json:"job_id" validate:"required"`
+	EnvironmentID uuid.UUID `json:"environment_id" validate:"required"`
+	Name          string    `json:"name" validate:"required"`
+	VersionID     uuid.UUID `json:"version_id" validate:"required"`
+	// Dispatch 为别名创建事件调度器，使匹配的事件投递到别名指向的版本
+	Dispatch bool `json:"dispatch"`
+}
+
+// PromoteJobAliasRequest 版本提升请求，将 To 别名移动到 From 别名当前指向的版本
+type PromoteJobAliasRequest struct {
+	JobID         uuid.UUID `json:"job_id" validate:"required"`
+	EnvironmentID uuid.UUID `json:"environment_id" validate:"required"`
+	From          string    `json:"from" validate:"required"`
+	To            string    `json:"to" validate:"required"`
+}
+
+// RollbackLatestRequest 回滚 latest 别名请求
+type RollbackLatestRequest struct {
+	JobID         uuid.UUID `json:"job_id" validate:"required"`
+	EnvironmentID uuid.UUID `json:"environment_id" validate:"required"`
+	// VersionID 回滚目标版本，为空时回滚到 latest 之前的最近版本
+	VersionID uuid.UUID `json:"version_id,omitempty"`
+}
+
 // CreateJobQueueRequest 创建队列请求
 type CreateJobQueueRequest struct {
 	Name          string    `json:"name" validate:"required"`
@@ -131,18 +207,28 @@ type CreateJobQueueRequest struct {
 	MaxJobs       int32     `json:"max_jobs"`
 }
 
+// SetEnvironmentMaxRunDurationRequest 设置环境默认运行最长持续时间请求
+type SetEnvironmentMaxRunDurationRequest struct {
+	EnvironmentID uuid.UUID `json:"environment_id" validate:"required"`
+	// MaxRunDuration 运行最长持续时间（秒），为 0 时清除默认值，运行不受限制
+	MaxRunDuration int `json:"max_run_duration" validate:"min=0"`
+}
+
 // Response DTOs
 type JobResponse struct {
-	ID             uuid.UUID            `json:"id"`
-	Slug           string               `json:"slug"`
-	Title          string               `json:"title"`
-	Internal       bool                 `json:"internal"`
-	OrganizationID uuid.UUID            `json:"organization_id"`
-	ProjectID      uuid.UUID            `json:"project_id"`
-	CreatedAt      time.Time            `json:"created_at"`
-	UpdatedAt      time.Time            `json:"updated_at"`
-	CurrentVersion *JobVersionResponse  `json:"current_version,omitempty"`
-	Versions       []JobVersionResponse `json:"versions,omitempty"`
+	ID              uuid.UUID            `json:"id"`
+	Slug            string               `json:"slug"`
+	Title           string               `json:"title"`
+	Internal        bool                 `json:"internal"`
+	OrganizationID  uuid.UUID            `json:"organization_id"`
+	ProjectID       uuid.UUID            `json:"project_id"`
+	Status          JobStatus            `json:"status"`
+	StatusChangedAt *time.Time           `json:"status_changed_at,omitempty"`
+	StatusChangedBy string               `json:"status_changed_by,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+	CurrentVersion  *JobVersionResponse  `json:"current_version,omitempty"`
+	Versions        []JobVersionResponse `json:"versions,omitempty"`
 }
 
 type JobVersionResponse struct {
@@ -152,10 +238,51 @@ type JobVersionResponse struct {
 	Properties         map[string]interface{} `json:"properties,omitempty"`
 	StartPosition      JobStartPosition       `json:"start_position"`
 	PreprocessRuns     bool                   `json:"preprocess_runs"`
+	MaxRunDuration     int                    `json:"max_run_duration,omitempty"`
 	CreatedAt          time.Time              `json:"created_at"`
 	UpdatedAt          time.Time              `json:"updated_at"`
 }
 
+// JobVersionChange 单个定义字段的变更
+type JobVersionChange struct {
+	Field    string          `json:"field"` // event | trigger | queue | integrations | maxRunDuration
+	Previous json.RawMessage `json:"previous,omitempty"`
+	Current  json.RawMessage `json:"current,omitempty"`
+}
+
+// JobVersionChangelogResponse 作业版本变更日志条目
+type JobVersionChangelogResponse struct {
+	ID              uuid.UUID          `json:"id"`
+	VersionID       uuid.UUID          `json:"version_id"`
+	Version         string             `json:"version"`
+	PreviousVersion string             `json:"previous_version,omitempty"`
+	Changes         []JobVersionChange `json:"changes"`
+	Overridden      bool               `json:"overridden"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type EventExampleResponse struct {
+	ID           uuid.UUID              `json:"id"`
+	JobVersionID uuid.UUID              `json:"job_version_id"`
+	Slug         string                 `json:"slug"`
+	Name         string                 `json:"name"`
+	Icon         string                 `json:"icon,omitempty"`
+	Payload      map[string]interface{} `json:"payload,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+type JobAliasResponse struct {
+	ID            uuid.UUID `json:"id"`
+	JobID         uuid.UUID `json:"job_id"`
+	VersionID     uuid.UUID `json:"version_id"`
+	EnvironmentID uuid.UUID `json:"environment_id"`
+	Name          string    `json:"name"`
+	Version       string    `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
 type JobQueueResponse struct {
 	ID            uuid.UUID `json:"id"`
 	Name          string    `json:"name"`
@@ -166,12 +293,17 @@ type JobQueueResponse struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+type EnvironmentMaxRunDurationResponse struct {
+	EnvironmentID  uuid.UUID `json:"environment_id"`
+	MaxRunDuration int       `json:"max_run_duration,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
 type ListJobsResponse struct {
-	Jobs    []JobResponse `json:"jobs"`
-	Total   int64         `json:"total"`
-	Limit   int32         `json:"limit"`
-	Offset  int32         `json:"offset"`
-	HasMore bool          `json:"has_more"`
+	Jobs       []JobResponse `json:"jobs"`
+	Limit      int32         `json:"limit"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	PrevCursor string        `json:"prev_cursor,omitempty"`
 }
 
 type ListJobVersionsResponse struct {
@@ -188,20 +320,25 @@ type TestJobResponse struct {
 
 // service 实现
 type service struct {
-	repo      Repository
-	eventsSvc events.Service
-	logger    *slog.Logger
+	repo          Repository
+	sharedQueries shared.Querier
+	eventsSvc     events.Service
+	runsSvc       runs.Service
+	logger        *slog.Logger
 }
 
 // NewService 创建服务实例
-func NewService(repo Repository, eventsSvc events.Service, logger *slog.Logger) Service {
+// runsSvc 用于作业测试创建运行以及作业恢复后启动等待中的运行，为 nil 时作业测试不可用
+func NewService(repo Repository, sharedQueries shared.Querier, eventsSvc events.Service, runsSvc runs.Service, logger *slog.Logger) Service {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	return &service{
-		repo:      repo,
-		eventsSvc: eventsSvc,
-		logger:    logger,
+		repo:          repo,
+		sharedQueries: sharedQueries,
+		eventsSvc:     eventsSvc,
+		runsSvc:       runsSvc,
+		logger:        logger,
 	}
 }
 
@@ -224,20 +361,29 @@ func (s *service) RegisterJob(ctx context.Context, endpointID uuid.UUID, req Reg
 
 	var result *JobResponse
 	err := s.repo.WithTx(ctx, func(txRepo Repository) error {
+		// 作业、队列和版本都归属端点所在的环境、组织和项目
+		endpoint, err := txRepo.GetEndpointScope(ctx, uuidToPgUUID(endpointID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("endpoint %s not found", endpointID)
+			}
+			return fmt.Errorf("failed to get endpoint: %w", err)
+		}
+
 		// 1. Upsert Job - 对齐 trigger.dev 的 #upsertJob 逻辑
-		job, err := s.upsertJob(ctx, txRepo, req, endpointID)
+		job, err := s.upsertJob(ctx, txRepo, req, endpoint)
 		if err != nil {
 			return fmt.Errorf("failed to upsert job: %w", err)
 		}
 
 		// 2. Upsert JobQueue - 对齐 queue 管理逻辑
-		jobQueue, err := s.upsertJobQueue(ctx, txRepo, req.Queue, endpointID)
+		jobQueue, err := s.upsertJobQueue(ctx, txRepo, req.Queue, endpoint)
 		if err != nil {
 			return fmt.Errorf("failed to upsert job queue: %w", err)
 		}
 
 		// 3. Upsert JobVersion - 核心版本管理逻辑
-		jobVersion, err := s.upsertJobVersion(ctx, txRepo, job, req, endpointID, jobQueue.ID)
+		jobVersion, err := s.upsertJobVersion(ctx, txRepo, job, req, endpoint, jobQueue.ID)
 		if err != nil {
 			return fmt.Errorf("failed to upsert job version: %w", err)
 		}
@@ -247,31 +393,28 @@ func (s *service) RegisterJob(ctx context.Context, endpointID uuid.UUID, req Reg
 			return fmt.Errorf("failed to manage event examples: %w", err)
 		}
 
-		// 5. 管理 JobAlias - 对齐别名管理逻辑（如果是最新版本）
+		// 5. 关联作业版本所需的集成，运行时据此检查连接
+		if err := s.linkJobVersionIntegrations(ctx, txRepo, job, jobVersion.ID, req.Integrations); err != nil {
+			return fmt.Errorf("failed to link job integrations: %w", err)
+		}
+
+		// 6. 管理 JobAlias - 对齐别名管理逻辑（如果是最新版本）
 		if err := s.manageJobAlias(ctx, txRepo, job, jobVersion, endpointID); err != nil {
 			return fmt.Errorf("failed to manage job alias: %w", err)
 		}
 
 		// 构造响应
-		result = &JobResponse{
-			ID:             pgUUIDToUUID(job.ID),
-			Slug:           job.Slug,
-			Title:          job.Title,
-			Internal:       job.Internal,
-			OrganizationID: pgUUIDToUUID(job.OrganizationID),
-			ProjectID:      pgUUIDToUUID(job.ProjectID),
-			CreatedAt:      job.CreatedAt.Time,
-			UpdatedAt:      job.UpdatedAt.Time,
-			CurrentVersion: &JobVersionResponse{
-				ID:                 pgUUIDToUUID(jobVersion.ID),
-				Version:            jobVersion.Version,
-				EventSpecification: jsonbToMap(jobVersion.EventSpecification),
-				Properties:         jsonbToMap(jobVersion.Properties),
-				StartPosition:      jobVersion.StartPosition,
-				PreprocessRuns:     jobVersion.PreprocessRuns,
-				CreatedAt:          jobVersion.CreatedAt.Time,
-				UpdatedAt:          jobVersion.UpdatedAt.Time,
-			},
+		result = convertJobToResponse(job)
+		result.CurrentVersion = &JobVersionResponse{
+			ID:                 pgUUIDToUUID(jobVersion.ID),
+			Version:            jobVersion.Version,
+			EventSpecification: jsonbToMap(jobVersion.EventSpecification),
+			Properties:         jsonbToMap(jobVersion.Properties),
+			StartPosition:      jobVersion.StartPosition,
+			PreprocessRuns:     jobVersion.PreprocessRuns,
+			MaxRunDuration:     int(jobVersion.MaxRunDurationSeconds.Int32),
+			CreatedAt:          jobVersion.CreatedAt.Time,
+			UpdatedAt:          jobVersion.UpdatedAt.Time,
 		}
 
 		return nil
@@ -296,16 +439,7 @@ func (s *service) GetJob(ctx context.Context, id uuid.UUID) (*JobResponse, error
 		return nil, fmt.Errorf("failed to get job: %w", err)
 	}
 
-	return &JobResponse{
-		ID:             pgUUIDToUUID(job.ID),
-		Slug:           job.Slug,
-		Title:          job.Title,
-		Internal:       job.Internal,
-		OrganizationID: pgUUIDToUUID(job.OrganizationID),
-		ProjectID:      pgUUIDToUUID(job.ProjectID),
-		CreatedAt:      job.CreatedAt.Time,
-		UpdatedAt:      job.UpdatedAt.Time,
-	}, nil
+	return convertJobToResponse(job), nil
 }
 
 // GetJobBySlug 根据 slug 获取作业
@@ -318,54 +452,57 @@ func (s *service) GetJobBySlug(ctx context.Context, projectID uuid.UUID, slug st
 		return nil, fmt.Errorf("failed to get job by slug: %w", err)
 	}
 
-	return &JobResponse{
-		ID:             pgUUIDToUUID(job.ID),
-		Slug:           job.Slug,
-		Title:          job.Title,
-		Internal:       job.Internal,
-		OrganizationID: pgUUIDToUUID(job.OrganizationID),
-		ProjectID:      pgUUIDToUUID(job.ProjectID),
-		CreatedAt:      job.CreatedAt.Time,
-		UpdatedAt:      job.UpdatedAt.Time,
-	}, nil
+	return convertJobToResponse(job), nil
 }
 
-// ListJobs 列出作业
+// ListJobs 按游标分页列出作业
 func (s *service) ListJobs(ctx context.Context, params ListJobsParams) (*ListJobsResponse, error) {
-	jobs, err := s.repo.ListJobsByProject(ctx, ListJobsByProjectParams{
-		ProjectID: uuidToPgUUID(params.ProjectID),
-		Limit:     params.Limit,
-		Offset:    params.Offset,
-	})
+	cursor, err := pagination.Decode(params.Cursor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list jobs: %w", err)
+		return nil, err
 	}
+	limit := pagination.NormalizeLimit(params.Limit)
 
-	total, err := s.repo.CountJobsByProject(ctx, uuidToPgUUID(params.ProjectID))
+	pageParams := ListJobsPageParams{
+		ProjectID:       uuidToPgUUID(params.ProjectID),
+		IncludeArchived: params.IncludeArchived,
+		PageSize:        limit + 1,
+	}
+	if params.SlugPrefix != "" {
+		pageParams.SlugPrefix = pgtype.Text{String: pagination.EscapeLike(params.SlugPrefix), Valid: true}
+	}
+	if params.Internal != nil {
+		pageParams.Internal = pgtype.Bool{Bool: *params.Internal, Valid: true}
+	}
+	if cursor != nil {
+		pageParams.CursorCreatedAt = pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true}
+		pageParams.CursorID = uuidToPgUUID(cursor.ID)
+	}
+
+	var jobs []Jobs
+	if cursor.Backward() {
+		jobs, err = s.repo.ListJobsPageReverse(ctx, ListJobsPageReverseParams(pageParams))
+	} else {
+		jobs, err = s.repo.ListJobsPage(ctx, pageParams)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to count jobs: %w", err)
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
 	}
 
-	var jobResponses []JobResponse
+	jobs, next, prev := pagination.Page(jobs, limit, cursor, func(job Jobs) (time.Time, uuid.UUID) {
+		return job.CreatedAt.Time, job.ID.Bytes
+	})
+
+	jobResponses := make([]JobResponse, 0, len(jobs))
 	for _, job := range jobs {
-		jobResponses = append(jobResponses, JobResponse{
-			ID:             pgUUIDToUUID(job.ID),
-			Slug:           job.Slug,
-			Title:          job.Title,
-			Internal:       job.Internal,
-			OrganizationID: pgUUIDToUUID(job.OrganizationID),
-			ProjectID:      pgUUIDToUUID(job.ProjectID),
-			CreatedAt:      job.CreatedAt.Time,
-			UpdatedAt:      job.UpdatedAt.Time,
-		})
+		jobResponses = append(jobResponses, *convertJobToResponse(job))
 	}
 
 	return &ListJobsResponse{
-		Jobs:    jobResponses,
-		Total:   total,
-		Limit:   params.Limit,
-		Offset:  params.Offset,
-		HasMore: params.Offset+params.Limit < int32(total),
+		Jobs:       jobResponses,
+		Limit:      limit,
+		NextCursor: next,
+		PrevCursor: prev,
 	}, nil
 }
 
@@ -397,6 +534,7 @@ func (s *service) GetJobVersion(ctx context.Context, id uuid.UUID) (*JobVersionR
 		Properties:         jsonbToMap(version.Properties),
 		StartPosition:      version.StartPosition,
 		PreprocessRuns:     version.PreprocessRuns,
+		MaxRunDuration:     int(version.MaxRunDurationSeconds.Int32),
 		CreatedAt:          version.CreatedAt.Time,
 		UpdatedAt:          version.UpdatedAt.Time,
 	}, nil
@@ -422,6 +560,7 @@ func (s *service) ListJobVersions(ctx context.Context, jobID uuid.UUID) (*ListJo
 			Properties:         jsonbToMap(version.Properties),
 			StartPosition:      version.StartPosition,
 			PreprocessRuns:     version.PreprocessRuns,
+			MaxRunDuration:     int(version.MaxRunDurationSeconds.Int32),
 			CreatedAt:          version.CreatedAt.Time,
 			UpdatedAt:          version.UpdatedAt.Time,
 		})
@@ -433,6 +572,270 @@ func (s *service) ListJobVersions(ctx context.Context, jobID uuid.UUID) (*ListJo
 	}, nil
 }
 
+// ListEventExamples 列出作业版本的事件示例，可作为 TestJob 的 payload 预设
+func (s *service) ListEventExamples(ctx context.Context, versionID uuid.UUID) ([]EventExampleResponse, error) {
+	examples, err := s.repo.ListEventExamplesByJobVersion(ctx, uuidToPgUUID(versionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event examples: %w", err)
+	}
+
+	responses := make([]EventExampleResponse, 0, len(examples))
+	for _, example := range examples {
+		responses = append(responses, convertEventExampleToResponse(example))
+	}
+	return responses, nil
+}
+
+// ListJobVersionChangelog 列出作业的版本变更日志，按时间倒序
+func (s *service) ListJobVersionChangelog(ctx context.Context, jobID uuid.UUID) ([]JobVersionChangelogResponse, error) {
+	changelogs, err := s.repo.ListJobVersionChangelogs(ctx, ListJobVersionChangelogsParams{
+		JobID: uuidToPgUUID(jobID),
+		Limit: 100, // 默认限制
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job version changelog: %w", err)
+	}
+
+	responses := make([]JobVersionChangelogResponse, 0, len(changelogs))
+	for _, changelog := range changelogs {
+		responses = append(responses, convertChangelogToResponse(changelog))
+	}
+	return responses, nil
+}
+
+// SetJobStatus 变更作业生命周期状态，记录操作者与时间
+// PAUSED 作业的运行保持 QUEUED，恢复时启动等待中的运行；ARCHIVED 作业不再匹配事件
+func (s *service) SetJobStatus(ctx context.Context, id uuid.UUID, req SetJobStatusRequest) (*JobResponse, error) {
+	logger := s.logger.With("operation", "set_job_status", "job_id", id.String(), "status", req.Status)
+
+	switch req.Status {
+	case JobStatusENABLED, JobStatusPAUSED, JobStatusARCHIVED:
+	default:
+		return nil, fmt.Errorf("invalid request: unknown job status %q", req.Status)
+	}
+	if req.ChangedBy == "" {
+		return nil, fmt.Errorf("invalid request: changed_by is required")
+	}
+
+	var previous JobStatus
+	var job Jobs
+	err := s.repo.WithTx(ctx, func(txRepo Repository) error {
+		current, err := txRepo.GetJobByID(ctx, uuidToPgUUID(id))
+		if err != nil {
+			return fmt.Errorf("failed to get job: %w", err)
+		}
+		previous = current.Status
+
+		job, err = txRepo.UpdateJobStatus(ctx, UpdateJobStatusParams{
+			ID:              current.ID,
+			Status:          req.Status,
+			StatusChangedBy: pgtype.Text{String: req.ChangedBy, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update job status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set job status", "error", err)
+		return nil, err
+	}
+
+	// 暂停期间保持排队的运行在状态变更提交后启动
+	if previous == JobStatusPAUSED && job.Status != JobStatusPAUSED && s.runsSvc != nil {
+		if err := s.runsSvc.StartJobRuns(ctx, id.String()); err != nil {
+			logger.Error("Failed to start held runs", "error", err)
+			return nil, fmt.Errorf("failed to start held runs: %w", err)
+		}
+	}
+
+	logger.Info("Job status changed", "previous_status", previous, "changed_by", req.ChangedBy)
+	return convertJobToResponse(job), nil
+}
+
+// SetJobAlias 创建或移动作业别名，并在同一事务中同步以该别名为目标的事件调度器
+func (s *service) SetJobAlias(ctx context.Context, req SetJobAliasRequest) (*JobAliasResponse, error) {
+	logger := s.logger.With(
+		"operation", "set_job_alias",
+		"job_id", req.JobID.String(),
+		"alias", req.Name,
+		"version_id", req.VersionID.String(),
+	)
+
+	if err := validateAliasName(req.Name); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	var alias JobAliases
+	err := s.repo.WithTx(ctx, func(txRepo Repository) error {
+		version, err := txRepo.GetJobVersionByID(ctx, uuidToPgUUID(req.VersionID))
+		if err != nil {
+			return fmt.Errorf("failed to get job version: %w", err)
+		}
+
+		alias, err = s.moveJobAlias(ctx, txRepo, uuidToPgUUID(req.JobID), uuidToPgUUID(req.EnvironmentID), req.Name, version)
+		if err != nil {
+			return err
+		}
+
+		if req.Dispatch {
+			return s.upsertAliasDispatcher(ctx, txRepo, alias, version)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set job alias", "error", err)
+		return nil, err
+	}
+
+	logger.Info("Job alias set", "version", alias.Value)
+	return convertAliasToResponse(alias), nil
+}
+
+// PromoteJobAlias 将 To 别名提升到 From 别名当前指向的版本，例如 canary -> stable
+func (s *service) PromoteJobAlias(ctx context.Context, req PromoteJobAliasRequest) (*JobAliasResponse, error) {
+	logger := s.logger.With("operation", "promote_job_alias", "job_id", req.JobID.String(), "from", req.From, "to", req.To)
+
+	if err := validateAliasName(req.To); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.From == req.To {
+		return nil, fmt.Errorf("invalid request: cannot promote alias %q to itself", req.From)
+	}
+
+	var alias JobAliases
+	err := s.repo.WithTx(ctx, func(txRepo Repository) error {
+		source, err := txRepo.GetJobAliasByName(ctx, GetJobAliasByNameParams{
+			JobID:         uuidToPgUUID(req.JobID),
+			EnvironmentID: uuidToPgUUID(req.EnvironmentID),
+			Name:          req.From,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get job alias %q: %w", req.From, err)
+		}
+
+		version, err := txRepo.GetJobVersionByID(ctx, source.VersionID)
+		if err != nil {
+			return fmt.Errorf("failed to get job version: %w", err)
+		}
+
+		alias, err = s.moveJobAlias(ctx, txRepo, source.JobID, source.EnvironmentID, req.To, version)
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to promote job alias", "error", err)
+		return nil, err
+	}
+
+	logger.Info("Job alias promoted", "version", alias.Value)
+	return convertAliasToResponse(alias), nil
+}
+
+// RollbackLatest 将 latest 别名回滚到之前的版本
+// 回滚后重新注册已有版本不会移动 latest，只有之后新注册的版本才会再次成为 latest
+func (s *service) RollbackLatest(ctx context.Context, req RollbackLatestRequest) (*JobAliasResponse, error) {
+	logger := s.logger.With("operation", "rollback_latest", "job_id", req.JobID.String())
+
+	jobID := uuidToPgUUID(req.JobID)
+	environmentID := uuidToPgUUID(req.EnvironmentID)
+
+	var alias JobAliases
+	err := s.repo.WithTx(ctx, func(txRepo Repository) error {
+		var version JobVersions
+		var err error
+
+		if req.VersionID != uuid.Nil {
+			version, err = txRepo.GetJobVersionByID(ctx, uuidToPgUUID(req.VersionID))
+			if err != nil {
+				return fmt.Errorf("failed to get job version: %w", err)
+			}
+		} else {
+			latest, err := txRepo.GetJobAliasByName(ctx, GetJobAliasByNameParams{
+				JobID:         jobID,
+				EnvironmentID: environmentID,
+				Name:          LatestAliasName,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get latest alias: %w", err)
+			}
+
+			version, err = txRepo.GetPreviousJobVersion(ctx, GetPreviousJobVersionParams{
+				JobID:         jobID,
+				EnvironmentID: environmentID,
+				Version:       latest.Value,
+			})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return fmt.Errorf("no version before %s to roll back to", latest.Value)
+				}
+				return fmt.Errorf("failed to get previous job version: %w", err)
+			}
+		}
+
+		alias, err = s.moveJobAlias(ctx, txRepo, jobID, environmentID, LatestAliasName, version)
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to roll back latest alias", "error", err)
+		return nil, err
+	}
+
+	logger.Info("Latest alias rolled back", "version", alias.Value)
+	return convertAliasToResponse(alias), nil
+}
+
+// ListJobAliases 列出作业在环境中的别名
+func (s *service) ListJobAliases(ctx context.Context, jobID, environmentID uuid.UUID) ([]JobAliasResponse, error) {
+	aliases, err := s.repo.ListJobAliasesByJob(ctx, ListJobAliasesByJobParams{
+		JobID:         uuidToPgUUID(jobID),
+		EnvironmentID: uuidToPgUUID(environmentID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job aliases: %w", err)
+	}
+
+	responses := make([]JobAliasResponse, 0, len(aliases))
+	for _, alias := range aliases {
+		responses = append(responses, *convertAliasToResponse(alias))
+	}
+	return responses, nil
+}
+
+// DeleteJobAlias 删除作业别名及以其为目标的事件调度器，latest 别名由注册流程维护，不允许删除
+func (s *service) DeleteJobAlias(ctx context.Context, jobID, environmentID uuid.UUID, name string) error {
+	logger := s.logger.With("operation", "delete_job_alias", "job_id", jobID.String(), "alias", name)
+
+	if name == LatestAliasName {
+		return fmt.Errorf("invalid request: alias %q cannot be deleted", LatestAliasName)
+	}
+
+	err := s.repo.WithTx(ctx, func(txRepo Repository) error {
+		alias, err := txRepo.GetJobAliasByName(ctx, GetJobAliasByNameParams{
+			JobID:         uuidToPgUUID(jobID),
+			EnvironmentID: uuidToPgUUID(environmentID),
+			Name:          name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get job alias %q: %w", name, err)
+		}
+
+		if err := txRepo.DeleteJobAliasEventDispatchers(ctx, pgUUIDToUUID(alias.ID).String()); err != nil {
+			return fmt.Errorf("failed to delete alias event dispatchers: %w", err)
+		}
+		if err := txRepo.DeleteJobAlias(ctx, alias.ID); err != nil {
+			return fmt.Errorf("failed to delete job alias: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to delete job alias", "error", err)
+		return err
+	}
+
+	logger.Info("Job alias deleted")
+	return nil
+}
+
 // GetJobQueue 获取作业队列
 func (s *service) GetJobQueue(ctx context.Context, environmentID uuid.UUID, name string) (*JobQueueResponse, error) {
 	queue, err := s.repo.GetJobQueueByName(ctx, GetJobQueueByNameParams{
@@ -482,7 +885,48 @@ func (s *service) CreateJobQueue(ctx context.Context, req CreateJobQueueRequest)
 	}, nil
 }
 
+// SetEnvironmentMaxRunDuration 设置环境的默认运行最长持续时间，超时监控对未设置 maxRunDuration 的版本使用该值
+func (s *service) SetEnvironmentMaxRunDuration(ctx context.Context, req SetEnvironmentMaxRunDurationRequest) (*EnvironmentMaxRunDurationResponse, error) {
+	logger := s.logger.With(
+		"operation", "set_environment_max_run_duration",
+		"environment_id", req.EnvironmentID.String(),
+		"max_run_duration", req.MaxRunDuration,
+	)
+
+	if req.EnvironmentID == uuid.Nil {
+		return nil, fmt.Errorf("invalid request: environment ID is required")
+	}
+	if req.MaxRunDuration < 0 || req.MaxRunDuration > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid request: max run duration must be between 0 and %d seconds", math.MaxInt32)
+	}
+
+	var duration pgtype.Int4
+	if req.MaxRunDuration > 0 {
+		duration = pgtype.Int4{Int32: int32(req.MaxRunDuration), Valid: true}
+	}
+
+	env, err := s.sharedQueries.UpdateRuntimeEnvironmentMaxRunDuration(ctx, shared.UpdateRuntimeEnvironmentMaxRunDurationParams{
+		ID:                           uuidToPgUUID(req.EnvironmentID),
+		DefaultMaxRunDurationSeconds: duration,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("environment %s not found", req.EnvironmentID)
+		}
+		logger.Error("Failed to set environment max run duration", "error", err)
+		return nil, fmt.Errorf("failed to set environment max run duration: %w", err)
+	}
+
+	logger.Info("Environment max run duration updated")
+	return &EnvironmentMaxRunDurationResponse{
+		EnvironmentID:  pgUUIDToUUID(env.ID),
+		MaxRunDuration: int(env.DefaultMaxRunDurationSeconds.Int32),
+		UpdatedAt:      env.UpdatedAt.Time,
+	}, nil
+}
+
 // TestJob 测试作业，对齐 trigger.dev TestJobService
+// 测试事件不经过调度器匹配，直接为请求的版本创建运行，过滤条件不匹配时同样可以测试
 func (s *service) TestJob(ctx context.Context, req TestJobRequest) (*TestJobResponse, error) {
 	logger := s.logger.With(
 		"operation", "test_job",
@@ -491,92 +935,103 @@ func (s *service) TestJob(ctx context.Context, req TestJobRequest) (*TestJobResp
 	)
 	logger.Info("Starting job test")
 
+	if s.runsSvc == nil {
+		return nil, fmt.Errorf("runs service not configured")
+	}
+
 	// 获取作业版本信息
 	version, err := s.repo.GetJobVersionByID(ctx, uuidToPgUUID(req.VersionID))
 	if err != nil {
 		logger.Error("Failed to get job version", "error", err)
 		return nil, fmt.Errorf("failed to get job version: %w", err)
 	}
-
-	// 解析事件规范
-	eventSpec := jsonbToMap(version.EventSpecification)
-	eventName, ok := eventSpec["name"].(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid event specification: missing name")
+	if version.EnvironmentID != uuidToPgUUID(req.EnvironmentID) {
+		return nil, fmt.Errorf("job version %s does not belong to environment %s", req.VersionID, req.EnvironmentID)
 	}
 
-	// 获取事件源（可选）
-	eventSource := "trigger.dev"
-	if source, exists := eventSpec["source"].(string); exists && source != "" {
-		eventSource = source
+	// 解析事件规范
+	eventName, eventSource, err := eventNameAndSource(version)
+	if err != nil {
+		return nil, err
 	}
 
-	// 生成测试事件ID
-	eventID := uuid.New().String()
-
-	// 构造事件上下文，标记为测试事件
-	contextData := map[string]interface{}{
-		"test":        true,
-		"job_version": version.Version,
-		"source":      "job_test",
+	// 解析测试 payload，使用事件示例时与请求的 payload 深度合并
+	payload, err := s.resolveTestPayload(ctx, version, req)
+	if err != nil {
+		return nil, err
 	}
 
-	// 使用 payload，如果没有则使用空 map
-	payload := req.Payload
-	if payload == nil {
-		payload = make(map[string]interface{})
+	// 加载运行环境及其项目、组织
+	env, err := s.sharedQueries.GetEnvironmentWithProjectAndOrg(ctx, uuidToPgUUID(req.EnvironmentID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("environment %s not found", req.EnvironmentID)
+		}
+		logger.Error("Failed to get environment", "error", err)
+		return nil, fmt.Errorf("failed to get environment: %w", err)
 	}
 
-	// 创建发送事件请求
+	// 构造事件上下文，标记为测试事件
 	sendEventReq := &events.SendEventRequest{
-		ID:      eventID,
+		ID:      uuid.New().String(),
 		Name:    eventName,
 		Source:  eventSource,
 		Payload: payload,
-		Context: contextData,
+		Context: map[string]interface{}{
+			"test":        true,
+			"job_version": version.Version,
+			"source":      "job_test",
+		},
 	}
 
-	// 创建发送事件选项
+	// 测试事件不加入 deliverEvent，运行由下面直接创建
+	isTest := true
 	sendEventOpts := &events.SendEventOptions{
-		// 测试事件立即投递
-		DeliverAt: nil,
-	}
-
-	// 构造认证环境（需要从 EnvironmentID 获取）
-	// TODO: 这里需要从实际的环境服务获取环境信息
-	// 暂时使用模拟的环境信息
-	authenticatedEnv := &apiauth.AuthenticatedEnvironment{
-		Environment: apiauth.RuntimeEnvironment{
-			ID:             uuidToPgUUID(req.EnvironmentID),
-			OrganizationID: uuidToPgUUID(uuid.New()), // TODO: 从环境服务获取真实的组织ID
-			ProjectID:      uuidToPgUUID(uuid.New()), // TODO: 从环境服务获取真实的项目ID
-		},
+		Test:         &isTest,
+		SkipDelivery: true,
 	}
 
 	// 通过 Events 服务创建事件记录
-	eventRecord, err := s.eventsSvc.IngestSendEvent(ctx, authenticatedEnv, sendEventReq, sendEventOpts)
+	eventRecord, err := s.eventsSvc.IngestSendEvent(ctx, convertEnvironmentToAuthenticated(env), sendEventReq, sendEventOpts)
 	if err != nil {
 		logger.Error("Failed to create event record", "error", err)
 		return nil, fmt.Errorf("failed to create event record: %w", err)
 	}
 
+	// 为请求的版本创建运行，对齐 trigger.dev TestJobService 中的 CreateRunService.call
+	run, err := s.runsSvc.CreateRun(ctx, &runs.CreateRunRequest{
+		EventRecordID: eventRecord.ID,
+		VersionID:     req.VersionID.String(),
+		IsTest:        true,
+	})
+	if err != nil {
+		logger.Error("Failed to create test run", "error", err)
+		// 事件与运行分属两个服务的事务，运行创建失败时删除已写入的测试事件
+		if deleteErr := s.eventsSvc.DeleteEventRecord(context.WithoutCancel(ctx), eventRecord.ID); deleteErr != nil {
+			logger.Error("Failed to delete orphaned test event", "event_record_id", eventRecord.ID, "error", deleteErr)
+		}
+		return nil, fmt.Errorf("failed to create test run: %w", err)
+	}
+
+	runID, err := uuid.Parse(run.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse run ID: %w", err)
+	}
+	eventID, err := uuid.Parse(eventRecord.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event ID: %w", err)
+	}
+
 	logger.Info("Job test completed successfully",
 		"event_id", eventRecord.EventID,
 		"event_record_id", eventRecord.ID,
-		"version_id", version.ID.String(),
+		"run_id", run.ID,
 		"event_name", eventName)
 
-	// 解析事件记录ID为UUID
-	eventUUID, parseErr := uuid.Parse(eventRecord.EventID)
-	if parseErr != nil {
-		logger.Error("Failed to parse event ID", "error", parseErr)
-		return nil, fmt.Errorf("failed to parse event ID: %w", parseErr)
-	}
-
 	return &TestJobResponse{
-		RunID:   uuid.New(), // TODO: 等待 Runs 服务集成后返回真实的 run ID
-		EventID: eventUUID,
-		Status:  "pending",
+		RunID:   runID,
+		EventID: eventID,
+		Status:  strings.ToLower(string(run.Status)),
 		Message: "Test job submitted successfully",
 	}, nil
 }
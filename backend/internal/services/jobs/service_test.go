@@ -2,14 +2,20 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
 	"testing"
 	"time"
 
 	"kongflow/backend/internal/services/apiauth"
 	"kongflow/backend/internal/services/events"
+	"kongflow/backend/internal/services/pagination"
+	"kongflow/backend/internal/services/runs"
+	"kongflow/backend/internal/shared"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,6 +37,11 @@ func (m *MockRepository) UpsertJob(ctx context.Context, params UpsertJobParams)
 	return args.Get(0).(Jobs), args.Error(1)
 }
 
+func (m *MockRepository) GetEndpointScope(ctx context.Context, endpointID pgtype.UUID) (GetEndpointScopeRow, error) {
+	args := m.Called(ctx, endpointID)
+	return args.Get(0).(GetEndpointScopeRow), args.Error(1)
+}
+
 func (m *MockRepository) UpsertJobQueue(ctx context.Context, params UpsertJobQueueParams) (JobQueues, error) {
 	args := m.Called(ctx, params)
 	return args.Get(0).(JobQueues), args.Error(1)
@@ -67,13 +78,25 @@ func (m *MockRepository) ListJobsByProject(ctx context.Context, params ListJobsB
 	args := m.Called(ctx, params)
 	return args.Get(0).([]Jobs), args.Error(1)
 }
-func (m *MockRepository) CountJobsByProject(ctx context.Context, projectID pgtype.UUID) (int64, error) {
-	args := m.Called(ctx, projectID)
+func (m *MockRepository) ListJobsPage(ctx context.Context, params ListJobsPageParams) ([]Jobs, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]Jobs), args.Error(1)
+}
+func (m *MockRepository) ListJobsPageReverse(ctx context.Context, params ListJobsPageReverseParams) ([]Jobs, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]Jobs), args.Error(1)
+}
+func (m *MockRepository) CountJobsByProject(ctx context.Context, params CountJobsByProjectParams) (int64, error) {
+	args := m.Called(ctx, params)
 	return args.Get(0).(int64), args.Error(1)
 }
 func (m *MockRepository) UpdateJob(ctx context.Context, params UpdateJobParams) (Jobs, error) {
 	return Jobs{}, nil
 }
+func (m *MockRepository) UpdateJobStatus(ctx context.Context, params UpdateJobStatusParams) (Jobs, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(Jobs), args.Error(1)
+}
 func (m *MockRepository) DeleteJob(ctx context.Context, id pgtype.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -86,7 +109,28 @@ func (m *MockRepository) GetJobVersionByID(ctx context.Context, id pgtype.UUID)
 	return args.Get(0).(JobVersions), args.Error(1)
 }
 func (m *MockRepository) GetJobVersionByJobAndVersion(ctx context.Context, params GetJobVersionByJobAndVersionParams) (JobVersions, error) {
-	return JobVersions{}, nil
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobVersions), args.Error(1)
+}
+func (m *MockRepository) UpsertIntegration(ctx context.Context, params UpsertIntegrationParams) (Integrations, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(Integrations), args.Error(1)
+}
+func (m *MockRepository) CreateJobVersionIntegration(ctx context.Context, params CreateJobVersionIntegrationParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+func (m *MockRepository) DeleteJobVersionIntegrations(ctx context.Context, jobVersionID pgtype.UUID) error {
+	args := m.Called(ctx, jobVersionID)
+	return args.Error(0)
+}
+func (m *MockRepository) CreateJobVersionChangelog(ctx context.Context, params CreateJobVersionChangelogParams) (JobVersionChangelogs, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobVersionChangelogs), args.Error(1)
+}
+func (m *MockRepository) ListJobVersionChangelogs(ctx context.Context, params ListJobVersionChangelogsParams) ([]JobVersionChangelogs, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]JobVersionChangelogs), args.Error(1)
 }
 func (m *MockRepository) ListJobVersionsByJob(ctx context.Context, params ListJobVersionsByJobParams) ([]JobVersions, error) {
 	args := m.Called(ctx, params)
@@ -125,14 +169,19 @@ func (m *MockEventsService) GetEventRecord(ctx context.Context, id string) (*eve
 	return args.Get(0).(*events.EventRecordResponse), args.Error(1)
 }
 
-func (m *MockEventsService) ListEventRecords(ctx context.Context, params events.ListEventRecordsParams) (*events.ListEventRecordsResponse, error) {
-	args := m.Called(ctx, params)
+func (m *MockEventsService) ListEventRecords(ctx context.Context, req events.ListEventRecordsRequest) (*events.ListEventRecordsResponse, error) {
+	args := m.Called(ctx, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*events.ListEventRecordsResponse), args.Error(1)
 }
 
+func (m *MockEventsService) DeleteEventRecord(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockEventsService) GetEventDispatcher(ctx context.Context, id string) (*events.EventDispatcherResponse, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -149,17 +198,70 @@ func (m *MockEventsService) ListEventDispatchers(ctx context.Context, params eve
 	return args.Get(0).(*events.ListEventDispatchersResponse), args.Error(1)
 }
 
+// MockRunsService 是 Runs 服务的模拟实现，仅作业恢复相关方法有期望
+type MockRunsService struct {
+	mock.Mock
+	runs.Service
+}
+
+func (m *MockRunsService) CreateRun(ctx context.Context, req *runs.CreateRunRequest) (*runs.RunResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*runs.RunResponse), args.Error(1)
+}
+
+func (m *MockRunsService) StartJobRuns(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+// MockSharedQueries 是共享查询的模拟实现，仅环境查询和环境运行限制有期望
+type MockSharedQueries struct {
+	mock.Mock
+	shared.Querier
+}
+
+func (m *MockSharedQueries) GetEnvironmentWithProjectAndOrg(ctx context.Context, id pgtype.UUID) (shared.GetEnvironmentWithProjectAndOrgRow, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(shared.GetEnvironmentWithProjectAndOrgRow), args.Error(1)
+}
+
+func (m *MockSharedQueries) UpdateRuntimeEnvironmentMaxRunDuration(ctx context.Context, arg shared.UpdateRuntimeEnvironmentMaxRunDurationParams) (shared.RuntimeEnvironments, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(shared.RuntimeEnvironments), args.Error(1)
+}
+
+// createTestEnvironment 创建与环境ID对应的环境查询结果
+func createTestEnvironment(environmentID uuid.UUID) shared.GetEnvironmentWithProjectAndOrgRow {
+	orgID := uuidToPgUUID(uuid.New())
+	projectID := uuidToPgUUID(uuid.New())
+	return shared.GetEnvironmentWithProjectAndOrgRow{
+		ID:             uuidToPgUUID(environmentID),
+		Slug:           "dev",
+		ApiKey:         "tr_dev_test",
+		Type:           "DEVELOPMENT",
+		OrganizationID: orgID,
+		ProjectID:      projectID,
+		ProjectID_2:    projectID,
+		ProjectSlug:    "test-project",
+		OrgID:          orgID,
+		OrgSlug:        "test-org",
+	}
+}
+
 // 辅助函数：创建测试用的服务
 func createTestService() Service {
 	mockRepo := &MockRepository{}
 	mockEvents := &MockEventsService{}
-	return NewService(mockRepo, mockEvents, slog.Default())
+	return NewService(mockRepo, nil, mockEvents, nil, slog.Default())
 }
 
 func createTestServiceWithMocks() (Service, *MockRepository, *MockEventsService) {
 	mockRepo := &MockRepository{}
 	mockEvents := &MockEventsService{}
-	service := NewService(mockRepo, mockEvents, slog.Default())
+	service := NewService(mockRepo, nil, mockEvents, nil, slog.Default())
 	return service, mockRepo, mockEvents
 }
 
@@ -202,16 +304,37 @@ func (m *MockRepository) GetJobAliasByID(ctx context.Context, id pgtype.UUID) (J
 	return JobAliases{}, nil
 }
 func (m *MockRepository) GetJobAliasByName(ctx context.Context, params GetJobAliasByNameParams) (JobAliases, error) {
-	return JobAliases{}, nil
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobAliases), args.Error(1)
 }
 func (m *MockRepository) UpsertJobAlias(ctx context.Context, params UpsertJobAliasParams) (JobAliases, error) {
 	args := m.Called(ctx, params)
 	return args.Get(0).(JobAliases), args.Error(1)
 }
 func (m *MockRepository) ListJobAliasesByJob(ctx context.Context, params ListJobAliasesByJobParams) ([]JobAliases, error) {
-	return nil, nil
+	args := m.Called(ctx, params)
+	return args.Get(0).([]JobAliases), args.Error(1)
+}
+func (m *MockRepository) DeleteJobAlias(ctx context.Context, id pgtype.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+func (m *MockRepository) UpsertJobAliasEventDispatcher(ctx context.Context, params UpsertJobAliasEventDispatcherParams) (pgtype.UUID, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(pgtype.UUID), args.Error(1)
+}
+func (m *MockRepository) UpdateJobAliasEventDispatchers(ctx context.Context, params UpdateJobAliasEventDispatchersParams) (int64, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockRepository) DeleteJobAliasEventDispatchers(ctx context.Context, dispatchableID string) error {
+	args := m.Called(ctx, dispatchableID)
+	return args.Error(0)
+}
+func (m *MockRepository) GetPreviousJobVersion(ctx context.Context, params GetPreviousJobVersionParams) (JobVersions, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobVersions), args.Error(1)
 }
-func (m *MockRepository) DeleteJobAlias(ctx context.Context, id pgtype.UUID) error { return nil }
 func (m *MockRepository) DeleteJobAliasesByJob(ctx context.Context, jobID pgtype.UUID) error {
 	return nil
 }
@@ -219,16 +342,19 @@ func (m *MockRepository) CreateEventExample(ctx context.Context, params CreateEv
 	return EventExamples{}, nil
 }
 func (m *MockRepository) GetEventExampleByID(ctx context.Context, id pgtype.UUID) (EventExamples, error) {
-	return EventExamples{}, nil
+	args := m.Called(ctx, id)
+	return args.Get(0).(EventExamples), args.Error(1)
 }
 func (m *MockRepository) GetEventExampleBySlug(ctx context.Context, params GetEventExampleBySlugParams) (EventExamples, error) {
-	return EventExamples{}, nil
+	args := m.Called(ctx, params)
+	return args.Get(0).(EventExamples), args.Error(1)
 }
 func (m *MockRepository) UpsertEventExample(ctx context.Context, params UpsertEventExampleParams) (EventExamples, error) {
 	return EventExamples{}, nil
 }
 func (m *MockRepository) ListEventExamplesByJobVersion(ctx context.Context, jobVersionID pgtype.UUID) ([]EventExamples, error) {
-	return nil, nil
+	args := m.Called(ctx, jobVersionID)
+	return args.Get(0).([]EventExamples), args.Error(1)
 }
 func (m *MockRepository) DeleteEventExample(ctx context.Context, id pgtype.UUID) error { return nil }
 func (m *MockRepository) DeleteEventExamplesByJobVersion(ctx context.Context, jobVersionID pgtype.UUID) error {
@@ -273,6 +399,15 @@ func createTestJobVersion() JobVersions {
 	}
 }
 
+func createTestEndpoint() GetEndpointScopeRow {
+	return GetEndpointScopeRow{
+		ID:             uuidToPgUUID(uuid.New()),
+		EnvironmentID:  uuidToPgUUID(uuid.New()),
+		OrganizationID: uuidToPgUUID(uuid.New()),
+		ProjectID:      uuidToPgUUID(uuid.New()),
+	}
+}
+
 func createTestJobQueue() JobQueues {
 	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
 	return JobQueues{
@@ -290,7 +425,7 @@ func createTestJobQueue() JobQueues {
 
 func TestService_GetJob_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	jobID := uuid.New()
 	expectedJob := createTestJob()
@@ -310,7 +445,7 @@ func TestService_GetJob_Success(t *testing.T) {
 
 func TestService_GetJob_NotFound(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	jobID := uuid.New()
 	mockRepo.On("GetJobByID", mock.Anything, uuidToPgUUID(jobID)).Return(Jobs{}, assert.AnError)
@@ -325,11 +460,16 @@ func TestService_GetJob_NotFound(t *testing.T) {
 }
 
 func TestService_TestJob_Success(t *testing.T) {
-	service, mockRepo, mockEvents := createTestServiceWithMocks()
+	mockRepo := &MockRepository{}
+	mockShared := &MockSharedQueries{}
+	mockEvents := &MockEventsService{}
+	mockRuns := &MockRunsService{}
+	service := NewService(mockRepo, mockShared, mockEvents, mockRuns, slog.Default())
 
 	// 准备测试数据
 	versionID := uuid.New()
 	environmentID := uuid.New()
+	env := createTestEnvironment(environmentID)
 
 	testVersion := createTestJobVersion()
 	testVersion.ID = uuidToPgUUID(versionID)
@@ -338,16 +478,32 @@ func TestService_TestJob_Success(t *testing.T) {
 	// 确保 EventSpecification 包含正确的 JSON
 	testVersion.EventSpecification = []byte(`{"name":"test.event","source":"api","type":"object"}`)
 
-	// 设置 Repository mock 期望
 	mockRepo.On("GetJobVersionByID", mock.Anything, uuidToPgUUID(versionID)).Return(testVersion, nil)
+	mockShared.On("GetEnvironmentWithProjectAndOrg", mock.Anything, uuidToPgUUID(environmentID)).Return(env, nil)
 
-	// 设置 Events service mock 期望
+	// 事件使用真实环境，作为测试事件记录且不经过调度器
 	mockEventRecord := &events.EventRecordResponse{
-		ID:      "test-record-id",
+		ID:      uuid.New().String(),
 		EventID: uuid.New().String(),
 	}
-	mockEvents.On("IngestSendEvent", mock.Anything, mock.AnythingOfType("*apiauth.AuthenticatedEnvironment"),
-		mock.AnythingOfType("*events.SendEventRequest"), mock.AnythingOfType("*events.SendEventOptions")).Return(mockEventRecord, nil)
+	mockEvents.On("IngestSendEvent", mock.Anything,
+		mock.MatchedBy(func(authEnv *apiauth.AuthenticatedEnvironment) bool {
+			return authEnv.Environment.ProjectID == env.ProjectID &&
+				authEnv.Environment.OrganizationID == env.OrganizationID
+		}),
+		mock.MatchedBy(func(req *events.SendEventRequest) bool {
+			return req.Name == "test.event" && req.Source == "api"
+		}),
+		mock.MatchedBy(func(opts *events.SendEventOptions) bool {
+			return opts.SkipDelivery && opts.Test != nil && *opts.Test
+		})).Return(mockEventRecord, nil)
+
+	runID := uuid.New()
+	mockRuns.On("CreateRun", mock.Anything, &runs.CreateRunRequest{
+		EventRecordID: mockEventRecord.ID,
+		VersionID:     versionID.String(),
+		IsTest:        true,
+	}).Return(&runs.RunResponse{ID: runID.String(), Status: runs.JobRunStatusPENDING}, nil)
 
 	// 构建请求
 	request := TestJobRequest{
@@ -361,16 +517,69 @@ func TestService_TestJob_Success(t *testing.T) {
 
 	// 验证结果
 	require.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.NotEmpty(t, result.RunID)
-	assert.NotEmpty(t, result.EventID)
+	assert.Equal(t, runID, result.RunID)
+	assert.Equal(t, mockEventRecord.EventID, result.EventID.String())
 	assert.Equal(t, "pending", result.Status)
 	assert.Equal(t, "Test job submitted successfully", result.Message)
 
 	mockRepo.AssertExpectations(t)
+	mockShared.AssertExpectations(t)
+	mockEvents.AssertExpectations(t)
+	mockRuns.AssertExpectations(t)
+}
+
+func TestService_TestJob_RunFailureDeletesEvent(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockShared := &MockSharedQueries{}
+	mockEvents := &MockEventsService{}
+	mockRuns := &MockRunsService{}
+	service := NewService(mockRepo, mockShared, mockEvents, mockRuns, slog.Default())
+
+	versionID := uuid.New()
+	environmentID := uuid.New()
+	testVersion := createTestJobVersion()
+	testVersion.ID = uuidToPgUUID(versionID)
+	testVersion.EnvironmentID = uuidToPgUUID(environmentID)
+	testVersion.EventSpecification = []byte(`{"name":"test.event","source":"api"}`)
+
+	mockRepo.On("GetJobVersionByID", mock.Anything, uuidToPgUUID(versionID)).Return(testVersion, nil)
+	mockShared.On("GetEnvironmentWithProjectAndOrg", mock.Anything, uuidToPgUUID(environmentID)).Return(createTestEnvironment(environmentID), nil)
+
+	eventRecord := &events.EventRecordResponse{ID: uuid.New().String(), EventID: uuid.New().String()}
+	mockEvents.On("IngestSendEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(eventRecord, nil)
+	mockRuns.On("CreateRun", mock.Anything, mock.Anything).Return(nil, errors.New("queue unavailable"))
+	// 运行创建失败时撤销测试事件，避免留下没有运行的事件
+	mockEvents.On("DeleteEventRecord", mock.Anything, eventRecord.ID).Return(nil)
+
+	_, err := service.TestJob(context.Background(), TestJobRequest{
+		VersionID:     versionID,
+		EnvironmentID: environmentID,
+		Payload:       map[string]interface{}{"test": "data"},
+	})
+
+	assert.ErrorContains(t, err, "queue unavailable")
 	mockEvents.AssertExpectations(t)
 }
 
+func TestService_TestJob_EnvironmentMismatch(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockEvents := &MockEventsService{}
+	service := NewService(mockRepo, &MockSharedQueries{}, mockEvents, &MockRunsService{}, slog.Default())
+
+	testVersion := createTestJobVersion()
+	mockRepo.On("GetJobVersionByID", mock.Anything, testVersion.ID).Return(testVersion, nil)
+
+	result, err := service.TestJob(context.Background(), TestJobRequest{
+		VersionID:     pgUUIDToUUID(testVersion.ID),
+		EnvironmentID: uuid.New(),
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "does not belong to environment")
+	mockEvents.AssertNotCalled(t, "IngestSendEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestHelpers_UUID_Conversion(t *testing.T) {
 	originalUUID := uuid.New()
 
@@ -399,9 +608,10 @@ func TestHelpers_JSONB_Conversion(t *testing.T) {
 
 func TestService_RegisterJob_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
-	endpointID := uuid.New()
+	endpoint := createTestEndpoint()
+	endpointID := pgUUIDToUUID(endpoint.ID)
 	request := RegisterJobRequest{
 		ID:      "data-processor",
 		Name:    "Data Processing Job",
@@ -426,12 +636,28 @@ func TestService_RegisterJob_Success(t *testing.T) {
 
 	// 设置 mock 期望
 	mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
-	mockRepo.On("UpsertJob", mock.Anything, mock.Anything).Return(expectedJob, nil)
-	mockRepo.On("UpsertJobQueue", mock.Anything, mock.Anything).Return(expectedQueue, nil)
-	mockRepo.On("UpsertJobVersion", mock.Anything, mock.Anything).Return(expectedVersion, nil)
+	mockRepo.On("GetEndpointScope", mock.Anything, endpoint.ID).Return(endpoint, nil)
+	mockRepo.On("UpsertJob", mock.Anything, mock.MatchedBy(func(params UpsertJobParams) bool {
+		return params.OrganizationID == endpoint.OrganizationID && params.ProjectID == endpoint.ProjectID
+	})).Return(expectedJob, nil)
+	mockRepo.On("UpsertJobQueue", mock.Anything, mock.MatchedBy(func(params UpsertJobQueueParams) bool {
+		return params.EnvironmentID == endpoint.EnvironmentID
+	})).Return(expectedQueue, nil)
+	mockRepo.On("GetJobVersionByJobAndVersion", mock.Anything, mock.MatchedBy(func(params GetJobVersionByJobAndVersionParams) bool {
+		return params.EnvironmentID == endpoint.EnvironmentID
+	})).Return(JobVersions{}, pgx.ErrNoRows)
+	mockRepo.On("GetLatestJobVersion", mock.Anything, mock.MatchedBy(func(params GetLatestJobVersionParams) bool {
+		return params.EnvironmentID == endpoint.EnvironmentID
+	})).Return(JobVersions{}, pgx.ErrNoRows)
+	mockRepo.On("UpsertJobVersion", mock.Anything, mock.MatchedBy(func(params UpsertJobVersionParams) bool {
+		return params.EndpointID == endpoint.ID && params.EnvironmentID == endpoint.EnvironmentID
+	})).Return(expectedVersion, nil)
+	mockRepo.On("DeleteJobVersionIntegrations", mock.Anything, expectedVersion.ID).Return(nil)
 	// manageJobAlias 相关的 mock
 	mockRepo.On("CountLaterJobVersions", mock.Anything, mock.Anything).Return(int64(0), nil)
+	mockRepo.On("GetJobAliasByName", mock.Anything, mock.Anything).Return(JobAliases{}, pgx.ErrNoRows)
 	mockRepo.On("UpsertJobAlias", mock.Anything, mock.Anything).Return(JobAliases{}, nil)
+	mockRepo.On("UpdateJobAliasEventDispatchers", mock.Anything, mock.Anything).Return(int64(0), nil)
 
 	result, err := service.RegisterJob(context.Background(), endpointID, request)
 
@@ -446,7 +672,7 @@ func TestService_RegisterJob_Success(t *testing.T) {
 
 func TestService_RegisterJob_ValidationError(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	endpointID := uuid.New()
 	invalidRequest := RegisterJobRequest{
@@ -464,7 +690,7 @@ func TestService_RegisterJob_ValidationError(t *testing.T) {
 
 func TestService_GetJobBySlug_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	projectID := uuid.New()
 	slug := "test-job"
@@ -484,35 +710,32 @@ func TestService_GetJobBySlug_Success(t *testing.T) {
 
 func TestService_ListJobs_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	projectID := uuid.New()
 	params := ListJobsParams{
 		ProjectID: projectID,
 		Limit:     10,
-		Offset:    0,
 	}
 
 	expectedJobs := []Jobs{createTestJob(), createTestJob()}
-	expectedCount := int64(2)
 
-	mockRepo.On("ListJobsByProject", mock.Anything, mock.Anything).Return(expectedJobs, nil)
-	mockRepo.On("CountJobsByProject", mock.Anything, mock.Anything).Return(expectedCount, nil)
+	mockRepo.On("ListJobsPage", mock.Anything, mock.Anything).Return(expectedJobs, nil)
 
 	result, err := service.ListJobs(context.Background(), params)
 
 	require.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Len(t, result.Jobs, 2)
-	assert.Equal(t, expectedCount, result.Total)
-	assert.False(t, result.HasMore)
+	assert.Empty(t, result.NextCursor)
+	assert.Empty(t, result.PrevCursor)
 
 	mockRepo.AssertExpectations(t)
 }
 
 func TestService_DeleteJob_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	jobID := uuid.New()
 	mockRepo.On("DeleteJob", mock.Anything, mock.Anything).Return(nil)
@@ -525,7 +748,7 @@ func TestService_DeleteJob_Success(t *testing.T) {
 
 func TestService_GetJobVersion_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	versionID := uuid.New()
 	expectedVersion := createTestJobVersion()
@@ -545,7 +768,7 @@ func TestService_GetJobVersion_Success(t *testing.T) {
 
 func TestService_ListJobVersions_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	jobID := uuid.New()
 	expectedVersions := []JobVersions{createTestJobVersion(), createTestJobVersion()}
@@ -564,7 +787,7 @@ func TestService_ListJobVersions_Success(t *testing.T) {
 
 func TestService_CreateJobQueue_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	request := CreateJobQueueRequest{
 		Name:          "test-queue",
@@ -590,7 +813,7 @@ func TestService_CreateJobQueue_Success(t *testing.T) {
 
 func TestService_CreateJobQueue_DefaultMaxJobs(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	request := CreateJobQueueRequest{
 		Name:          "test-queue",
@@ -614,7 +837,7 @@ func TestService_CreateJobQueue_DefaultMaxJobs(t *testing.T) {
 
 func TestService_GetJobQueue_Success(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 
 	environmentID := uuid.New()
 	queueName := "test-queue"
@@ -634,13 +857,14 @@ func TestService_GetJobQueue_Success(t *testing.T) {
 
 func TestService_TestJob_InvalidEventSpecification(t *testing.T) {
 	mockRepo := &MockRepository{}
-	service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+	service := NewService(mockRepo, &MockSharedQueries{}, &MockEventsService{}, &MockRunsService{}, slog.Default())
 
 	versionID := uuid.New()
 	environmentID := uuid.New()
 
 	// 创建无效的 EventSpecification（缺少 name 字段）
 	invalidVersion := createTestJobVersion()
+	invalidVersion.EnvironmentID = uuidToPgUUID(environmentID)
 	invalidVersion.EventSpecification = []byte(`{"source":"api","type":"object"}`) // 缺少 name
 
 	mockRepo.On("GetJobVersionByID", mock.Anything, uuidToPgUUID(versionID)).Return(invalidVersion, nil)
@@ -704,9 +928,786 @@ func TestService_ErrorHandling_RepositoryFailures(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockRepository{}
-			service := NewService(mockRepo, &MockEventsService{}, slog.Default())
+			service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
 			tt.testFunc(mockRepo, service)
 			mockRepo.AssertExpectations(t)
 		})
 	}
 }
+
+// ========== 作业别名管理测试 ==========
+
+func TestService_SetJobAlias_MovesAliasAndDispatchers(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	version := createTestJobVersion()
+	version.Version = "2.0.0"
+	alias := JobAliases{
+		ID:            uuidToPgUUID(uuid.New()),
+		JobID:         version.JobID,
+		VersionID:     version.ID,
+		EnvironmentID: version.EnvironmentID,
+		Name:          "stable",
+		Value:         version.Version,
+	}
+	aliasID := pgUUIDToUUID(alias.ID).String()
+
+	mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetJobVersionByID", mock.Anything, version.ID).Return(version, nil)
+	mockRepo.On("UpsertJobAlias", mock.Anything, UpsertJobAliasParams{
+		JobID:         version.JobID,
+		VersionID:     version.ID,
+		EnvironmentID: version.EnvironmentID,
+		Name:          "stable",
+		Value:         "2.0.0",
+	}).Return(alias, nil)
+	mockRepo.On("UpdateJobAliasEventDispatchers", mock.Anything, mock.MatchedBy(func(p UpdateJobAliasEventDispatchersParams) bool {
+		return p.DispatchableID == aliasID && p.Event == "test.event" && p.Source == "api"
+	})).Return(int64(1), nil)
+	mockRepo.On("UpsertJobAliasEventDispatcher", mock.Anything, mock.MatchedBy(func(p UpsertJobAliasEventDispatcherParams) bool {
+		dispatchable := jsonbToMap(p.Dispatchable)
+		return p.DispatchableID == aliasID &&
+			p.EnvironmentID == version.EnvironmentID &&
+			dispatchable["type"] == JobAliasDispatchableType &&
+			dispatchable["versionId"] == pgUUIDToUUID(version.ID).String()
+	})).Return(uuidToPgUUID(uuid.New()), nil)
+
+	result, err := service.SetJobAlias(context.Background(), SetJobAliasRequest{
+		JobID:         pgUUIDToUUID(version.JobID),
+		EnvironmentID: pgUUIDToUUID(version.EnvironmentID),
+		Name:          "stable",
+		VersionID:     pgUUIDToUUID(version.ID),
+		Dispatch:      true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "stable", result.Name)
+	assert.Equal(t, "2.0.0", result.Version)
+	assert.Equal(t, pgUUIDToUUID(version.ID), result.VersionID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_SetJobAlias_RejectsForeignVersion(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	version := createTestJobVersion()
+	mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetJobVersionByID", mock.Anything, version.ID).Return(version, nil)
+
+	result, err := service.SetJobAlias(context.Background(), SetJobAliasRequest{
+		JobID:         uuid.New(),
+		EnvironmentID: pgUUIDToUUID(version.EnvironmentID),
+		Name:          "canary",
+		VersionID:     pgUUIDToUUID(version.ID),
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "does not belong to job")
+	mockRepo.AssertNotCalled(t, "UpsertJobAlias", mock.Anything, mock.Anything)
+}
+
+func TestService_PromoteJobAlias_Success(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	version := createTestJobVersion()
+	canary := JobAliases{
+		ID:            uuidToPgUUID(uuid.New()),
+		JobID:         version.JobID,
+		VersionID:     version.ID,
+		EnvironmentID: version.EnvironmentID,
+		Name:          "canary",
+		Value:         version.Version,
+	}
+	stable := canary
+	stable.ID = uuidToPgUUID(uuid.New())
+	stable.Name = "stable"
+
+	mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetJobAliasByName", mock.Anything, GetJobAliasByNameParams{
+		JobID:         version.JobID,
+		EnvironmentID: version.EnvironmentID,
+		Name:          "canary",
+	}).Return(canary, nil)
+	mockRepo.On("GetJobVersionByID", mock.Anything, version.ID).Return(version, nil)
+	mockRepo.On("UpsertJobAlias", mock.Anything, mock.MatchedBy(func(p UpsertJobAliasParams) bool {
+		return p.Name == "stable" && p.VersionID == version.ID
+	})).Return(stable, nil)
+	mockRepo.On("UpdateJobAliasEventDispatchers", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	result, err := service.PromoteJobAlias(context.Background(), PromoteJobAliasRequest{
+		JobID:         pgUUIDToUUID(version.JobID),
+		EnvironmentID: pgUUIDToUUID(version.EnvironmentID),
+		From:          "canary",
+		To:            "stable",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "stable", result.Name)
+	assert.Equal(t, pgUUIDToUUID(version.ID), result.VersionID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_RollbackLatest_UsesPreviousVersion(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	previous := createTestJobVersion()
+	current := previous
+	current.ID = uuidToPgUUID(uuid.New())
+	current.Version = "1.1.0"
+	latest := JobAliases{
+		ID:            uuidToPgUUID(uuid.New()),
+		JobID:         current.JobID,
+		VersionID:     current.ID,
+		EnvironmentID: current.EnvironmentID,
+		Name:          LatestAliasName,
+		Value:         current.Version,
+	}
+	rolledBack := latest
+	rolledBack.VersionID = previous.ID
+	rolledBack.Value = previous.Version
+
+	mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetJobAliasByName", mock.Anything, mock.Anything).Return(latest, nil)
+	mockRepo.On("GetPreviousJobVersion", mock.Anything, GetPreviousJobVersionParams{
+		JobID:         current.JobID,
+		EnvironmentID: current.EnvironmentID,
+		Version:       "1.1.0",
+	}).Return(previous, nil)
+	mockRepo.On("UpsertJobAlias", mock.Anything, mock.MatchedBy(func(p UpsertJobAliasParams) bool {
+		return p.Name == LatestAliasName && p.VersionID == previous.ID && p.Value == "1.0.0"
+	})).Return(rolledBack, nil)
+	mockRepo.On("UpdateJobAliasEventDispatchers", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	result, err := service.RollbackLatest(context.Background(), RollbackLatestRequest{
+		JobID:         pgUUIDToUUID(current.JobID),
+		EnvironmentID: pgUUIDToUUID(current.EnvironmentID),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", result.Version)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_RollbackLatest_NoPreviousVersion(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetJobAliasByName", mock.Anything, mock.Anything).Return(JobAliases{Value: "1.0.0"}, nil)
+	mockRepo.On("GetPreviousJobVersion", mock.Anything, mock.Anything).Return(JobVersions{}, pgx.ErrNoRows)
+
+	result, err := service.RollbackLatest(context.Background(), RollbackLatestRequest{
+		JobID:         uuid.New(),
+		EnvironmentID: uuid.New(),
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "no version before 1.0.0")
+}
+
+func TestService_RegisterJob_KeepsRolledBackLatest(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default()).(*service)
+
+	version := createTestJobVersion()
+	version.CreatedAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+	latest := JobAliases{
+		VersionID: uuidToPgUUID(uuid.New()),
+		Name:      LatestAliasName,
+		UpdatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+
+	mockRepo.On("CountLaterJobVersions", mock.Anything, mock.Anything).Return(int64(0), nil)
+	mockRepo.On("GetJobAliasByName", mock.Anything, mock.Anything).Return(latest, nil)
+
+	err := svc.manageJobAlias(context.Background(), mockRepo, createTestJob(), version, uuid.New())
+
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "UpsertJobAlias", mock.Anything, mock.Anything)
+}
+
+func TestService_DeleteJobAlias(t *testing.T) {
+	t.Run("RefusesLatest", func(t *testing.T) {
+		mockRepo := &MockRepository{}
+		service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+		err := service.DeleteJobAlias(context.Background(), uuid.New(), uuid.New(), LatestAliasName)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "DeleteJobAlias", mock.Anything, mock.Anything)
+	})
+
+	t.Run("DeletesDispatchers", func(t *testing.T) {
+		mockRepo := &MockRepository{}
+		service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+		alias := JobAliases{ID: uuidToPgUUID(uuid.New()), Name: "canary"}
+		mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetJobAliasByName", mock.Anything, mock.Anything).Return(alias, nil)
+		mockRepo.On("DeleteJobAliasEventDispatchers", mock.Anything, pgUUIDToUUID(alias.ID).String()).Return(nil)
+		mockRepo.On("DeleteJobAlias", mock.Anything, alias.ID).Return(nil)
+
+		err := service.DeleteJobAlias(context.Background(), uuid.New(), uuid.New(), "canary")
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestService_LinkJobVersionIntegrations(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default()).(*service)
+
+	job := createTestJob()
+	versionID := uuidToPgUUID(uuid.New())
+	integration := Integrations{ID: uuidToPgUUID(uuid.New()), Slug: "github-client"}
+
+	mockRepo.On("DeleteJobVersionIntegrations", mock.Anything, versionID).Return(nil)
+	mockRepo.On("UpsertIntegration", mock.Anything, UpsertIntegrationParams{
+		OrganizationID: job.OrganizationID,
+		Slug:           "github-client",
+		Title:          "GitHub",
+		Identifier:     "github",
+	}).Return(integration, nil)
+	mockRepo.On("CreateJobVersionIntegration", mock.Anything, CreateJobVersionIntegrationParams{
+		JobVersionID:  versionID,
+		IntegrationID: integration.ID,
+		Key:           "github",
+	}).Return(nil)
+
+	err := svc.linkJobVersionIntegrations(context.Background(), mockRepo, job, versionID, map[string]IntegrationConf{
+		"github": {ID: "github-client", Metadata: IntegrationMetadata{ID: "github", Name: "GitHub"}},
+	})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// ========== 版本漂移与变更日志测试 ==========
+
+func newDriftTestRequest() RegisterJobRequest {
+	return RegisterJobRequest{
+		ID:      "data-processor",
+		Name:    "Data Processing Job",
+		Version: "1.0.0",
+		Event:   EventSpecification{Name: "data.processed", Source: "api"},
+		Trigger: TriggerMetadata{
+			Type: "static",
+			Rule: &TriggerRule{Event: "data.processed", Source: "api"},
+		},
+	}
+}
+
+func storedDefinition(t *testing.T, req RegisterJobRequest) []byte {
+	data, err := json.Marshal(newJobVersionDefinition(req))
+	require.NoError(t, err)
+	return data
+}
+
+// versionLookup 匹配在端点所在环境中查找同一版本的参数
+func versionLookup(endpoint GetEndpointScopeRow) interface{} {
+	return mock.MatchedBy(func(params GetJobVersionByJobAndVersionParams) bool {
+		return params.EnvironmentID == endpoint.EnvironmentID
+	})
+}
+
+// latestLookup 匹配在端点所在环境中查找最新版本的参数
+func latestLookup(endpoint GetEndpointScopeRow) interface{} {
+	return mock.MatchedBy(func(params GetLatestJobVersionParams) bool {
+		return params.EnvironmentID == endpoint.EnvironmentID
+	})
+}
+
+func TestService_UpsertJobVersion_SameDefinition(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default()).(*service)
+
+	endpoint := createTestEndpoint()
+	req := newDriftTestRequest()
+	existing := createTestJobVersion()
+	existing.Definition = storedDefinition(t, req)
+
+	mockRepo.On("GetJobVersionByJobAndVersion", mock.Anything, versionLookup(endpoint)).Return(existing, nil)
+	mockRepo.On("UpsertJobVersion", mock.Anything, mock.Anything).Return(existing, nil)
+
+	_, err := svc.upsertJobVersion(context.Background(), mockRepo, createTestJob(), req, endpoint, existing.QueueID)
+
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "CreateJobVersionChangelog", mock.Anything, mock.Anything)
+}
+
+func TestService_UpsertJobVersion_RefusesDrift(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default()).(*service)
+
+	endpoint := createTestEndpoint()
+	req := newDriftTestRequest()
+	existing := createTestJobVersion()
+	existing.Definition = storedDefinition(t, req)
+
+	req.Queue = &QueueConfig{Name: "critical"}
+	mockRepo.On("GetJobVersionByJobAndVersion", mock.Anything, versionLookup(endpoint)).Return(existing, nil)
+
+	_, err := svc.upsertJobVersion(context.Background(), mockRepo, createTestJob(), req, endpoint, existing.QueueID)
+
+	assert.ErrorIs(t, err, ErrJobVersionDrift)
+	assert.Contains(t, err.Error(), "queue")
+	mockRepo.AssertNotCalled(t, "UpsertJobVersion", mock.Anything, mock.Anything)
+}
+
+func TestService_UpsertJobVersion_OverrideRecordsChangelog(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default()).(*service)
+
+	endpoint := createTestEndpoint()
+	req := newDriftTestRequest()
+	existing := createTestJobVersion()
+	existing.Definition = storedDefinition(t, req)
+
+	req.Trigger.Rule = &TriggerRule{Event: "data.processed", Source: "api", Payload: map[string]interface{}{"kind": []interface{}{"csv"}}}
+	req.Override = true
+
+	mockRepo.On("GetJobVersionByJobAndVersion", mock.Anything, versionLookup(endpoint)).Return(existing, nil)
+	mockRepo.On("UpsertJobVersion", mock.Anything, mock.Anything).Return(existing, nil)
+	mockRepo.On("CreateJobVersionChangelog", mock.Anything, mock.MatchedBy(func(params CreateJobVersionChangelogParams) bool {
+		var changes []JobVersionChange
+		require.NoError(t, json.Unmarshal(params.Changes, &changes))
+		return params.Overridden &&
+			params.PreviousVersion.String == existing.Version &&
+			len(changes) == 1 && changes[0].Field == "trigger"
+	})).Return(JobVersionChangelogs{}, nil)
+
+	_, err := svc.upsertJobVersion(context.Background(), mockRepo, createTestJob(), req, endpoint, existing.QueueID)
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_UpsertJobVersion_NewVersionRecordsChangelog(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default()).(*service)
+
+	endpoint := createTestEndpoint()
+	req := newDriftTestRequest()
+	latest := createTestJobVersion()
+	latest.Definition = storedDefinition(t, req)
+
+	req.Version = "1.1.0"
+	req.Event.Name = "data.imported"
+	created := createTestJobVersion()
+	created.Version = req.Version
+
+	mockRepo.On("GetJobVersionByJobAndVersion", mock.Anything, versionLookup(endpoint)).Return(JobVersions{}, pgx.ErrNoRows)
+	mockRepo.On("GetLatestJobVersion", mock.Anything, latestLookup(endpoint)).Return(latest, nil)
+	mockRepo.On("UpsertJobVersion", mock.Anything, mock.Anything).Return(created, nil)
+	mockRepo.On("CreateJobVersionChangelog", mock.Anything, mock.MatchedBy(func(params CreateJobVersionChangelogParams) bool {
+		return !params.Overridden && params.Version == "1.1.0" && params.PreviousVersion.String == "1.0.0"
+	})).Return(JobVersionChangelogs{}, nil)
+
+	_, err := svc.upsertJobVersion(context.Background(), mockRepo, createTestJob(), req, endpoint, created.QueueID)
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_UpsertJobVersion_StoresMaxRunDuration(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default()).(*service)
+
+	endpoint := createTestEndpoint()
+	req := newDriftTestRequest()
+	req.MaxRunDuration = 3600
+	created := createTestJobVersion()
+
+	mockRepo.On("GetJobVersionByJobAndVersion", mock.Anything, versionLookup(endpoint)).Return(JobVersions{}, pgx.ErrNoRows)
+	mockRepo.On("GetLatestJobVersion", mock.Anything, latestLookup(endpoint)).Return(JobVersions{}, pgx.ErrNoRows)
+	mockRepo.On("UpsertJobVersion", mock.Anything, mock.MatchedBy(func(params UpsertJobVersionParams) bool {
+		return params.MaxRunDurationSeconds.Valid && params.MaxRunDurationSeconds.Int32 == 3600
+	})).Return(created, nil)
+
+	_, err := svc.upsertJobVersion(context.Background(), mockRepo, createTestJob(), req, endpoint, created.QueueID)
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDiffJobVersionDefinition(t *testing.T) {
+	req := newDriftTestRequest()
+	stored, err := json.Marshal(newJobVersionDefinition(req))
+	require.NoError(t, err)
+
+	// 旧版本没有快照
+	changes, err := diffJobVersionDefinition(nil, newJobVersionDefinition(req))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// 显式指定默认队列不算变更
+	req.Queue = &QueueConfig{Name: DefaultQueueName, MaxConcurrent: DefaultMaxConcurrentRuns}
+	changes, err = diffJobVersionDefinition(stored, newJobVersionDefinition(req))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	req.Event.Source = "webhook"
+	req.Integrations = map[string]IntegrationConf{"github": {ID: "github"}}
+	changes, err = diffJobVersionDefinition(stored, newJobVersionDefinition(req))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"event", "integrations"}, changedFields(changes))
+
+	req.MaxRunDuration = 600
+	changes, err = diffJobVersionDefinition(stored, newJobVersionDefinition(req))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"event", "integrations", "maxRunDuration"}, changedFields(changes))
+}
+
+// ========== 作业生命周期测试 ==========
+
+func TestService_SetJobStatus_Pause(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockRuns := &MockRunsService{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, mockRuns, slog.Default())
+
+	job := createTestJob()
+	paused := job
+	paused.Status = JobStatusPAUSED
+	paused.StatusChangedBy = pgtype.Text{String: "user_1", Valid: true}
+	paused.StatusChangedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetJobByID", mock.Anything, job.ID).Return(job, nil)
+	mockRepo.On("UpdateJobStatus", mock.Anything, UpdateJobStatusParams{
+		ID:              job.ID,
+		Status:          JobStatusPAUSED,
+		StatusChangedBy: pgtype.Text{String: "user_1", Valid: true},
+	}).Return(paused, nil)
+
+	result, err := service.SetJobStatus(context.Background(), pgUUIDToUUID(job.ID), SetJobStatusRequest{
+		Status:    JobStatusPAUSED,
+		ChangedBy: "user_1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusPAUSED, result.Status)
+	assert.Equal(t, "user_1", result.StatusChangedBy)
+	assert.NotNil(t, result.StatusChangedAt)
+	mockRepo.AssertExpectations(t)
+	mockRuns.AssertNotCalled(t, "StartJobRuns", mock.Anything, mock.Anything)
+}
+
+func TestService_SetJobStatus_ResumeStartsHeldRuns(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockRuns := &MockRunsService{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, mockRuns, slog.Default())
+
+	job := createTestJob()
+	job.Status = JobStatusPAUSED
+	enabled := job
+	enabled.Status = JobStatusENABLED
+
+	mockRepo.On("WithTx", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetJobByID", mock.Anything, job.ID).Return(job, nil)
+	mockRepo.On("UpdateJobStatus", mock.Anything, mock.Anything).Return(enabled, nil)
+	mockRuns.On("StartJobRuns", mock.Anything, pgUUIDToUUID(job.ID).String()).Return(nil)
+
+	result, err := service.SetJobStatus(context.Background(), pgUUIDToUUID(job.ID), SetJobStatusRequest{
+		Status:    JobStatusENABLED,
+		ChangedBy: "user_1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusENABLED, result.Status)
+	mockRepo.AssertExpectations(t)
+	mockRuns.AssertExpectations(t)
+}
+
+func TestService_SetJobStatus_InvalidRequest(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	_, err := service.SetJobStatus(context.Background(), uuid.New(), SetJobStatusRequest{Status: "DELETED", ChangedBy: "user_1"})
+	assert.Error(t, err)
+
+	_, err = service.SetJobStatus(context.Background(), uuid.New(), SetJobStatusRequest{Status: JobStatusARCHIVED})
+	assert.Error(t, err)
+
+	mockRepo.AssertNotCalled(t, "UpdateJobStatus", mock.Anything, mock.Anything)
+}
+
+func TestService_ListJobs_HidesArchivedByDefault(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	projectID := uuid.New()
+	mockRepo.On("ListJobsPage", mock.Anything, ListJobsPageParams{
+		ProjectID:       uuidToPgUUID(projectID),
+		IncludeArchived: false,
+		PageSize:        11,
+	}).Return([]Jobs{createTestJob()}, nil)
+
+	result, err := service.ListJobs(context.Background(), ListJobsParams{ProjectID: projectID, Limit: 10})
+
+	require.NoError(t, err)
+	assert.Len(t, result.Jobs, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_ListJobs_CursorAndFilters(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	projectID := uuid.New()
+	internal := true
+	base := time.Now().UTC().Truncate(time.Second)
+	jobs := make([]Jobs, 3)
+	for i := range jobs {
+		jobs[i] = createTestJob()
+		jobs[i].CreatedAt = pgtype.Timestamptz{Time: base.Add(-time.Duration(i) * time.Minute), Valid: true}
+	}
+
+	// 第一页：多取一条用于判断是否还有下一页
+	mockRepo.On("ListJobsPage", mock.Anything, ListJobsPageParams{
+		ProjectID:  uuidToPgUUID(projectID),
+		SlugPrefix: pgtype.Text{String: `my\_job`, Valid: true},
+		Internal:   pgtype.Bool{Bool: true, Valid: true},
+		PageSize:   3,
+	}).Return(jobs, nil).Once()
+
+	first, err := service.ListJobs(context.Background(), ListJobsParams{
+		ProjectID:  projectID,
+		Limit:      2,
+		SlugPrefix: "my_job",
+		Internal:   &internal,
+	})
+	require.NoError(t, err)
+	require.Len(t, first.Jobs, 2)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Empty(t, first.PrevCursor)
+
+	// 第二页从上一页最后一条之后开始
+	mockRepo.On("ListJobsPage", mock.Anything, ListJobsPageParams{
+		ProjectID:       uuidToPgUUID(projectID),
+		CursorCreatedAt: jobs[1].CreatedAt,
+		CursorID:        jobs[1].ID,
+		PageSize:        3,
+	}).Return(jobs[2:], nil).Once()
+
+	second, err := service.ListJobs(context.Background(), ListJobsParams{
+		ProjectID: projectID,
+		Limit:     2,
+		Cursor:    first.NextCursor,
+	})
+	require.NoError(t, err)
+	require.Len(t, second.Jobs, 1)
+	assert.Equal(t, pgUUIDToUUID(jobs[2].ID), second.Jobs[0].ID)
+	assert.Empty(t, second.NextCursor)
+	assert.NotEmpty(t, second.PrevCursor)
+
+	// 翻回上一页走反向查询，结果按正序返回
+	mockRepo.On("ListJobsPageReverse", mock.Anything, ListJobsPageReverseParams{
+		ProjectID:       uuidToPgUUID(projectID),
+		CursorCreatedAt: jobs[2].CreatedAt,
+		CursorID:        jobs[2].ID,
+		PageSize:        3,
+	}).Return([]Jobs{jobs[1], jobs[0]}, nil).Once()
+
+	back, err := service.ListJobs(context.Background(), ListJobsParams{
+		ProjectID: projectID,
+		Limit:     2,
+		Cursor:    second.PrevCursor,
+	})
+	require.NoError(t, err)
+	require.Len(t, back.Jobs, 2)
+	assert.Equal(t, pgUUIDToUUID(jobs[0].ID), back.Jobs[0].ID)
+	assert.NotEmpty(t, back.NextCursor)
+	assert.Empty(t, back.PrevCursor)
+
+	_, err = service.ListJobs(context.Background(), ListJobsParams{ProjectID: projectID, Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// ========== 事件示例测试 ==========
+
+func TestService_ListEventExamples(t *testing.T) {
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, nil, &MockEventsService{}, nil, slog.Default())
+
+	versionID := uuid.New()
+	example := EventExamples{
+		ID:           uuidToPgUUID(uuid.New()),
+		JobVersionID: uuidToPgUUID(versionID),
+		Slug:         "new-user",
+		Name:         "New user",
+		Icon:         pgtype.Text{String: "user", Valid: true},
+		Payload:      []byte(`{"userId":"user_1"}`),
+	}
+	mockRepo.On("ListEventExamplesByJobVersion", mock.Anything, uuidToPgUUID(versionID)).Return([]EventExamples{example}, nil)
+
+	result, err := service.ListEventExamples(context.Background(), versionID)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "new-user", result[0].Slug)
+	assert.Equal(t, "user", result[0].Icon)
+	assert.Equal(t, "user_1", result[0].Payload["userId"])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_TestJob_UsesExamplePayloadWithOverrides(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockShared := &MockSharedQueries{}
+	mockEvents := &MockEventsService{}
+	mockRuns := &MockRunsService{}
+	service := NewService(mockRepo, mockShared, mockEvents, mockRuns, slog.Default())
+
+	version := createTestJobVersion()
+	environmentID := pgUUIDToUUID(version.EnvironmentID)
+	example := EventExamples{
+		ID:           uuidToPgUUID(uuid.New()),
+		JobVersionID: version.ID,
+		Slug:         "new-user",
+		Payload:      []byte(`{"user":{"id":"user_1","plan":"free"},"tags":["a"]}`),
+	}
+
+	mockRepo.On("GetJobVersionByID", mock.Anything, version.ID).Return(version, nil)
+	mockRepo.On("GetEventExampleBySlug", mock.Anything, GetEventExampleBySlugParams{
+		JobVersionID: version.ID,
+		Slug:         "new-user",
+	}).Return(example, nil)
+	mockShared.On("GetEnvironmentWithProjectAndOrg", mock.Anything, version.EnvironmentID).
+		Return(createTestEnvironment(environmentID), nil)
+	mockEvents.On("IngestSendEvent", mock.Anything, mock.Anything,
+		mock.MatchedBy(func(req *events.SendEventRequest) bool {
+			user, _ := req.Payload["user"].(map[string]interface{})
+			return user["id"] == "user_1" && user["plan"] == "pro" && req.Payload["tags"] != nil
+		}), mock.Anything).
+		Return(&events.EventRecordResponse{ID: uuid.New().String(), EventID: uuid.New().String()}, nil)
+	mockRuns.On("CreateRun", mock.Anything, mock.Anything).
+		Return(&runs.RunResponse{ID: uuid.New().String(), Status: runs.JobRunStatusPENDING}, nil)
+
+	_, err := service.TestJob(context.Background(), TestJobRequest{
+		EnvironmentID: environmentID,
+		VersionID:     pgUUIDToUUID(version.ID),
+		ExampleSlug:   "new-user",
+		Payload:       map[string]interface{}{"user": map[string]interface{}{"plan": "pro"}},
+	})
+
+	require.NoError(t, err)
+	mockEvents.AssertExpectations(t)
+}
+
+func TestService_TestJob_RejectsForeignExample(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockEvents := &MockEventsService{}
+	service := NewService(mockRepo, &MockSharedQueries{}, mockEvents, &MockRunsService{}, slog.Default())
+
+	version := createTestJobVersion()
+	example := EventExamples{ID: uuidToPgUUID(uuid.New()), JobVersionID: uuidToPgUUID(uuid.New())}
+
+	mockRepo.On("GetJobVersionByID", mock.Anything, version.ID).Return(version, nil)
+	mockRepo.On("GetEventExampleByID", mock.Anything, example.ID).Return(example, nil)
+
+	result, err := service.TestJob(context.Background(), TestJobRequest{
+		EnvironmentID: pgUUIDToUUID(version.EnvironmentID),
+		VersionID:     pgUUIDToUUID(version.ID),
+		ExampleID:     pgUUIDToUUID(example.ID),
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "does not belong to job version")
+	mockEvents.AssertNotCalled(t, "IngestSendEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHelpers_MergePayload(t *testing.T) {
+	base := map[string]interface{}{
+		"user":  map[string]interface{}{"id": "user_1", "plan": "free"},
+		"count": 1,
+	}
+	overrides := map[string]interface{}{
+		"user":  map[string]interface{}{"plan": "pro"},
+		"count": map[string]interface{}{"value": 2},
+	}
+
+	merged := mergePayload(base, overrides)
+
+	assert.Equal(t, map[string]interface{}{"id": "user_1", "plan": "pro"}, merged["user"])
+	assert.Equal(t, map[string]interface{}{"value": 2}, merged["count"])
+	// 基础 payload 不被修改
+	assert.Equal(t, "free", base["user"].(map[string]interface{})["plan"])
+}
+
+func TestService_SetEnvironmentMaxRunDuration(t *testing.T) {
+	t.Run("SetsDefault", func(t *testing.T) {
+		mockShared := &MockSharedQueries{}
+		service := NewService(&MockRepository{}, mockShared, &MockEventsService{}, nil, slog.Default())
+
+		environmentID := uuid.New()
+		mockShared.On("UpdateRuntimeEnvironmentMaxRunDuration", mock.Anything, shared.UpdateRuntimeEnvironmentMaxRunDurationParams{
+			ID:                           uuidToPgUUID(environmentID),
+			DefaultMaxRunDurationSeconds: pgtype.Int4{Int32: 3600, Valid: true},
+		}).Return(shared.RuntimeEnvironments{
+			ID:                           uuidToPgUUID(environmentID),
+			DefaultMaxRunDurationSeconds: pgtype.Int4{Int32: 3600, Valid: true},
+		}, nil)
+
+		result, err := service.SetEnvironmentMaxRunDuration(context.Background(), SetEnvironmentMaxRunDurationRequest{
+			EnvironmentID:  environmentID,
+			MaxRunDuration: 3600,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, environmentID, result.EnvironmentID)
+		assert.Equal(t, 3600, result.MaxRunDuration)
+		mockShared.AssertExpectations(t)
+	})
+
+	t.Run("ZeroClearsDefault", func(t *testing.T) {
+		mockShared := &MockSharedQueries{}
+		service := NewService(&MockRepository{}, mockShared, &MockEventsService{}, nil, slog.Default())
+
+		environmentID := uuid.New()
+		mockShared.On("UpdateRuntimeEnvironmentMaxRunDuration", mock.Anything, shared.UpdateRuntimeEnvironmentMaxRunDurationParams{
+			ID: uuidToPgUUID(environmentID),
+		}).Return(shared.RuntimeEnvironments{ID: uuidToPgUUID(environmentID)}, nil)
+
+		result, err := service.SetEnvironmentMaxRunDuration(context.Background(), SetEnvironmentMaxRunDurationRequest{
+			EnvironmentID: environmentID,
+		})
+
+		require.NoError(t, err)
+		assert.Zero(t, result.MaxRunDuration)
+		mockShared.AssertExpectations(t)
+	})
+
+	t.Run("RejectsInvalidDuration", func(t *testing.T) {
+		mockShared := &MockSharedQueries{}
+		service := NewService(&MockRepository{}, mockShared, &MockEventsService{}, nil, slog.Default())
+
+		_, err := service.SetEnvironmentMaxRunDuration(context.Background(), SetEnvironmentMaxRunDurationRequest{
+			EnvironmentID:  uuid.New(),
+			MaxRunDuration: -1,
+		})
+
+		assert.ErrorContains(t, err, "invalid request")
+		mockShared.AssertNotCalled(t, "UpdateRuntimeEnvironmentMaxRunDuration", mock.Anything, mock.Anything)
+	})
+
+	t.Run("EnvironmentNotFound", func(t *testing.T) {
+		mockShared := &MockSharedQueries{}
+		service := NewService(&MockRepository{}, mockShared, &MockEventsService{}, nil, slog.Default())
+
+		mockShared.On("UpdateRuntimeEnvironmentMaxRunDuration", mock.Anything, mock.Anything).Return(shared.RuntimeEnvironments{}, pgx.ErrNoRows)
+
+		_, err := service.SetEnvironmentMaxRunDuration(context.Background(), SetEnvironmentMaxRunDurationRequest{
+			EnvironmentID:  uuid.New(),
+			MaxRunDuration: 60,
+		})
+
+		assert.ErrorContains(t, err, "not found")
+	})
+}
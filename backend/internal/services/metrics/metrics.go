@@ -0,0 +1,156 @@
+// Package metrics provides Prometheus instrumentation for KongFlow services
+// Services declare small metrics interfaces and Metrics implements all of them,
+// so instrumentation stays optional and services do not depend on Prometheus
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"kongflow/backend/internal/services/apiauth"
+	"kongflow/backend/internal/services/endpointapi"
+	"kongflow/backend/internal/services/events"
+	"kongflow/backend/internal/services/runs"
+	"kongflow/backend/internal/services/workerqueue"
+)
+
+// namespace prefixes every KongFlow metric
+const namespace = "kongflow"
+
+// Metrics holds the KongFlow collectors and the registry they are exposed from
+type Metrics struct {
+	registry *prometheus.Registry
+
+	eventsIngested       *prometheus.CounterVec
+	eventDeliveryLatency prometheus.Histogram
+	dispatcherMatches    prometheus.Counter
+	endpointRequests     *prometheus.HistogramVec
+	jobsFinished         *prometheus.CounterVec
+	jobDuration          *prometheus.HistogramVec
+	authFailures         *prometheus.CounterVec
+	runsFinished         *prometheus.CounterVec
+}
+
+var (
+	_ events.Metrics         = (*Metrics)(nil)
+	_ endpointapi.Metrics    = (*Metrics)(nil)
+	_ workerqueue.JobMetrics = (*Metrics)(nil)
+	_ apiauth.AuthMetrics    = (*Metrics)(nil)
+	_ runs.Metrics           = (*Metrics)(nil)
+)
+
+// New creates the KongFlow metrics on a new registry, together with the Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		eventsIngested: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_ingested_total",
+			Help:      "Events ingested through the send event API, by outcome.",
+		}, []string{"outcome"}),
+		eventDeliveryLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "event_delivery_latency_seconds",
+			Help:      "Time from an event's scheduled delivery to its dispatch.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+		}),
+		dispatcherMatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "event_dispatcher_matches_total",
+			Help:      "Event dispatchers matched by delivered events.",
+		}),
+		endpointRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "endpoint_request_duration_seconds",
+			Help:      "Latency of endpoint API calls, by action and HTTP status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"action", "status"}),
+		jobsFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_finished_total",
+			Help:      "Worker jobs run to completion, by kind, queue and outcome.",
+		}, []string{"kind", "queue", "outcome"}),
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "job_duration_seconds",
+			Help:      "Time spent working jobs, by kind and queue.",
+			Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300},
+		}, []string{"kind", "queue"}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_failures_total",
+			Help:      "Rejected API requests, by failure reason.",
+		}, []string{"reason"}),
+		runsFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "runs_finished_total",
+			Help:      "Job runs that reached a final status, by status.",
+		}, []string{"status"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.eventsIngested,
+		m.eventDeliveryLatency,
+		m.dispatcherMatches,
+		m.endpointRequests,
+		m.jobsFinished,
+		m.jobDuration,
+		m.authFailures,
+		m.runsFinished,
+	)
+	return m
+}
+
+// Registry returns the registry the metrics are exposed from, for registering extra collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns the /metrics handler, serving OpenMetrics when the scraper accepts it
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+}
+
+// EventIngested implements events.Metrics
+func (m *Metrics) EventIngested(outcome string) {
+	m.eventsIngested.WithLabelValues(outcome).Inc()
+}
+
+// EventDelivered implements events.Metrics
+func (m *Metrics) EventDelivered(latency time.Duration) {
+	m.eventDeliveryLatency.Observe(latency.Seconds())
+}
+
+// DispatchersMatched implements events.Metrics
+func (m *Metrics) DispatchersMatched(count int) {
+	m.dispatcherMatches.Add(float64(count))
+}
+
+// ObserveEndpointRequest implements endpointapi.Metrics
+func (m *Metrics) ObserveEndpointRequest(action, status string, duration time.Duration) {
+	m.endpointRequests.WithLabelValues(action, status).Observe(duration.Seconds())
+}
+
+// JobFinished implements workerqueue.JobMetrics
+func (m *Metrics) JobFinished(kind, queue, outcome string, duration time.Duration) {
+	m.jobsFinished.WithLabelValues(kind, queue, outcome).Inc()
+	m.jobDuration.WithLabelValues(kind, queue).Observe(duration.Seconds())
+}
+
+// AuthFailed implements apiauth.AuthMetrics
+func (m *Metrics) AuthFailed(reason string) {
+	m.authFailures.WithLabelValues(reason).Inc()
+}
+
+// RunFinished implements runs.Metrics
+func (m *Metrics) RunFinished(status string) {
+	m.runsFinished.WithLabelValues(status).Inc()
+}
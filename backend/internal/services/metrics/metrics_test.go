@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kongflow/backend/internal/services/workerqueue"
+)
+
+// fakeQueueStats returns fixed queue statistics
+type fakeQueueStats struct {
+	stats *workerqueue.ManagerStats
+	err   error
+}
+
+func (f *fakeQueueStats) Stats(ctx context.Context) (*workerqueue.ManagerStats, error) {
+	return f.stats, f.err
+}
+
+// scrape fetches the metrics handler output, requesting OpenMetrics when openMetrics is set
+func scrape(t *testing.T, m *Metrics, openMetrics bool) (string, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if openMetrics {
+		req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	}
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body), rec.Header().Get("Content-Type")
+}
+
+func TestMetrics_ServiceInstrumentation(t *testing.T) {
+	m := New()
+
+	m.EventIngested("success")
+	m.EventIngested("error")
+	m.EventDelivered(1500 * time.Millisecond)
+	m.DispatchersMatched(3)
+	m.ObserveEndpointRequest("EXECUTE_JOB", "200", 120*time.Millisecond)
+	m.JobFinished("start_run", "execution", workerqueue.JobOutcomeSuccess, 2*time.Second)
+	m.AuthFailed("invalid_key")
+	m.RunFinished("FAILURE")
+
+	body, contentType := scrape(t, m, true)
+	assert.Contains(t, contentType, "application/openmetrics-text")
+	assert.Contains(t, body, `kongflow_events_ingested_total{outcome="success"} 1`)
+	assert.Contains(t, body, `kongflow_events_ingested_total{outcome="error"} 1`)
+	assert.Contains(t, body, `kongflow_event_delivery_latency_seconds_count 1`)
+	assert.Contains(t, body, `kongflow_event_dispatcher_matches_total 3`)
+	assert.Contains(t, body, `kongflow_endpoint_request_duration_seconds_count{action="EXECUTE_JOB",status="200"} 1`)
+	assert.Contains(t, body, `kongflow_jobs_finished_total{kind="start_run",outcome="success",queue="execution"} 1`)
+	assert.Contains(t, body, `kongflow_job_duration_seconds_sum{kind="start_run",queue="execution"} 2`)
+	assert.Contains(t, body, `kongflow_auth_failures_total{reason="invalid_key"} 1`)
+	assert.Contains(t, body, `kongflow_runs_finished_total{status="FAILURE"} 1`)
+	assert.Contains(t, body, "# EOF")
+
+	// Plain Prometheus text format is still served to scrapers without OpenMetrics support
+	_, contentType = scrape(t, m, false)
+	assert.Contains(t, contentType, "text/plain")
+}
+
+func TestMetrics_QueueStats(t *testing.T) {
+	m := New()
+	source := &fakeQueueStats{stats: &workerqueue.ManagerStats{
+		Queues: []workerqueue.QueueStats{{
+			Queue:              "events",
+			JobStateCounts:     workerqueue.JobStateCounts{Available: 7, Running: 2, Discarded: 1},
+			OldestAvailableAge: 30 * time.Second,
+			MaxWorkers:         20,
+			ActiveWorkers:      2,
+			Utilization:        0.1,
+		}},
+	}}
+	require.NoError(t, m.RegisterQueueStats(source))
+
+	body, _ := scrape(t, m, false)
+	assert.Contains(t, body, `kongflow_queue_jobs{queue="events",state="available"} 7`)
+	assert.Contains(t, body, `kongflow_queue_jobs{queue="events",state="running"} 2`)
+	assert.Contains(t, body, `kongflow_queue_jobs{queue="events",state="discarded"} 1`)
+	assert.Contains(t, body, `kongflow_queue_oldest_available_job_age_seconds{queue="events"} 30`)
+	assert.Contains(t, body, `kongflow_queue_active_workers{queue="events"} 2`)
+	assert.Contains(t, body, `kongflow_queue_worker_utilization_ratio{queue="events"} 0.1`)
+
+	// A failed stats query fails the scrape instead of reporting stale values
+	source.err = errors.New("database unavailable")
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,108 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+func itemKey(i item) (time.Time, uuid.UUID) {
+	return i.createdAt, i.id
+}
+
+// newItems 返回按 (created_at, id) 倒序排列的记录
+func newItems(n int) []item {
+	base := time.Now().UTC().Truncate(time.Second)
+	items := make([]item, n)
+	for i := range items {
+		items[i] = item{createdAt: base.Add(-time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+	return items
+}
+
+func TestEncodeDecode(t *testing.T) {
+	c := Cursor{CreatedAt: time.Now().UTC(), ID: uuid.New(), Direction: Prev}
+
+	decoded, err := Decode(Encode(c))
+	require.NoError(t, err)
+	assert.True(t, c.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, c.ID, decoded.ID)
+	assert.True(t, decoded.Backward())
+}
+
+func TestDecode_Empty(t *testing.T) {
+	c, err := Decode("")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+	assert.False(t, c.Backward())
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, s := range []string{"%%%", "bm90LWpzb24", Encode(Cursor{ID: uuid.New(), Direction: Next})} {
+		_, err := Decode(s)
+		assert.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestNormalizeLimit(t *testing.T) {
+	assert.Equal(t, DefaultLimit, NormalizeLimit(0))
+	assert.Equal(t, int32(5), NormalizeLimit(5))
+	assert.Equal(t, MaxLimit, NormalizeLimit(MaxLimit+1))
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `a\_b\%c\\`, EscapeLike(`a_b%c\`))
+}
+
+func TestPage_Forward(t *testing.T) {
+	items := newItems(3)
+
+	page, next, prev := Page(items, 2, nil, itemKey)
+	require.Len(t, page, 2)
+	assert.Empty(t, prev)
+	require.NotEmpty(t, next)
+
+	c, err := Decode(next)
+	require.NoError(t, err)
+	assert.Equal(t, items[1].id, c.ID)
+	assert.Equal(t, Next, c.Direction)
+
+	page, next, prev = Page(items[2:], 2, c, itemKey)
+	require.Len(t, page, 1)
+	assert.Empty(t, next)
+	assert.NotEmpty(t, prev)
+}
+
+func TestPage_Backward(t *testing.T) {
+	items := newItems(4)
+	cursor := &Cursor{CreatedAt: items[3].createdAt, ID: items[3].id, Direction: Prev}
+
+	// 反向查询按正序返回，多取的一条表示前面还有记录
+	rows := []item{items[2], items[1], items[0]}
+	page, next, prev := Page(rows, 2, cursor, itemKey)
+	require.Len(t, page, 2)
+	assert.Equal(t, items[1].id, page[0].id)
+	assert.Equal(t, items[2].id, page[1].id)
+	assert.NotEmpty(t, next)
+	require.NotEmpty(t, prev)
+
+	c, err := Decode(prev)
+	require.NoError(t, err)
+	assert.Equal(t, items[1].id, c.ID)
+	assert.True(t, c.Backward())
+}
+
+func TestPage_Empty(t *testing.T) {
+	page, next, prev := Page([]item{}, 2, nil, itemKey)
+	assert.Empty(t, page)
+	assert.Empty(t, next)
+	assert.Empty(t, prev)
+}
@@ -0,0 +1,129 @@
+package runs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrRunNotCancelable 运行已结束，无法取消
+	ErrRunNotCancelable = errors.New("run already finished")
+	// ErrRunNotFinished 运行尚未结束，无法重跑
+	ErrRunNotFinished = errors.New("run not finished")
+)
+
+// CancelRun 取消运行，对齐 trigger.dev CancelRunService.call
+// 在同一事务中取消未完成的任务和尚未开始的执行任务，并释放运行占用的队列槽位
+func (s *service) CancelRun(ctx context.Context, runID string) (*RunResponse, error) {
+	logger := s.logger.With("operation", "cancel_run", "run_id", runID)
+
+	id, err := stringToPgUUID(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	var run JobRuns
+	var canceledJobs int
+	err = s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		run, err = txRepo.CancelJobRun(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return runNotCancelable(ctx, txRepo, id)
+			}
+			return fmt.Errorf("failed to cancel run: %w", err)
+		}
+
+		if err := txRepo.CancelPendingTasks(ctx, id); err != nil {
+			return fmt.Errorf("failed to cancel tasks: %w", err)
+		}
+		if err := txRepo.CancelOpenJobRunExecutions(ctx, id); err != nil {
+			return fmt.Errorf("failed to cancel run executions: %w", err)
+		}
+
+		canceledJobs, err = s.queueSvc.CancelRunExecutionsTx(ctx, tx, runID)
+		if err != nil {
+			return fmt.Errorf("failed to cancel queued executions: %w", err)
+		}
+
+		if err := releaseQueueSlotTx(ctx, txRepo, id); err != nil {
+			return err
+		}
+		// 槽位已释放，startQueuedRuns 会启动下一个等待的运行
+		return s.finishRun(ctx, tx, run)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrRunNotFound) && !errors.Is(err, ErrRunNotCancelable) {
+			logger.Error("Failed to cancel run", "error", err)
+		}
+		return nil, err
+	}
+
+	logger.Info("Run canceled", "canceled_jobs", canceledJobs)
+	return convertRunToResponse(run), nil
+}
+
+// runNotCancelable 区分运行不存在与运行已结束
+func runNotCancelable(ctx context.Context, repo Repository, id pgtype.UUID) error {
+	if _, err := repo.GetJobRunByID(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRunNotFound
+		}
+		return fmt.Errorf("failed to get run: %w", err)
+	}
+	return ErrRunNotCancelable
+}
+
+// RerunRun 基于原运行的事件记录创建新运行，对齐 trigger.dev ReRunService
+// 新运行使用原运行的版本或作业最新版本，并通过 rerun_of_id 关联原运行
+func (s *service) RerunRun(ctx context.Context, req *RerunRunRequest) (*RunResponse, error) {
+	id, err := stringToPgUUID(req.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.repo.GetJobRunByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get run: %w", err)
+	}
+	if !isRunFinished(original.Status) {
+		return nil, ErrRunNotFinished
+	}
+
+	versionID := original.VersionID
+	if req.UseLatestVersion {
+		versionID, err = s.repo.GetLatestJobVersionIDForRun(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest job version: %w", err)
+		}
+	}
+
+	createReq := &CreateRunRequest{
+		EventRecordID: pgUUIDToString(original.EventID),
+		VersionID:     pgUUIDToString(versionID),
+		IsTest:        original.IsTest,
+		RerunOfID:     req.RunID,
+	}
+	if original.ExternalAccountID.Valid {
+		externalAccountID := pgUUIDToString(original.ExternalAccountID)
+		createReq.ExternalAccountID = &externalAccountID
+	}
+
+	return s.CreateRun(ctx, createReq)
+}
+
+// isRunFinished 运行是否处于终止状态
+func isRunFinished(status JobRunStatus) bool {
+	switch status {
+	case JobRunStatusSUCCESS, JobRunStatusFAILURE, JobRunStatusABORTED, JobRunStatusCANCELED:
+		return true
+	default:
+		return false
+	}
+}
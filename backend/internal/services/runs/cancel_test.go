@@ -0,0 +1,134 @@
+package runs
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CancelRun_CancelsJobsAndReleasesSlot(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	metrics := &recordingMetrics{}
+	svc := NewServiceWithMetrics(repo, queueSvc, nil, nil, nil, metrics, slog.Default())
+
+	run := createTestRun(JobRunStatusEXECUTING)
+	canceled := run
+	canceled.Status = JobRunStatusCANCELED
+
+	repo.On("CancelJobRun", mock.Anything, run.ID).Return(canceled, nil)
+	repo.On("CancelPendingTasks", mock.Anything, run.ID).Return(nil)
+	repo.On("CancelOpenJobRunExecutions", mock.Anything, run.ID).Return(nil)
+	queueSvc.On("CancelRunExecutionsTx", mock.Anything, pgUUIDToString(run.ID)).Return(1, nil)
+	repo.On("ReleaseJobRunQueueSlot", mock.Anything, run.ID).Return(run.QueueID, nil)
+	repo.On("DecrementJobCount", mock.Anything, run.QueueID).Return(DecrementJobCountRow{ID: run.QueueID}, nil)
+	expectRunFinished(queueSvc, canceled)
+
+	result, err := svc.CancelRun(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	assert.Equal(t, JobRunStatusCANCELED, result.Status)
+	assert.Equal(t, []string{"CANCELED"}, metrics.finished)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_CancelRun_AlreadyFinished(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusSUCCESS)
+	repo.On("CancelJobRun", mock.Anything, run.ID).Return(JobRuns{}, pgx.ErrNoRows)
+	repo.On("GetJobRunByID", mock.Anything, run.ID).Return(run, nil)
+
+	_, err := svc.CancelRun(context.Background(), pgUUIDToString(run.ID))
+
+	assert.ErrorIs(t, err, ErrRunNotCancelable)
+	queueSvc.AssertNotCalled(t, "CancelRunExecutionsTx", mock.Anything, mock.Anything)
+}
+
+func TestService_CancelRun_NotFound(t *testing.T) {
+	repo := &MockRepository{}
+	svc := newTestService(repo, &MockQueueService{})
+
+	id := newPgUUID()
+	repo.On("CancelJobRun", mock.Anything, id).Return(JobRuns{}, pgx.ErrNoRows)
+	repo.On("GetJobRunByID", mock.Anything, id).Return(JobRuns{}, pgx.ErrNoRows)
+
+	_, err := svc.CancelRun(context.Background(), pgUUIDToString(id))
+
+	assert.ErrorIs(t, err, ErrRunNotFound)
+}
+
+func TestService_RerunRun_UsesLatestVersion(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	original := createTestRun(JobRunStatusFAILURE)
+	original.ExternalAccountID = newPgUUID()
+	latest := GetJobVersionForRunRow{ID: newPgUUID(), JobID: original.JobID, QueueID: original.QueueID}
+	rerun := createTestRun(JobRunStatusPENDING)
+	rerun.RerunOfID = original.ID
+
+	repo.On("GetJobRunByID", mock.Anything, original.ID).Return(original, nil)
+	repo.On("GetLatestJobVersionIDForRun", mock.Anything, original.ID).Return(latest.ID, nil)
+	repo.On("GetJobVersionForRun", mock.Anything, latest.ID).Return(latest, nil)
+	repo.On("ListMissingIntegrationsForVersion", mock.Anything, latest.ID).Return([]ListMissingIntegrationsForVersionRow{}, nil)
+	repo.On("CreateJobRun", mock.Anything, mock.MatchedBy(func(p CreateJobRunParams) bool {
+		return p.VersionID == latest.ID && p.EventID == original.EventID &&
+			p.RerunOfID == original.ID && p.ExternalAccountID == original.ExternalAccountID
+	})).Return(rerun, nil)
+	queueSvc.On("EnqueueStartRunTx", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := svc.RerunRun(context.Background(), &RerunRunRequest{
+		RunID:            pgUUIDToString(original.ID),
+		UseLatestVersion: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, pgUUIDToString(original.ID), result.RerunOfID)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_RerunRun_RequiresFinishedRun(t *testing.T) {
+	repo := &MockRepository{}
+	svc := newTestService(repo, &MockQueueService{})
+
+	run := createTestRun(JobRunStatusEXECUTING)
+	repo.On("GetJobRunByID", mock.Anything, run.ID).Return(run, nil)
+
+	_, err := svc.RerunRun(context.Background(), &RerunRunRequest{RunID: pgUUIDToString(run.ID)})
+
+	assert.ErrorIs(t, err, ErrRunNotFinished)
+	repo.AssertNotCalled(t, "CreateJobRun", mock.Anything, mock.Anything)
+}
+
+func TestService_PerformRunExecution_SkipsCanceledRun(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusQUEUED)
+
+	// 运行在读取执行上下文后被取消
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, "http://localhost"), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(JobRuns{}, pgx.ErrNoRows)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonExecuteJob,
+	})
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "UpsertJobRunExecution", mock.Anything, mock.Anything)
+}
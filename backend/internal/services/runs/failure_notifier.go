@@ -0,0 +1,175 @@
+package runs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"kongflow/backend/internal/services/email"
+	"kongflow/backend/internal/services/endpointapi"
+	"kongflow/backend/internal/services/rendermarkdown"
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FailureNotificationWindow 同一作业的失败通知去重窗口
+const FailureNotificationWindow = time.Hour
+
+// FailureNotifier 运行失败时通过 workflow_failed 邮件通知组织成员
+// 只通知生产环境的运行，同一作业在去重窗口内只通知一次，并跳过关闭了运行失败邮件的成员
+type FailureNotifier struct {
+	repo      Repository
+	emailSvc  email.EmailService
+	appOrigin string
+	logger    *slog.Logger
+}
+
+var _ workerqueue.RunFailureNotifier = (*FailureNotifier)(nil)
+
+// NewFailureNotifier 创建运行失败通知器，appOrigin 用于生成运行链接
+func NewFailureNotifier(repo Repository, emailSvc email.EmailService, appOrigin string, logger *slog.Logger) *FailureNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &FailureNotifier{
+		repo:      repo,
+		emailSvc:  emailSvc,
+		appOrigin: strings.TrimRight(appOrigin, "/"),
+		logger:    logger,
+	}
+}
+
+// NotifyRunFailed 向组织成员发送运行失败邮件
+// 占用去重窗口与发送在同一事务中完成，全部收件人发送失败时释放窗口以便重试
+func (n *FailureNotifier) NotifyRunFailed(ctx context.Context, runID string) error {
+	logger := n.logger.With("operation", "notify_run_failed", "run_id", runID)
+
+	id, err := stringToPgUUID(runID)
+	if err != nil {
+		return err
+	}
+
+	run, err := n.repo.GetRunFailureNotificationContext(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Debug("Run not failed, skipping notification")
+			return nil
+		}
+		return fmt.Errorf("failed to get run failure context: %w", err)
+	}
+	if run.EnvironmentType != "PRODUCTION" {
+		return nil
+	}
+
+	failure := runFailureError(run.Output)
+	failureHTML, err := rendermarkdown.RenderMarkdown(failureMarkdown(failure))
+	if err != nil {
+		// 渲染失败时邮件回退为纯文本错误信息
+		logger.Warn("Failed to render failure reason", "error", err)
+		failureHTML = ""
+	}
+
+	data := email.WorkflowFailedEmailData{
+		WorkflowName:      run.JobTitle,
+		FailureReason:     failure.Message,
+		FailureReasonHTML: template.HTML(failureHTML),
+		WorkflowLink:      RunLink(n.appOrigin, run.OrganizationSlug, run.ProjectSlug, run.JobSlug, runID),
+		RunID:             runID,
+	}
+	if run.CompletedAt.Valid {
+		data.FailedAt = run.CompletedAt.Time.UTC().Format(time.RFC1123)
+	}
+
+	// 没有收件人时不占用去重窗口，成员加入后的下一次失败仍会通知
+	recipients, err := n.repo.ListRunFailureRecipients(ctx, run.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("failed to list recipients: %w", err)
+	}
+	if len(recipients) == 0 {
+		logger.Info("No recipients for run failure notification, skipping")
+		return nil
+	}
+
+	sent := 0
+	err = n.repo.WithTx(ctx, func(txRepo Repository) error {
+		if _, err := txRepo.ClaimJobFailureNotification(ctx, ClaimJobFailureNotificationParams{
+			JobID:         run.JobID,
+			RunID:         run.ID,
+			WindowSeconds: int32(FailureNotificationWindow / time.Second),
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Info("Job failure already notified within window, skipping")
+				return nil
+			}
+			return fmt.Errorf("failed to claim failure notification: %w", err)
+		}
+
+		var lastErr error
+		for _, recipient := range recipients {
+			data.UserName = recipient.Name.String
+			payload, err := json.Marshal(data)
+			if err != nil {
+				return fmt.Errorf("failed to marshal workflow failed data: %w", err)
+			}
+
+			if err := n.emailSvc.ScheduleEmail(ctx, email.DeliverEmail{
+				Email: string(email.EmailTypeWorkflowFailed),
+				To:    recipient.Email,
+				Data:  payload,
+			}, nil); err != nil {
+				logger.Warn("Failed to send workflow failed email", "to", recipient.Email, "error", err)
+				lastErr = err
+				continue
+			}
+			sent++
+		}
+
+		if sent == 0 && lastErr != nil {
+			return fmt.Errorf("failed to send workflow failed emails: %w", lastErr)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if sent > 0 {
+		logger.Info("Run failure notified", "recipients", sent)
+	}
+	return nil
+}
+
+// RunLink 运行详情页面的链接
+func RunLink(appOrigin, organizationSlug, projectSlug, jobSlug, runID string) string {
+	return fmt.Sprintf("%s/orgs/%s/projects/%s/jobs/%s/runs/%s",
+		strings.TrimRight(appOrigin, "/"),
+		url.PathEscape(organizationSlug), url.PathEscape(projectSlug),
+		url.PathEscape(jobSlug), url.PathEscape(runID))
+}
+
+// runFailureError 从运行输出中解析失败错误，输出无法解析时使用通用信息
+func runFailureError(output []byte) endpointapi.ErrorWithStack {
+	var failure endpointapi.ErrorWithStack
+	if len(output) > 0 {
+		_ = json.Unmarshal(output, &failure)
+	}
+	if failure.Message == "" {
+		failure.Message = "Run failed"
+	}
+	return failure
+}
+
+// failureMarkdown 错误信息按 markdown 渲染，堆栈放在代码块中
+func failureMarkdown(failure endpointapi.ErrorWithStack) string {
+	if failure.Stack == "" {
+		return failure.Message
+	}
+	return fmt.Sprintf("%s\n\n```\n%s\n```", failure.Message, failure.Stack)
+}
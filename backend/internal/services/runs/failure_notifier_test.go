@@ -0,0 +1,144 @@
+package runs
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"kongflow/backend/internal/services/email"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockEmailService 模拟邮件服务，只实现 ScheduleEmail
+type MockEmailService struct {
+	email.EmailService
+	mock.Mock
+}
+
+func (m *MockEmailService) ScheduleEmail(ctx context.Context, data email.DeliverEmail, delay *time.Duration) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func createTestFailureContext(environmentType string) GetRunFailureNotificationContextRow {
+	return GetRunFailureNotificationContextRow{
+		ID:               newPgUUID(),
+		JobID:            newPgUUID(),
+		OrganizationID:   newPgUUID(),
+		Output:           []byte(`{"message":"Charge **declined**","stack":"at charge()"}`),
+		CompletedAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		JobSlug:          "charge-customer",
+		JobTitle:         "Charge customer",
+		EnvironmentType:  environmentType,
+		OrganizationSlug: "acme",
+		ProjectSlug:      "billing",
+	}
+}
+
+func TestFailureNotifier_NotifiesOrganizationMembers(t *testing.T) {
+	repo := &MockRepository{}
+	emailSvc := &MockEmailService{}
+	notifier := NewFailureNotifier(repo, emailSvc, "https://app.kongflow.dev/", slog.Default())
+
+	run := createTestFailureContext("PRODUCTION")
+	runID := pgUUIDToString(run.ID)
+
+	repo.On("GetRunFailureNotificationContext", mock.Anything, run.ID).Return(run, nil)
+	repo.On("ClaimJobFailureNotification", mock.Anything, ClaimJobFailureNotificationParams{
+		JobID:         run.JobID,
+		RunID:         run.ID,
+		WindowSeconds: 3600,
+	}).Return(run.JobID, nil)
+	repo.On("ListRunFailureRecipients", mock.Anything, run.OrganizationID).Return([]ListRunFailureRecipientsRow{
+		{Email: "alice@acme.dev", Name: pgtype.Text{String: "Alice", Valid: true}},
+		{Email: "bob@acme.dev"},
+	}, nil)
+
+	var sent []email.WorkflowFailedEmailData
+	emailSvc.On("ScheduleEmail", mock.Anything, mock.MatchedBy(func(d email.DeliverEmail) bool {
+		return d.Email == string(email.EmailTypeWorkflowFailed)
+	})).Run(func(args mock.Arguments) {
+		var data email.WorkflowFailedEmailData
+		require.NoError(t, json.Unmarshal(args.Get(1).(email.DeliverEmail).Data, &data))
+		sent = append(sent, data)
+	}).Return(nil)
+
+	err := notifier.NotifyRunFailed(context.Background(), runID)
+
+	require.NoError(t, err)
+	require.Len(t, sent, 2)
+	assert.Equal(t, "Alice", sent[0].UserName)
+	assert.Equal(t, "Charge customer", sent[0].WorkflowName)
+	assert.Equal(t, "Charge **declined**", sent[0].FailureReason)
+	assert.Contains(t, string(sent[0].FailureReasonHTML), "<strong>declined</strong>")
+	assert.Contains(t, string(sent[0].FailureReasonHTML), "at charge()")
+	assert.Equal(t, "https://app.kongflow.dev/orgs/acme/projects/billing/jobs/charge-customer/runs/"+runID, sent[0].WorkflowLink)
+	assert.Empty(t, sent[1].UserName)
+	emailSvc.AssertNumberOfCalls(t, "ScheduleEmail", 2)
+}
+
+func TestFailureNotifier_SkipsNonProductionRuns(t *testing.T) {
+	repo := &MockRepository{}
+	emailSvc := &MockEmailService{}
+	notifier := NewFailureNotifier(repo, emailSvc, "https://app.kongflow.dev", slog.Default())
+
+	run := createTestFailureContext("DEVELOPMENT")
+	repo.On("GetRunFailureNotificationContext", mock.Anything, run.ID).Return(run, nil)
+
+	err := notifier.NotifyRunFailed(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "ClaimJobFailureNotification", mock.Anything, mock.Anything)
+	emailSvc.AssertNotCalled(t, "ScheduleEmail", mock.Anything, mock.Anything)
+}
+
+func TestFailureNotifier_DeduplicatesPerJob(t *testing.T) {
+	repo := &MockRepository{}
+	emailSvc := &MockEmailService{}
+	notifier := NewFailureNotifier(repo, emailSvc, "https://app.kongflow.dev", slog.Default())
+
+	run := createTestFailureContext("PRODUCTION")
+	repo.On("GetRunFailureNotificationContext", mock.Anything, run.ID).Return(run, nil)
+	repo.On("ListRunFailureRecipients", mock.Anything, run.OrganizationID).Return([]ListRunFailureRecipientsRow{
+		{Email: "alice@acme.dev"},
+	}, nil)
+	// 同一作业在窗口内已经通知过
+	repo.On("ClaimJobFailureNotification", mock.Anything, mock.Anything).Return(pgtype.UUID{}, pgx.ErrNoRows)
+
+	err := notifier.NotifyRunFailed(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	emailSvc.AssertNotCalled(t, "ScheduleEmail", mock.Anything, mock.Anything)
+}
+
+func TestFailureNotifier_NoRecipientsDoesNotClaim(t *testing.T) {
+	repo := &MockRepository{}
+	emailSvc := &MockEmailService{}
+	notifier := NewFailureNotifier(repo, emailSvc, "https://app.kongflow.dev", slog.Default())
+
+	run := createTestFailureContext("PRODUCTION")
+	repo.On("GetRunFailureNotificationContext", mock.Anything, run.ID).Return(run, nil)
+	repo.On("ListRunFailureRecipients", mock.Anything, run.OrganizationID).Return([]ListRunFailureRecipientsRow{}, nil)
+
+	err := notifier.NotifyRunFailed(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "ClaimJobFailureNotification", mock.Anything, mock.Anything)
+	emailSvc.AssertNotCalled(t, "ScheduleEmail", mock.Anything, mock.Anything)
+}
+
+func TestFailureMarkdown(t *testing.T) {
+	assert.Equal(t, "boom", failureMarkdown(runFailureError([]byte(`{"message":"boom"}`))))
+	assert.Equal(t, "Run failed", runFailureError(nil).Message)
+
+	markdown := failureMarkdown(runFailureError([]byte(`{"message":"boom","stack":"at main()"}`)))
+	assert.True(t, strings.HasPrefix(markdown, "boom\n\n```\nat main()"))
+}
@@ -0,0 +1,296 @@
+package runs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"kongflow/backend/internal/services/endpointapi"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// UUID 转换辅助函数
+
+// stringToPgUUID 将字符串转换为 pgtype.UUID
+func stringToPgUUID(s string) (pgtype.UUID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("invalid UUID %q: %w", s, err)
+	}
+	return pgtype.UUID{Bytes: u, Valid: true}, nil
+}
+
+// pgUUIDToString 将 pgtype.UUID 转换为字符串
+func pgUUIDToString(pu pgtype.UUID) string {
+	if !pu.Valid {
+		return ""
+	}
+	return uuid.UUID(pu.Bytes).String()
+}
+
+// timestamptzToPtr 将 pgtype.Timestamptz 转换为 *time.Time
+func timestamptzToPtr(ts pgtype.Timestamptz) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	t := ts.Time
+	return &t
+}
+
+// JSON 转换辅助函数
+
+// jsonbToMap 将 JSONB 转换为 map[string]interface{}
+func jsonbToMap(data []byte) map[string]interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+	return result
+}
+
+// jsonbToValue 将 JSONB 转换为任意 JSON 值
+func jsonbToValue(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+	return result
+}
+
+// readErrorBody 读取非 2xx 响应体作为错误信息，对齐 trigger.dev 的 rawBody 处理
+func readErrorBody(resp *http.Response) string {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil || len(body) == 0 {
+		return resp.Status
+	}
+	return string(body)
+}
+
+// completeExecution 记录一次执行的结果
+func completeExecution(ctx context.Context, repo Repository, id pgtype.UUID, status string, execErr *endpointapi.ErrorWithStack) error {
+	var errData []byte
+	if execErr != nil {
+		data, err := json.Marshal(execErr)
+		if err != nil {
+			return fmt.Errorf("failed to marshal execution error: %w", err)
+		}
+		errData = data
+	}
+
+	if err := repo.CompleteJobRunExecution(ctx, CompleteJobRunExecutionParams{
+		ID:     id,
+		Status: status,
+		Error:  errData,
+	}); err != nil {
+		return fmt.Errorf("failed to record run execution: %w", err)
+	}
+	return nil
+}
+
+// jsonbToError 解析端点返回的 ErrorWithStack
+func jsonbToError(data []byte) *endpointapi.ErrorWithStack {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var result endpointapi.ErrorWithStack
+	if err := json.Unmarshal(data, &result); err != nil || result.Message == "" {
+		return nil
+	}
+	return &result
+}
+
+// marshalJSONB 将任意值序列化为 JSONB，nil 保持为 NULL
+func marshalJSONB(v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// buildRunContext 构建发送给端点的运行上下文，对齐 trigger.dev RunJobBody 结构
+func buildRunContext(row GetJobRunExecutionContextRow, isRetry bool) (map[string]interface{}, map[string]interface{}) {
+	runContext := map[string]interface{}{
+		"event": map[string]interface{}{
+			"id":        row.EventID,
+			"name":      row.EventName,
+			"source":    row.EventSource,
+			"context":   jsonbToMap(row.EventContext),
+			"timestamp": timestamptzToPtr(row.EventTimestamp),
+		},
+		"job": map[string]interface{}{
+			"id":      row.JobSlug,
+			"version": row.JobVersion,
+		},
+		"environment": map[string]interface{}{
+			"id":   pgUUIDToString(row.EnvironmentID),
+			"slug": row.EnvironmentSlug,
+			"type": row.EnvironmentType,
+		},
+		"organization": map[string]interface{}{
+			"id":    pgUUIDToString(row.OrganizationID),
+			"slug":  row.OrganizationSlug,
+			"title": row.OrganizationTitle,
+		},
+		"project": map[string]interface{}{
+			"id":   pgUUIDToString(row.ProjectID),
+			"slug": row.ProjectSlug,
+			"name": row.ProjectName,
+		},
+	}
+
+	jobRun := map[string]interface{}{
+		"id":        pgUUIDToString(row.ID),
+		"isTest":    row.IsTest,
+		"startedAt": timestamptzToPtr(row.StartedAt),
+		"isRetry":   isRetry,
+	}
+	runContext["run"] = jobRun
+
+	return runContext, jobRun
+}
+
+// convertRunToResponse 转换运行记录为响应格式
+func convertRunToResponse(run JobRuns) *RunResponse {
+	var properties []endpointapi.DisplayProperty
+	if len(run.Properties) > 0 {
+		_ = json.Unmarshal(run.Properties, &properties)
+	}
+
+	return &RunResponse{
+		ID:            pgUUIDToString(run.ID),
+		JobID:         pgUUIDToString(run.JobID),
+		VersionID:     pgUUIDToString(run.VersionID),
+		EventID:       pgUUIDToString(run.EventID),
+		EnvironmentID: pgUUIDToString(run.EnvironmentID),
+		Status:        run.Status,
+		Properties:    properties,
+		Output:        jsonbToValue(run.Output),
+		IsTest:        run.IsTest,
+		Executions:    run.ExecutionCount,
+		QueuedAt:      timestamptzToPtr(run.QueuedAt),
+		StartedAt:     timestamptzToPtr(run.StartedAt),
+		CompletedAt:   timestamptzToPtr(run.CompletedAt),
+		CreatedAt:     run.CreatedAt.Time,
+		RerunOfID:     pgUUIDToString(run.RerunOfID),
+	}
+}
+
+// Task 转换辅助函数
+
+// upsertServerTask 按幂等键持久化端点报告的任务
+// status 非空时覆盖端点报告的状态
+func upsertServerTask(ctx context.Context, repo Repository, runID pgtype.UUID, task *endpointapi.ServerTask, status TaskStatus) (Tasks, error) {
+	if task.IdempotencyKey == "" {
+		return Tasks{}, fmt.Errorf("task %q is missing idempotency key", task.Name)
+	}
+
+	if status == "" {
+		status = TaskStatus(task.Status)
+	}
+	switch status {
+	case TaskStatusPENDING, TaskStatusWAITING, TaskStatusRUNNING,
+		TaskStatusCOMPLETED, TaskStatusERRORED, TaskStatusCANCELED:
+	default:
+		return Tasks{}, fmt.Errorf("task %q has invalid status %q", task.IdempotencyKey, task.Status)
+	}
+
+	params, err := marshalJSONB(task.Params)
+	if err != nil {
+		return Tasks{}, fmt.Errorf("failed to marshal task params: %w", err)
+	}
+	output, err := marshalJSONB(task.Output)
+	if err != nil {
+		return Tasks{}, fmt.Errorf("failed to marshal task output: %w", err)
+	}
+
+	upsert := UpsertTaskParams{
+		RunID:          runID,
+		IdempotencyKey: task.IdempotencyKey,
+		DisplayKey:     pgtype.Text{String: task.DisplayKey, Valid: task.DisplayKey != ""},
+		Name:           task.Name,
+		Icon:           pgtype.Text{String: task.Icon, Valid: task.Icon != ""},
+		Status:         status,
+		Noop:           task.Noop,
+		Params:         params,
+		Output:         output,
+	}
+	if task.ParentID != "" {
+		if parentID, err := stringToPgUUID(task.ParentID); err == nil {
+			upsert.ParentID = parentID
+		}
+	}
+	if task.Error != nil {
+		upsert.Error, err = json.Marshal(task.Error)
+		if err != nil {
+			return Tasks{}, fmt.Errorf("failed to marshal task error: %w", err)
+		}
+	}
+	if task.DelayUntil != nil {
+		upsert.DelayUntil = pgtype.Timestamptz{Time: *task.DelayUntil, Valid: true}
+	}
+	if status == TaskStatusCOMPLETED || status == TaskStatusERRORED {
+		upsert.CompletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	}
+
+	saved, err := repo.UpsertTask(ctx, upsert)
+	if err != nil {
+		return Tasks{}, fmt.Errorf("failed to save task %q: %w", task.IdempotencyKey, err)
+	}
+	return saved, nil
+}
+
+// convertTasksToCached 将已完成任务转换为回传给端点的缓存任务
+func convertTasksToCached(tasks []Tasks) []endpointapi.CachedTask {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	cached := make([]endpointapi.CachedTask, 0, len(tasks))
+	for _, task := range tasks {
+		cached = append(cached, endpointapi.CachedTask{
+			ID:             pgUUIDToString(task.ID),
+			IdempotencyKey: task.IdempotencyKey,
+			Status:         string(task.Status),
+			Noop:           task.Noop,
+			Output:         jsonbToValue(task.Output),
+			ParentID:       pgUUIDToString(task.ParentID),
+		})
+	}
+	return cached
+}
+
+// convertTaskToResponse 转换任务记录为响应格式
+func convertTaskToResponse(task Tasks) *TaskResponse {
+	return &TaskResponse{
+		ID:             pgUUIDToString(task.ID),
+		RunID:          pgUUIDToString(task.RunID),
+		ParentID:       pgUUIDToString(task.ParentID),
+		IdempotencyKey: task.IdempotencyKey,
+		DisplayKey:     task.DisplayKey.String,
+		Name:           task.Name,
+		Icon:           task.Icon.String,
+		Status:         task.Status,
+		Noop:           task.Noop,
+		Params:         jsonbToValue(task.Params),
+		Output:         jsonbToValue(task.Output),
+		Error:          jsonbToError(task.Error),
+		DelayUntil:     timestamptzToPtr(task.DelayUntil),
+		StartedAt:      timestamptzToPtr(task.StartedAt),
+		CompletedAt:    timestamptzToPtr(task.CompletedAt),
+	}
+}
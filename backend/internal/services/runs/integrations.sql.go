@@ -0,0 +1,226 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: integrations.sql
+
+package runs
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listIntegrationsForVersion = `-- name: ListIntegrationsForVersion :many
+SELECT jvi.key, jvi.integration_id
+FROM job_version_integrations jvi
+WHERE jvi.job_version_id = $1
+ORDER BY jvi.key
+`
+
+type ListIntegrationsForVersionRow struct {
+	Key           string      `json:"key"`
+	IntegrationID pgtype.UUID `json:"integration_id"`
+}
+
+// 作业版本所需的集成及其在作业中的键，执行时据此附加连接凭证
+func (q *Queries) ListIntegrationsForVersion(ctx context.Context, jobVersionID pgtype.UUID) ([]ListIntegrationsForVersionRow, error) {
+	rows, err := q.db.Query(ctx, listIntegrationsForVersion, jobVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListIntegrationsForVersionRow
+	for rows.Next() {
+		var i ListIntegrationsForVersionRow
+		if err := rows.Scan(&i.Key, &i.IntegrationID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMissingIntegrationsForVersion = `-- name: ListMissingIntegrationsForVersion :many
+
+SELECT i.id, i.organization_id, i.slug, i.title
+FROM job_version_integrations jvi
+JOIN integrations i ON i.id = jvi.integration_id
+WHERE jvi.job_version_id = $1
+    AND NOT EXISTS (
+        SELECT 1 FROM integration_connections c
+        WHERE c.integration_id = i.id
+            AND (c.expires_at IS NULL OR c.expires_at > NOW())
+    )
+ORDER BY i.slug
+`
+
+type ListMissingIntegrationsForVersionRow struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Slug           string      `json:"slug"`
+	Title          string      `json:"title"`
+}
+
+// integrations.sql
+// 运行所需集成连接的检查，对齐 trigger.dev MissingConnection 处理
+// 作业版本所需但尚未连接（或连接已过期）的集成
+func (q *Queries) ListMissingIntegrationsForVersion(ctx context.Context, jobVersionID pgtype.UUID) ([]ListMissingIntegrationsForVersionRow, error) {
+	rows, err := q.db.Query(ctx, listMissingIntegrationsForVersion, jobVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMissingIntegrationsForVersionRow
+	for rows.Next() {
+		var i ListMissingIntegrationsForVersionRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Slug,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRunsWaitingOnIntegration = `-- name: ListRunsWaitingOnIntegration :many
+SELECT r.id FROM job_runs r
+WHERE r.status = 'WAITING_ON_CONNECTIONS'
+    AND EXISTS (
+        SELECT 1 FROM job_version_integrations jvi
+        WHERE jvi.job_version_id = r.version_id AND jvi.integration_id = $1
+    )
+ORDER BY r.created_at
+`
+
+// 等待指定集成连接的运行
+func (q *Queries) ListRunsWaitingOnIntegration(ctx context.Context, integrationID pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listRunsWaitingOnIntegration, integrationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markJobRunPendingFromWaiting = `-- name: MarkJobRunPendingFromWaiting :one
+UPDATE job_runs
+SET status = 'PENDING', updated_at = NOW()
+WHERE id = $1 AND status = 'WAITING_ON_CONNECTIONS'
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+func (q *Queries) MarkJobRunPendingFromWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, markJobRunPendingFromWaiting, id)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const markMissingConnectionNotified = `-- name: MarkMissingConnectionNotified :execrows
+UPDATE missing_connections
+SET notified_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND notified_at IS NULL
+`
+
+// 原子地标记已通知，返回 0 表示已由其他运行发送过提醒
+func (q *Queries) MarkMissingConnectionNotified(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markMissingConnectionNotified, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseMissingConnectionNotification = `-- name: ReleaseMissingConnectionNotification :exec
+UPDATE missing_connections
+SET notified_at = NULL, updated_at = NOW()
+WHERE id = $1 AND resolved_at IS NULL
+`
+
+// 提醒发送失败时撤销通知标记，下一个等待该连接的运行会再次提醒
+func (q *Queries) ReleaseMissingConnectionNotification(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, releaseMissingConnectionNotification, id)
+	return err
+}
+
+const upsertMissingConnection = `-- name: UpsertMissingConnection :one
+INSERT INTO missing_connections (integration_id, organization_id)
+VALUES ($1, $2)
+ON CONFLICT (integration_id)
+DO UPDATE SET
+    notified_at = CASE WHEN missing_connections.resolved_at IS NULL
+        THEN missing_connections.notified_at ELSE NULL END,
+    resolved_at = NULL,
+    updated_at = NOW()
+RETURNING id, integration_id, organization_id, notified_at, resolved_at, created_at, updated_at
+`
+
+type UpsertMissingConnectionParams struct {
+	IntegrationID  pgtype.UUID `json:"integration_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+// 记录缺失连接，已解决的记录重新打开并重置通知时间
+func (q *Queries) UpsertMissingConnection(ctx context.Context, arg UpsertMissingConnectionParams) (MissingConnections, error) {
+	row := q.db.QueryRow(ctx, upsertMissingConnection, arg.IntegrationID, arg.OrganizationID)
+	var i MissingConnections
+	err := row.Scan(
+		&i.ID,
+		&i.IntegrationID,
+		&i.OrganizationID,
+		&i.NotifiedAt,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
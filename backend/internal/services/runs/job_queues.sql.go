@@ -0,0 +1,56 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: job_queues.sql
+
+package runs
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const decrementJobCount = `-- name: DecrementJobCount :one
+UPDATE job_queues
+SET job_count = GREATEST(job_count - 1, 0), updated_at = NOW()
+WHERE id = $1
+RETURNING id, job_count, max_jobs
+`
+
+type DecrementJobCountRow struct {
+	ID       pgtype.UUID `json:"id"`
+	JobCount int32       `json:"job_count"`
+	MaxJobs  int32       `json:"max_jobs"`
+}
+
+func (q *Queries) DecrementJobCount(ctx context.Context, id pgtype.UUID) (DecrementJobCountRow, error) {
+	row := q.db.QueryRow(ctx, decrementJobCount, id)
+	var i DecrementJobCountRow
+	err := row.Scan(&i.ID, &i.JobCount, &i.MaxJobs)
+	return i, err
+}
+
+const incrementJobCount = `-- name: IncrementJobCount :one
+
+UPDATE job_queues
+SET job_count = job_count + 1, updated_at = NOW()
+WHERE id = $1 AND job_count < max_jobs
+RETURNING id, job_count, max_jobs
+`
+
+type IncrementJobCountRow struct {
+	ID       pgtype.UUID `json:"id"`
+	JobCount int32       `json:"job_count"`
+	MaxJobs  int32       `json:"max_jobs"`
+}
+
+// job_queues.sql
+// JobQueue 并发槽位相关查询，对齐 trigger.dev StartRunService 的 jobCount 检查
+// 原子地占用一个并发槽位，队列已满时不返回任何行
+func (q *Queries) IncrementJobCount(ctx context.Context, id pgtype.UUID) (IncrementJobCountRow, error) {
+	row := q.db.QueryRow(ctx, incrementJobCount, id)
+	var i IncrementJobCountRow
+	err := row.Scan(&i.ID, &i.JobCount, &i.MaxJobs)
+	return i, err
+}
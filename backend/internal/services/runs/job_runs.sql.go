@@ -0,0 +1,871 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: job_runs.sql
+
+package runs
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelJobRun = `-- name: CancelJobRun :one
+UPDATE job_runs
+SET status = 'CANCELED', completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+// 取消尚未结束的运行，已结束的运行返回 no rows
+func (q *Queries) CancelJobRun(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, cancelJobRun, id)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const completeJobRun = `-- name: CompleteJobRun :one
+UPDATE job_runs
+SET status = $2, output = $3, completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+type CompleteJobRunParams struct {
+	ID     pgtype.UUID  `json:"id"`
+	Status JobRunStatus `json:"status"`
+	Output []byte       `json:"output"`
+}
+
+func (q *Queries) CompleteJobRun(ctx context.Context, arg CompleteJobRunParams) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, completeJobRun, arg.ID, arg.Status, arg.Output)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const createJobRun = `-- name: CreateJobRun :one
+
+INSERT INTO job_runs (
+    job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status,
+    is_test, preprocess, rerun_of_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+type CreateJobRunParams struct {
+	JobID             pgtype.UUID  `json:"job_id"`
+	VersionID         pgtype.UUID  `json:"version_id"`
+	EventID           pgtype.UUID  `json:"event_id"`
+	EnvironmentID     pgtype.UUID  `json:"environment_id"`
+	OrganizationID    pgtype.UUID  `json:"organization_id"`
+	ProjectID         pgtype.UUID  `json:"project_id"`
+	EndpointID        pgtype.UUID  `json:"endpoint_id"`
+	QueueID           pgtype.UUID  `json:"queue_id"`
+	ExternalAccountID pgtype.UUID  `json:"external_account_id"`
+	Status            JobRunStatus `json:"status"`
+	IsTest            bool         `json:"is_test"`
+	Preprocess        bool         `json:"preprocess"`
+	RerunOfID         pgtype.UUID  `json:"rerun_of_id"`
+}
+
+// job_runs.sql
+// JobRun 作业运行相关查询
+func (q *Queries) CreateJobRun(ctx context.Context, arg CreateJobRunParams) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, createJobRun,
+		arg.JobID,
+		arg.VersionID,
+		arg.EventID,
+		arg.EnvironmentID,
+		arg.OrganizationID,
+		arg.ProjectID,
+		arg.EndpointID,
+		arg.QueueID,
+		arg.ExternalAccountID,
+		arg.Status,
+		arg.IsTest,
+		arg.Preprocess,
+		arg.RerunOfID,
+	)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const getJobRunByEventAndVersion = `-- name: GetJobRunByEventAndVersion :one
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE event_id = $1 AND version_id = $2 AND rerun_of_id IS NULL
+ORDER BY created_at
+LIMIT 1
+`
+
+type GetJobRunByEventAndVersionParams struct {
+	EventID   pgtype.UUID `json:"event_id"`
+	VersionID pgtype.UUID `json:"version_id"`
+}
+
+// 事件已为该作业版本创建的运行（不含重跑），调度任务重试时复用
+func (q *Queries) GetJobRunByEventAndVersion(ctx context.Context, arg GetJobRunByEventAndVersionParams) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, getJobRunByEventAndVersion, arg.EventID, arg.VersionID)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const getJobRunByID = `-- name: GetJobRunByID :one
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE id = $1
+`
+
+func (q *Queries) GetJobRunByID(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, getJobRunByID, id)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const getJobRunExecutionContext = `-- name: GetJobRunExecutionContext :one
+SELECT
+    r.id, r.status, r.properties, r.is_test, r.preprocess, r.started_at,
+    r.execution_count, r.version_id,
+    e.event_id AS event_id, e.name AS event_name, e.source AS event_source,
+    e.payload AS event_payload, e.context AS event_context,
+    e.timestamp AS event_timestamp,
+    j.slug AS job_slug, v.version AS job_version,
+    env.id AS environment_id, env.slug AS environment_slug,
+    env.type AS environment_type, env.api_key AS environment_api_key,
+    o.id AS organization_id, o.slug AS organization_slug, o.title AS organization_title,
+    p.id AS project_id, p.slug AS project_slug, p.name AS project_name,
+    ep.id AS endpoint_id, ep.slug AS endpoint_slug, ep.url AS endpoint_url
+FROM job_runs r
+JOIN event_records e ON e.id = r.event_id
+JOIN job_versions v ON v.id = r.version_id
+JOIN jobs j ON j.id = r.job_id
+JOIN runtime_environments env ON env.id = r.environment_id
+JOIN organizations o ON o.id = r.organization_id
+JOIN projects p ON p.id = r.project_id
+JOIN endpoints ep ON ep.id = r.endpoint_id
+WHERE r.id = $1
+`
+
+type GetJobRunExecutionContextRow struct {
+	ID                pgtype.UUID        `json:"id"`
+	Status            JobRunStatus       `json:"status"`
+	Properties        []byte             `json:"properties"`
+	IsTest            bool               `json:"is_test"`
+	Preprocess        bool               `json:"preprocess"`
+	StartedAt         pgtype.Timestamptz `json:"started_at"`
+	ExecutionCount    int32              `json:"execution_count"`
+	VersionID         pgtype.UUID        `json:"version_id"`
+	EventID           string             `json:"event_id"`
+	EventName         string             `json:"event_name"`
+	EventSource       string             `json:"event_source"`
+	EventPayload      []byte             `json:"event_payload"`
+	EventContext      []byte             `json:"event_context"`
+	EventTimestamp    pgtype.Timestamptz `json:"event_timestamp"`
+	JobSlug           string             `json:"job_slug"`
+	JobVersion        string             `json:"job_version"`
+	EnvironmentID     pgtype.UUID        `json:"environment_id"`
+	EnvironmentSlug   string             `json:"environment_slug"`
+	EnvironmentType   string             `json:"environment_type"`
+	EnvironmentApiKey string             `json:"environment_api_key"`
+	OrganizationID    pgtype.UUID        `json:"organization_id"`
+	OrganizationSlug  string             `json:"organization_slug"`
+	OrganizationTitle string             `json:"organization_title"`
+	ProjectID         pgtype.UUID        `json:"project_id"`
+	ProjectSlug       string             `json:"project_slug"`
+	ProjectName       string             `json:"project_name"`
+	EndpointID        pgtype.UUID        `json:"endpoint_id"`
+	EndpointSlug      string             `json:"endpoint_slug"`
+	EndpointUrl       string             `json:"endpoint_url"`
+}
+
+// 加载执行运行所需的全部上下文，对齐 trigger.dev performRunExecution 的 include 结构
+func (q *Queries) GetJobRunExecutionContext(ctx context.Context, id pgtype.UUID) (GetJobRunExecutionContextRow, error) {
+	row := q.db.QueryRow(ctx, getJobRunExecutionContext, id)
+	var i GetJobRunExecutionContextRow
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.Properties,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.StartedAt,
+		&i.ExecutionCount,
+		&i.VersionID,
+		&i.EventID,
+		&i.EventName,
+		&i.EventSource,
+		&i.EventPayload,
+		&i.EventContext,
+		&i.EventTimestamp,
+		&i.JobSlug,
+		&i.JobVersion,
+		&i.EnvironmentID,
+		&i.EnvironmentSlug,
+		&i.EnvironmentType,
+		&i.EnvironmentApiKey,
+		&i.OrganizationID,
+		&i.OrganizationSlug,
+		&i.OrganizationTitle,
+		&i.ProjectID,
+		&i.ProjectSlug,
+		&i.ProjectName,
+		&i.EndpointID,
+		&i.EndpointSlug,
+		&i.EndpointUrl,
+	)
+	return i, err
+}
+
+const getJobStatus = `-- name: GetJobStatus :one
+SELECT status FROM jobs WHERE id = $1
+`
+
+// 作业暂停时运行保持 QUEUED，不占用队列槽位
+func (q *Queries) GetJobStatus(ctx context.Context, id pgtype.UUID) (JobStatus, error) {
+	row := q.db.QueryRow(ctx, getJobStatus, id)
+	var status JobStatus
+	err := row.Scan(&status)
+	return status, err
+}
+
+const getJobVersionForRun = `-- name: GetJobVersionForRun :one
+SELECT v.id, v.job_id, v.endpoint_id, v.environment_id, v.organization_id,
+    v.project_id, v.queue_id, v.preprocess_runs
+FROM job_versions v
+WHERE v.id = $1
+`
+
+type GetJobVersionForRunRow struct {
+	ID             pgtype.UUID `json:"id"`
+	JobID          pgtype.UUID `json:"job_id"`
+	EndpointID     pgtype.UUID `json:"endpoint_id"`
+	EnvironmentID  pgtype.UUID `json:"environment_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	ProjectID      pgtype.UUID `json:"project_id"`
+	QueueID        pgtype.UUID `json:"queue_id"`
+	PreprocessRuns bool        `json:"preprocess_runs"`
+}
+
+// 查找创建运行所需的作业版本信息
+func (q *Queries) GetJobVersionForRun(ctx context.Context, id pgtype.UUID) (GetJobVersionForRunRow, error) {
+	row := q.db.QueryRow(ctx, getJobVersionForRun, id)
+	var i GetJobVersionForRunRow
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.EndpointID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.QueueID,
+		&i.PreprocessRuns,
+	)
+	return i, err
+}
+
+const getLatestJobVersionIDForRun = `-- name: GetLatestJobVersionIDForRun :one
+SELECT a.version_id FROM job_aliases a
+JOIN job_runs r ON r.job_id = a.job_id AND r.environment_id = a.environment_id
+WHERE r.id = $1 AND a.name = 'latest'
+`
+
+// 运行所属作业在同一环境中 latest 别名指向的版本
+func (q *Queries) GetLatestJobVersionIDForRun(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getLatestJobVersionIDForRun, id)
+	var version_id pgtype.UUID
+	err := row.Scan(&version_id)
+	return version_id, err
+}
+
+const getNextQueuedJobRun = `-- name: GetNextQueuedJobRun :one
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE queue_id = $1 AND status = 'QUEUED' AND holds_queue_slot = false
+    AND NOT EXISTS (
+        SELECT 1 FROM jobs j WHERE j.id = job_runs.job_id AND j.status = 'PAUSED'
+    )
+ORDER BY queued_at, created_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+// 查找队列中最早等待槽位的运行，跳过被其他事务锁定的行和已暂停作业的运行
+func (q *Queries) GetNextQueuedJobRun(ctx context.Context, queueID pgtype.UUID) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, getNextQueuedJobRun, queueID)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const listTimedOutJobRuns = `-- name: ListTimedOutJobRuns :many
+SELECT r.id,
+    COALESCE(v.max_run_duration_seconds, e.default_max_run_duration_seconds)::INTEGER AS max_run_duration_seconds
+FROM job_runs r
+JOIN job_versions v ON v.id = r.version_id
+JOIN runtime_environments e ON e.id = r.environment_id
+WHERE r.status IN ('EXECUTING', 'WAITING')
+    AND COALESCE(v.max_run_duration_seconds, e.default_max_run_duration_seconds) IS NOT NULL
+    AND r.started_at < NOW() - make_interval(secs => COALESCE(v.max_run_duration_seconds, e.default_max_run_duration_seconds))
+ORDER BY r.started_at
+LIMIT $1
+`
+
+type ListTimedOutJobRunsRow struct {
+	ID                    pgtype.UUID `json:"id"`
+	MaxRunDurationSeconds int32       `json:"max_run_duration_seconds"`
+}
+
+// 执行中或等待中且超过最长持续时间的运行，作业版本未声明时使用环境默认值
+func (q *Queries) ListTimedOutJobRuns(ctx context.Context, limit int32) ([]ListTimedOutJobRunsRow, error) {
+	rows, err := q.db.Query(ctx, listTimedOutJobRuns, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTimedOutJobRunsRow
+	for rows.Next() {
+		var i ListTimedOutJobRunsRow
+		if err := rows.Scan(&i.ID, &i.MaxRunDurationSeconds); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWaitingQueuesByJob = `-- name: ListWaitingQueuesByJob :many
+SELECT DISTINCT queue_id
+FROM job_runs
+WHERE job_id = $1 AND status = 'QUEUED' AND holds_queue_slot = false
+`
+
+// 查找作业仍有等待运行的队列，作业恢复后逐个启动
+func (q *Queries) ListWaitingQueuesByJob(ctx context.Context, jobID pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listWaitingQueuesByJob, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var queue_id pgtype.UUID
+		if err := rows.Scan(&queue_id); err != nil {
+			return nil, err
+		}
+		items = append(items, queue_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markJobRunExecuting = `-- name: MarkJobRunExecuting :one
+UPDATE job_runs
+SET status = 'EXECUTING',
+    started_at = COALESCE(started_at, NOW()),
+    execution_count = execution_count + 1,
+    updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+// 每次调用端点执行前递增执行次数
+func (q *Queries) MarkJobRunExecuting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, markJobRunExecuting, id)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const markJobRunHoldsQueueSlot = `-- name: MarkJobRunHoldsQueueSlot :exec
+UPDATE job_runs
+SET holds_queue_slot = true, updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkJobRunHoldsQueueSlot(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markJobRunHoldsQueueSlot, id)
+	return err
+}
+
+const markJobRunPreprocessed = `-- name: MarkJobRunPreprocessed :one
+UPDATE job_runs
+SET status = $2,
+    properties = $3,
+    completed_at = CASE WHEN $2 = 'ABORTED'::job_run_status THEN NOW() ELSE completed_at END,
+    updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+type MarkJobRunPreprocessedParams struct {
+	ID         pgtype.UUID  `json:"id"`
+	Status     JobRunStatus `json:"status"`
+	Properties []byte       `json:"properties"`
+}
+
+// 保存预处理返回的属性，并根据 abort 标记决定运行是否继续
+func (q *Queries) MarkJobRunPreprocessed(ctx context.Context, arg MarkJobRunPreprocessedParams) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, markJobRunPreprocessed, arg.ID, arg.Status, arg.Properties)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const markJobRunPreprocessing = `-- name: MarkJobRunPreprocessing :one
+UPDATE job_runs
+SET status = 'PREPROCESSING', updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+func (q *Queries) MarkJobRunPreprocessing(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, markJobRunPreprocessing, id)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const markJobRunQueued = `-- name: MarkJobRunQueued :one
+UPDATE job_runs
+SET status = 'QUEUED', queued_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status = 'PENDING'
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+func (q *Queries) MarkJobRunQueued(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, markJobRunQueued, id)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const markJobRunWaiting = `-- name: MarkJobRunWaiting :one
+UPDATE job_runs
+SET status = 'WAITING', updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+func (q *Queries) MarkJobRunWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, markJobRunWaiting, id)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const releaseJobRunQueueSlot = `-- name: ReleaseJobRunQueueSlot :one
+UPDATE job_runs
+SET holds_queue_slot = false, updated_at = NOW()
+WHERE id = $1 AND holds_queue_slot = true
+RETURNING queue_id
+`
+
+// 仅在运行仍占用槽位时释放，重复调用不会多次递减 job_count
+func (q *Queries) ReleaseJobRunQueueSlot(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, releaseJobRunQueueSlot, id)
+	var queue_id pgtype.UUID
+	err := row.Scan(&queue_id)
+	return queue_id, err
+}
+
+const timeoutJobRun = `-- name: TimeoutJobRun :one
+UPDATE job_runs
+SET status = 'FAILURE', output = $2, completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status IN ('EXECUTING', 'WAITING')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+type TimeoutJobRunParams struct {
+	ID     pgtype.UUID `json:"id"`
+	Output []byte      `json:"output"`
+}
+
+// 仅超时仍处于执行中或等待中的运行，期间已结束的运行返回 no rows
+func (q *Queries) TimeoutJobRun(ctx context.Context, arg TimeoutJobRunParams) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, timeoutJobRun, arg.ID, arg.Output)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
+
+const updateJobRunStatus = `-- name: UpdateJobRunStatus :one
+UPDATE job_runs
+SET status = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+`
+
+type UpdateJobRunStatusParams struct {
+	ID     pgtype.UUID  `json:"id"`
+	Status JobRunStatus `json:"status"`
+}
+
+func (q *Queries) UpdateJobRunStatus(ctx context.Context, arg UpdateJobRunStatusParams) (JobRuns, error) {
+	row := q.db.QueryRow(ctx, updateJobRunStatus, arg.ID, arg.Status)
+	var i JobRuns
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.VersionID,
+		&i.EventID,
+		&i.EnvironmentID,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.EndpointID,
+		&i.QueueID,
+		&i.ExternalAccountID,
+		&i.Status,
+		&i.Properties,
+		&i.Output,
+		&i.IsTest,
+		&i.Preprocess,
+		&i.QueuedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExecutionCount,
+		&i.HoldsQueueSlot,
+		&i.RerunOfID,
+	)
+	return i, err
+}
@@ -0,0 +1,240 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package runs
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type JobRunStatus string
+
+const (
+	JobRunStatusPENDING              JobRunStatus = "PENDING"
+	JobRunStatusWAITINGONCONNECTIONS JobRunStatus = "WAITING_ON_CONNECTIONS"
+	JobRunStatusQUEUED               JobRunStatus = "QUEUED"
+	JobRunStatusPREPROCESSING        JobRunStatus = "PREPROCESSING"
+	JobRunStatusSTARTED              JobRunStatus = "STARTED"
+	JobRunStatusSUCCESS              JobRunStatus = "SUCCESS"
+	JobRunStatusFAILURE              JobRunStatus = "FAILURE"
+	JobRunStatusABORTED              JobRunStatus = "ABORTED"
+	JobRunStatusEXECUTING            JobRunStatus = "EXECUTING"
+	JobRunStatusWAITING              JobRunStatus = "WAITING"
+	JobRunStatusCANCELED             JobRunStatus = "CANCELED"
+)
+
+func (e *JobRunStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = JobRunStatus(s)
+	case string:
+		*e = JobRunStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for JobRunStatus: %T", src)
+	}
+	return nil
+}
+
+type NullJobRunStatus struct {
+	JobRunStatus JobRunStatus `json:"job_run_status"`
+	Valid        bool         `json:"valid"` // Valid is true if JobRunStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullJobRunStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.JobRunStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.JobRunStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullJobRunStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.JobRunStatus), nil
+}
+
+type JobStatus string
+
+const (
+	JobStatusENABLED  JobStatus = "ENABLED"
+	JobStatusPAUSED   JobStatus = "PAUSED"
+	JobStatusARCHIVED JobStatus = "ARCHIVED"
+)
+
+func (e *JobStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = JobStatus(s)
+	case string:
+		*e = JobStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for JobStatus: %T", src)
+	}
+	return nil
+}
+
+type NullJobStatus struct {
+	JobStatus JobStatus `json:"job_status"`
+	Valid     bool      `json:"valid"` // Valid is true if JobStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullJobStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.JobStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.JobStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullJobStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.JobStatus), nil
+}
+
+type TaskStatus string
+
+const (
+	TaskStatusPENDING   TaskStatus = "PENDING"
+	TaskStatusWAITING   TaskStatus = "WAITING"
+	TaskStatusRUNNING   TaskStatus = "RUNNING"
+	TaskStatusCOMPLETED TaskStatus = "COMPLETED"
+	TaskStatusERRORED   TaskStatus = "ERRORED"
+	TaskStatusCANCELED  TaskStatus = "CANCELED"
+)
+
+func (e *TaskStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TaskStatus(s)
+	case string:
+		*e = TaskStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TaskStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTaskStatus struct {
+	TaskStatus TaskStatus `json:"task_status"`
+	Valid      bool       `json:"valid"` // Valid is true if TaskStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTaskStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TaskStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TaskStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTaskStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TaskStatus), nil
+}
+
+// 运行执行记录，用于运行时间线
+type JobRunExecutions struct {
+	ID    pgtype.UUID `json:"id"`
+	RunID pgtype.UUID `json:"run_id"`
+	// 运行的第几次执行，对应 job_runs.execution_count
+	Number int32 `json:"number"`
+	// 执行原因，如 EXECUTE_JOB、PREPROCESS
+	Reason string `json:"reason"`
+	// 执行结果：EXECUTING、SUCCESS、ERROR、RESUME_WITH_TASK、YIELD_EXECUTION 或 FAILED（端点不可达）
+	Status string `json:"status"`
+	// 端点返回的 ErrorWithStack
+	Error       []byte             `json:"error"`
+	StartedAt   pgtype.Timestamptz `json:"started_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+// JobRun 作业运行表，对齐 trigger.dev JobRun 模型
+type JobRuns struct {
+	ID        pgtype.UUID `json:"id"`
+	JobID     pgtype.UUID `json:"job_id"`
+	VersionID pgtype.UUID `json:"version_id"`
+	// 触发运行的事件记录ID (event_records.id)
+	EventID           pgtype.UUID `json:"event_id"`
+	EnvironmentID     pgtype.UUID `json:"environment_id"`
+	OrganizationID    pgtype.UUID `json:"organization_id"`
+	ProjectID         pgtype.UUID `json:"project_id"`
+	EndpointID        pgtype.UUID `json:"endpoint_id"`
+	QueueID           pgtype.UUID `json:"queue_id"`
+	ExternalAccountID pgtype.UUID `json:"external_account_id"`
+	// 运行状态，对齐 trigger.dev JobRunStatus
+	Status JobRunStatus `json:"status"`
+	// 运行展示属性，由端点预处理返回
+	Properties []byte `json:"properties"`
+	// 运行输出 JSON
+	Output []byte `json:"output"`
+	IsTest bool   `json:"is_test"`
+	// 是否需要在执行前调用端点预处理
+	Preprocess  bool               `json:"preprocess"`
+	QueuedAt    pgtype.Timestamptz `json:"queued_at"`
+	StartedAt   pgtype.Timestamptz `json:"started_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	// 端点执行调用次数
+	ExecutionCount int32 `json:"execution_count"`
+	// 运行是否占用队列并发槽位 (job_queues.job_count)
+	HoldsQueueSlot bool `json:"holds_queue_slot"`
+	// 重新运行时指向原始运行
+	RerunOfID pgtype.UUID `json:"rerun_of_id"`
+}
+
+// 缺失的集成连接，用于挂起运行并发送连接提醒
+type MissingConnections struct {
+	ID             pgtype.UUID        `json:"id"`
+	IntegrationID  pgtype.UUID        `json:"integration_id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	NotifiedAt     pgtype.Timestamptz `json:"notified_at"`
+	ResolvedAt     pgtype.Timestamptz `json:"resolved_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Task 运行任务表，对齐 trigger.dev Task 模型
+type Tasks struct {
+	ID       pgtype.UUID `json:"id"`
+	RunID    pgtype.UUID `json:"run_id"`
+	ParentID pgtype.UUID `json:"parent_id"`
+	// 任务幂等键，在运行内唯一
+	IdempotencyKey string      `json:"idempotency_key"`
+	DisplayKey     pgtype.Text `json:"display_key"`
+	Name           string      `json:"name"`
+	Icon           pgtype.Text `json:"icon"`
+	Status         TaskStatus  `json:"status"`
+	Noop           bool        `json:"noop"`
+	Params         []byte      `json:"params"`
+	// 任务输出 JSON，后续执行时回传给端点
+	Output []byte `json:"output"`
+	// 端点返回的 ErrorWithStack
+	Error []byte `json:"error"`
+	// 延迟任务的恢复时间
+	DelayUntil  pgtype.Timestamptz `json:"delay_until"`
+	StartedAt   pgtype.Timestamptz `json:"started_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
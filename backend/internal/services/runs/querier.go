@@ -0,0 +1,109 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package runs
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Querier interface {
+	// 取消尚未结束的运行，已结束的运行返回 no rows
+	CancelJobRun(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	// 运行取消时结束进行中的执行记录
+	CancelOpenJobRunExecutions(ctx context.Context, runID pgtype.UUID) error
+	// 运行取消时取消未完成的任务
+	CancelPendingTasks(ctx context.Context, runID pgtype.UUID) error
+	// 原子地占用作业的通知窗口，窗口内已通知过时返回 no rows
+	ClaimJobFailureNotification(ctx context.Context, arg ClaimJobFailureNotificationParams) (pgtype.UUID, error)
+	CompleteJobRun(ctx context.Context, arg CompleteJobRunParams) (JobRuns, error)
+	CompleteJobRunExecution(ctx context.Context, arg CompleteJobRunExecutionParams) error
+	// 完成等待中的任务，对齐 trigger.dev CompleteRunTaskService / ResumeTaskService
+	CompleteTask(ctx context.Context, arg CompleteTaskParams) (Tasks, error)
+	// job_runs.sql
+	// JobRun 作业运行相关查询
+	CreateJobRun(ctx context.Context, arg CreateJobRunParams) (JobRuns, error)
+	DecrementJobCount(ctx context.Context, id pgtype.UUID) (DecrementJobCountRow, error)
+	// 事件已为该作业版本创建的运行（不含重跑），调度任务重试时复用
+	GetJobRunByEventAndVersion(ctx context.Context, arg GetJobRunByEventAndVersionParams) (JobRuns, error)
+	GetJobRunByID(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	// 运行对应的事件记录
+	GetJobRunEvent(ctx context.Context, id pgtype.UUID) (GetJobRunEventRow, error)
+	// 加载执行运行所需的全部上下文，对齐 trigger.dev performRunExecution 的 include 结构
+	GetJobRunExecutionContext(ctx context.Context, id pgtype.UUID) (GetJobRunExecutionContextRow, error)
+	// 作业暂停时运行保持 QUEUED，不占用队列槽位
+	GetJobStatus(ctx context.Context, id pgtype.UUID) (JobStatus, error)
+	// 查找创建运行所需的作业版本信息
+	GetJobVersionForRun(ctx context.Context, id pgtype.UUID) (GetJobVersionForRunRow, error)
+	// 运行所属作业在同一环境中 latest 别名指向的版本
+	GetLatestJobVersionIDForRun(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
+	// 查找队列中最早等待槽位的运行，跳过被其他事务锁定的行和已暂停作业的运行
+	GetNextQueuedJobRun(ctx context.Context, queueID pgtype.UUID) (JobRuns, error)
+	// notifications.sql
+	// 运行失败邮件通知相关查询
+	// 失败运行的作业、环境与组织信息，用于生成通知内容和链接
+	GetRunFailureNotificationContext(ctx context.Context, id pgtype.UUID) (GetRunFailureNotificationContextRow, error)
+	GetTaskByID(ctx context.Context, id pgtype.UUID) (Tasks, error)
+	GetTaskByIdempotencyKey(ctx context.Context, arg GetTaskByIdempotencyKeyParams) (Tasks, error)
+	// job_queues.sql
+	// JobQueue 并发槽位相关查询，对齐 trigger.dev StartRunService 的 jobCount 检查
+	// 原子地占用一个并发槽位，队列已满时不返回任何行
+	IncrementJobCount(ctx context.Context, id pgtype.UUID) (IncrementJobCountRow, error)
+	// 已完成任务在下次执行时作为缓存回传给端点
+	ListCompletedTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error)
+	// 作业版本所需的集成及其在作业中的键，执行时据此附加连接凭证
+	ListIntegrationsForVersion(ctx context.Context, jobVersionID pgtype.UUID) ([]ListIntegrationsForVersionRow, error)
+	ListJobRunExecutions(ctx context.Context, runID pgtype.UUID) ([]JobRunExecutions, error)
+	// run_queries.sql
+	// 运行查询与时间线相关查询，对齐 trigger.dev RunPresenter
+	// 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+	ListJobRunsPage(ctx context.Context, arg ListJobRunsPageParams) ([]JobRuns, error)
+	// 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+	ListJobRunsPageReverse(ctx context.Context, arg ListJobRunsPageReverseParams) ([]JobRuns, error)
+	// integrations.sql
+	// 运行所需集成连接的检查，对齐 trigger.dev MissingConnection 处理
+	// 作业版本所需但尚未连接（或连接已过期）的集成
+	ListMissingIntegrationsForVersion(ctx context.Context, jobVersionID pgtype.UUID) ([]ListMissingIntegrationsForVersionRow, error)
+	// 组织中未关闭运行失败邮件的成员
+	ListRunFailureRecipients(ctx context.Context, organizationID pgtype.UUID) ([]ListRunFailureRecipientsRow, error)
+	// 等待指定集成连接的运行
+	ListRunsWaitingOnIntegration(ctx context.Context, integrationID pgtype.UUID) ([]pgtype.UUID, error)
+	ListTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error)
+	// 执行中或等待中且超过最长持续时间的运行，作业版本未声明时使用环境默认值
+	ListTimedOutJobRuns(ctx context.Context, limit int32) ([]ListTimedOutJobRunsRow, error)
+	// 查找作业仍有等待运行的队列，作业恢复后逐个启动
+	ListWaitingQueuesByJob(ctx context.Context, jobID pgtype.UUID) ([]pgtype.UUID, error)
+	// 每次调用端点执行前递增执行次数
+	MarkJobRunExecuting(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	MarkJobRunHoldsQueueSlot(ctx context.Context, id pgtype.UUID) error
+	MarkJobRunPendingFromWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	// 保存预处理返回的属性，并根据 abort 标记决定运行是否继续
+	MarkJobRunPreprocessed(ctx context.Context, arg MarkJobRunPreprocessedParams) (JobRuns, error)
+	MarkJobRunPreprocessing(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	MarkJobRunQueued(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	MarkJobRunWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	// 原子地标记已通知，返回 0 表示已由其他运行发送过提醒
+	MarkMissingConnectionNotified(ctx context.Context, id pgtype.UUID) (int64, error)
+	// 提醒发送失败时撤销通知标记，下一个等待该连接的运行会再次提醒
+	ReleaseMissingConnectionNotification(ctx context.Context, id pgtype.UUID) error
+	// 仅在运行仍占用槽位时释放，重复调用不会多次递减 job_count
+	ReleaseJobRunQueueSlot(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
+	// 仅超时仍处于执行中或等待中的运行，期间已结束的运行返回 no rows
+	TimeoutJobRun(ctx context.Context, arg TimeoutJobRunParams) (JobRuns, error)
+	// 运行超时时结束进行中的执行记录
+	TimeoutOpenJobRunExecutions(ctx context.Context, arg TimeoutOpenJobRunExecutionsParams) error
+	UpdateJobRunStatus(ctx context.Context, arg UpdateJobRunStatusParams) (JobRuns, error)
+	// 记录一次执行的开始，同一次执行重试时重置结果
+	UpsertJobRunExecution(ctx context.Context, arg UpsertJobRunExecutionParams) (JobRunExecutions, error)
+	// 记录缺失连接，已解决的记录重新打开并重置通知时间
+	UpsertMissingConnection(ctx context.Context, arg UpsertMissingConnectionParams) (MissingConnections, error)
+	// tasks.sql
+	// Task 运行任务相关查询
+	// 按 (run_id, idempotency_key) 幂等写入任务，对齐 trigger.dev RunTaskService
+	UpsertTask(ctx context.Context, arg UpsertTaskParams) (Tasks, error)
+}
+
+var _ Querier = (*Queries)(nil)
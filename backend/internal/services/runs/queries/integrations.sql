@@ -0,0 +1,65 @@
+-- integrations.sql
+-- 运行所需集成连接的检查，对齐 trigger.dev MissingConnection 处理
+
+-- name: ListMissingIntegrationsForVersion :many
+-- 作业版本所需但尚未连接（或连接已过期）的集成
+SELECT i.id, i.organization_id, i.slug, i.title
+FROM job_version_integrations jvi
+JOIN integrations i ON i.id = jvi.integration_id
+WHERE jvi.job_version_id = $1
+    AND NOT EXISTS (
+        SELECT 1 FROM integration_connections c
+        WHERE c.integration_id = i.id
+            AND (c.expires_at IS NULL OR c.expires_at > NOW())
+    )
+ORDER BY i.slug;
+
+-- name: ListIntegrationsForVersion :many
+-- 作业版本所需的集成及其在作业中的键，执行时据此附加连接凭证
+SELECT jvi.key, jvi.integration_id
+FROM job_version_integrations jvi
+WHERE jvi.job_version_id = $1
+ORDER BY jvi.key;
+
+-- name: UpsertMissingConnection :one
+-- 记录缺失连接，已解决的记录重新打开并重置通知时间
+INSERT INTO missing_connections (integration_id, organization_id)
+VALUES ($1, $2)
+ON CONFLICT (integration_id)
+DO UPDATE SET
+    notified_at = CASE WHEN missing_connections.resolved_at IS NULL
+        THEN missing_connections.notified_at ELSE NULL END,
+    resolved_at = NULL,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: MarkMissingConnectionNotified :execrows
+-- 原子地标记已通知，返回 0 表示已由其他运行发送过提醒
+UPDATE missing_connections
+SET notified_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND notified_at IS NULL;
+
+-- name: ReleaseMissingConnectionNotification :exec
+-- 提醒发送失败时撤销通知标记，下一个等待该连接的运行会再次提醒
+UPDATE missing_connections
+SET notified_at = NULL, updated_at = NOW()
+WHERE id = $1 AND resolved_at IS NULL;
+
+-- name: ListRunsWaitingOnIntegration :many
+-- 等待指定集成连接的运行
+SELECT r.id FROM job_runs r
+WHERE r.status = 'WAITING_ON_CONNECTIONS'
+    AND EXISTS (
+        SELECT 1 FROM job_version_integrations jvi
+        WHERE jvi.job_version_id = r.version_id AND jvi.integration_id = $1
+    )
+ORDER BY r.created_at;
+
+-- name: MarkJobRunPendingFromWaiting :one
+UPDATE job_runs
+SET status = 'PENDING', updated_at = NOW()
+WHERE id = $1 AND status = 'WAITING_ON_CONNECTIONS'
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
@@ -0,0 +1,15 @@
+-- job_queues.sql
+-- JobQueue 并发槽位相关查询，对齐 trigger.dev StartRunService 的 jobCount 检查
+
+-- name: IncrementJobCount :one
+-- 原子地占用一个并发槽位，队列已满时不返回任何行
+UPDATE job_queues
+SET job_count = job_count + 1, updated_at = NOW()
+WHERE id = $1 AND job_count < max_jobs
+RETURNING id, job_count, max_jobs;
+
+-- name: DecrementJobCount :one
+UPDATE job_queues
+SET job_count = GREATEST(job_count - 1, 0), updated_at = NOW()
+WHERE id = $1
+RETURNING id, job_count, max_jobs;
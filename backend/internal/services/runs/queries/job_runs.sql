@@ -0,0 +1,210 @@
+-- job_runs.sql
+-- JobRun 作业运行相关查询
+
+-- name: CreateJobRun :one
+INSERT INTO job_runs (
+    job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status,
+    is_test, preprocess, rerun_of_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: GetJobRunByID :one
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE id = $1;
+
+-- name: GetJobRunByEventAndVersion :one
+-- 事件已为该作业版本创建的运行（不含重跑），调度任务重试时复用
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE event_id = $1 AND version_id = $2 AND rerun_of_id IS NULL
+ORDER BY created_at
+LIMIT 1;
+
+-- name: GetJobRunExecutionContext :one
+-- 加载执行运行所需的全部上下文，对齐 trigger.dev performRunExecution 的 include 结构
+SELECT
+    r.id, r.status, r.properties, r.is_test, r.preprocess, r.started_at,
+    r.execution_count, r.version_id,
+    e.event_id AS event_id, e.name AS event_name, e.source AS event_source,
+    e.payload AS event_payload, e.context AS event_context,
+    e.timestamp AS event_timestamp,
+    j.slug AS job_slug, v.version AS job_version,
+    env.id AS environment_id, env.slug AS environment_slug,
+    env.type AS environment_type, env.api_key AS environment_api_key,
+    o.id AS organization_id, o.slug AS organization_slug, o.title AS organization_title,
+    p.id AS project_id, p.slug AS project_slug, p.name AS project_name,
+    ep.id AS endpoint_id, ep.slug AS endpoint_slug, ep.url AS endpoint_url
+FROM job_runs r
+JOIN event_records e ON e.id = r.event_id
+JOIN job_versions v ON v.id = r.version_id
+JOIN jobs j ON j.id = r.job_id
+JOIN runtime_environments env ON env.id = r.environment_id
+JOIN organizations o ON o.id = r.organization_id
+JOIN projects p ON p.id = r.project_id
+JOIN endpoints ep ON ep.id = r.endpoint_id
+WHERE r.id = $1;
+
+-- name: GetJobVersionForRun :one
+-- 查找创建运行所需的作业版本信息
+SELECT v.id, v.job_id, v.endpoint_id, v.environment_id, v.organization_id,
+    v.project_id, v.queue_id, v.preprocess_runs
+FROM job_versions v
+WHERE v.id = $1;
+
+-- name: UpdateJobRunStatus :one
+UPDATE job_runs
+SET status = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: MarkJobRunQueued :one
+UPDATE job_runs
+SET status = 'QUEUED', queued_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status = 'PENDING'
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: MarkJobRunPreprocessing :one
+UPDATE job_runs
+SET status = 'PREPROCESSING', updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: MarkJobRunPreprocessed :one
+-- 保存预处理返回的属性，并根据 abort 标记决定运行是否继续
+UPDATE job_runs
+SET status = $2,
+    properties = $3,
+    completed_at = CASE WHEN $2 = 'ABORTED'::job_run_status THEN NOW() ELSE completed_at END,
+    updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: MarkJobRunExecuting :one
+-- 每次调用端点执行前递增执行次数
+UPDATE job_runs
+SET status = 'EXECUTING',
+    started_at = COALESCE(started_at, NOW()),
+    execution_count = execution_count + 1,
+    updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: MarkJobRunWaiting :one
+UPDATE job_runs
+SET status = 'WAITING', updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: CancelJobRun :one
+-- 取消尚未结束的运行，已结束的运行返回 no rows
+UPDATE job_runs
+SET status = 'CANCELED', completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: GetLatestJobVersionIDForRun :one
+-- 运行所属作业在同一环境中 latest 别名指向的版本
+SELECT a.version_id FROM job_aliases a
+JOIN job_runs r ON r.job_id = a.job_id AND r.environment_id = a.environment_id
+WHERE r.id = $1 AND a.name = 'latest';
+
+-- name: CompleteJobRun :one
+UPDATE job_runs
+SET status = $2, output = $3, completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('SUCCESS', 'FAILURE', 'ABORTED', 'CANCELED')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
+
+-- name: GetNextQueuedJobRun :one
+-- 查找队列中最早等待槽位的运行，跳过被其他事务锁定的行和已暂停作业的运行
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE queue_id = $1 AND status = 'QUEUED' AND holds_queue_slot = false
+    AND NOT EXISTS (
+        SELECT 1 FROM jobs j WHERE j.id = job_runs.job_id AND j.status = 'PAUSED'
+    )
+ORDER BY queued_at, created_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkJobRunHoldsQueueSlot :exec
+UPDATE job_runs
+SET holds_queue_slot = true, updated_at = NOW()
+WHERE id = $1;
+
+-- name: ReleaseJobRunQueueSlot :one
+-- 仅在运行仍占用槽位时释放，重复调用不会多次递减 job_count
+UPDATE job_runs
+SET holds_queue_slot = false, updated_at = NOW()
+WHERE id = $1 AND holds_queue_slot = true
+RETURNING queue_id;
+
+-- name: GetJobStatus :one
+-- 作业暂停时运行保持 QUEUED，不占用队列槽位
+SELECT status FROM jobs WHERE id = $1;
+
+-- name: ListWaitingQueuesByJob :many
+-- 查找作业仍有等待运行的队列，作业恢复后逐个启动
+SELECT DISTINCT queue_id
+FROM job_runs
+WHERE job_id = $1 AND status = 'QUEUED' AND holds_queue_slot = false;
+
+-- name: ListTimedOutJobRuns :many
+-- 执行中或等待中且超过最长持续时间的运行，作业版本未声明时使用环境默认值
+SELECT r.id,
+    COALESCE(v.max_run_duration_seconds, e.default_max_run_duration_seconds)::INTEGER AS max_run_duration_seconds
+FROM job_runs r
+JOIN job_versions v ON v.id = r.version_id
+JOIN runtime_environments e ON e.id = r.environment_id
+WHERE r.status IN ('EXECUTING', 'WAITING')
+    AND COALESCE(v.max_run_duration_seconds, e.default_max_run_duration_seconds) IS NOT NULL
+    AND r.started_at < NOW() - make_interval(secs => COALESCE(v.max_run_duration_seconds, e.default_max_run_duration_seconds))
+ORDER BY r.started_at
+LIMIT $1;
+
+-- name: TimeoutJobRun :one
+-- 仅超时仍处于执行中或等待中的运行，期间已结束的运行返回 no rows
+UPDATE job_runs
+SET status = 'FAILURE', output = $2, completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status IN ('EXECUTING', 'WAITING')
+RETURNING id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id;
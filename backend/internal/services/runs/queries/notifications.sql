@@ -0,0 +1,34 @@
+-- notifications.sql
+-- 运行失败邮件通知相关查询
+
+-- name: GetRunFailureNotificationContext :one
+-- 失败运行的作业、环境与组织信息，用于生成通知内容和链接
+SELECT r.id, r.job_id, r.organization_id, r.output, r.completed_at,
+    j.slug AS job_slug, j.title AS job_title,
+    e.type AS environment_type,
+    o.slug AS organization_slug,
+    p.slug AS project_slug
+FROM job_runs r
+JOIN jobs j ON j.id = r.job_id
+JOIN runtime_environments e ON e.id = r.environment_id
+JOIN organizations o ON o.id = r.organization_id
+JOIN projects p ON p.id = r.project_id
+WHERE r.id = $1 AND r.status = 'FAILURE';
+
+-- name: ClaimJobFailureNotification :one
+-- 原子地占用作业的通知窗口，窗口内已通知过时返回 no rows
+INSERT INTO job_failure_notifications (job_id, run_id, notified_at)
+VALUES (@job_id, @run_id, NOW())
+ON CONFLICT (job_id) DO UPDATE
+SET run_id = EXCLUDED.run_id, notified_at = NOW()
+WHERE job_failure_notifications.notified_at <= NOW() - make_interval(secs => @window_seconds::INTEGER)
+RETURNING job_id;
+
+-- name: ListRunFailureRecipients :many
+-- 组织中未关闭运行失败邮件的成员
+SELECT u.email, u.name
+FROM org_members m
+JOIN users u ON u.id = m.user_id
+LEFT JOIN user_notification_preferences np ON np.user_id = u.id
+WHERE m.organization_id = $1 AND COALESCE(np.run_failure_emails, true)
+ORDER BY u.email;
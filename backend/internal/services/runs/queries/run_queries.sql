@@ -0,0 +1,80 @@
+-- run_queries.sql
+-- 运行查询与时间线相关查询，对齐 trigger.dev RunPresenter
+
+-- name: ListJobRunsPage :many
+-- 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE project_id = sqlc.arg(project_id)
+    AND (sqlc.narg(job_id)::UUID IS NULL OR job_id = sqlc.narg(job_id)::UUID)
+    AND (sqlc.narg(environment_id)::UUID IS NULL OR environment_id = sqlc.narg(environment_id)::UUID)
+    AND (sqlc.narg(status)::job_run_status IS NULL OR status = sqlc.narg(status)::job_run_status)
+    AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+    AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+    AND (sqlc.narg(cursor_created_at)::TIMESTAMPTZ IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at)::TIMESTAMPTZ, sqlc.narg(cursor_id)::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListJobRunsPageReverse :many
+-- 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE project_id = sqlc.arg(project_id)
+    AND (sqlc.narg(job_id)::UUID IS NULL OR job_id = sqlc.narg(job_id)::UUID)
+    AND (sqlc.narg(environment_id)::UUID IS NULL OR environment_id = sqlc.narg(environment_id)::UUID)
+    AND (sqlc.narg(status)::job_run_status IS NULL OR status = sqlc.narg(status)::job_run_status)
+    AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+    AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+    AND (created_at, id) > (sqlc.arg(cursor_created_at)::TIMESTAMPTZ, sqlc.arg(cursor_id)::UUID)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(page_size);
+
+-- name: GetJobRunEvent :one
+-- 运行对应的事件记录
+SELECT e.id, e.event_id, e.name, e.source, e.payload, e.context, e.timestamp,
+    e.is_test, e.created_at, e.deliver_at, e.delivered_at
+FROM event_records e
+JOIN job_runs r ON r.event_id = e.id
+WHERE r.id = $1;
+
+-- name: UpsertJobRunExecution :one
+-- 记录一次执行的开始，同一次执行重试时重置结果
+INSERT INTO job_run_executions (run_id, number, reason)
+VALUES ($1, $2, $3)
+ON CONFLICT (run_id, number)
+DO UPDATE SET
+    reason = EXCLUDED.reason,
+    status = 'EXECUTING',
+    error = NULL,
+    started_at = NOW(),
+    completed_at = NULL
+RETURNING *;
+
+-- name: CompleteJobRunExecution :exec
+UPDATE job_run_executions
+SET status = $2, error = $3, completed_at = NOW()
+WHERE id = $1;
+
+-- name: ListJobRunExecutions :many
+SELECT * FROM job_run_executions
+WHERE run_id = $1
+ORDER BY number;
+
+-- name: CancelOpenJobRunExecutions :exec
+-- 运行取消时结束进行中的执行记录
+UPDATE job_run_executions
+SET status = 'CANCELED', completed_at = NOW()
+WHERE run_id = $1 AND status = 'EXECUTING';
+
+-- name: TimeoutOpenJobRunExecutions :exec
+-- 运行超时时结束进行中的执行记录
+UPDATE job_run_executions
+SET status = 'TIMED_OUT', error = $2, completed_at = NOW()
+WHERE run_id = $1 AND status = 'EXECUTING';
@@ -0,0 +1,66 @@
+-- tasks.sql
+-- Task 运行任务相关查询
+
+-- name: UpsertTask :one
+-- 按 (run_id, idempotency_key) 幂等写入任务，对齐 trigger.dev RunTaskService
+INSERT INTO tasks (
+    run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, completed_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (run_id, idempotency_key)
+DO UPDATE SET
+    status = EXCLUDED.status,
+    output = EXCLUDED.output,
+    error = EXCLUDED.error,
+    delay_until = EXCLUDED.delay_until,
+    completed_at = EXCLUDED.completed_at,
+    updated_at = NOW()
+RETURNING id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at;
+
+-- name: GetTaskByID :one
+SELECT id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+FROM tasks
+WHERE id = $1;
+
+-- name: GetTaskByIdempotencyKey :one
+SELECT id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+FROM tasks
+WHERE run_id = $1 AND idempotency_key = $2;
+
+-- name: ListTasksByRun :many
+SELECT id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+FROM tasks
+WHERE run_id = $1
+ORDER BY created_at ASC;
+
+-- name: ListCompletedTasksByRun :many
+-- 已完成任务在下次执行时作为缓存回传给端点
+SELECT id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+FROM tasks
+WHERE run_id = $1 AND status = 'COMPLETED'
+ORDER BY created_at ASC;
+
+-- name: CompleteTask :one
+-- 完成等待中的任务，对齐 trigger.dev CompleteRunTaskService / ResumeTaskService
+UPDATE tasks
+SET status = 'COMPLETED', output = $3, completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND run_id = $2 AND status IN ('PENDING', 'WAITING', 'RUNNING')
+RETURNING id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at;
+
+-- name: CancelPendingTasks :exec
+-- 运行取消时取消未完成的任务
+UPDATE tasks
+SET status = 'CANCELED', completed_at = NOW(), updated_at = NOW()
+WHERE run_id = $1 AND status IN ('PENDING', 'WAITING', 'RUNNING');
@@ -0,0 +1,286 @@
+package runs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"kongflow/backend/internal/services/endpointapi"
+	"kongflow/backend/internal/services/pagination"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrRunNotFound 运行不存在
+var ErrRunNotFound = errors.New("run not found")
+
+// QueryService 运行查询服务，用于调试运行，对齐 trigger.dev RunListPresenter / RunPresenter
+type QueryService interface {
+	// ListRuns 按作业、环境、状态和时间过滤并分页列出运行
+	ListRuns(ctx context.Context, req ListRunsRequest) (*ListRunsResponse, error)
+
+	// GetRunDetail 获取运行及其事件、任务、执行记录和错误
+	GetRunDetail(ctx context.Context, id string) (*RunDetailResponse, error)
+
+	// GetRunTimeline 按时间顺序返回运行从事件接收到完成的全过程
+	GetRunTimeline(ctx context.Context, id string) ([]TimelineEntry, error)
+}
+
+// queryService 实现
+type queryService struct {
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewQueryService 创建运行查询服务
+func NewQueryService(repo Repository, logger *slog.Logger) QueryService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &queryService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ListRuns 按 (created_at, id) 倒序的键集分页列出运行
+func (s *queryService) ListRuns(ctx context.Context, req ListRunsRequest) (*ListRunsResponse, error) {
+	projectID, err := stringToPgUUID(req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := pagination.NormalizeLimit(req.Limit)
+
+	pageParams := ListJobRunsPageParams{
+		ProjectID:     projectID,
+		CreatedAfter:  optionalTimestamptz(req.CreatedAfter),
+		CreatedBefore: optionalTimestamptz(req.CreatedBefore),
+		PageSize:      limit + 1,
+	}
+	if req.JobID != "" {
+		if pageParams.JobID, err = stringToPgUUID(req.JobID); err != nil {
+			return nil, err
+		}
+	}
+	if req.EnvironmentID != "" {
+		if pageParams.EnvironmentID, err = stringToPgUUID(req.EnvironmentID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Status != "" {
+		pageParams.Status = NullJobRunStatus{JobRunStatus: req.Status, Valid: true}
+	}
+	if cursor != nil {
+		pageParams.CursorCreatedAt = pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true}
+		pageParams.CursorID = pgtype.UUID{Bytes: cursor.ID, Valid: true}
+	}
+
+	var runs []JobRuns
+	if cursor.Backward() {
+		runs, err = s.repo.ListJobRunsPageReverse(ctx, ListJobRunsPageReverseParams(pageParams))
+	} else {
+		runs, err = s.repo.ListJobRunsPage(ctx, pageParams)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	runs, next, prev := pagination.Page(runs, limit, cursor, func(run JobRuns) (time.Time, uuid.UUID) {
+		return run.CreatedAt.Time, run.ID.Bytes
+	})
+
+	responses := make([]RunResponse, 0, len(runs))
+	for _, run := range runs {
+		responses = append(responses, *convertRunToResponse(run))
+	}
+
+	return &ListRunsResponse{
+		Runs:       responses,
+		Limit:      limit,
+		NextCursor: next,
+		PrevCursor: prev,
+	}, nil
+}
+
+// GetRunDetail 获取运行详情
+func (s *queryService) GetRunDetail(ctx context.Context, id string) (*RunDetailResponse, error) {
+	details, err := s.loadRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RunDetailResponse{
+		Run:        *convertRunToResponse(details.run),
+		Tasks:      make([]TaskResponse, 0, len(details.tasks)),
+		Executions: make([]RunExecutionResponse, 0, len(details.executions)),
+		Error:      runError(details.run),
+	}
+	if details.event != nil {
+		response.Event = convertEventToResponse(*details.event)
+	}
+	for _, task := range details.tasks {
+		response.Tasks = append(response.Tasks, *convertTaskToResponse(task))
+	}
+	for _, execution := range details.executions {
+		response.Executions = append(response.Executions, convertExecutionToResponse(execution))
+	}
+	return response, nil
+}
+
+// GetRunTimeline 获取运行时间线
+func (s *queryService) GetRunTimeline(ctx context.Context, id string) ([]TimelineEntry, error) {
+	details, err := s.loadRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return buildRunTimeline(details), nil
+}
+
+// runDetails 运行及其关联记录
+type runDetails struct {
+	run        JobRuns
+	event      *GetJobRunEventRow
+	tasks      []Tasks
+	executions []JobRunExecutions
+}
+
+// loadRun 加载运行及其事件、任务和执行记录
+func (s *queryService) loadRun(ctx context.Context, id string) (*runDetails, error) {
+	runID, err := stringToPgUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.repo.GetJobRunByID(ctx, runID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get run: %w", err)
+	}
+	details := &runDetails{run: run}
+
+	event, err := s.repo.GetJobRunEvent(ctx, runID)
+	switch {
+	case err == nil:
+		details.event = &event
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("failed to get run event: %w", err)
+	}
+
+	if details.tasks, err = s.repo.ListTasksByRun(ctx, runID); err != nil {
+		return nil, fmt.Errorf("failed to list run tasks: %w", err)
+	}
+	if details.executions, err = s.repo.ListJobRunExecutions(ctx, runID); err != nil {
+		return nil, fmt.Errorf("failed to list run executions: %w", err)
+	}
+	return details, nil
+}
+
+// buildRunTimeline 按时间顺序组装时间线：事件接收、调度器匹配、排队、启动、每次执行和完成
+func buildRunTimeline(details *runDetails) []TimelineEntry {
+	run := details.run
+	var entries []TimelineEntry
+	add := func(ts pgtype.Timestamptz, entry TimelineEntry) {
+		if !ts.Valid {
+			return
+		}
+		entry.Timestamp = ts.Time
+		entries = append(entries, entry)
+	}
+
+	if details.event != nil {
+		add(details.event.CreatedAt, TimelineEntry{Type: TimelineEventReceived})
+	}
+	// 运行由匹配的事件调度器创建
+	add(run.CreatedAt, TimelineEntry{Type: TimelineDispatcherMatched})
+	add(run.QueuedAt, TimelineEntry{Type: TimelineRunQueued})
+	add(run.StartedAt, TimelineEntry{Type: TimelineRunStarted})
+
+	for _, execution := range details.executions {
+		add(execution.StartedAt, TimelineEntry{
+			Type:      TimelineExecutionStarted,
+			Execution: execution.Number,
+		})
+		add(execution.CompletedAt, TimelineEntry{
+			Type:      TimelineExecutionCompleted,
+			Execution: execution.Number,
+			Status:    execution.Status,
+			Error:     jsonbToError(execution.Error),
+		})
+	}
+
+	for _, task := range details.tasks {
+		if task.Status != TaskStatusERRORED {
+			continue
+		}
+		add(task.CompletedAt, TimelineEntry{
+			Type:   TimelineTaskFailed,
+			TaskID: pgUUIDToString(task.ID),
+			Status: string(task.Status),
+			Error:  jsonbToError(task.Error),
+		})
+	}
+
+	add(run.CompletedAt, TimelineEntry{
+		Type:   TimelineRunCompleted,
+		Status: string(run.Status),
+		Error:  runError(run),
+	})
+
+	// 时间相同时保持上面的阶段顺序
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries
+}
+
+// runError 失败运行的输出为端点返回的 ErrorWithStack
+func runError(run JobRuns) *endpointapi.ErrorWithStack {
+	if run.Status != JobRunStatusFAILURE {
+		return nil
+	}
+	return jsonbToError(run.Output)
+}
+
+func convertEventToResponse(event GetJobRunEventRow) *RunEventResponse {
+	return &RunEventResponse{
+		ID:          event.EventID,
+		Name:        event.Name,
+		Source:      event.Source,
+		Payload:     jsonbToMap(event.Payload),
+		Context:     jsonbToMap(event.Context),
+		Timestamp:   event.Timestamp.Time,
+		IsTest:      event.IsTest,
+		ReceivedAt:  event.CreatedAt.Time,
+		DeliveredAt: timestamptzToPtr(event.DeliveredAt),
+	}
+}
+
+func convertExecutionToResponse(execution JobRunExecutions) RunExecutionResponse {
+	return RunExecutionResponse{
+		Number:      execution.Number,
+		Reason:      execution.Reason,
+		Status:      execution.Status,
+		Error:       jsonbToError(execution.Error),
+		StartedAt:   execution.StartedAt.Time,
+		CompletedAt: timestamptzToPtr(execution.CompletedAt),
+	}
+}
+
+// optionalTimestamptz 将可选时间转换为可为空的查询参数
+func optionalTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
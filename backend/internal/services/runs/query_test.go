@@ -0,0 +1,159 @@
+package runs
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"kongflow/backend/internal/services/pagination"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func ts(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
+
+func TestQueryService_ListRuns_Filters(t *testing.T) {
+	repo := &MockRepository{}
+	svc := NewQueryService(repo, slog.Default())
+
+	run := createTestRun(JobRunStatusFAILURE)
+	after := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	repo.On("ListJobRunsPage", mock.Anything, mock.MatchedBy(func(p ListJobRunsPageParams) bool {
+		return p.ProjectID == run.ProjectID &&
+			p.JobID == run.JobID &&
+			!p.EnvironmentID.Valid &&
+			p.Status == NullJobRunStatus{JobRunStatus: JobRunStatusFAILURE, Valid: true} &&
+			p.CreatedAfter.Valid && p.CreatedAfter.Time.Equal(after) &&
+			!p.CreatedBefore.Valid &&
+			!p.CursorCreatedAt.Valid &&
+			p.PageSize == 3
+	})).Return([]JobRuns{run, run, run}, nil)
+
+	resp, err := svc.ListRuns(context.Background(), ListRunsRequest{
+		ProjectID:    pgUUIDToString(run.ProjectID),
+		JobID:        pgUUIDToString(run.JobID),
+		Status:       JobRunStatusFAILURE,
+		CreatedAfter: &after,
+		Limit:        2,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, resp.Runs, 2)
+	assert.NotEmpty(t, resp.NextCursor)
+	assert.Empty(t, resp.PrevCursor)
+	repo.AssertExpectations(t)
+}
+
+func TestQueryService_ListRuns_InvalidCursor(t *testing.T) {
+	svc := NewQueryService(&MockRepository{}, slog.Default())
+
+	_, err := svc.ListRuns(context.Background(), ListRunsRequest{
+		ProjectID: pgUUIDToString(newPgUUID()),
+		Cursor:    "not-a-cursor",
+	})
+	assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+}
+
+func TestQueryService_GetRunTimeline(t *testing.T) {
+	repo := &MockRepository{}
+	svc := NewQueryService(repo, slog.Default())
+
+	base := time.Now().UTC().Truncate(time.Second)
+	run := createTestRun(JobRunStatusFAILURE)
+	run.CreatedAt = ts(base.Add(time.Second))
+	run.QueuedAt = ts(base.Add(2 * time.Second))
+	run.StartedAt = ts(base.Add(3 * time.Second))
+	run.CompletedAt = ts(base.Add(9 * time.Second))
+	run.Output = []byte(`{"message":"boom","stack":"Error: boom\n    at run (index.js:1:1)"}`)
+
+	repo.On("GetJobRunByID", mock.Anything, run.ID).Return(run, nil)
+	repo.On("GetJobRunEvent", mock.Anything, run.ID).Return(GetJobRunEventRow{
+		EventID:   "evt_123",
+		Name:      "user.created",
+		CreatedAt: ts(base),
+	}, nil)
+	repo.On("ListTasksByRun", mock.Anything, run.ID).Return([]Tasks{{
+		ID:          newPgUUID(),
+		Status:      TaskStatusERRORED,
+		Error:       []byte(`{"message":"task failed","stack":"at task"}`),
+		CompletedAt: ts(base.Add(7 * time.Second)),
+	}}, nil)
+	repo.On("ListJobRunExecutions", mock.Anything, run.ID).Return([]JobRunExecutions{
+		{Number: 1, Status: "RESUME_WITH_TASK", StartedAt: ts(base.Add(4 * time.Second)), CompletedAt: ts(base.Add(5 * time.Second))},
+		{Number: 2, Status: "ERROR", StartedAt: ts(base.Add(6 * time.Second)), CompletedAt: ts(base.Add(8 * time.Second)),
+			Error: []byte(`{"message":"boom","stack":"Error: boom"}`)},
+	}, nil)
+
+	timeline, err := svc.GetRunTimeline(context.Background(), pgUUIDToString(run.ID))
+	require.NoError(t, err)
+
+	types := make([]TimelineEntryType, 0, len(timeline))
+	for _, entry := range timeline {
+		types = append(types, entry.Type)
+	}
+	assert.Equal(t, []TimelineEntryType{
+		TimelineEventReceived,
+		TimelineDispatcherMatched,
+		TimelineRunQueued,
+		TimelineRunStarted,
+		TimelineExecutionStarted,
+		TimelineExecutionCompleted,
+		TimelineExecutionStarted,
+		TimelineTaskFailed,
+		TimelineExecutionCompleted,
+		TimelineRunCompleted,
+	}, types)
+
+	assert.Equal(t, "at task", timeline[7].Error.Stack)
+	assert.Equal(t, int32(2), timeline[8].Execution)
+	assert.Equal(t, "Error: boom", timeline[8].Error.Stack)
+
+	completed := timeline[len(timeline)-1]
+	assert.Equal(t, string(JobRunStatusFAILURE), completed.Status)
+	require.NotNil(t, completed.Error)
+	assert.Contains(t, completed.Error.Stack, "index.js:1:1")
+}
+
+func TestQueryService_GetRunDetail(t *testing.T) {
+	repo := &MockRepository{}
+	svc := NewQueryService(repo, slog.Default())
+
+	run := createTestRun(JobRunStatusSUCCESS)
+	run.Output = []byte(`{"ok":true}`)
+
+	repo.On("GetJobRunByID", mock.Anything, run.ID).Return(run, nil)
+	repo.On("GetJobRunEvent", mock.Anything, run.ID).Return(GetJobRunEventRow{
+		EventID: "evt_123",
+		Name:    "user.created",
+		Payload: []byte(`{"userId":"user_1"}`),
+	}, nil)
+	repo.On("ListTasksByRun", mock.Anything, run.ID).Return([]Tasks{{ID: newPgUUID(), Name: "Send email", Status: TaskStatusCOMPLETED}}, nil)
+	repo.On("ListJobRunExecutions", mock.Anything, run.ID).Return([]JobRunExecutions{{Number: 1, Status: "SUCCESS"}}, nil)
+
+	detail, err := svc.GetRunDetail(context.Background(), pgUUIDToString(run.ID))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ok": true}, detail.Run.Output)
+	assert.Equal(t, "user_1", detail.Event.Payload["userId"])
+	assert.Len(t, detail.Tasks, 1)
+	assert.Len(t, detail.Executions, 1)
+	assert.Nil(t, detail.Error)
+}
+
+func TestQueryService_GetRunDetail_NotFound(t *testing.T) {
+	repo := &MockRepository{}
+	svc := NewQueryService(repo, slog.Default())
+
+	runID := newPgUUID()
+	repo.On("GetJobRunByID", mock.Anything, runID).Return(JobRuns{}, pgx.ErrNoRows)
+
+	_, err := svc.GetRunDetail(context.Background(), pgUUIDToString(runID))
+	assert.ErrorIs(t, err, ErrRunNotFound)
+}
@@ -0,0 +1,116 @@
+// Package queue provides River-based queue service implementation for runs
+package queue
+
+import (
+	"context"
+
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// WorkerQueueManager 定义队列管理器接口，便于测试
+type WorkerQueueManager interface {
+	EnqueueJob(ctx context.Context, identifier string, payload interface{}, opts *workerqueue.JobOptions) (*rivertype.JobInsertResult, error)
+	EnqueueJobTx(ctx context.Context, tx pgx.Tx, identifier string, payload interface{}, opts *workerqueue.JobOptions) (*rivertype.JobInsertResult, error)
+	CancelJobsByArgTx(ctx context.Context, tx pgx.Tx, kind, field, value string) (int, error)
+}
+
+// riverQueueService 基于WorkerQueue的Runs服务实现
+type riverQueueService struct {
+	manager WorkerQueueManager
+}
+
+// NewRiverQueueService 创建基于WorkerQueue的Runs队列服务
+func NewRiverQueueService(manager WorkerQueueManager) QueueService {
+	return &riverQueueService{
+		manager: manager,
+	}
+}
+
+// EnqueueStartRun 将启动运行任务加入队列
+func (r *riverQueueService) EnqueueStartRun(ctx context.Context, req *EnqueueStartRunRequest) (*rivertype.JobInsertResult, error) {
+	args := workerqueue.StartRunArgs{ID: req.RunID}
+	return r.manager.EnqueueJob(ctx, args.Kind(), args, startRunOptions())
+}
+
+// EnqueuePerformRunExecution 将运行执行任务加入队列
+func (r *riverQueueService) EnqueuePerformRunExecution(ctx context.Context, req *EnqueuePerformRunExecutionRequest) (*rivertype.JobInsertResult, error) {
+	args := performRunExecutionArgs(req)
+	return r.manager.EnqueueJob(ctx, args.Kind(), args, performRunExecutionOptions(req))
+}
+
+// EnqueueStartRunTx 在事务中将启动运行任务加入队列
+func (r *riverQueueService) EnqueueStartRunTx(ctx context.Context, tx pgx.Tx, req *EnqueueStartRunRequest) (*rivertype.JobInsertResult, error) {
+	args := workerqueue.StartRunArgs{ID: req.RunID}
+	return r.manager.EnqueueJobTx(ctx, tx, args.Kind(), args, startRunOptions())
+}
+
+// EnqueuePerformRunExecutionTx 在事务中将运行执行任务加入队列
+func (r *riverQueueService) EnqueuePerformRunExecutionTx(ctx context.Context, tx pgx.Tx, req *EnqueuePerformRunExecutionRequest) (*rivertype.JobInsertResult, error) {
+	args := performRunExecutionArgs(req)
+	return r.manager.EnqueueJobTx(ctx, tx, args.Kind(), args, performRunExecutionOptions(req))
+}
+
+// EnqueueStartQueuedRuns 将启动排队运行任务加入队列
+func (r *riverQueueService) EnqueueStartQueuedRuns(ctx context.Context, req *EnqueueStartQueuedRunsRequest) (*rivertype.JobInsertResult, error) {
+	args := startQueuedRunsArgs(req)
+	return r.manager.EnqueueJob(ctx, args.Kind(), args, startRunOptions())
+}
+
+// EnqueueStartQueuedRunsTx 在事务中将启动排队运行任务加入队列
+func (r *riverQueueService) EnqueueStartQueuedRunsTx(ctx context.Context, tx pgx.Tx, req *EnqueueStartQueuedRunsRequest) (*rivertype.JobInsertResult, error) {
+	args := startQueuedRunsArgs(req)
+	return r.manager.EnqueueJobTx(ctx, tx, args.Kind(), args, startRunOptions())
+}
+
+// EnqueueNotifyRunFailedTx 在事务中将运行失败通知任务加入队列
+func (r *riverQueueService) EnqueueNotifyRunFailedTx(ctx context.Context, tx pgx.Tx, runID string) (*rivertype.JobInsertResult, error) {
+	args := workerqueue.NotifyRunFailedArgs{RunID: runID}
+	return r.manager.EnqueueJobTx(ctx, tx, args.Kind(), args, &workerqueue.JobOptions{
+		QueueName: string(workerqueue.QueueDefault),
+		Priority:  int(workerqueue.PriorityLow),
+	})
+}
+
+// CancelRunExecutionsTx 在事务中取消运行尚未开始的 performRunExecutionV2 任务
+func (r *riverQueueService) CancelRunExecutionsTx(ctx context.Context, tx pgx.Tx, runID string) (int, error) {
+	return r.manager.CancelJobsByArgTx(ctx, tx, workerqueue.PerformRunExecutionV2Args{}.Kind(), "id", runID)
+}
+
+func startRunOptions() *workerqueue.JobOptions {
+	return &workerqueue.JobOptions{
+		QueueName: string(workerqueue.QueueExecution),
+		Priority:  int(workerqueue.PriorityHigh),
+	}
+}
+
+func startQueuedRunsArgs(req *EnqueueStartQueuedRunsRequest) workerqueue.StartQueuedRunsArgs {
+	return workerqueue.StartQueuedRunsArgs{
+		ProjectID:     req.ProjectID,
+		QueueID:       req.QueueID,
+		FinishedRunID: req.FinishedRunID,
+	}
+}
+
+func performRunExecutionArgs(req *EnqueuePerformRunExecutionRequest) workerqueue.PerformRunExecutionV2Args {
+	return workerqueue.PerformRunExecutionV2Args{
+		ID:             req.RunID,
+		ProjectID:      req.ProjectID,
+		EnvironmentID:  req.EnvironmentID,
+		EndpointID:     req.EndpointID,
+		Reason:         workerqueue.ExecutionReason(req.Reason),
+		ResumeTaskID:   req.ResumeTaskID,
+		IsRetry:        req.IsRetry,
+		ExecutionCount: req.ExecutionCount,
+	}
+}
+
+func performRunExecutionOptions(req *EnqueuePerformRunExecutionRequest) *workerqueue.JobOptions {
+	return &workerqueue.JobOptions{
+		QueueName: string(workerqueue.QueueExecution),
+		Priority:  int(workerqueue.PriorityHigh),
+		RunAt:     req.ScheduledFor,
+	}
+}
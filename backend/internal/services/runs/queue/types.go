@@ -0,0 +1,77 @@
+// Package queue provides queue service interface for runs service
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// QueueService Runs队列服务接口，对齐 trigger.dev startRun / performRunExecutionV2 / startQueuedRuns 任务
+type QueueService interface {
+	// 标准队列操作
+	EnqueueStartRun(ctx context.Context, req *EnqueueStartRunRequest) (*rivertype.JobInsertResult, error)
+	EnqueuePerformRunExecution(ctx context.Context, req *EnqueuePerformRunExecutionRequest) (*rivertype.JobInsertResult, error)
+	EnqueueStartQueuedRuns(ctx context.Context, req *EnqueueStartQueuedRunsRequest) (*rivertype.JobInsertResult, error)
+
+	// 事务性队列操作
+	EnqueueStartRunTx(ctx context.Context, tx pgx.Tx, req *EnqueueStartRunRequest) (*rivertype.JobInsertResult, error)
+	EnqueuePerformRunExecutionTx(ctx context.Context, tx pgx.Tx, req *EnqueuePerformRunExecutionRequest) (*rivertype.JobInsertResult, error)
+	EnqueueStartQueuedRunsTx(ctx context.Context, tx pgx.Tx, req *EnqueueStartQueuedRunsRequest) (*rivertype.JobInsertResult, error)
+
+	// EnqueueNotifyRunFailedTx 在事务中加入运行失败通知任务
+	EnqueueNotifyRunFailedTx(ctx context.Context, tx pgx.Tx, runID string) (*rivertype.JobInsertResult, error)
+
+	// CancelRunExecutionsTx 取消运行尚未开始的执行任务，返回取消数量
+	CancelRunExecutionsTx(ctx context.Context, tx pgx.Tx, runID string) (int, error)
+}
+
+// EnqueueStartRunRequest 启动运行队列请求
+type EnqueueStartRunRequest struct {
+	// RunID 运行ID
+	RunID string `json:"runId" validate:"required"`
+}
+
+// EnqueuePerformRunExecutionRequest 运行执行队列请求
+type EnqueuePerformRunExecutionRequest struct {
+	// RunID 运行ID
+	RunID string `json:"runId" validate:"required"`
+
+	// ProjectID 项目ID，用于队列路由
+	ProjectID string `json:"projectId"`
+
+	// EnvironmentID 环境ID，执行队列按环境公平调度
+	EnvironmentID string `json:"environmentId"`
+
+	// EndpointID 端点ID，可作为执行限流的键
+	EndpointID string `json:"endpointId"`
+
+	// Reason 执行原因：PREPROCESS 或 EXECUTE_JOB
+	Reason string `json:"reason" validate:"required"`
+
+	// IsRetry 是否为重试执行
+	IsRetry bool `json:"isRetry"`
+
+	// ResumeTaskID 延迟结束后需要恢复的任务ID（可选）
+	ResumeTaskID string `json:"resumeTaskId,omitempty"`
+
+	// ExecutionCount 运行已执行的次数，用于区分同一运行的多次执行
+	ExecutionCount int `json:"executionCount"`
+
+	// ScheduledFor 计划执行时间（可选）
+	ScheduledFor *time.Time `json:"scheduledFor,omitempty"`
+}
+
+// EnqueueStartQueuedRunsRequest 启动排队运行队列请求
+type EnqueueStartQueuedRunsRequest struct {
+	// QueueID 作业队列ID (job_queues.id)
+	QueueID string `json:"queueId" validate:"required"`
+
+	// ProjectID 项目ID，用于队列路由
+	ProjectID string `json:"projectId"`
+
+	// FinishedRunID 已结束并需要释放槽位的运行ID（可选）
+	FinishedRunID string `json:"finishedRunId,omitempty"`
+}
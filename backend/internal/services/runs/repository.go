@@ -0,0 +1,325 @@
+package runs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository Runs 数据仓储接口，遵循 events 服务的模式
+type Repository interface {
+	// JobRun 操作
+	CreateJobRun(ctx context.Context, params CreateJobRunParams) (JobRuns, error)
+	GetJobRunByID(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	GetJobRunByEventAndVersion(ctx context.Context, params GetJobRunByEventAndVersionParams) (JobRuns, error)
+	GetJobRunExecutionContext(ctx context.Context, id pgtype.UUID) (GetJobRunExecutionContextRow, error)
+	UpdateJobRunStatus(ctx context.Context, params UpdateJobRunStatusParams) (JobRuns, error)
+	MarkJobRunQueued(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	MarkJobRunPreprocessing(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	MarkJobRunPreprocessed(ctx context.Context, params MarkJobRunPreprocessedParams) (JobRuns, error)
+	MarkJobRunExecuting(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	MarkJobRunWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	CompleteJobRun(ctx context.Context, params CompleteJobRunParams) (JobRuns, error)
+	CancelJobRun(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+	GetLatestJobVersionIDForRun(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
+	ListTimedOutJobRuns(ctx context.Context, limit int32) ([]ListTimedOutJobRunsRow, error)
+	TimeoutJobRun(ctx context.Context, params TimeoutJobRunParams) (JobRuns, error)
+
+	// 运行查询与执行记录
+	ListJobRunsPage(ctx context.Context, params ListJobRunsPageParams) ([]JobRuns, error)
+	ListJobRunsPageReverse(ctx context.Context, params ListJobRunsPageReverseParams) ([]JobRuns, error)
+	GetJobRunEvent(ctx context.Context, runID pgtype.UUID) (GetJobRunEventRow, error)
+	UpsertJobRunExecution(ctx context.Context, params UpsertJobRunExecutionParams) (JobRunExecutions, error)
+	CompleteJobRunExecution(ctx context.Context, params CompleteJobRunExecutionParams) error
+	ListJobRunExecutions(ctx context.Context, runID pgtype.UUID) ([]JobRunExecutions, error)
+	CancelOpenJobRunExecutions(ctx context.Context, runID pgtype.UUID) error
+	TimeoutOpenJobRunExecutions(ctx context.Context, params TimeoutOpenJobRunExecutionsParams) error
+
+	// 队列并发槽位操作
+	GetNextQueuedJobRun(ctx context.Context, queueID pgtype.UUID) (JobRuns, error)
+	MarkJobRunHoldsQueueSlot(ctx context.Context, id pgtype.UUID) error
+	ReleaseJobRunQueueSlot(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
+	IncrementJobCount(ctx context.Context, queueID pgtype.UUID) (IncrementJobCountRow, error)
+	DecrementJobCount(ctx context.Context, queueID pgtype.UUID) (DecrementJobCountRow, error)
+	GetJobStatus(ctx context.Context, jobID pgtype.UUID) (JobStatus, error)
+	ListWaitingQueuesByJob(ctx context.Context, jobID pgtype.UUID) ([]pgtype.UUID, error)
+
+	// 集成连接检查
+	ListMissingIntegrationsForVersion(ctx context.Context, versionID pgtype.UUID) ([]ListMissingIntegrationsForVersionRow, error)
+	ListIntegrationsForVersion(ctx context.Context, versionID pgtype.UUID) ([]ListIntegrationsForVersionRow, error)
+	UpsertMissingConnection(ctx context.Context, params UpsertMissingConnectionParams) (MissingConnections, error)
+	MarkMissingConnectionNotified(ctx context.Context, id pgtype.UUID) (int64, error)
+	ReleaseMissingConnectionNotification(ctx context.Context, id pgtype.UUID) error
+	ListRunsWaitingOnIntegration(ctx context.Context, integrationID pgtype.UUID) ([]pgtype.UUID, error)
+	MarkJobRunPendingFromWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error)
+
+	// 运行失败通知
+	GetRunFailureNotificationContext(ctx context.Context, id pgtype.UUID) (GetRunFailureNotificationContextRow, error)
+	ClaimJobFailureNotification(ctx context.Context, params ClaimJobFailureNotificationParams) (pgtype.UUID, error)
+	ListRunFailureRecipients(ctx context.Context, organizationID pgtype.UUID) ([]ListRunFailureRecipientsRow, error)
+
+	// Task 操作
+	UpsertTask(ctx context.Context, params UpsertTaskParams) (Tasks, error)
+	GetTaskByID(ctx context.Context, id pgtype.UUID) (Tasks, error)
+	GetTaskByIdempotencyKey(ctx context.Context, params GetTaskByIdempotencyKeyParams) (Tasks, error)
+	ListTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error)
+	ListCompletedTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error)
+	CompleteTask(ctx context.Context, params CompleteTaskParams) (Tasks, error)
+	CancelPendingTasks(ctx context.Context, runID pgtype.UUID) error
+
+	// JobVersion 只读操作
+	GetJobVersionForRun(ctx context.Context, id pgtype.UUID) (GetJobVersionForRunRow, error)
+
+	// 事务支持
+	WithTx(ctx context.Context, fn func(Repository) error) error
+	WithTxAndReturn(ctx context.Context, fn func(Repository, pgx.Tx) error) error
+}
+
+// repository 实现
+type repository struct {
+	queries Querier
+	db      *pgxpool.Pool
+}
+
+// NewRepository 创建仓储实例
+func NewRepository(queries Querier, db *pgxpool.Pool) Repository {
+	return &repository{
+		queries: queries,
+		db:      db,
+	}
+}
+
+// JobRun 操作实现
+func (r *repository) CreateJobRun(ctx context.Context, params CreateJobRunParams) (JobRuns, error) {
+	return r.queries.CreateJobRun(ctx, params)
+}
+
+func (r *repository) GetJobRunByID(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	return r.queries.GetJobRunByID(ctx, id)
+}
+
+func (r *repository) GetJobRunByEventAndVersion(ctx context.Context, params GetJobRunByEventAndVersionParams) (JobRuns, error) {
+	return r.queries.GetJobRunByEventAndVersion(ctx, params)
+}
+
+func (r *repository) GetJobRunExecutionContext(ctx context.Context, id pgtype.UUID) (GetJobRunExecutionContextRow, error) {
+	return r.queries.GetJobRunExecutionContext(ctx, id)
+}
+
+func (r *repository) UpdateJobRunStatus(ctx context.Context, params UpdateJobRunStatusParams) (JobRuns, error) {
+	return r.queries.UpdateJobRunStatus(ctx, params)
+}
+
+func (r *repository) MarkJobRunQueued(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	return r.queries.MarkJobRunQueued(ctx, id)
+}
+
+func (r *repository) MarkJobRunPreprocessing(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	return r.queries.MarkJobRunPreprocessing(ctx, id)
+}
+
+func (r *repository) MarkJobRunPreprocessed(ctx context.Context, params MarkJobRunPreprocessedParams) (JobRuns, error) {
+	return r.queries.MarkJobRunPreprocessed(ctx, params)
+}
+
+func (r *repository) MarkJobRunExecuting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	return r.queries.MarkJobRunExecuting(ctx, id)
+}
+
+func (r *repository) MarkJobRunWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	return r.queries.MarkJobRunWaiting(ctx, id)
+}
+
+func (r *repository) CompleteJobRun(ctx context.Context, params CompleteJobRunParams) (JobRuns, error) {
+	return r.queries.CompleteJobRun(ctx, params)
+}
+
+func (r *repository) CancelJobRun(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	return r.queries.CancelJobRun(ctx, id)
+}
+
+func (r *repository) GetLatestJobVersionIDForRun(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	return r.queries.GetLatestJobVersionIDForRun(ctx, id)
+}
+
+func (r *repository) ListTimedOutJobRuns(ctx context.Context, limit int32) ([]ListTimedOutJobRunsRow, error) {
+	return r.queries.ListTimedOutJobRuns(ctx, limit)
+}
+
+func (r *repository) TimeoutJobRun(ctx context.Context, params TimeoutJobRunParams) (JobRuns, error) {
+	return r.queries.TimeoutJobRun(ctx, params)
+}
+
+// 队列并发槽位操作实现
+func (r *repository) GetNextQueuedJobRun(ctx context.Context, queueID pgtype.UUID) (JobRuns, error) {
+	return r.queries.GetNextQueuedJobRun(ctx, queueID)
+}
+
+func (r *repository) MarkJobRunHoldsQueueSlot(ctx context.Context, id pgtype.UUID) error {
+	return r.queries.MarkJobRunHoldsQueueSlot(ctx, id)
+}
+
+func (r *repository) ReleaseJobRunQueueSlot(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	return r.queries.ReleaseJobRunQueueSlot(ctx, id)
+}
+
+func (r *repository) GetJobStatus(ctx context.Context, jobID pgtype.UUID) (JobStatus, error) {
+	return r.queries.GetJobStatus(ctx, jobID)
+}
+
+func (r *repository) ListWaitingQueuesByJob(ctx context.Context, jobID pgtype.UUID) ([]pgtype.UUID, error) {
+	return r.queries.ListWaitingQueuesByJob(ctx, jobID)
+}
+
+func (r *repository) IncrementJobCount(ctx context.Context, queueID pgtype.UUID) (IncrementJobCountRow, error) {
+	return r.queries.IncrementJobCount(ctx, queueID)
+}
+
+func (r *repository) DecrementJobCount(ctx context.Context, queueID pgtype.UUID) (DecrementJobCountRow, error) {
+	return r.queries.DecrementJobCount(ctx, queueID)
+}
+
+// 运行查询与执行记录实现
+func (r *repository) ListJobRunsPage(ctx context.Context, params ListJobRunsPageParams) ([]JobRuns, error) {
+	return r.queries.ListJobRunsPage(ctx, params)
+}
+
+func (r *repository) ListJobRunsPageReverse(ctx context.Context, params ListJobRunsPageReverseParams) ([]JobRuns, error) {
+	return r.queries.ListJobRunsPageReverse(ctx, params)
+}
+
+func (r *repository) GetJobRunEvent(ctx context.Context, runID pgtype.UUID) (GetJobRunEventRow, error) {
+	return r.queries.GetJobRunEvent(ctx, runID)
+}
+
+func (r *repository) UpsertJobRunExecution(ctx context.Context, params UpsertJobRunExecutionParams) (JobRunExecutions, error) {
+	return r.queries.UpsertJobRunExecution(ctx, params)
+}
+
+func (r *repository) CompleteJobRunExecution(ctx context.Context, params CompleteJobRunExecutionParams) error {
+	return r.queries.CompleteJobRunExecution(ctx, params)
+}
+
+func (r *repository) ListJobRunExecutions(ctx context.Context, runID pgtype.UUID) ([]JobRunExecutions, error) {
+	return r.queries.ListJobRunExecutions(ctx, runID)
+}
+
+func (r *repository) CancelOpenJobRunExecutions(ctx context.Context, runID pgtype.UUID) error {
+	return r.queries.CancelOpenJobRunExecutions(ctx, runID)
+}
+
+func (r *repository) TimeoutOpenJobRunExecutions(ctx context.Context, params TimeoutOpenJobRunExecutionsParams) error {
+	return r.queries.TimeoutOpenJobRunExecutions(ctx, params)
+}
+
+// 集成连接检查实现
+func (r *repository) ListMissingIntegrationsForVersion(ctx context.Context, versionID pgtype.UUID) ([]ListMissingIntegrationsForVersionRow, error) {
+	return r.queries.ListMissingIntegrationsForVersion(ctx, versionID)
+}
+
+func (r *repository) ListIntegrationsForVersion(ctx context.Context, versionID pgtype.UUID) ([]ListIntegrationsForVersionRow, error) {
+	return r.queries.ListIntegrationsForVersion(ctx, versionID)
+}
+
+func (r *repository) UpsertMissingConnection(ctx context.Context, params UpsertMissingConnectionParams) (MissingConnections, error) {
+	return r.queries.UpsertMissingConnection(ctx, params)
+}
+
+func (r *repository) MarkMissingConnectionNotified(ctx context.Context, id pgtype.UUID) (int64, error) {
+	return r.queries.MarkMissingConnectionNotified(ctx, id)
+}
+
+func (r *repository) ReleaseMissingConnectionNotification(ctx context.Context, id pgtype.UUID) error {
+	return r.queries.ReleaseMissingConnectionNotification(ctx, id)
+}
+
+func (r *repository) ListRunsWaitingOnIntegration(ctx context.Context, integrationID pgtype.UUID) ([]pgtype.UUID, error) {
+	return r.queries.ListRunsWaitingOnIntegration(ctx, integrationID)
+}
+
+func (r *repository) MarkJobRunPendingFromWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	return r.queries.MarkJobRunPendingFromWaiting(ctx, id)
+}
+
+// Task 操作实现
+func (r *repository) GetRunFailureNotificationContext(ctx context.Context, id pgtype.UUID) (GetRunFailureNotificationContextRow, error) {
+	return r.queries.GetRunFailureNotificationContext(ctx, id)
+}
+
+func (r *repository) ClaimJobFailureNotification(ctx context.Context, params ClaimJobFailureNotificationParams) (pgtype.UUID, error) {
+	return r.queries.ClaimJobFailureNotification(ctx, params)
+}
+
+func (r *repository) ListRunFailureRecipients(ctx context.Context, organizationID pgtype.UUID) ([]ListRunFailureRecipientsRow, error) {
+	return r.queries.ListRunFailureRecipients(ctx, organizationID)
+}
+
+func (r *repository) UpsertTask(ctx context.Context, params UpsertTaskParams) (Tasks, error) {
+	return r.queries.UpsertTask(ctx, params)
+}
+
+func (r *repository) GetTaskByID(ctx context.Context, id pgtype.UUID) (Tasks, error) {
+	return r.queries.GetTaskByID(ctx, id)
+}
+
+func (r *repository) GetTaskByIdempotencyKey(ctx context.Context, params GetTaskByIdempotencyKeyParams) (Tasks, error) {
+	return r.queries.GetTaskByIdempotencyKey(ctx, params)
+}
+
+func (r *repository) ListTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error) {
+	return r.queries.ListTasksByRun(ctx, runID)
+}
+
+func (r *repository) ListCompletedTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error) {
+	return r.queries.ListCompletedTasksByRun(ctx, runID)
+}
+
+func (r *repository) CompleteTask(ctx context.Context, params CompleteTaskParams) (Tasks, error) {
+	return r.queries.CompleteTask(ctx, params)
+}
+
+func (r *repository) CancelPendingTasks(ctx context.Context, runID pgtype.UUID) error {
+	return r.queries.CancelPendingTasks(ctx, runID)
+}
+
+// JobVersion 只读操作实现
+func (r *repository) GetJobVersionForRun(ctx context.Context, id pgtype.UUID) (GetJobVersionForRunRow, error) {
+	return r.queries.GetJobVersionForRun(ctx, id)
+}
+
+// WithTx 事务支持
+func (r *repository) WithTx(ctx context.Context, fn func(Repository) error) error {
+	return r.WithTxAndReturn(ctx, func(txRepo Repository, _ pgx.Tx) error {
+		return fn(txRepo)
+	})
+}
+
+// WithTxAndReturn 事务支持（带事务对象返回）
+func (r *repository) WithTxAndReturn(ctx context.Context, fn func(Repository, pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// 创建事务查询器
+	txRepo := &repository{
+		queries: New(tx),
+		db:      r.db,
+	}
+
+	// 执行事务内的操作
+	if err := fn(txRepo, tx); err != nil {
+		return err
+	}
+
+	// 提交事务
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
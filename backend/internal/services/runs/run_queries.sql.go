@@ -0,0 +1,345 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: run_queries.sql
+
+package runs
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelOpenJobRunExecutions = `-- name: CancelOpenJobRunExecutions :exec
+UPDATE job_run_executions
+SET status = 'CANCELED', completed_at = NOW()
+WHERE run_id = $1 AND status = 'EXECUTING'
+`
+
+// 运行取消时结束进行中的执行记录
+func (q *Queries) CancelOpenJobRunExecutions(ctx context.Context, runID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, cancelOpenJobRunExecutions, runID)
+	return err
+}
+
+const completeJobRunExecution = `-- name: CompleteJobRunExecution :exec
+UPDATE job_run_executions
+SET status = $2, error = $3, completed_at = NOW()
+WHERE id = $1
+`
+
+type CompleteJobRunExecutionParams struct {
+	ID     pgtype.UUID `json:"id"`
+	Status string      `json:"status"`
+	Error  []byte      `json:"error"`
+}
+
+func (q *Queries) CompleteJobRunExecution(ctx context.Context, arg CompleteJobRunExecutionParams) error {
+	_, err := q.db.Exec(ctx, completeJobRunExecution, arg.ID, arg.Status, arg.Error)
+	return err
+}
+
+const getJobRunEvent = `-- name: GetJobRunEvent :one
+SELECT e.id, e.event_id, e.name, e.source, e.payload, e.context, e.timestamp,
+    e.is_test, e.created_at, e.deliver_at, e.delivered_at
+FROM event_records e
+JOIN job_runs r ON r.event_id = e.id
+WHERE r.id = $1
+`
+
+type GetJobRunEventRow struct {
+	ID          pgtype.UUID        `json:"id"`
+	EventID     string             `json:"event_id"`
+	Name        string             `json:"name"`
+	Source      string             `json:"source"`
+	Payload     []byte             `json:"payload"`
+	Context     []byte             `json:"context"`
+	Timestamp   pgtype.Timestamptz `json:"timestamp"`
+	IsTest      bool               `json:"is_test"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	DeliverAt   pgtype.Timestamptz `json:"deliver_at"`
+	DeliveredAt pgtype.Timestamptz `json:"delivered_at"`
+}
+
+// 运行对应的事件记录
+func (q *Queries) GetJobRunEvent(ctx context.Context, id pgtype.UUID) (GetJobRunEventRow, error) {
+	row := q.db.QueryRow(ctx, getJobRunEvent, id)
+	var i GetJobRunEventRow
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.Name,
+		&i.Source,
+		&i.Payload,
+		&i.Context,
+		&i.Timestamp,
+		&i.IsTest,
+		&i.CreatedAt,
+		&i.DeliverAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const listJobRunExecutions = `-- name: ListJobRunExecutions :many
+SELECT id, run_id, number, reason, status, error, started_at, completed_at, created_at FROM job_run_executions
+WHERE run_id = $1
+ORDER BY number
+`
+
+func (q *Queries) ListJobRunExecutions(ctx context.Context, runID pgtype.UUID) ([]JobRunExecutions, error) {
+	rows, err := q.db.Query(ctx, listJobRunExecutions, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobRunExecutions
+	for rows.Next() {
+		var i JobRunExecutions
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.Number,
+			&i.Reason,
+			&i.Status,
+			&i.Error,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobRunsPage = `-- name: ListJobRunsPage :many
+
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE project_id = $1
+    AND ($2::UUID IS NULL OR job_id = $2::UUID)
+    AND ($3::UUID IS NULL OR environment_id = $3::UUID)
+    AND ($4::job_run_status IS NULL OR status = $4::job_run_status)
+    AND ($5::TIMESTAMPTZ IS NULL OR created_at >= $5::TIMESTAMPTZ)
+    AND ($6::TIMESTAMPTZ IS NULL OR created_at < $6::TIMESTAMPTZ)
+    AND ($7::TIMESTAMPTZ IS NULL
+        OR (created_at, id) < ($7::TIMESTAMPTZ, $8::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $9
+`
+
+type ListJobRunsPageParams struct {
+	ProjectID       pgtype.UUID        `json:"project_id"`
+	JobID           pgtype.UUID        `json:"job_id"`
+	EnvironmentID   pgtype.UUID        `json:"environment_id"`
+	Status          NullJobRunStatus   `json:"status"`
+	CreatedAfter    pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore   pgtype.Timestamptz `json:"created_before"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+// run_queries.sql
+// 运行查询与时间线相关查询，对齐 trigger.dev RunPresenter
+// 按 (created_at, id) 倒序的键集分页，游标为空时返回第一页
+func (q *Queries) ListJobRunsPage(ctx context.Context, arg ListJobRunsPageParams) ([]JobRuns, error) {
+	rows, err := q.db.Query(ctx, listJobRunsPage,
+		arg.ProjectID,
+		arg.JobID,
+		arg.EnvironmentID,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobRuns
+	for rows.Next() {
+		var i JobRuns
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.VersionID,
+			&i.EventID,
+			&i.EnvironmentID,
+			&i.OrganizationID,
+			&i.ProjectID,
+			&i.EndpointID,
+			&i.QueueID,
+			&i.ExternalAccountID,
+			&i.Status,
+			&i.Properties,
+			&i.Output,
+			&i.IsTest,
+			&i.Preprocess,
+			&i.QueuedAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExecutionCount,
+			&i.HoldsQueueSlot,
+			&i.RerunOfID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobRunsPageReverse = `-- name: ListJobRunsPageReverse :many
+SELECT id, job_id, version_id, event_id, environment_id, organization_id,
+    project_id, endpoint_id, queue_id, external_account_id, status, properties,
+    output, is_test, preprocess, queued_at, started_at,
+    completed_at, created_at, updated_at, execution_count, holds_queue_slot, rerun_of_id
+FROM job_runs
+WHERE project_id = $1
+    AND ($2::UUID IS NULL OR job_id = $2::UUID)
+    AND ($3::UUID IS NULL OR environment_id = $3::UUID)
+    AND ($4::job_run_status IS NULL OR status = $4::job_run_status)
+    AND ($5::TIMESTAMPTZ IS NULL OR created_at >= $5::TIMESTAMPTZ)
+    AND ($6::TIMESTAMPTZ IS NULL OR created_at < $6::TIMESTAMPTZ)
+    AND (created_at, id) > ($7::TIMESTAMPTZ, $8::UUID)
+ORDER BY created_at ASC, id ASC
+LIMIT $9
+`
+
+type ListJobRunsPageReverseParams struct {
+	ProjectID       pgtype.UUID        `json:"project_id"`
+	JobID           pgtype.UUID        `json:"job_id"`
+	EnvironmentID   pgtype.UUID        `json:"environment_id"`
+	Status          NullJobRunStatus   `json:"status"`
+	CreatedAfter    pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore   pgtype.Timestamptz `json:"created_before"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+// 游标之前（更新）的一页，按正序返回，由调用方反转为倒序
+func (q *Queries) ListJobRunsPageReverse(ctx context.Context, arg ListJobRunsPageReverseParams) ([]JobRuns, error) {
+	rows, err := q.db.Query(ctx, listJobRunsPageReverse,
+		arg.ProjectID,
+		arg.JobID,
+		arg.EnvironmentID,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobRuns
+	for rows.Next() {
+		var i JobRuns
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.VersionID,
+			&i.EventID,
+			&i.EnvironmentID,
+			&i.OrganizationID,
+			&i.ProjectID,
+			&i.EndpointID,
+			&i.QueueID,
+			&i.ExternalAccountID,
+			&i.Status,
+			&i.Properties,
+			&i.Output,
+			&i.IsTest,
+			&i.Preprocess,
+			&i.QueuedAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExecutionCount,
+			&i.HoldsQueueSlot,
+			&i.RerunOfID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const timeoutOpenJobRunExecutions = `-- name: TimeoutOpenJobRunExecutions :exec
+UPDATE job_run_executions
+SET status = 'TIMED_OUT', error = $2, completed_at = NOW()
+WHERE run_id = $1 AND status = 'EXECUTING'
+`
+
+type TimeoutOpenJobRunExecutionsParams struct {
+	RunID pgtype.UUID `json:"run_id"`
+	Error []byte      `json:"error"`
+}
+
+// 运行超时时结束进行中的执行记录
+func (q *Queries) TimeoutOpenJobRunExecutions(ctx context.Context, arg TimeoutOpenJobRunExecutionsParams) error {
+	_, err := q.db.Exec(ctx, timeoutOpenJobRunExecutions, arg.RunID, arg.Error)
+	return err
+}
+
+const upsertJobRunExecution = `-- name: UpsertJobRunExecution :one
+INSERT INTO job_run_executions (run_id, number, reason)
+VALUES ($1, $2, $3)
+ON CONFLICT (run_id, number)
+DO UPDATE SET
+    reason = EXCLUDED.reason,
+    status = 'EXECUTING',
+    error = NULL,
+    started_at = NOW(),
+    completed_at = NULL
+RETURNING id, run_id, number, reason, status, error, started_at, completed_at, created_at
+`
+
+type UpsertJobRunExecutionParams struct {
+	RunID  pgtype.UUID `json:"run_id"`
+	Number int32       `json:"number"`
+	Reason string      `json:"reason"`
+}
+
+// 记录一次执行的开始，同一次执行重试时重置结果
+func (q *Queries) UpsertJobRunExecution(ctx context.Context, arg UpsertJobRunExecutionParams) (JobRunExecutions, error) {
+	row := q.db.QueryRow(ctx, upsertJobRunExecution, arg.RunID, arg.Number, arg.Reason)
+	var i JobRunExecutions
+	err := row.Scan(
+		&i.ID,
+		&i.RunID,
+		&i.Number,
+		&i.Reason,
+		&i.Status,
+		&i.Error,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,1016 @@
+package runs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"kongflow/backend/internal/services/endpointapi"
+	"kongflow/backend/internal/services/runs/queue"
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Service Runs 服务接口，对齐 trigger.dev 运行生命周期实现
+type Service interface {
+	// 运行创建 - 对齐 CreateRunService.call
+	CreateRun(ctx context.Context, req *CreateRunRequest) (*RunResponse, error)
+
+	// 运行启动 - 对齐 StartRunService.call
+	StartRun(ctx context.Context, runID string) error
+
+	// 排队运行启动 - 对齐 StartQueuedRunsService.call
+	StartQueuedRuns(ctx context.Context, req *workerqueue.StartQueuedRunsRequest) error
+
+	// 作业恢复后启动其等待中的运行
+	StartJobRuns(ctx context.Context, jobID string) error
+
+	// 集成连接建立后启动等待该连接的运行
+	ResumeConnectionRuns(ctx context.Context, integrationID string) error
+
+	// 运行执行 - 对齐 PerformRunExecutionV2Service.call
+	PerformRunExecution(ctx context.Context, req *workerqueue.RunExecutionRequest) error
+
+	// 任务完成 - 对齐 CompleteRunTaskService.call
+	CompleteTask(ctx context.Context, req *CompleteTaskRequest) (*TaskResponse, error)
+
+	// 运行取消与重跑
+	CancelRun(ctx context.Context, runID string) (*RunResponse, error)
+	RerunRun(ctx context.Context, req *RerunRunRequest) (*RunResponse, error)
+
+	// 运行超时巡检，将超过最长持续时间的运行标记为失败
+	TimeoutRuns(ctx context.Context) (int, error)
+
+	// 运行查询
+	GetRun(ctx context.Context, id string) (*RunResponse, error)
+}
+
+// 确保 service 可直接作为 workerqueue 的运行执行器和超时巡检器
+var (
+	_ workerqueue.RunExecutor        = (Service)(nil)
+	_ workerqueue.RunTimeoutEnforcer = (Service)(nil)
+)
+
+// service 实现
+type service struct {
+	repo          Repository
+	queueSvc      queue.QueueService
+	clientFactory EndpointClientFactory
+	notifier      ConnectionNotifier
+	auth          ConnectionAuthProvider
+	metrics       Metrics
+	logger        *slog.Logger
+}
+
+// NewService 创建服务实例
+// clientFactory 为 nil 时使用基于 endpointapi.Client 的默认实现，notifier 为 nil 时不发送连接提醒
+// auth 为 nil 时执行请求不携带集成连接凭证
+func NewService(repo Repository, queueSvc queue.QueueService, clientFactory EndpointClientFactory, notifier ConnectionNotifier, auth ConnectionAuthProvider, logger *slog.Logger) Service {
+	return NewServiceWithMetrics(repo, queueSvc, clientFactory, notifier, auth, nil, logger)
+}
+
+// NewServiceWithMetrics 创建记录运行指标的服务实例，metrics 为 nil 时不记录
+func NewServiceWithMetrics(repo Repository, queueSvc queue.QueueService, clientFactory EndpointClientFactory, notifier ConnectionNotifier, auth ConnectionAuthProvider, metrics Metrics, logger *slog.Logger) Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if clientFactory == nil {
+		clientFactory = NewEndpointClientFactory(logger)
+	}
+	return &service{
+		repo:          repo,
+		queueSvc:      queueSvc,
+		clientFactory: clientFactory,
+		notifier:      notifier,
+		auth:          auth,
+		metrics:       metrics,
+		logger:        logger,
+	}
+}
+
+// CreateRun 创建运行并加入启动队列，对齐 trigger.dev CreateRunService.call
+func (s *service) CreateRun(ctx context.Context, req *CreateRunRequest) (*RunResponse, error) {
+	logger := s.logger.With("operation", "create_run", "version_id", req.VersionID)
+
+	versionID, err := stringToPgUUID(req.VersionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version ID: %w", err)
+	}
+	eventID, err := stringToPgUUID(req.EventRecordID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event record ID: %w", err)
+	}
+
+	var externalAccountID pgtype.UUID
+	if req.ExternalAccountID != nil {
+		externalAccountID, err = stringToPgUUID(*req.ExternalAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid external account ID: %w", err)
+		}
+	}
+
+	var rerunOfID pgtype.UUID
+	if req.RerunOfID != "" {
+		rerunOfID, err = stringToPgUUID(req.RerunOfID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rerun of ID: %w", err)
+		}
+	}
+
+	var run JobRuns
+	var existing bool
+	var missing []MissingConnection
+	var unnotified []pgtype.UUID
+	err = s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		// 调度任务重试时复用已创建的运行，重跑总是创建新运行
+		if !rerunOfID.Valid {
+			run, err = txRepo.GetJobRunByEventAndVersion(ctx, GetJobRunByEventAndVersionParams{
+				EventID:   eventID,
+				VersionID: versionID,
+			})
+			if err == nil {
+				existing = true
+				return nil
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("failed to check existing run: %w", err)
+			}
+		}
+
+		version, err := txRepo.GetJobVersionForRun(ctx, versionID)
+		if err != nil {
+			return fmt.Errorf("failed to get job version: %w", err)
+		}
+
+		// 所需集成未连接时运行保持 WAITING_ON_CONNECTIONS，对齐 trigger.dev CreateRunService
+		integrations, err := txRepo.ListMissingIntegrationsForVersion(ctx, version.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check integration connections: %w", err)
+		}
+		status := JobRunStatusPENDING
+		if len(integrations) > 0 {
+			status = JobRunStatusWAITINGONCONNECTIONS
+		}
+
+		run, err = txRepo.CreateJobRun(ctx, CreateJobRunParams{
+			JobID:             version.JobID,
+			VersionID:         version.ID,
+			EventID:           eventID,
+			EnvironmentID:     version.EnvironmentID,
+			OrganizationID:    version.OrganizationID,
+			ProjectID:         version.ProjectID,
+			EndpointID:        version.EndpointID,
+			QueueID:           version.QueueID,
+			ExternalAccountID: externalAccountID,
+			Status:            status,
+			IsTest:            req.IsTest,
+			Preprocess:        version.PreprocessRuns,
+			RerunOfID:         rerunOfID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create job run: %w", err)
+		}
+
+		if len(integrations) > 0 {
+			for _, integration := range integrations {
+				connection, err := txRepo.UpsertMissingConnection(ctx, UpsertMissingConnectionParams{
+					IntegrationID:  integration.ID,
+					OrganizationID: integration.OrganizationID,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to record missing connection: %w", err)
+				}
+				if !connection.NotifiedAt.Valid {
+					missing = append(missing, MissingConnection{
+						IntegrationID:  pgUUIDToString(integration.ID),
+						OrganizationID: pgUUIDToString(integration.OrganizationID),
+						Slug:           integration.Slug,
+						Title:          integration.Title,
+					})
+					unnotified = append(unnotified, connection.ID)
+				}
+			}
+			// 等待连接建立后由 ResumeConnectionRuns 加入启动队列
+			return nil
+		}
+
+		// 在同一事务中加入启动队列，对齐 trigger.dev workerQueue.enqueue("startRun", { tx })
+		_, err = s.queueSvc.EnqueueStartRunTx(ctx, tx, &queue.EnqueueStartRunRequest{
+			RunID: pgUUIDToString(run.ID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enqueue start run: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to create run", "error", err)
+		return nil, err
+	}
+	if existing {
+		logger.Info("Run already created for event", "run_id", pgUUIDToString(run.ID))
+		return convertRunToResponse(run), nil
+	}
+
+	if run.Status == JobRunStatusWAITINGONCONNECTIONS {
+		logger.Info("Run waiting on integration connections", "run_id", pgUUIDToString(run.ID))
+		for i, connection := range missing {
+			s.notifyMissingConnection(ctx, unnotified[i], connection)
+		}
+	}
+
+	logger.Info("Run created", "run_id", pgUUIDToString(run.ID), "preprocess", run.Preprocess)
+	return convertRunToResponse(run), nil
+}
+
+// notifyMissingConnection 发送缺失连接提醒，每个缺失连接只通知一次，失败时只记录日志
+// 先提交通知标记再发送，发送期间不持有事务；任一收件人发送失败时撤销标记，
+// 下一个等待该连接的运行会再次提醒
+func (s *service) notifyMissingConnection(ctx context.Context, id pgtype.UUID, connection MissingConnection) {
+	if s.notifier == nil {
+		return
+	}
+	logger := s.logger.With("integration_id", connection.IntegrationID, "integration", connection.Slug)
+
+	claimed, err := s.repo.MarkMissingConnectionNotified(ctx, id)
+	if err != nil {
+		logger.Error("Failed to mark missing connection notified", "error", err)
+		return
+	}
+	if claimed == 0 {
+		return
+	}
+
+	if err := s.notifier.NotifyMissingConnection(ctx, connection); err != nil {
+		logger.Error("Failed to notify missing connection", "error", err)
+		if err := s.repo.ReleaseMissingConnectionNotification(context.WithoutCancel(ctx), id); err != nil {
+			logger.Error("Failed to release missing connection notification", "error", err)
+		}
+	}
+}
+
+// connectionAuths 加载作业版本所需集成的连接凭证，按作业中的集成键索引
+func (s *service) connectionAuths(ctx context.Context, versionID pgtype.UUID) (map[string]interface{}, error) {
+	if s.auth == nil {
+		return nil, nil
+	}
+
+	integrations, err := s.repo.ListIntegrationsForVersion(ctx, versionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list integrations for version: %w", err)
+	}
+
+	connections := make(map[string]interface{}, len(integrations))
+	for _, integration := range integrations {
+		auth, err := s.auth.ConnectionAuth(ctx, pgUUIDToString(integration.IntegrationID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load connection for integration %s: %w", integration.Key, err)
+		}
+		connections[integration.Key] = auth
+	}
+	return connections, nil
+}
+
+// ResumeConnectionRuns 集成连接建立后，将所需集成均已连接的等待运行恢复为 PENDING 并加入启动队列
+func (s *service) ResumeConnectionRuns(ctx context.Context, integrationID string) error {
+	logger := s.logger.With("operation", "resume_connection_runs", "integration_id", integrationID)
+
+	id, err := stringToPgUUID(integrationID)
+	if err != nil {
+		return err
+	}
+
+	runIDs, err := s.repo.ListRunsWaitingOnIntegration(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to list runs waiting on integration: %w", err)
+	}
+
+	resumed := 0
+	for _, runID := range runIDs {
+		err := s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+			run, err := txRepo.GetJobRunByID(ctx, runID)
+			if err != nil {
+				return fmt.Errorf("failed to get run: %w", err)
+			}
+
+			// 运行可能还在等待其他集成的连接
+			missing, err := txRepo.ListMissingIntegrationsForVersion(ctx, run.VersionID)
+			if err != nil {
+				return fmt.Errorf("failed to check integration connections: %w", err)
+			}
+			if len(missing) > 0 {
+				return nil
+			}
+
+			if _, err := txRepo.MarkJobRunPendingFromWaiting(ctx, runID); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil
+				}
+				return fmt.Errorf("failed to resume run: %w", err)
+			}
+
+			if _, err := s.queueSvc.EnqueueStartRunTx(ctx, tx, &queue.EnqueueStartRunRequest{
+				RunID: pgUUIDToString(runID),
+			}); err != nil {
+				return fmt.Errorf("failed to enqueue start run: %w", err)
+			}
+			resumed++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if resumed > 0 {
+		logger.Info("Runs resumed after connection", "count", resumed)
+	}
+	return nil
+}
+
+// StartRun 启动运行，对齐 trigger.dev StartRunService.call
+// 运行需先占用作业队列的并发槽位，队列已满时保持 QUEUED 状态，等待 StartQueuedRuns 启动
+// 作业暂停时运行同样保持 QUEUED 状态，等待作业恢复后由 StartJobRuns 启动
+func (s *service) StartRun(ctx context.Context, runID string) error {
+	logger := s.logger.With("operation", "start_run", "run_id", runID)
+
+	id, err := stringToPgUUID(runID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		run, err := txRepo.MarkJobRunQueued(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// 运行不存在或已启动，保持幂等
+				logger.Debug("Run not pending, skipping start")
+				return nil
+			}
+			return fmt.Errorf("failed to queue run: %w", err)
+		}
+
+		status, err := txRepo.GetJobStatus(ctx, run.JobID)
+		if err != nil {
+			return fmt.Errorf("failed to get job status: %w", err)
+		}
+		if status == JobStatusPAUSED {
+			logger.Info("Job is paused, run held in queue", "job_id", pgUUIDToString(run.JobID))
+			return nil
+		}
+
+		claimed, err := s.claimQueueSlot(ctx, txRepo, run)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			logger.Info("Job queue is full, run parked", "queue_id", pgUUIDToString(run.QueueID))
+			return nil
+		}
+
+		return s.beginRun(ctx, txRepo, tx, run)
+	})
+}
+
+// StartQueuedRuns 释放已结束运行的槽位并启动等待中的运行，对齐 trigger.dev StartQueuedRunsService.call
+func (s *service) StartQueuedRuns(ctx context.Context, req *workerqueue.StartQueuedRunsRequest) error {
+	logger := s.logger.With("operation", "start_queued_runs", "queue_id", req.QueueID)
+
+	queueID, err := stringToPgUUID(req.QueueID)
+	if err != nil {
+		return err
+	}
+
+	if req.FinishedRunID != "" {
+		if err := s.releaseQueueSlot(ctx, req.FinishedRunID); err != nil {
+			return err
+		}
+	}
+
+	return s.startQueuedRuns(ctx, queueID, logger)
+}
+
+// StartJobRuns 作业恢复后启动其等待中的运行，逐个队列按并发槽位启动
+func (s *service) StartJobRuns(ctx context.Context, jobID string) error {
+	logger := s.logger.With("operation", "start_job_runs", "job_id", jobID)
+
+	id, err := stringToPgUUID(jobID)
+	if err != nil {
+		return err
+	}
+
+	queueIDs, err := s.repo.ListWaitingQueuesByJob(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to list waiting queues: %w", err)
+	}
+
+	for _, queueID := range queueIDs {
+		if err := s.startQueuedRuns(ctx, queueID, logger.With("queue_id", pgUUIDToString(queueID))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startQueuedRuns 在队列槽位可用时依次启动等待中的运行
+func (s *service) startQueuedRuns(ctx context.Context, queueID pgtype.UUID, logger *slog.Logger) error {
+	started := 0
+	for {
+		var more bool
+		err := s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+			run, err := txRepo.GetNextQueuedJobRun(ctx, queueID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil
+				}
+				return fmt.Errorf("failed to get next queued run: %w", err)
+			}
+
+			claimed, err := s.claimQueueSlot(ctx, txRepo, run)
+			if err != nil || !claimed {
+				return err
+			}
+
+			more = true
+			return s.beginRun(ctx, txRepo, tx, run)
+		})
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+		started++
+	}
+
+	if started > 0 {
+		logger.Info("Queued runs started", "count", started)
+	}
+	return nil
+}
+
+// claimQueueSlot 原子地占用运行所属队列的并发槽位，队列已满时返回 false
+func (s *service) claimQueueSlot(ctx context.Context, repo Repository, run JobRuns) (bool, error) {
+	if _, err := repo.IncrementJobCount(ctx, run.QueueID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim queue slot: %w", err)
+	}
+
+	if err := repo.MarkJobRunHoldsQueueSlot(ctx, run.ID); err != nil {
+		return false, fmt.Errorf("failed to mark run holding queue slot: %w", err)
+	}
+	return true, nil
+}
+
+// releaseQueueSlot 释放已结束运行占用的槽位，重复调用时保持幂等
+func (s *service) releaseQueueSlot(ctx context.Context, runID string) error {
+	id, err := stringToPgUUID(runID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(txRepo Repository) error {
+		return releaseQueueSlotTx(ctx, txRepo, id)
+	})
+}
+
+// releaseQueueSlotTx 在给定事务中释放运行占用的槽位，运行未占用槽位时不做任何操作
+func releaseQueueSlotTx(ctx context.Context, repo Repository, runID pgtype.UUID) error {
+	queueID, err := repo.ReleaseJobRunQueueSlot(ctx, runID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to release queue slot: %w", err)
+	}
+
+	if _, err := repo.DecrementJobCount(ctx, queueID); err != nil {
+		return fmt.Errorf("failed to decrement job count: %w", err)
+	}
+	return nil
+}
+
+// beginRun 为已占用槽位的运行加入首次执行，需要预处理的运行先进入 PREPROCESSING 状态
+func (s *service) beginRun(ctx context.Context, repo Repository, tx pgx.Tx, run JobRuns) error {
+	reason := workerqueue.ExecutionReasonExecuteJob
+	if run.Preprocess {
+		if _, err := repo.MarkJobRunPreprocessing(ctx, run.ID); err != nil {
+			return fmt.Errorf("failed to mark run preprocessing: %w", err)
+		}
+		reason = workerqueue.ExecutionReasonPreprocess
+	}
+
+	_, err := s.queueSvc.EnqueuePerformRunExecutionTx(ctx, tx, &queue.EnqueuePerformRunExecutionRequest{
+		RunID:         pgUUIDToString(run.ID),
+		ProjectID:     pgUUIDToString(run.ProjectID),
+		EnvironmentID: pgUUIDToString(run.EnvironmentID),
+		EndpointID:    pgUUIDToString(run.EndpointID),
+		Reason:        string(reason),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue run execution: %w", err)
+	}
+
+	s.logger.Info("Run started", "run_id", pgUUIDToString(run.ID), "reason", reason)
+	return nil
+}
+
+// finishRun 运行结束后加入 startQueuedRuns 任务，释放槽位并启动下一个等待的运行
+// 成功、失败、取消和超时的运行都经过这里，因此在此记录运行结束指标
+func (s *service) finishRun(ctx context.Context, tx pgx.Tx, run JobRuns) error {
+	_, err := s.queueSvc.EnqueueStartQueuedRunsTx(ctx, tx, &queue.EnqueueStartQueuedRunsRequest{
+		QueueID:       pgUUIDToString(run.QueueID),
+		ProjectID:     pgUUIDToString(run.ProjectID),
+		FinishedRunID: pgUUIDToString(run.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue start queued runs: %w", err)
+	}
+
+	if s.metrics != nil {
+		s.metrics.RunFinished(string(run.Status))
+	}
+	return nil
+}
+
+// PerformRunExecution 执行运行，对齐 trigger.dev PerformRunExecutionV2Service.call
+func (s *service) PerformRunExecution(ctx context.Context, req *workerqueue.RunExecutionRequest) error {
+	id, err := stringToPgUUID(req.RunID)
+	if err != nil {
+		return err
+	}
+
+	row, err := s.repo.GetJobRunExecutionContext(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Run not found, skipping execution", "run_id", req.RunID)
+			return nil
+		}
+		return fmt.Errorf("failed to load run: %w", err)
+	}
+
+	switch req.Reason {
+	case workerqueue.ExecutionReasonPreprocess:
+		err = s.preprocessRun(ctx, row, req)
+	case workerqueue.ExecutionReasonExecuteJob:
+		err = s.executeRun(ctx, row, req)
+	default:
+		return fmt.Errorf("unknown execution reason: %s", req.Reason)
+	}
+	if err != nil && req.FinalAttempt && !drainInterrupted(ctx) {
+		return s.discardExecution(ctx, row.ID, req, err)
+	}
+	return err
+}
+
+// discardExecution 队列任务重试耗尽时将运行标记为失败，释放其占用的队列槽位
+// 否则运行会停留在 EXECUTING 状态并永久占用槽位；最后一次尝试因作业超时失败时 ctx 已取消，因此不随 ctx 取消
+func (s *service) discardExecution(ctx context.Context, runID pgtype.UUID, req *workerqueue.RunExecutionRequest, cause error) error {
+	s.logger.Error("Run execution attempts exhausted, failing run", "run_id", req.RunID, "attempt", req.Attempt, "error", cause)
+	if err := s.failRun(context.WithoutCancel(ctx), runID, &endpointapi.ErrorWithStack{
+		Message: fmt.Sprintf("Run execution failed after %d attempts: %s", req.Attempt, cause),
+	}); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// preprocessRun 调用端点预处理动作，对齐 trigger.dev #preprocessRun
+func (s *service) preprocessRun(ctx context.Context, row GetJobRunExecutionContextRow, req *workerqueue.RunExecutionRequest) error {
+	logger := s.logger.With("operation", "preprocess_run", "run_id", req.RunID)
+
+	if row.Status != JobRunStatusPREPROCESSING {
+		logger.Debug("Run not preprocessing, skipping", "status", row.Status)
+		return nil
+	}
+
+	client := s.clientFactory(row.EnvironmentApiKey, row.EndpointUrl, row.EndpointSlug)
+	runContext, _ := buildRunContext(row, req.IsRetry)
+
+	result, err := client.PreprocessRunRequest(ctx, &endpointapi.PreprocessRunBody{
+		ID:      row.JobSlug,
+		Payload: jsonbToMap(row.EventPayload),
+		Context: runContext,
+	})
+	if err != nil {
+		// 无法连接端点，交由 River 重试
+		logger.Warn("Failed to connect to endpoint for preprocessing", "error", err)
+		return fmt.Errorf("failed to preprocess run: %w", err)
+	}
+
+	if result.Response.StatusCode < 200 || result.Response.StatusCode >= 300 {
+		message := readErrorBody(result.Response)
+		logger.Error("Endpoint preprocess request failed", "status", result.Response.StatusCode)
+		return s.failRun(ctx, row.ID, &endpointapi.ErrorWithStack{
+			Message: fmt.Sprintf("Endpoint preprocess request failed: %s", message),
+		})
+	}
+
+	var response endpointapi.PreprocessRunResponse
+	if err := result.Parse(&response); err != nil {
+		logger.Error("Invalid preprocess response", "error", err)
+		return s.failRun(ctx, row.ID, &endpointapi.ErrorWithStack{
+			Message: fmt.Sprintf("Invalid preprocess response: %s", err),
+		})
+	}
+
+	var properties []byte
+	if len(response.Properties) > 0 {
+		properties, err = json.Marshal(response.Properties)
+		if err != nil {
+			return fmt.Errorf("failed to marshal run properties: %w", err)
+		}
+	}
+
+	status := JobRunStatusSTARTED
+	if response.Abort {
+		status = JobRunStatusABORTED
+	}
+
+	return s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		run, err := txRepo.MarkJobRunPreprocessed(ctx, MarkJobRunPreprocessedParams{
+			ID:         row.ID,
+			Status:     status,
+			Properties: properties,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Info("Run finished during preprocessing, skipping")
+				return nil
+			}
+			return fmt.Errorf("failed to save preprocess result: %w", err)
+		}
+
+		if response.Abort {
+			logger.Info("Run aborted by endpoint preprocess")
+			return s.finishRun(ctx, tx, run)
+		}
+
+		// 预处理完成后才加入正常执行队列
+		_, err = s.queueSvc.EnqueuePerformRunExecutionTx(ctx, tx, &queue.EnqueuePerformRunExecutionRequest{
+			RunID:         req.RunID,
+			ProjectID:     pgUUIDToString(run.ProjectID),
+			EnvironmentID: pgUUIDToString(run.EnvironmentID),
+			EndpointID:    pgUUIDToString(run.EndpointID),
+			Reason:        string(workerqueue.ExecutionReasonExecuteJob),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enqueue run execution: %w", err)
+		}
+
+		logger.Info("Run preprocessed", "properties", len(response.Properties))
+		return nil
+	})
+}
+
+// executeRun 调用端点执行动作，对齐 trigger.dev PerformRunExecutionV2Service #executeJob
+// 每次执行都会回传已完成的任务，端点据此跳过已执行的步骤，从而使运行可以跨越多次 HTTP 调用
+func (s *service) executeRun(ctx context.Context, row GetJobRunExecutionContextRow, req *workerqueue.RunExecutionRequest) error {
+	logger := s.logger.With("operation", "execute_run", "run_id", req.RunID)
+
+	switch row.Status {
+	case JobRunStatusQUEUED, JobRunStatusSTARTED, JobRunStatusEXECUTING, JobRunStatusWAITING:
+	default:
+		logger.Debug("Run not executable, skipping", "status", row.Status)
+		return nil
+	}
+
+	// 延迟任务到期，先完成该任务再继续执行，对齐 trigger.dev ResumeTaskService
+	if req.ResumeTaskID != "" {
+		if err := s.resumeTask(ctx, row.ID, req.ResumeTaskID); err != nil {
+			return err
+		}
+	}
+
+	run, err := s.repo.MarkJobRunExecuting(ctx, row.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Info("Run finished before execution, skipping")
+			return nil
+		}
+		return fmt.Errorf("failed to mark run executing: %w", err)
+	}
+	row.StartedAt = run.StartedAt
+	row.ExecutionCount = run.ExecutionCount
+
+	// 记录本次执行，供运行时间线使用
+	reason := string(req.Reason)
+	if reason == "" {
+		reason = string(workerqueue.ExecutionReasonExecuteJob)
+	}
+	execution, err := s.repo.UpsertJobRunExecution(ctx, UpsertJobRunExecutionParams{
+		RunID:  row.ID,
+		Number: run.ExecutionCount,
+		Reason: reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record run execution: %w", err)
+	}
+
+	completedTasks, err := s.repo.ListCompletedTasksByRun(ctx, row.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list completed tasks: %w", err)
+	}
+
+	client := s.clientFactory(row.EnvironmentApiKey, row.EndpointUrl, row.EndpointSlug)
+	runContext, jobRun := buildRunContext(row, req.IsRetry)
+
+	connections, err := s.connectionAuths(ctx, row.VersionID)
+	if err != nil {
+		logger.Warn("Failed to load integration connections", "error", err)
+		return err
+	}
+	if len(connections) > 0 {
+		runContext["connections"] = connections
+	}
+
+	result, err := client.ExecuteJobRequest(ctx, &endpointapi.RunJobBody{
+		ID:      row.JobSlug,
+		Payload: jsonbToMap(row.EventPayload),
+		Context: runContext,
+		JobRun:  jobRun,
+		Tasks:   convertTasksToCached(completedTasks),
+	})
+	if err != nil {
+		if drainInterrupted(ctx) {
+			s.interruptExecution(ctx, logger, execution.ID, err)
+			return fmt.Errorf("run execution interrupted: %w", err)
+		}
+		logger.Warn("Failed to connect to endpoint for execution", "error", err)
+		if recordErr := completeExecution(ctx, s.repo, execution.ID, ExecutionStatusFailed, &endpointapi.ErrorWithStack{
+			Message: err.Error(),
+		}); recordErr != nil {
+			logger.Error("Failed to record run execution", "error", recordErr)
+		}
+		return fmt.Errorf("failed to execute run: %w", err)
+	}
+
+	if result.Response.StatusCode < 200 || result.Response.StatusCode >= 300 {
+		message := readErrorBody(result.Response)
+		logger.Error("Endpoint execute request failed", "status", result.Response.StatusCode)
+		return s.failExecution(ctx, row.ID, execution.ID, &endpointapi.ErrorWithStack{
+			Message: fmt.Sprintf("Endpoint execute request failed: %s", message),
+		})
+	}
+
+	var response endpointapi.RunJobResponse
+	if err := result.Parse(&response); err != nil {
+		return s.failExecution(ctx, row.ID, execution.ID, &endpointapi.ErrorWithStack{
+			Message: fmt.Sprintf("Invalid execute response: %s", err),
+		})
+	}
+
+	logger.Info("Run execution returned", "status", response.Status, "execution", run.ExecutionCount)
+
+	err = s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		executionError := response.Error
+		if executionError == nil && response.Status == endpointapi.RunJobStatusError {
+			executionError = &endpointapi.ErrorWithStack{Message: response.Message}
+		}
+		if err := completeExecution(ctx, txRepo, execution.ID, response.Status, executionError); err != nil {
+			return err
+		}
+
+		// 持久化端点在本次执行中报告的任务
+		for i := range response.Tasks {
+			if _, err := upsertServerTask(ctx, txRepo, row.ID, &response.Tasks[i], ""); err != nil {
+				return err
+			}
+		}
+
+		switch response.Status {
+		case endpointapi.RunJobStatusSuccess:
+			output, err := marshalJSONB(response.Output)
+			if err != nil {
+				return fmt.Errorf("failed to marshal run output: %w", err)
+			}
+			completed, err := txRepo.CompleteJobRun(ctx, CompleteJobRunParams{
+				ID:     row.ID,
+				Status: JobRunStatusSUCCESS,
+				Output: output,
+			})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					logger.Info("Run finished during execution, discarding output")
+					return nil
+				}
+				return fmt.Errorf("failed to complete run: %w", err)
+			}
+			return s.finishRun(ctx, tx, completed)
+
+		case endpointapi.RunJobStatusError:
+			if response.Task != nil {
+				if _, err := upsertServerTask(ctx, txRepo, row.ID, response.Task, TaskStatusERRORED); err != nil {
+					return err
+				}
+			}
+			runError := response.Error
+			if runError == nil {
+				runError = &endpointapi.ErrorWithStack{Message: response.Message}
+			}
+			return s.failRunTx(ctx, txRepo, tx, row.ID, runError)
+
+		case endpointapi.RunJobStatusResumeWithTask:
+			if response.Task == nil {
+				return s.failRunTx(ctx, txRepo, tx, row.ID, &endpointapi.ErrorWithStack{
+					Message: "Invalid execute response: missing task for RESUME_WITH_TASK",
+				})
+			}
+			task, err := upsertServerTask(ctx, txRepo, row.ID, response.Task, TaskStatusWAITING)
+			if err != nil {
+				return err
+			}
+			if _, err := txRepo.MarkJobRunWaiting(ctx, row.ID); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					logger.Info("Run finished during execution, not resuming")
+					return nil
+				}
+				return fmt.Errorf("failed to mark run waiting: %w", err)
+			}
+			// 未设置延迟的任务等待外部完成 (CompleteTask)
+			if response.Task.DelayUntil == nil {
+				return nil
+			}
+			return s.enqueueExecution(ctx, tx, run, &queue.EnqueuePerformRunExecutionRequest{
+				ResumeTaskID: pgUUIDToString(task.ID),
+				ScheduledFor: response.Task.DelayUntil,
+			})
+
+		case endpointapi.RunJobStatusYieldExecution:
+			// 携带已完成任务立即重新执行
+			return s.enqueueExecution(ctx, tx, run, &queue.EnqueuePerformRunExecutionRequest{})
+
+		default:
+			return s.failRunTx(ctx, txRepo, tx, row.ID, &endpointapi.ErrorWithStack{
+				Message: fmt.Sprintf("Invalid execute response status: %s", response.Status),
+			})
+		}
+	})
+	if err != nil && drainInterrupted(ctx) {
+		// 结果未能提交，事务已回滚，下次执行由端点根据已完成任务重放
+		s.interruptExecution(ctx, logger, execution.ID, err)
+	}
+	return err
+}
+
+// drainInterrupted 执行是否因 worker 关闭而被取消
+// 作业超时同样会取消 ctx，此时按普通失败处理，由队列重试或耗尽后标记运行失败
+func drainInterrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), workerqueue.ErrWorkerDraining)
+}
+
+// interruptExecution 记录被 worker 关闭中断的执行
+// 运行保持 EXECUTING 状态，队列重新投递后携带已完成任务继续执行
+func (s *service) interruptExecution(ctx context.Context, logger *slog.Logger, executionID pgtype.UUID, cause error) {
+	logger.Warn("Run execution interrupted", "error", cause)
+	if err := completeExecution(context.WithoutCancel(ctx), s.repo, executionID, ExecutionStatusInterrupted, &endpointapi.ErrorWithStack{
+		Message: fmt.Sprintf("Execution interrupted: %s", cause),
+	}); err != nil {
+		logger.Error("Failed to record interrupted run execution", "error", err)
+	}
+}
+
+// enqueueExecution 为运行加入下一次 EXECUTE_JOB 执行
+func (s *service) enqueueExecution(ctx context.Context, tx pgx.Tx, run JobRuns, req *queue.EnqueuePerformRunExecutionRequest) error {
+	req.RunID = pgUUIDToString(run.ID)
+	req.ProjectID = pgUUIDToString(run.ProjectID)
+	req.EnvironmentID = pgUUIDToString(run.EnvironmentID)
+	req.EndpointID = pgUUIDToString(run.EndpointID)
+	req.Reason = string(workerqueue.ExecutionReasonExecuteJob)
+	req.ExecutionCount = int(run.ExecutionCount)
+
+	if _, err := s.queueSvc.EnqueuePerformRunExecutionTx(ctx, tx, req); err != nil {
+		return fmt.Errorf("failed to enqueue run execution: %w", err)
+	}
+	return nil
+}
+
+// resumeTask 完成到期的延迟任务，重复调用时保持幂等
+func (s *service) resumeTask(ctx context.Context, runID pgtype.UUID, taskID string) error {
+	id, err := stringToPgUUID(taskID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.CompleteTask(ctx, CompleteTaskParams{ID: id, RunID: runID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to resume task: %w", err)
+	}
+	return nil
+}
+
+// failExecution 记录执行失败并将运行标记为失败
+func (s *service) failExecution(ctx context.Context, runID, executionID pgtype.UUID, runError *endpointapi.ErrorWithStack) error {
+	return s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		if err := completeExecution(ctx, txRepo, executionID, endpointapi.RunJobStatusError, runError); err != nil {
+			return err
+		}
+		return s.failRunTx(ctx, txRepo, tx, runID, runError)
+	})
+}
+
+// failRun 在独立事务中将运行标记为失败，对齐 trigger.dev #failRunExecution
+func (s *service) failRun(ctx context.Context, runID pgtype.UUID, runError *endpointapi.ErrorWithStack) error {
+	return s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		return s.failRunTx(ctx, txRepo, tx, runID, runError)
+	})
+}
+
+// failRunTx 在给定事务中将运行标记为失败
+func (s *service) failRunTx(ctx context.Context, repo Repository, tx pgx.Tx, runID pgtype.UUID, runError *endpointapi.ErrorWithStack) error {
+	output, err := marshalJSONB(runError)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run error: %w", err)
+	}
+
+	run, err := repo.CompleteJobRun(ctx, CompleteJobRunParams{
+		ID:     runID,
+		Status: JobRunStatusFAILURE,
+		Output: output,
+	})
+	if err != nil {
+		// 运行已被取消或超时时保持原状态
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to mark run failed: %w", err)
+	}
+	return s.runFailed(ctx, tx, run)
+}
+
+// runFailed 运行失败后加入失败通知任务，并走运行结束的收尾流程
+func (s *service) runFailed(ctx context.Context, tx pgx.Tx, run JobRuns) error {
+	if _, err := s.queueSvc.EnqueueNotifyRunFailedTx(ctx, tx, pgUUIDToString(run.ID)); err != nil {
+		return fmt.Errorf("failed to enqueue run failure notification: %w", err)
+	}
+	return s.finishRun(ctx, tx, run)
+}
+
+// CompleteTask 完成等待中的任务并恢复运行，对齐 trigger.dev CompleteRunTaskService
+func (s *service) CompleteTask(ctx context.Context, req *CompleteTaskRequest) (*TaskResponse, error) {
+	logger := s.logger.With("operation", "complete_task", "run_id", req.RunID, "task_id", req.TaskID)
+
+	runID, err := stringToPgUUID(req.RunID)
+	if err != nil {
+		return nil, err
+	}
+	taskID, err := stringToPgUUID(req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	output, err := marshalJSONB(req.Output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task output: %w", err)
+	}
+
+	var task Tasks
+	err = s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		task, err = txRepo.CompleteTask(ctx, CompleteTaskParams{ID: taskID, RunID: runID, Output: output})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("task %s is not waiting", req.TaskID)
+			}
+			return fmt.Errorf("failed to complete task: %w", err)
+		}
+
+		run, err := txRepo.GetJobRunByID(ctx, runID)
+		if err != nil {
+			return fmt.Errorf("failed to get run: %w", err)
+		}
+
+		// 仅恢复正在等待的运行
+		if run.Status != JobRunStatusWAITING {
+			return nil
+		}
+		return s.enqueueExecution(ctx, tx, run, &queue.EnqueuePerformRunExecutionRequest{})
+	})
+	if err != nil {
+		logger.Error("Failed to complete task", "error", err)
+		return nil, err
+	}
+
+	logger.Info("Task completed")
+	return convertTaskToResponse(task), nil
+}
+
+// GetRun 获取运行
+func (s *service) GetRun(ctx context.Context, id string) (*RunResponse, error) {
+	runID, err := stringToPgUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.repo.GetJobRunByID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run: %w", err)
+	}
+
+	return convertRunToResponse(run), nil
+}
@@ -0,0 +1,1283 @@
+package runs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kongflow/backend/internal/services/endpointapi"
+	"kongflow/backend/internal/services/runs/queue"
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository 模拟仓储
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateJobRun(ctx context.Context, params CreateJobRunParams) (JobRuns, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) GetJobRunByID(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) GetJobRunByEventAndVersion(ctx context.Context, params GetJobRunByEventAndVersionParams) (JobRuns, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) GetJobRunExecutionContext(ctx context.Context, id pgtype.UUID) (GetJobRunExecutionContextRow, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(GetJobRunExecutionContextRow), args.Error(1)
+}
+
+func (m *MockRepository) UpdateJobRunStatus(ctx context.Context, params UpdateJobRunStatusParams) (JobRuns, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) MarkJobRunQueued(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) MarkJobRunPreprocessing(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) MarkJobRunPreprocessed(ctx context.Context, params MarkJobRunPreprocessedParams) (JobRuns, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) MarkJobRunExecuting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) MarkJobRunWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) CompleteJobRun(ctx context.Context, params CompleteJobRunParams) (JobRuns, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) CancelJobRun(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) GetLatestJobVersionIDForRun(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(pgtype.UUID), args.Error(1)
+}
+
+func (m *MockRepository) ListTimedOutJobRuns(ctx context.Context, limit int32) ([]ListTimedOutJobRunsRow, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]ListTimedOutJobRunsRow), args.Error(1)
+}
+
+func (m *MockRepository) TimeoutJobRun(ctx context.Context, params TimeoutJobRunParams) (JobRuns, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) ListJobRunsPage(ctx context.Context, params ListJobRunsPageParams) ([]JobRuns, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) ListJobRunsPageReverse(ctx context.Context, params ListJobRunsPageReverseParams) ([]JobRuns, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) GetJobRunEvent(ctx context.Context, runID pgtype.UUID) (GetJobRunEventRow, error) {
+	args := m.Called(ctx, runID)
+	return args.Get(0).(GetJobRunEventRow), args.Error(1)
+}
+
+func (m *MockRepository) UpsertJobRunExecution(ctx context.Context, params UpsertJobRunExecutionParams) (JobRunExecutions, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(JobRunExecutions), args.Error(1)
+}
+
+func (m *MockRepository) CompleteJobRunExecution(ctx context.Context, params CompleteJobRunExecutionParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListJobRunExecutions(ctx context.Context, runID pgtype.UUID) ([]JobRunExecutions, error) {
+	args := m.Called(ctx, runID)
+	return args.Get(0).([]JobRunExecutions), args.Error(1)
+}
+
+func (m *MockRepository) CancelOpenJobRunExecutions(ctx context.Context, runID pgtype.UUID) error {
+	args := m.Called(ctx, runID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TimeoutOpenJobRunExecutions(ctx context.Context, params TimeoutOpenJobRunExecutionsParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetNextQueuedJobRun(ctx context.Context, queueID pgtype.UUID) (JobRuns, error) {
+	args := m.Called(ctx, queueID)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) MarkJobRunHoldsQueueSlot(ctx context.Context, id pgtype.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReleaseJobRunQueueSlot(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(pgtype.UUID), args.Error(1)
+}
+
+func (m *MockRepository) GetJobStatus(ctx context.Context, jobID pgtype.UUID) (JobStatus, error) {
+	args := m.Called(ctx, jobID)
+	return args.Get(0).(JobStatus), args.Error(1)
+}
+
+func (m *MockRepository) ListWaitingQueuesByJob(ctx context.Context, jobID pgtype.UUID) ([]pgtype.UUID, error) {
+	args := m.Called(ctx, jobID)
+	return args.Get(0).([]pgtype.UUID), args.Error(1)
+}
+
+func (m *MockRepository) IncrementJobCount(ctx context.Context, queueID pgtype.UUID) (IncrementJobCountRow, error) {
+	args := m.Called(ctx, queueID)
+	return args.Get(0).(IncrementJobCountRow), args.Error(1)
+}
+
+func (m *MockRepository) DecrementJobCount(ctx context.Context, queueID pgtype.UUID) (DecrementJobCountRow, error) {
+	args := m.Called(ctx, queueID)
+	return args.Get(0).(DecrementJobCountRow), args.Error(1)
+}
+
+func (m *MockRepository) ListMissingIntegrationsForVersion(ctx context.Context, versionID pgtype.UUID) ([]ListMissingIntegrationsForVersionRow, error) {
+	args := m.Called(ctx, versionID)
+	return args.Get(0).([]ListMissingIntegrationsForVersionRow), args.Error(1)
+}
+
+func (m *MockRepository) ListIntegrationsForVersion(ctx context.Context, versionID pgtype.UUID) ([]ListIntegrationsForVersionRow, error) {
+	args := m.Called(ctx, versionID)
+	return args.Get(0).([]ListIntegrationsForVersionRow), args.Error(1)
+}
+
+func (m *MockRepository) UpsertMissingConnection(ctx context.Context, params UpsertMissingConnectionParams) (MissingConnections, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(MissingConnections), args.Error(1)
+}
+
+func (m *MockRepository) MarkMissingConnectionNotified(ctx context.Context, id pgtype.UUID) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) ReleaseMissingConnectionNotification(ctx context.Context, id pgtype.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListRunsWaitingOnIntegration(ctx context.Context, integrationID pgtype.UUID) ([]pgtype.UUID, error) {
+	args := m.Called(ctx, integrationID)
+	return args.Get(0).([]pgtype.UUID), args.Error(1)
+}
+
+func (m *MockRepository) MarkJobRunPendingFromWaiting(ctx context.Context, id pgtype.UUID) (JobRuns, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(JobRuns), args.Error(1)
+}
+
+func (m *MockRepository) GetRunFailureNotificationContext(ctx context.Context, id pgtype.UUID) (GetRunFailureNotificationContextRow, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(GetRunFailureNotificationContextRow), args.Error(1)
+}
+
+func (m *MockRepository) ClaimJobFailureNotification(ctx context.Context, params ClaimJobFailureNotificationParams) (pgtype.UUID, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(pgtype.UUID), args.Error(1)
+}
+
+func (m *MockRepository) ListRunFailureRecipients(ctx context.Context, organizationID pgtype.UUID) ([]ListRunFailureRecipientsRow, error) {
+	args := m.Called(ctx, organizationID)
+	return args.Get(0).([]ListRunFailureRecipientsRow), args.Error(1)
+}
+
+func (m *MockRepository) UpsertTask(ctx context.Context, params UpsertTaskParams) (Tasks, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(Tasks), args.Error(1)
+}
+
+func (m *MockRepository) GetTaskByID(ctx context.Context, id pgtype.UUID) (Tasks, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Tasks), args.Error(1)
+}
+
+func (m *MockRepository) GetTaskByIdempotencyKey(ctx context.Context, params GetTaskByIdempotencyKeyParams) (Tasks, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(Tasks), args.Error(1)
+}
+
+func (m *MockRepository) ListTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error) {
+	args := m.Called(ctx, runID)
+	return args.Get(0).([]Tasks), args.Error(1)
+}
+
+func (m *MockRepository) ListCompletedTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error) {
+	args := m.Called(ctx, runID)
+	return args.Get(0).([]Tasks), args.Error(1)
+}
+
+func (m *MockRepository) CompleteTask(ctx context.Context, params CompleteTaskParams) (Tasks, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(Tasks), args.Error(1)
+}
+
+func (m *MockRepository) CancelPendingTasks(ctx context.Context, runID pgtype.UUID) error {
+	args := m.Called(ctx, runID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetJobVersionForRun(ctx context.Context, id pgtype.UUID) (GetJobVersionForRunRow, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(GetJobVersionForRunRow), args.Error(1)
+}
+
+func (m *MockRepository) WithTx(ctx context.Context, fn func(Repository) error) error {
+	return fn(m)
+}
+
+func (m *MockRepository) WithTxAndReturn(ctx context.Context, fn func(Repository, pgx.Tx) error) error {
+	return fn(m, nil)
+}
+
+// MockQueueService 模拟队列服务
+type MockQueueService struct {
+	mock.Mock
+}
+
+func (m *MockQueueService) EnqueueStartRun(ctx context.Context, req *queue.EnqueueStartRunRequest) (*rivertype.JobInsertResult, error) {
+	args := m.Called(ctx, req)
+	return nil, args.Error(0)
+}
+
+func (m *MockQueueService) EnqueuePerformRunExecution(ctx context.Context, req *queue.EnqueuePerformRunExecutionRequest) (*rivertype.JobInsertResult, error) {
+	args := m.Called(ctx, req)
+	return nil, args.Error(0)
+}
+
+func (m *MockQueueService) EnqueueStartRunTx(ctx context.Context, tx pgx.Tx, req *queue.EnqueueStartRunRequest) (*rivertype.JobInsertResult, error) {
+	args := m.Called(ctx, req)
+	return nil, args.Error(0)
+}
+
+func (m *MockQueueService) EnqueuePerformRunExecutionTx(ctx context.Context, tx pgx.Tx, req *queue.EnqueuePerformRunExecutionRequest) (*rivertype.JobInsertResult, error) {
+	args := m.Called(ctx, req)
+	return nil, args.Error(0)
+}
+
+func (m *MockQueueService) EnqueueStartQueuedRuns(ctx context.Context, req *queue.EnqueueStartQueuedRunsRequest) (*rivertype.JobInsertResult, error) {
+	args := m.Called(ctx, req)
+	return nil, args.Error(0)
+}
+
+func (m *MockQueueService) EnqueueStartQueuedRunsTx(ctx context.Context, tx pgx.Tx, req *queue.EnqueueStartQueuedRunsRequest) (*rivertype.JobInsertResult, error) {
+	args := m.Called(ctx, req)
+	return nil, args.Error(0)
+}
+
+func (m *MockQueueService) CancelRunExecutionsTx(ctx context.Context, tx pgx.Tx, runID string) (int, error) {
+	args := m.Called(ctx, runID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQueueService) EnqueueNotifyRunFailedTx(ctx context.Context, tx pgx.Tx, runID string) (*rivertype.JobInsertResult, error) {
+	args := m.Called(ctx, runID)
+	return nil, args.Error(0)
+}
+
+// MockConnectionNotifier 模拟缺失连接通知
+type MockConnectionNotifier struct {
+	mock.Mock
+}
+
+func (m *MockConnectionNotifier) NotifyMissingConnection(ctx context.Context, connection MissingConnection) error {
+	args := m.Called(ctx, connection)
+	return args.Error(0)
+}
+
+// MockConnectionAuthProvider 模拟连接认证提供者
+type MockConnectionAuthProvider struct {
+	mock.Mock
+}
+
+func (m *MockConnectionAuthProvider) ConnectionAuth(ctx context.Context, integrationID string) (map[string]interface{}, error) {
+	args := m.Called(ctx, integrationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+// 测试辅助函数
+
+func newPgUUID() pgtype.UUID {
+	return pgtype.UUID{Bytes: uuid.New(), Valid: true}
+}
+
+func createTestRun(status JobRunStatus) JobRuns {
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return JobRuns{
+		ID:             newPgUUID(),
+		JobID:          newPgUUID(),
+		VersionID:      newPgUUID(),
+		EventID:        newPgUUID(),
+		EnvironmentID:  newPgUUID(),
+		OrganizationID: newPgUUID(),
+		ProjectID:      newPgUUID(),
+		EndpointID:     newPgUUID(),
+		QueueID:        newPgUUID(),
+		Status:         status,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+func createTestExecutionContext(run JobRuns, endpointURL string) GetJobRunExecutionContextRow {
+	return GetJobRunExecutionContextRow{
+		ID:                run.ID,
+		Status:            run.Status,
+		Preprocess:        run.Preprocess,
+		VersionID:         run.VersionID,
+		EventID:           "evt_123",
+		EventName:         "user.created",
+		EventSource:       "trigger.dev",
+		EventPayload:      []byte(`{"userId":"user_1"}`),
+		JobSlug:           "welcome-email",
+		JobVersion:        "1.0.0",
+		EnvironmentID:     run.EnvironmentID,
+		EnvironmentSlug:   "prod",
+		EnvironmentType:   "PRODUCTION",
+		EnvironmentApiKey: "tr_prod_123",
+		OrganizationID:    run.OrganizationID,
+		ProjectID:         run.ProjectID,
+		EndpointID:        run.EndpointID,
+		EndpointSlug:      "my-endpoint",
+		EndpointUrl:       endpointURL,
+	}
+}
+
+// expectQueueSlot 模拟占用队列槽位的结果
+func expectQueueSlot(repo *MockRepository, run JobRuns, available bool) {
+	if !available {
+		repo.On("IncrementJobCount", mock.Anything, run.QueueID).Return(IncrementJobCountRow{}, pgx.ErrNoRows)
+		return
+	}
+	repo.On("IncrementJobCount", mock.Anything, run.QueueID).Return(IncrementJobCountRow{ID: run.QueueID, JobCount: 1, MaxJobs: 10}, nil)
+	repo.On("MarkJobRunHoldsQueueSlot", mock.Anything, run.ID).Return(nil)
+}
+
+// expectExecutionRecorded 期望记录一次执行及其结果
+func expectExecutionRecorded(repo *MockRepository, run JobRuns, status string) {
+	executionID := newPgUUID()
+	repo.On("UpsertJobRunExecution", mock.Anything, mock.MatchedBy(func(p UpsertJobRunExecutionParams) bool {
+		return p.RunID == run.ID
+	})).Return(JobRunExecutions{ID: executionID, RunID: run.ID}, nil)
+	repo.On("CompleteJobRunExecution", mock.Anything, mock.MatchedBy(func(p CompleteJobRunExecutionParams) bool {
+		return p.ID == executionID && p.Status == status
+	})).Return(nil)
+}
+
+// expectRunFinished 期望运行结束后加入 startQueuedRuns 任务
+func expectRunFinished(queueSvc *MockQueueService, run JobRuns) {
+	queueSvc.On("EnqueueStartQueuedRunsTx", mock.Anything, &queue.EnqueueStartQueuedRunsRequest{
+		QueueID:       pgUUIDToString(run.QueueID),
+		ProjectID:     pgUUIDToString(run.ProjectID),
+		FinishedRunID: pgUUIDToString(run.ID),
+	}).Return(nil)
+}
+
+// expectRunFailed 期望运行失败后加入失败通知任务和 startQueuedRuns 任务
+func expectRunFailed(queueSvc *MockQueueService, run JobRuns) {
+	queueSvc.On("EnqueueNotifyRunFailedTx", mock.Anything, pgUUIDToString(run.ID)).Return(nil)
+	expectRunFinished(queueSvc, run)
+}
+
+func newTestService(repo *MockRepository, queueSvc *MockQueueService) Service {
+	return NewService(repo, queueSvc, nil, nil, nil, slog.Default())
+}
+
+// recordingMetrics 记录运行结束时的状态
+type recordingMetrics struct {
+	finished []string
+}
+
+func (m *recordingMetrics) RunFinished(status string) {
+	m.finished = append(m.finished, status)
+}
+
+func TestService_CreateRun_EnqueuesStartRun(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	version := GetJobVersionForRunRow{
+		ID:             newPgUUID(),
+		JobID:          newPgUUID(),
+		EndpointID:     newPgUUID(),
+		EnvironmentID:  newPgUUID(),
+		OrganizationID: newPgUUID(),
+		ProjectID:      newPgUUID(),
+		QueueID:        newPgUUID(),
+		PreprocessRuns: true,
+	}
+	run := createTestRun(JobRunStatusPENDING)
+	run.Preprocess = true
+	eventID := uuid.New()
+
+	repo.On("GetJobRunByEventAndVersion", mock.Anything, mock.Anything).Return(JobRuns{}, pgx.ErrNoRows)
+	repo.On("GetJobVersionForRun", mock.Anything, version.ID).Return(version, nil)
+	repo.On("ListMissingIntegrationsForVersion", mock.Anything, version.ID).Return([]ListMissingIntegrationsForVersionRow{}, nil)
+	repo.On("CreateJobRun", mock.Anything, mock.MatchedBy(func(p CreateJobRunParams) bool {
+		return p.VersionID == version.ID && p.Preprocess && p.Status == JobRunStatusPENDING &&
+			p.EventID.Bytes == eventID
+	})).Return(run, nil)
+	queueSvc.On("EnqueueStartRunTx", mock.Anything, &queue.EnqueueStartRunRequest{RunID: pgUUIDToString(run.ID)}).Return(nil)
+
+	result, err := svc.CreateRun(context.Background(), &CreateRunRequest{
+		EventRecordID: eventID.String(),
+		VersionID:     pgUUIDToString(version.ID),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, pgUUIDToString(run.ID), result.ID)
+	assert.Equal(t, JobRunStatusPENDING, result.Status)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_CreateRun_ReusesRunForRetriedDispatch(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusQUEUED)
+
+	// 上一次调度已创建运行，之后的步骤失败导致任务重试
+	repo.On("GetJobRunByEventAndVersion", mock.Anything, GetJobRunByEventAndVersionParams{
+		EventID:   run.EventID,
+		VersionID: run.VersionID,
+	}).Return(run, nil)
+
+	result, err := svc.CreateRun(context.Background(), &CreateRunRequest{
+		EventRecordID: pgUUIDToString(run.EventID),
+		VersionID:     pgUUIDToString(run.VersionID),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, pgUUIDToString(run.ID), result.ID)
+	repo.AssertNotCalled(t, "CreateJobRun", mock.Anything, mock.Anything)
+	queueSvc.AssertNotCalled(t, "EnqueueStartRunTx", mock.Anything, mock.Anything)
+}
+
+func TestService_CreateRun_WaitsOnMissingConnections(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	notifier := &MockConnectionNotifier{}
+	svc := NewService(repo, queueSvc, nil, notifier, nil, slog.Default())
+
+	version := GetJobVersionForRunRow{ID: newPgUUID(), JobID: newPgUUID(), QueueID: newPgUUID()}
+	github := ListMissingIntegrationsForVersionRow{ID: newPgUUID(), OrganizationID: newPgUUID(), Slug: "github", Title: "GitHub"}
+	slack := ListMissingIntegrationsForVersionRow{ID: newPgUUID(), OrganizationID: newPgUUID(), Slug: "slack", Title: "Slack"}
+	run := createTestRun(JobRunStatusWAITINGONCONNECTIONS)
+
+	githubConnection := MissingConnections{ID: newPgUUID(), IntegrationID: github.ID}
+	// slack 的缺失连接已通知过
+	slackConnection := MissingConnections{ID: newPgUUID(), IntegrationID: slack.ID, NotifiedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+
+	repo.On("GetJobRunByEventAndVersion", mock.Anything, mock.Anything).Return(JobRuns{}, pgx.ErrNoRows)
+	repo.On("GetJobVersionForRun", mock.Anything, version.ID).Return(version, nil)
+	repo.On("ListMissingIntegrationsForVersion", mock.Anything, version.ID).Return([]ListMissingIntegrationsForVersionRow{github, slack}, nil)
+	repo.On("CreateJobRun", mock.Anything, mock.MatchedBy(func(p CreateJobRunParams) bool {
+		return p.Status == JobRunStatusWAITINGONCONNECTIONS
+	})).Return(run, nil)
+	repo.On("UpsertMissingConnection", mock.Anything, UpsertMissingConnectionParams{IntegrationID: github.ID, OrganizationID: github.OrganizationID}).Return(githubConnection, nil)
+	repo.On("UpsertMissingConnection", mock.Anything, UpsertMissingConnectionParams{IntegrationID: slack.ID, OrganizationID: slack.OrganizationID}).Return(slackConnection, nil)
+	repo.On("MarkMissingConnectionNotified", mock.Anything, githubConnection.ID).Return(int64(1), nil)
+	notifier.On("NotifyMissingConnection", mock.Anything, MissingConnection{
+		IntegrationID:  pgUUIDToString(github.ID),
+		OrganizationID: pgUUIDToString(github.OrganizationID),
+		Slug:           "github",
+		Title:          "GitHub",
+	}).Return(nil)
+
+	result, err := svc.CreateRun(context.Background(), &CreateRunRequest{
+		EventRecordID: uuid.New().String(),
+		VersionID:     pgUUIDToString(version.ID),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, JobRunStatusWAITINGONCONNECTIONS, result.Status)
+	queueSvc.AssertNotCalled(t, "EnqueueStartRunTx", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestService_CreateRun_FailedConnectionNotificationReleasesClaim(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	notifier := &MockConnectionNotifier{}
+	svc := NewService(repo, queueSvc, nil, notifier, nil, slog.Default())
+
+	version := GetJobVersionForRunRow{ID: newPgUUID(), JobID: newPgUUID(), QueueID: newPgUUID()}
+	github := ListMissingIntegrationsForVersionRow{ID: newPgUUID(), OrganizationID: newPgUUID(), Slug: "github", Title: "GitHub"}
+	run := createTestRun(JobRunStatusWAITINGONCONNECTIONS)
+	githubConnection := MissingConnections{ID: newPgUUID(), IntegrationID: github.ID}
+
+	repo.On("GetJobRunByEventAndVersion", mock.Anything, mock.Anything).Return(JobRuns{}, pgx.ErrNoRows)
+	repo.On("GetJobVersionForRun", mock.Anything, version.ID).Return(version, nil)
+	repo.On("ListMissingIntegrationsForVersion", mock.Anything, version.ID).Return([]ListMissingIntegrationsForVersionRow{github}, nil)
+	repo.On("CreateJobRun", mock.Anything, mock.Anything).Return(run, nil)
+	repo.On("UpsertMissingConnection", mock.Anything, mock.Anything).Return(githubConnection, nil)
+	repo.On("MarkMissingConnectionNotified", mock.Anything, githubConnection.ID).Return(int64(1), nil)
+	notifier.On("NotifyMissingConnection", mock.Anything, mock.Anything).Return(assert.AnError)
+	repo.On("ReleaseMissingConnectionNotification", mock.Anything, githubConnection.ID).Return(nil)
+
+	result, err := svc.CreateRun(context.Background(), &CreateRunRequest{
+		EventRecordID: uuid.New().String(),
+		VersionID:     pgUUIDToString(version.ID),
+	})
+
+	// 提醒失败不影响运行创建，但需要撤销通知标记以便下一个运行再次提醒
+	require.NoError(t, err)
+	assert.Equal(t, JobRunStatusWAITINGONCONNECTIONS, result.Status)
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestService_ResumeConnectionRuns(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	integrationID := newPgUUID()
+	ready := createTestRun(JobRunStatusWAITINGONCONNECTIONS)
+	stillWaiting := createTestRun(JobRunStatusWAITINGONCONNECTIONS)
+
+	repo.On("ListRunsWaitingOnIntegration", mock.Anything, integrationID).Return([]pgtype.UUID{ready.ID, stillWaiting.ID}, nil)
+	repo.On("GetJobRunByID", mock.Anything, ready.ID).Return(ready, nil)
+	repo.On("GetJobRunByID", mock.Anything, stillWaiting.ID).Return(stillWaiting, nil)
+	repo.On("ListMissingIntegrationsForVersion", mock.Anything, ready.VersionID).Return([]ListMissingIntegrationsForVersionRow{}, nil)
+	repo.On("ListMissingIntegrationsForVersion", mock.Anything, stillWaiting.VersionID).Return([]ListMissingIntegrationsForVersionRow{{ID: newPgUUID(), Slug: "slack"}}, nil)
+	repo.On("MarkJobRunPendingFromWaiting", mock.Anything, ready.ID).Return(ready, nil)
+	queueSvc.On("EnqueueStartRunTx", mock.Anything, &queue.EnqueueStartRunRequest{RunID: pgUUIDToString(ready.ID)}).Return(nil)
+
+	err := svc.ResumeConnectionRuns(context.Background(), pgUUIDToString(integrationID))
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "MarkJobRunPendingFromWaiting", mock.Anything, stillWaiting.ID)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_StartRun_WithPreprocess(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusQUEUED)
+	run.Preprocess = true
+
+	repo.On("MarkJobRunQueued", mock.Anything, run.ID).Return(run, nil)
+	repo.On("GetJobStatus", mock.Anything, run.JobID).Return(JobStatusENABLED, nil)
+	expectQueueSlot(repo, run, true)
+	repo.On("MarkJobRunPreprocessing", mock.Anything, run.ID).Return(run, nil)
+	queueSvc.On("EnqueuePerformRunExecutionTx", mock.Anything, mock.MatchedBy(func(req *queue.EnqueuePerformRunExecutionRequest) bool {
+		return req.Reason == string(workerqueue.ExecutionReasonPreprocess)
+	})).Return(nil)
+
+	err := svc.StartRun(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_StartRun_WithoutPreprocess(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusQUEUED)
+
+	repo.On("MarkJobRunQueued", mock.Anything, run.ID).Return(run, nil)
+	repo.On("GetJobStatus", mock.Anything, run.JobID).Return(JobStatusENABLED, nil)
+	expectQueueSlot(repo, run, true)
+	queueSvc.On("EnqueuePerformRunExecutionTx", mock.Anything, mock.MatchedBy(func(req *queue.EnqueuePerformRunExecutionRequest) bool {
+		return req.Reason == string(workerqueue.ExecutionReasonExecuteJob)
+	})).Return(nil)
+
+	err := svc.StartRun(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "MarkJobRunPreprocessing", mock.Anything, mock.Anything)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_StartRun_AlreadyStarted(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusSTARTED)
+	repo.On("MarkJobRunQueued", mock.Anything, run.ID).Return(JobRuns{}, pgx.ErrNoRows)
+
+	err := svc.StartRun(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	queueSvc.AssertNotCalled(t, "EnqueuePerformRunExecutionTx", mock.Anything, mock.Anything)
+}
+
+func TestService_PerformRunExecution_PreprocessSavesProperties(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PREPROCESS_RUN", r.Header.Get("x-trigger-action"))
+		assert.Equal(t, "tr_prod_123", r.Header.Get("x-trigger-api-key"))
+
+		var body endpointapi.PreprocessRunBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "welcome-email", body.ID)
+		assert.Equal(t, "user_1", body.Payload["userId"])
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"abort": false, "properties": [{"label": "User", "text": "user_1"}]}`))
+	}))
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusPREPROCESSING)
+	run.Preprocess = true
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunPreprocessed", mock.Anything, mock.MatchedBy(func(p MarkJobRunPreprocessedParams) bool {
+		var props []endpointapi.DisplayProperty
+		_ = json.Unmarshal(p.Properties, &props)
+		return p.Status == JobRunStatusSTARTED && len(props) == 1 && props[0].Label == "User"
+	})).Return(run, nil)
+	queueSvc.On("EnqueuePerformRunExecutionTx", mock.Anything, mock.MatchedBy(func(req *queue.EnqueuePerformRunExecutionRequest) bool {
+		return req.Reason == string(workerqueue.ExecutionReasonExecuteJob)
+	})).Return(nil)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonPreprocess,
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_PerformRunExecution_PreprocessAbort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"abort": true}`))
+	}))
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusPREPROCESSING)
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunPreprocessed", mock.Anything, mock.MatchedBy(func(p MarkJobRunPreprocessedParams) bool {
+		return p.Status == JobRunStatusABORTED
+	})).Return(run, nil)
+	expectRunFinished(queueSvc, run)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonPreprocess,
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+	queueSvc.AssertNotCalled(t, "EnqueuePerformRunExecutionTx", mock.Anything, mock.Anything)
+}
+
+func TestService_PerformRunExecution_PreprocessEndpointError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusPREPROCESSING)
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("CompleteJobRun", mock.Anything, mock.MatchedBy(func(p CompleteJobRunParams) bool {
+		var output endpointapi.ErrorWithStack
+		_ = json.Unmarshal(p.Output, &output)
+		return p.Status == JobRunStatusFAILURE && assert.Contains(t, output.Message, "boom")
+	})).Return(run, nil)
+	expectRunFailed(queueSvc, run)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonPreprocess,
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertNotCalled(t, "EnqueuePerformRunExecutionTx", mock.Anything, mock.Anything)
+}
+
+func TestService_PerformRunExecution_SkipsWhenNotPreprocessing(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusABORTED)
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, "http://unused"), nil)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonPreprocess,
+	})
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "MarkJobRunPreprocessed", mock.Anything, mock.Anything)
+}
+
+func newExecuteServer(t *testing.T, response string, inspect func(body endpointapi.RunJobBody)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "EXECUTE_JOB", r.Header.Get("x-trigger-action"))
+
+		var body endpointapi.RunJobBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if inspect != nil {
+			inspect(body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+}
+
+func TestService_PerformRunExecution_ExecuteSuccess(t *testing.T) {
+	completed := Tasks{
+		ID:             newPgUUID(),
+		IdempotencyKey: "send-email",
+		Name:           "Send email",
+		Status:         TaskStatusCOMPLETED,
+		Output:         []byte(`{"sent":true}`),
+	}
+	server := newExecuteServer(t, `{"status": "SUCCESS", "output": {"ok": true}}`, func(body endpointapi.RunJobBody) {
+		require.Len(t, body.Tasks, 1)
+		assert.Equal(t, "send-email", body.Tasks[0].IdempotencyKey)
+		assert.Equal(t, map[string]interface{}{"sent": true}, body.Tasks[0].Output)
+	})
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusQUEUED)
+	executing := run
+	executing.Status = JobRunStatusEXECUTING
+	executing.ExecutionCount = 2
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(executing, nil)
+	expectExecutionRecorded(repo, run, endpointapi.RunJobStatusSuccess)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{completed}, nil)
+	repo.On("CompleteJobRun", mock.Anything, mock.MatchedBy(func(p CompleteJobRunParams) bool {
+		return p.Status == JobRunStatusSUCCESS && string(p.Output) == `{"ok":true}`
+	})).Return(executing, nil)
+	expectRunFinished(queueSvc, executing)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonExecuteJob,
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertNotCalled(t, "EnqueuePerformRunExecutionTx", mock.Anything, mock.Anything)
+}
+
+func TestService_PerformRunExecution_AttachesConnections(t *testing.T) {
+	server := newExecuteServer(t, `{"status": "SUCCESS"}`, func(body endpointapi.RunJobBody) {
+		connections, ok := body.Context["connections"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, map[string]interface{}{"type": "oauth2", "accessToken": "gho_123"}, connections["github"])
+	})
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	auth := &MockConnectionAuthProvider{}
+	svc := NewService(repo, queueSvc, nil, nil, auth, slog.Default())
+
+	run := createTestRun(JobRunStatusQUEUED)
+	executing := run
+	executing.Status = JobRunStatusEXECUTING
+	integrationID := newPgUUID()
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(executing, nil)
+	expectExecutionRecorded(repo, run, endpointapi.RunJobStatusSuccess)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{}, nil)
+	repo.On("ListIntegrationsForVersion", mock.Anything, run.VersionID).Return([]ListIntegrationsForVersionRow{
+		{Key: "github", IntegrationID: integrationID},
+	}, nil)
+	auth.On("ConnectionAuth", mock.Anything, pgUUIDToString(integrationID)).
+		Return(map[string]interface{}{"type": "oauth2", "accessToken": "gho_123"}, nil)
+	repo.On("CompleteJobRun", mock.Anything, mock.Anything).Return(executing, nil)
+	expectRunFinished(queueSvc, executing)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonExecuteJob,
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	auth.AssertExpectations(t)
+}
+
+func TestService_PerformRunExecution_ExecuteError(t *testing.T) {
+	server := newExecuteServer(t, `{
+		"status": "ERROR",
+		"error": {"message": "charge failed", "stack": "at charge()"},
+		"task": {"id": "t1", "idempotencyKey": "charge", "name": "Charge card", "status": "RUNNING"}
+	}`, nil)
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusEXECUTING)
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(run, nil)
+	expectExecutionRecorded(repo, run, endpointapi.RunJobStatusError)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{}, nil)
+	repo.On("UpsertTask", mock.Anything, mock.MatchedBy(func(p UpsertTaskParams) bool {
+		return p.IdempotencyKey == "charge" && p.Status == TaskStatusERRORED && p.CompletedAt.Valid
+	})).Return(Tasks{ID: newPgUUID()}, nil)
+	repo.On("CompleteJobRun", mock.Anything, mock.MatchedBy(func(p CompleteJobRunParams) bool {
+		var output endpointapi.ErrorWithStack
+		_ = json.Unmarshal(p.Output, &output)
+		return p.Status == JobRunStatusFAILURE && output.Message == "charge failed" && output.Stack == "at charge()"
+	})).Return(run, nil)
+	expectRunFailed(queueSvc, run)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonExecuteJob,
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestService_PerformRunExecution_FinalAttemptFailsRun(t *testing.T) {
+	// 端点不可达，队列任务最后一次尝试失败
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusEXECUTING)
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(run, nil)
+	expectExecutionRecorded(repo, run, ExecutionStatusFailed)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{}, nil)
+	repo.On("CompleteJobRun", mock.Anything, mock.MatchedBy(func(p CompleteJobRunParams) bool {
+		var output endpointapi.ErrorWithStack
+		_ = json.Unmarshal(p.Output, &output)
+		return p.Status == JobRunStatusFAILURE && strings.HasPrefix(output.Message, "Run execution failed after 3 attempts")
+	})).Return(run, nil)
+	expectRunFailed(queueSvc, run)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:        pgUUIDToString(run.ID),
+		Reason:       workerqueue.ExecutionReasonExecuteJob,
+		Attempt:      3,
+		FinalAttempt: true,
+	})
+
+	require.Error(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_PerformRunExecution_RetryKeepsRunExecuting(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusEXECUTING)
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(run, nil)
+	expectExecutionRecorded(repo, run, ExecutionStatusFailed)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{}, nil)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:   pgUUIDToString(run.ID),
+		Reason:  workerqueue.ExecutionReasonExecuteJob,
+		Attempt: 1,
+	})
+
+	require.Error(t, err)
+	repo.AssertNotCalled(t, "CompleteJobRun", mock.Anything, mock.Anything)
+	queueSvc.AssertNotCalled(t, "EnqueueNotifyRunFailedTx", mock.Anything, mock.Anything)
+}
+
+func TestService_PerformRunExecution_InterruptedByShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	// 端点执行期间 worker 关闭，任务上下文被取消
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		cancel(workerqueue.ErrWorkerDraining)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusEXECUTING)
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(run, nil)
+	expectExecutionRecorded(repo, run, ExecutionStatusInterrupted)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{}, nil)
+
+	err := svc.PerformRunExecution(ctx, &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonExecuteJob,
+	})
+
+	require.Error(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CompleteJobRun", mock.Anything, mock.Anything)
+	queueSvc.AssertNotCalled(t, "EnqueueNotifyRunFailedTx", mock.Anything, mock.Anything)
+}
+
+func TestService_PerformRunExecution_JobTimeoutIsNotInterruption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 作业超时取消上下文，但 worker 并未关闭
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusEXECUTING)
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(run, nil)
+	expectExecutionRecorded(repo, run, ExecutionStatusFailed)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{}, nil)
+
+	err := svc.PerformRunExecution(ctx, &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonExecuteJob,
+	})
+
+	require.Error(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CompleteJobRun", mock.Anything, mock.Anything)
+}
+
+func TestService_PerformRunExecution_ResumeWithDelayedTask(t *testing.T) {
+	delayUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	server := newExecuteServer(t, `{
+		"status": "RESUME_WITH_TASK",
+		"task": {"id": "t1", "idempotencyKey": "wait-1h", "name": "wait", "status": "WAITING", "noop": true,
+			"delayUntil": "`+delayUntil.Format(time.RFC3339)+`"}
+	}`, nil)
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusEXECUTING)
+	run.ExecutionCount = 1
+	task := Tasks{ID: newPgUUID(), RunID: run.ID, IdempotencyKey: "wait-1h", Status: TaskStatusWAITING}
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(run, nil)
+	expectExecutionRecorded(repo, run, endpointapi.RunJobStatusResumeWithTask)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{}, nil)
+	repo.On("UpsertTask", mock.Anything, mock.MatchedBy(func(p UpsertTaskParams) bool {
+		return p.IdempotencyKey == "wait-1h" && p.Status == TaskStatusWAITING && p.Noop &&
+			p.DelayUntil.Time.Equal(delayUntil) && !p.CompletedAt.Valid
+	})).Return(task, nil)
+	repo.On("MarkJobRunWaiting", mock.Anything, run.ID).Return(run, nil)
+	queueSvc.On("EnqueuePerformRunExecutionTx", mock.Anything, mock.MatchedBy(func(req *queue.EnqueuePerformRunExecutionRequest) bool {
+		return req.ResumeTaskID == pgUUIDToString(task.ID) && req.ExecutionCount == 1 &&
+			req.ScheduledFor != nil && req.ScheduledFor.Equal(delayUntil)
+	})).Return(nil)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonExecuteJob,
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_PerformRunExecution_YieldReenqueues(t *testing.T) {
+	server := newExecuteServer(t, `{
+		"status": "YIELD_EXECUTION",
+		"tasks": [{"id": "t1", "idempotencyKey": "step-1", "name": "Step 1", "status": "COMPLETED", "output": 1}]
+	}`, nil)
+	defer server.Close()
+
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusEXECUTING)
+	run.ExecutionCount = 3
+
+	repo.On("GetJobRunExecutionContext", mock.Anything, run.ID).Return(createTestExecutionContext(run, server.URL), nil)
+	repo.On("MarkJobRunExecuting", mock.Anything, run.ID).Return(run, nil)
+	expectExecutionRecorded(repo, run, endpointapi.RunJobStatusYieldExecution)
+	repo.On("ListCompletedTasksByRun", mock.Anything, run.ID).Return([]Tasks{}, nil)
+	repo.On("UpsertTask", mock.Anything, mock.MatchedBy(func(p UpsertTaskParams) bool {
+		return p.IdempotencyKey == "step-1" && p.Status == TaskStatusCOMPLETED && string(p.Output) == "1"
+	})).Return(Tasks{ID: newPgUUID()}, nil)
+	queueSvc.On("EnqueuePerformRunExecutionTx", mock.Anything, mock.MatchedBy(func(req *queue.EnqueuePerformRunExecutionRequest) bool {
+		return req.ExecutionCount == 3 && req.ResumeTaskID == "" && req.ScheduledFor == nil
+	})).Return(nil)
+
+	err := svc.PerformRunExecution(context.Background(), &workerqueue.RunExecutionRequest{
+		RunID:  pgUUIDToString(run.ID),
+		Reason: workerqueue.ExecutionReasonExecuteJob,
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_CompleteTask_ResumesWaitingRun(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusWAITING)
+	run.ExecutionCount = 2
+	task := Tasks{ID: newPgUUID(), RunID: run.ID, IdempotencyKey: "approval", Status: TaskStatusCOMPLETED}
+
+	repo.On("CompleteTask", mock.Anything, mock.MatchedBy(func(p CompleteTaskParams) bool {
+		return p.ID == task.ID && p.RunID == run.ID && string(p.Output) == `{"approved":true}`
+	})).Return(task, nil)
+	repo.On("GetJobRunByID", mock.Anything, run.ID).Return(run, nil)
+	queueSvc.On("EnqueuePerformRunExecutionTx", mock.Anything, mock.MatchedBy(func(req *queue.EnqueuePerformRunExecutionRequest) bool {
+		return req.RunID == pgUUIDToString(run.ID) && req.ExecutionCount == 2
+	})).Return(nil)
+
+	result, err := svc.CompleteTask(context.Background(), &CompleteTaskRequest{
+		RunID:  pgUUIDToString(run.ID),
+		TaskID: pgUUIDToString(task.ID),
+		Output: map[string]interface{}{"approved": true},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, TaskStatusCOMPLETED, result.Status)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_CompleteTask_NotWaiting(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	repo.On("CompleteTask", mock.Anything, mock.Anything).Return(Tasks{}, pgx.ErrNoRows)
+
+	_, err := svc.CompleteTask(context.Background(), &CompleteTaskRequest{
+		RunID:  uuid.NewString(),
+		TaskID: uuid.NewString(),
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not waiting")
+	queueSvc.AssertNotCalled(t, "EnqueuePerformRunExecutionTx", mock.Anything, mock.Anything)
+}
+
+func TestService_StartRun_ParksWhenQueueFull(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusQUEUED)
+	run.Preprocess = true
+
+	repo.On("MarkJobRunQueued", mock.Anything, run.ID).Return(run, nil)
+	repo.On("GetJobStatus", mock.Anything, run.JobID).Return(JobStatusENABLED, nil)
+	expectQueueSlot(repo, run, false)
+
+	err := svc.StartRun(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkJobRunHoldsQueueSlot", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "MarkJobRunPreprocessing", mock.Anything, mock.Anything)
+	queueSvc.AssertNotCalled(t, "EnqueuePerformRunExecutionTx", mock.Anything, mock.Anything)
+}
+
+func TestService_StartRun_HoldsWhenJobPaused(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusQUEUED)
+
+	repo.On("MarkJobRunQueued", mock.Anything, run.ID).Return(run, nil)
+	repo.On("GetJobStatus", mock.Anything, run.JobID).Return(JobStatusPAUSED, nil)
+
+	err := svc.StartRun(context.Background(), pgUUIDToString(run.ID))
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "IncrementJobCount", mock.Anything, mock.Anything)
+	queueSvc.AssertNotCalled(t, "EnqueuePerformRunExecutionTx", mock.Anything, mock.Anything)
+}
+
+func TestService_StartJobRuns_StartsWaitingRuns(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	run := createTestRun(JobRunStatusQUEUED)
+
+	repo.On("ListWaitingQueuesByJob", mock.Anything, run.JobID).Return([]pgtype.UUID{run.QueueID}, nil)
+	repo.On("GetNextQueuedJobRun", mock.Anything, run.QueueID).Return(run, nil).Once()
+	expectQueueSlot(repo, run, true)
+	queueSvc.On("EnqueuePerformRunExecutionTx", mock.Anything, mock.MatchedBy(func(req *queue.EnqueuePerformRunExecutionRequest) bool {
+		return req.RunID == pgUUIDToString(run.ID)
+	})).Return(nil).Once()
+	repo.On("GetNextQueuedJobRun", mock.Anything, run.QueueID).Return(JobRuns{}, pgx.ErrNoRows).Once()
+
+	err := svc.StartJobRuns(context.Background(), pgUUIDToString(run.JobID))
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_StartQueuedRuns_ReleasesSlotAndStartsNext(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	finished := createTestRun(JobRunStatusSUCCESS)
+	next := createTestRun(JobRunStatusQUEUED)
+	next.QueueID = finished.QueueID
+
+	repo.On("ReleaseJobRunQueueSlot", mock.Anything, finished.ID).Return(finished.QueueID, nil)
+	repo.On("DecrementJobCount", mock.Anything, finished.QueueID).Return(DecrementJobCountRow{ID: finished.QueueID}, nil)
+	repo.On("GetNextQueuedJobRun", mock.Anything, finished.QueueID).Return(next, nil).Once()
+	expectQueueSlot(repo, next, true)
+	queueSvc.On("EnqueuePerformRunExecutionTx", mock.Anything, mock.MatchedBy(func(req *queue.EnqueuePerformRunExecutionRequest) bool {
+		return req.RunID == pgUUIDToString(next.ID) && req.Reason == string(workerqueue.ExecutionReasonExecuteJob)
+	})).Return(nil).Once()
+	repo.On("GetNextQueuedJobRun", mock.Anything, finished.QueueID).Return(JobRuns{}, pgx.ErrNoRows).Once()
+
+	err := svc.StartQueuedRuns(context.Background(), &workerqueue.StartQueuedRunsRequest{
+		QueueID:       pgUUIDToString(finished.QueueID),
+		FinishedRunID: pgUUIDToString(finished.ID),
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_StartQueuedRuns_StopsWhenQueueFull(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	finished := createTestRun(JobRunStatusFAILURE)
+	next := createTestRun(JobRunStatusQUEUED)
+	next.QueueID = finished.QueueID
+
+	// 重复释放：运行已不再占用槽位，job_count 不应再次递减
+	repo.On("ReleaseJobRunQueueSlot", mock.Anything, finished.ID).Return(pgtype.UUID{}, pgx.ErrNoRows)
+	repo.On("GetNextQueuedJobRun", mock.Anything, finished.QueueID).Return(next, nil).Once()
+	expectQueueSlot(repo, next, false)
+
+	err := svc.StartQueuedRuns(context.Background(), &workerqueue.StartQueuedRunsRequest{
+		QueueID:       pgUUIDToString(finished.QueueID),
+		FinishedRunID: pgUUIDToString(finished.ID),
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "DecrementJobCount", mock.Anything, mock.Anything)
+	queueSvc.AssertNotCalled(t, "EnqueuePerformRunExecutionTx", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,308 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: tasks.sql
+
+package runs
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelPendingTasks = `-- name: CancelPendingTasks :exec
+UPDATE tasks
+SET status = 'CANCELED', completed_at = NOW(), updated_at = NOW()
+WHERE run_id = $1 AND status IN ('PENDING', 'WAITING', 'RUNNING')
+`
+
+// 运行取消时取消未完成的任务
+func (q *Queries) CancelPendingTasks(ctx context.Context, runID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, cancelPendingTasks, runID)
+	return err
+}
+
+const completeTask = `-- name: CompleteTask :one
+UPDATE tasks
+SET status = 'COMPLETED', output = $3, completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND run_id = $2 AND status IN ('PENDING', 'WAITING', 'RUNNING')
+RETURNING id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+`
+
+type CompleteTaskParams struct {
+	ID     pgtype.UUID `json:"id"`
+	RunID  pgtype.UUID `json:"run_id"`
+	Output []byte      `json:"output"`
+}
+
+// 完成等待中的任务，对齐 trigger.dev CompleteRunTaskService / ResumeTaskService
+func (q *Queries) CompleteTask(ctx context.Context, arg CompleteTaskParams) (Tasks, error) {
+	row := q.db.QueryRow(ctx, completeTask, arg.ID, arg.RunID, arg.Output)
+	var i Tasks
+	err := row.Scan(
+		&i.ID,
+		&i.RunID,
+		&i.ParentID,
+		&i.IdempotencyKey,
+		&i.DisplayKey,
+		&i.Name,
+		&i.Icon,
+		&i.Status,
+		&i.Noop,
+		&i.Params,
+		&i.Output,
+		&i.Error,
+		&i.DelayUntil,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTaskByID = `-- name: GetTaskByID :one
+SELECT id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+FROM tasks
+WHERE id = $1
+`
+
+func (q *Queries) GetTaskByID(ctx context.Context, id pgtype.UUID) (Tasks, error) {
+	row := q.db.QueryRow(ctx, getTaskByID, id)
+	var i Tasks
+	err := row.Scan(
+		&i.ID,
+		&i.RunID,
+		&i.ParentID,
+		&i.IdempotencyKey,
+		&i.DisplayKey,
+		&i.Name,
+		&i.Icon,
+		&i.Status,
+		&i.Noop,
+		&i.Params,
+		&i.Output,
+		&i.Error,
+		&i.DelayUntil,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTaskByIdempotencyKey = `-- name: GetTaskByIdempotencyKey :one
+SELECT id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+FROM tasks
+WHERE run_id = $1 AND idempotency_key = $2
+`
+
+type GetTaskByIdempotencyKeyParams struct {
+	RunID          pgtype.UUID `json:"run_id"`
+	IdempotencyKey string      `json:"idempotency_key"`
+}
+
+func (q *Queries) GetTaskByIdempotencyKey(ctx context.Context, arg GetTaskByIdempotencyKeyParams) (Tasks, error) {
+	row := q.db.QueryRow(ctx, getTaskByIdempotencyKey, arg.RunID, arg.IdempotencyKey)
+	var i Tasks
+	err := row.Scan(
+		&i.ID,
+		&i.RunID,
+		&i.ParentID,
+		&i.IdempotencyKey,
+		&i.DisplayKey,
+		&i.Name,
+		&i.Icon,
+		&i.Status,
+		&i.Noop,
+		&i.Params,
+		&i.Output,
+		&i.Error,
+		&i.DelayUntil,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCompletedTasksByRun = `-- name: ListCompletedTasksByRun :many
+SELECT id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+FROM tasks
+WHERE run_id = $1 AND status = 'COMPLETED'
+ORDER BY created_at ASC
+`
+
+// 已完成任务在下次执行时作为缓存回传给端点
+func (q *Queries) ListCompletedTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error) {
+	rows, err := q.db.Query(ctx, listCompletedTasksByRun, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tasks
+	for rows.Next() {
+		var i Tasks
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.ParentID,
+			&i.IdempotencyKey,
+			&i.DisplayKey,
+			&i.Name,
+			&i.Icon,
+			&i.Status,
+			&i.Noop,
+			&i.Params,
+			&i.Output,
+			&i.Error,
+			&i.DelayUntil,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasksByRun = `-- name: ListTasksByRun :many
+SELECT id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+FROM tasks
+WHERE run_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListTasksByRun(ctx context.Context, runID pgtype.UUID) ([]Tasks, error) {
+	rows, err := q.db.Query(ctx, listTasksByRun, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tasks
+	for rows.Next() {
+		var i Tasks
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.ParentID,
+			&i.IdempotencyKey,
+			&i.DisplayKey,
+			&i.Name,
+			&i.Icon,
+			&i.Status,
+			&i.Noop,
+			&i.Params,
+			&i.Output,
+			&i.Error,
+			&i.DelayUntil,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTask = `-- name: UpsertTask :one
+
+INSERT INTO tasks (
+    run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, completed_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (run_id, idempotency_key)
+DO UPDATE SET
+    status = EXCLUDED.status,
+    output = EXCLUDED.output,
+    error = EXCLUDED.error,
+    delay_until = EXCLUDED.delay_until,
+    completed_at = EXCLUDED.completed_at,
+    updated_at = NOW()
+RETURNING id, run_id, parent_id, idempotency_key, display_key, name, icon, status,
+    noop, params, output, error, delay_until, started_at, completed_at,
+    created_at, updated_at
+`
+
+type UpsertTaskParams struct {
+	RunID          pgtype.UUID        `json:"run_id"`
+	ParentID       pgtype.UUID        `json:"parent_id"`
+	IdempotencyKey string             `json:"idempotency_key"`
+	DisplayKey     pgtype.Text        `json:"display_key"`
+	Name           string             `json:"name"`
+	Icon           pgtype.Text        `json:"icon"`
+	Status         TaskStatus         `json:"status"`
+	Noop           bool               `json:"noop"`
+	Params         []byte             `json:"params"`
+	Output         []byte             `json:"output"`
+	Error          []byte             `json:"error"`
+	DelayUntil     pgtype.Timestamptz `json:"delay_until"`
+	CompletedAt    pgtype.Timestamptz `json:"completed_at"`
+}
+
+// tasks.sql
+// Task 运行任务相关查询
+// 按 (run_id, idempotency_key) 幂等写入任务，对齐 trigger.dev RunTaskService
+func (q *Queries) UpsertTask(ctx context.Context, arg UpsertTaskParams) (Tasks, error) {
+	row := q.db.QueryRow(ctx, upsertTask,
+		arg.RunID,
+		arg.ParentID,
+		arg.IdempotencyKey,
+		arg.DisplayKey,
+		arg.Name,
+		arg.Icon,
+		arg.Status,
+		arg.Noop,
+		arg.Params,
+		arg.Output,
+		arg.Error,
+		arg.DelayUntil,
+		arg.CompletedAt,
+	)
+	var i Tasks
+	err := row.Scan(
+		&i.ID,
+		&i.RunID,
+		&i.ParentID,
+		&i.IdempotencyKey,
+		&i.DisplayKey,
+		&i.Name,
+		&i.Icon,
+		&i.Status,
+		&i.Noop,
+		&i.Params,
+		&i.Output,
+		&i.Error,
+		&i.DelayUntil,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,93 @@
+package runs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"kongflow/backend/internal/services/endpointapi"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// timeoutBatchSize 每次巡检最多处理的超时运行数量，其余运行留给下一次巡检
+const timeoutBatchSize = 100
+
+// TimeoutRuns 将执行中或等待中且超过最长持续时间的运行标记为失败
+// 最长持续时间取作业版本的 max_run_duration_seconds，未声明时使用环境默认值，两者都为空时不限制
+func (s *service) TimeoutRuns(ctx context.Context) (int, error) {
+	logger := s.logger.With("operation", "timeout_runs")
+
+	expired, err := s.repo.ListTimedOutJobRuns(ctx, timeoutBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list timed out runs: %w", err)
+	}
+
+	timedOut := 0
+	for _, row := range expired {
+		maxDuration := time.Duration(row.MaxRunDurationSeconds) * time.Second
+		ok, err := s.timeoutRun(ctx, row.ID, maxDuration)
+		if err != nil {
+			// 单个运行失败不影响其余运行，留给下一次巡检重试
+			logger.Error("Failed to time out run", "run_id", pgUUIDToString(row.ID), "error", err)
+			continue
+		}
+		if ok {
+			timedOut++
+		}
+	}
+
+	if timedOut > 0 {
+		logger.Info("Runs timed out", "count", timedOut)
+	}
+	return timedOut, nil
+}
+
+// timeoutRun 在同一事务中记录超时错误、结束进行中的执行与任务，并走运行失败的收尾流程
+// 运行在巡检期间已结束时返回 false
+func (s *service) timeoutRun(ctx context.Context, id pgtype.UUID, maxDuration time.Duration) (bool, error) {
+	runError := &endpointapi.ErrorWithStack{
+		Message: fmt.Sprintf("Run exceeded maximum duration of %s", maxDuration),
+	}
+	output, err := marshalJSONB(runError)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal run error: %w", err)
+	}
+
+	timedOut := false
+	err = s.repo.WithTxAndReturn(ctx, func(txRepo Repository, tx pgx.Tx) error {
+		run, err := txRepo.TimeoutJobRun(ctx, TimeoutJobRunParams{ID: id, Output: output})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("failed to mark run timed out: %w", err)
+		}
+
+		if err := txRepo.TimeoutOpenJobRunExecutions(ctx, TimeoutOpenJobRunExecutionsParams{
+			RunID: id,
+			Error: output,
+		}); err != nil {
+			return fmt.Errorf("failed to time out run executions: %w", err)
+		}
+		if err := txRepo.CancelPendingTasks(ctx, id); err != nil {
+			return fmt.Errorf("failed to cancel tasks: %w", err)
+		}
+		if _, err := s.queueSvc.CancelRunExecutionsTx(ctx, tx, pgUUIDToString(id)); err != nil {
+			return fmt.Errorf("failed to cancel queued executions: %w", err)
+		}
+
+		timedOut = true
+		return s.runFailed(ctx, tx, run)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if timedOut {
+		s.logger.Warn("Run timed out", "run_id", pgUUIDToString(id), "max_duration", maxDuration)
+	}
+	return timedOut, nil
+}
@@ -0,0 +1,72 @@
+package runs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_TimeoutRuns_FailsExpiredRuns(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	metrics := &recordingMetrics{}
+	svc := NewServiceWithMetrics(repo, queueSvc, nil, nil, nil, metrics, slog.Default())
+
+	run := createTestRun(JobRunStatusWAITING)
+	failed := run
+	failed.Status = JobRunStatusFAILURE
+
+	repo.On("ListTimedOutJobRuns", mock.Anything, int32(timeoutBatchSize)).Return([]ListTimedOutJobRunsRow{
+		{ID: run.ID, MaxRunDurationSeconds: 3600},
+	}, nil)
+	repo.On("TimeoutJobRun", mock.Anything, mock.MatchedBy(func(p TimeoutJobRunParams) bool {
+		return p.ID == run.ID && string(p.Output) == `{"message":"Run exceeded maximum duration of 1h0m0s"}`
+	})).Return(failed, nil)
+	repo.On("TimeoutOpenJobRunExecutions", mock.Anything, mock.MatchedBy(func(p TimeoutOpenJobRunExecutionsParams) bool {
+		return p.RunID == run.ID && len(p.Error) > 0
+	})).Return(nil)
+	repo.On("CancelPendingTasks", mock.Anything, run.ID).Return(nil)
+	queueSvc.On("CancelRunExecutionsTx", mock.Anything, pgUUIDToString(run.ID)).Return(0, nil)
+	expectRunFailed(queueSvc, failed)
+
+	count, err := svc.TimeoutRuns(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"FAILURE"}, metrics.finished)
+	repo.AssertExpectations(t)
+	queueSvc.AssertExpectations(t)
+}
+
+func TestService_TimeoutRuns_SkipsRunsFinishedMeanwhile(t *testing.T) {
+	repo := &MockRepository{}
+	queueSvc := &MockQueueService{}
+	svc := newTestService(repo, queueSvc)
+
+	finished := createTestRun(JobRunStatusEXECUTING)
+	broken := createTestRun(JobRunStatusEXECUTING)
+
+	repo.On("ListTimedOutJobRuns", mock.Anything, int32(timeoutBatchSize)).Return([]ListTimedOutJobRunsRow{
+		{ID: finished.ID, MaxRunDurationSeconds: 60},
+		{ID: broken.ID, MaxRunDurationSeconds: 60},
+	}, nil)
+	// 第一个运行在巡检期间已完成，第二个运行更新失败，均不计入超时数量
+	repo.On("TimeoutJobRun", mock.Anything, mock.MatchedBy(func(p TimeoutJobRunParams) bool {
+		return p.ID == finished.ID
+	})).Return(JobRuns{}, pgx.ErrNoRows)
+	repo.On("TimeoutJobRun", mock.Anything, mock.MatchedBy(func(p TimeoutJobRunParams) bool {
+		return p.ID == broken.ID
+	})).Return(JobRuns{}, errors.New("connection reset"))
+
+	count, err := svc.TimeoutRuns(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	queueSvc.AssertNotCalled(t, "EnqueueStartQueuedRunsTx", mock.Anything, mock.Anything)
+}
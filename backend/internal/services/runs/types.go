@@ -0,0 +1,240 @@
+package runs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kongflow/backend/internal/services/endpointapi"
+)
+
+// EndpointClient 运行执行所需的端点 API 子集，便于单元测试和依赖注入
+type EndpointClient interface {
+	// PreprocessRunRequest 调用端点预处理动作 (PREPROCESS_RUN)
+	PreprocessRunRequest(ctx context.Context, options *endpointapi.PreprocessRunBody) (*endpointapi.PreprocessRunResult, error)
+
+	// ExecuteJobRequest 调用端点执行动作 (EXECUTE_JOB)
+	ExecuteJobRequest(ctx context.Context, options *endpointapi.RunJobBody) (*endpointapi.JobExecutionResult, error)
+}
+
+// 确保 endpointapi.Client 实现了 EndpointClient 接口
+var _ EndpointClient = (*endpointapi.Client)(nil)
+
+// EndpointClientFactory 根据端点信息创建客户端，对齐 trigger.dev new EndpointApi(apiKey, url)
+type EndpointClientFactory func(apiKey, url, endpointID string) EndpointClient
+
+// NewEndpointClientFactory 创建默认的端点客户端工厂
+func NewEndpointClientFactory(logger *slog.Logger) EndpointClientFactory {
+	return NewEndpointClientFactoryWithMetrics(logger, nil)
+}
+
+// NewEndpointClientFactoryWithMetrics 创建记录调用指标的端点客户端工厂
+func NewEndpointClientFactoryWithMetrics(logger *slog.Logger, metrics endpointapi.Metrics) EndpointClientFactory {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(apiKey, url, endpointID string) EndpointClient {
+		client := endpointapi.NewClient(apiKey, url, endpointID, &endpointLogger{logger: logger})
+		if metrics != nil {
+			client.SetMetrics(metrics)
+		}
+		return client
+	}
+}
+
+// endpointLogger 将 slog.Logger 适配为 endpointapi.Logger
+type endpointLogger struct {
+	logger *slog.Logger
+}
+
+func (l *endpointLogger) Debug(msg string, fields map[string]interface{}) {
+	l.logger.Debug(msg, mapToAttrs(fields)...)
+}
+
+func (l *endpointLogger) Error(msg string, fields map[string]interface{}) {
+	l.logger.Error(msg, mapToAttrs(fields)...)
+}
+
+func mapToAttrs(fields map[string]interface{}) []any {
+	attrs := make([]any, 0, len(fields)*2)
+	for k, v := range fields {
+		attrs = append(attrs, k, v)
+	}
+	return attrs
+}
+
+// MissingConnection 运行所需但尚未连接的集成
+type MissingConnection struct {
+	IntegrationID  string
+	OrganizationID string
+	Slug           string
+	Title          string
+}
+
+// ConnectionNotifier 运行因缺少集成连接而挂起时发送连接提醒，对齐 trigger.dev connect_integration 邮件
+type ConnectionNotifier interface {
+	NotifyMissingConnection(ctx context.Context, connection MissingConnection) error
+}
+
+// Metrics 运行指标，nil 时不记录
+type Metrics interface {
+	// RunFinished 记录一次运行结束及其最终状态，例如 "SUCCESS"、"FAILURE" 或 "CANCELED"
+	RunFinished(status string)
+}
+
+// ConnectionAuthProvider 提供集成连接的认证信息，执行时作为 RunJobBody.Context.connections 发送给端点
+// 对齐 trigger.dev ConnectionAuth {type, accessToken, scopes}
+type ConnectionAuthProvider interface {
+	ConnectionAuth(ctx context.Context, integrationID string) (map[string]interface{}, error)
+}
+
+// 执行记录状态，端点返回时沿用 RunJobResponse 的状态值
+const (
+	ExecutionStatusExecuting = "EXECUTING"
+	// ExecutionStatusFailed 端点不可达，执行将由队列重试
+	ExecutionStatusFailed = "FAILED"
+	// ExecutionStatusTimedOut 运行超过最长持续时间时仍在进行的执行
+	ExecutionStatusTimedOut = "TIMED_OUT"
+	// ExecutionStatusInterrupted worker 关闭时仍在进行的执行，运行将由队列重新投递后继续
+	ExecutionStatusInterrupted = "INTERRUPTED"
+)
+
+// API 请求和响应类型定义，对齐 trigger.dev
+
+// CreateRunRequest 创建运行请求，对齐 trigger.dev CreateRunService.call 参数
+type CreateRunRequest struct {
+	EventRecordID     string  `json:"eventRecordId" validate:"required"`
+	VersionID         string  `json:"versionId" validate:"required"`
+	IsTest            bool    `json:"isTest"`
+	ExternalAccountID *string `json:"externalAccountId,omitempty"`
+	// RerunOfID 重跑时指向原运行
+	RerunOfID string `json:"rerunOfId,omitempty"`
+}
+
+// RerunRunRequest 重跑运行请求
+type RerunRunRequest struct {
+	RunID string `json:"runId" validate:"required"`
+	// UseLatestVersion 为 true 时使用作业在该环境 latest 别名指向的版本，否则沿用原运行的版本
+	UseLatestVersion bool `json:"useLatestVersion"`
+}
+
+// RunResponse 运行响应
+type RunResponse struct {
+	ID            string                        `json:"id"`
+	JobID         string                        `json:"jobId"`
+	VersionID     string                        `json:"versionId"`
+	EventID       string                        `json:"eventId"`
+	EnvironmentID string                        `json:"environmentId"`
+	Status        JobRunStatus                  `json:"status"`
+	Properties    []endpointapi.DisplayProperty `json:"properties,omitempty"`
+	Output        interface{}                   `json:"output,omitempty"`
+	IsTest        bool                          `json:"isTest"`
+	Executions    int32                         `json:"executions"`
+	QueuedAt      *time.Time                    `json:"queuedAt,omitempty"`
+	StartedAt     *time.Time                    `json:"startedAt,omitempty"`
+	CompletedAt   *time.Time                    `json:"completedAt,omitempty"`
+	CreatedAt     time.Time                     `json:"createdAt"`
+	RerunOfID     string                        `json:"rerunOfId,omitempty"`
+}
+
+// CompleteTaskRequest 完成任务请求，对齐 trigger.dev CompleteRunTaskService.call 参数
+type CompleteTaskRequest struct {
+	RunID  string      `json:"runId" validate:"required"`
+	TaskID string      `json:"taskId" validate:"required"`
+	Output interface{} `json:"output,omitempty"`
+}
+
+// TaskResponse 任务响应
+type TaskResponse struct {
+	ID             string                      `json:"id"`
+	RunID          string                      `json:"runId"`
+	ParentID       string                      `json:"parentId,omitempty"`
+	IdempotencyKey string                      `json:"idempotencyKey"`
+	DisplayKey     string                      `json:"displayKey,omitempty"`
+	Name           string                      `json:"name"`
+	Icon           string                      `json:"icon,omitempty"`
+	Status         TaskStatus                  `json:"status"`
+	Noop           bool                        `json:"noop"`
+	Params         interface{}                 `json:"params,omitempty"`
+	Output         interface{}                 `json:"output,omitempty"`
+	Error          *endpointapi.ErrorWithStack `json:"error,omitempty"`
+	DelayUntil     *time.Time                  `json:"delayUntil,omitempty"`
+	StartedAt      *time.Time                  `json:"startedAt,omitempty"`
+	CompletedAt    *time.Time                  `json:"completedAt,omitempty"`
+}
+
+// ListRunsRequest 运行列表请求，除 ProjectID 外的过滤条件均可选
+type ListRunsRequest struct {
+	ProjectID     string       `json:"projectId" validate:"required"`
+	JobID         string       `json:"jobId,omitempty"`
+	EnvironmentID string       `json:"environmentId,omitempty"`
+	Status        JobRunStatus `json:"status,omitempty"`
+	CreatedAfter  *time.Time   `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time   `json:"createdBefore,omitempty"`
+	Cursor        string       `json:"cursor,omitempty"`
+	Limit         int32        `json:"limit,omitempty"`
+}
+
+// ListRunsResponse 运行列表响应
+type ListRunsResponse struct {
+	Runs       []RunResponse `json:"runs"`
+	Limit      int32         `json:"limit"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+	PrevCursor string        `json:"prevCursor,omitempty"`
+}
+
+// RunEventResponse 触发运行的事件
+type RunEventResponse struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Source      string                 `json:"source"`
+	Payload     map[string]interface{} `json:"payload,omitempty"`
+	Context     map[string]interface{} `json:"context,omitempty"`
+	Timestamp   time.Time              `json:"timestamp"`
+	IsTest      bool                   `json:"isTest"`
+	ReceivedAt  time.Time              `json:"receivedAt"`
+	DeliveredAt *time.Time             `json:"deliveredAt,omitempty"`
+}
+
+// RunExecutionResponse 运行的一次执行
+type RunExecutionResponse struct {
+	Number      int32                       `json:"number"`
+	Reason      string                      `json:"reason"`
+	Status      string                      `json:"status"`
+	Error       *endpointapi.ErrorWithStack `json:"error,omitempty"`
+	StartedAt   time.Time                   `json:"startedAt"`
+	CompletedAt *time.Time                  `json:"completedAt,omitempty"`
+}
+
+// RunDetailResponse 运行详情，包含事件、任务、执行记录和错误
+type RunDetailResponse struct {
+	Run        RunResponse                 `json:"run"`
+	Event      *RunEventResponse           `json:"event,omitempty"`
+	Tasks      []TaskResponse              `json:"tasks"`
+	Executions []RunExecutionResponse      `json:"executions"`
+	Error      *endpointapi.ErrorWithStack `json:"error,omitempty"`
+}
+
+// TimelineEntryType 时间线条目类型
+type TimelineEntryType string
+
+const (
+	TimelineEventReceived      TimelineEntryType = "EVENT_RECEIVED"
+	TimelineDispatcherMatched  TimelineEntryType = "DISPATCHER_MATCHED"
+	TimelineRunQueued          TimelineEntryType = "RUN_QUEUED"
+	TimelineRunStarted         TimelineEntryType = "RUN_STARTED"
+	TimelineExecutionStarted   TimelineEntryType = "EXECUTION_STARTED"
+	TimelineExecutionCompleted TimelineEntryType = "EXECUTION_COMPLETED"
+	TimelineTaskFailed         TimelineEntryType = "TASK_FAILED"
+	TimelineRunCompleted       TimelineEntryType = "RUN_COMPLETED"
+)
+
+// TimelineEntry 运行时间线中的一个条目
+type TimelineEntry struct {
+	Type      TimelineEntryType           `json:"type"`
+	Timestamp time.Time                   `json:"timestamp"`
+	Status    string                      `json:"status,omitempty"`
+	Execution int32                       `json:"execution,omitempty"`
+	TaskID    string                      `json:"taskId,omitempty"`
+	Error     *endpointapi.ErrorWithStack `json:"error,omitempty"`
+}
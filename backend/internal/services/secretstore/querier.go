@@ -9,6 +9,7 @@ import (
 )
 
 type Querier interface {
+	DeleteSecretStore(ctx context.Context, key string) error
 	GetSecretStore(ctx context.Context, key string) (SecretStore, error)
 	UpsertSecretStore(ctx context.Context, arg UpsertSecretStoreParams) error
 }
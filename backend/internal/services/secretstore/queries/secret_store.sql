@@ -6,4 +6,6 @@ INSERT INTO "SecretStore" ("key", "value", "createdAt", "updatedAt")
 VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 ON CONFLICT ("key") DO UPDATE SET
     "value" = EXCLUDED."value",
-    "updatedAt" = CURRENT_TIMESTAMP;
\ No newline at end of file
+    "updatedAt" = CURRENT_TIMESTAMP;
+-- name: DeleteSecretStore :exec
+DELETE FROM "SecretStore" WHERE "key" = $1;
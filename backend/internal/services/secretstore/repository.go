@@ -13,6 +13,7 @@ var ErrSecretNotFound = errors.New("secret not found")
 type Repository interface {
 	GetSecret(ctx context.Context, key string) (*SecretStore, error)
 	UpsertSecret(ctx context.Context, key string, value []byte) error
+	DeleteSecret(ctx context.Context, key string) error
 }
 
 type repository struct {
@@ -42,3 +43,7 @@ func (r *repository) UpsertSecret(ctx context.Context, key string, value []byte)
 		Value: value,
 	})
 }
+
+func (r *repository) DeleteSecret(ctx context.Context, key string) error {
+	return r.queries.DeleteSecretStore(ctx, key)
+}
@@ -9,6 +9,15 @@ import (
 	"context"
 )
 
+const deleteSecretStore = `-- name: DeleteSecretStore :exec
+DELETE FROM "SecretStore" WHERE "key" = $1
+`
+
+func (q *Queries) DeleteSecretStore(ctx context.Context, key string) error {
+	_, err := q.db.Exec(ctx, deleteSecretStore, key)
+	return err
+}
+
 const getSecretStore = `-- name: GetSecretStore :one
 SELECT key, value, "createdAt", "updatedAt" FROM "SecretStore" WHERE "key" = $1
 `
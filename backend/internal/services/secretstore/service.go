@@ -2,19 +2,52 @@ package secretstore
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+)
+
+var (
+	// ErrInvalidEncryptionKey 加密密钥长度不是 16、24 或 32 字节
+	ErrInvalidEncryptionKey = errors.New("invalid encryption key: must be 16, 24, or 32 bytes")
+
+	// ErrDecryptionFailed 密文无法解密（密钥错误或数据被篡改）
+	ErrDecryptionFailed = errors.New("failed to decrypt secret")
 )
 
 type Service struct {
 	repo Repository
+	aead cipher.AEAD
 }
 
 func NewService(repo Repository) *Service {
 	return &Service{repo: repo}
 }
 
+// NewEncryptedService 创建使用 AES-GCM 加密存储值的服务，key 为 16、24 或 32 字节
+func NewEncryptedService(repo Repository, key []byte) (*Service, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{repo: repo, aead: aead}, nil
+}
+
+// Encrypted 存储的值是否加密
+func (s *Service) Encrypted() bool {
+	return s.aead != nil
+}
+
 // GetSecret 获取密钥并反序列化到 target
 func (s *Service) GetSecret(ctx context.Context, key string, target interface{}) error {
 	secret, err := s.repo.GetSecret(ctx, key)
@@ -22,7 +55,12 @@ func (s *Service) GetSecret(ctx context.Context, key string, target interface{})
 		return fmt.Errorf("failed to get secret %s: %w", key, err)
 	}
 
-	if err := json.Unmarshal(secret.Value, target); err != nil {
+	data, err := s.open(secret.Value)
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s: %w", key, err)
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
 		return fmt.Errorf("failed to unmarshal secret %s: %w", key, err)
 	}
 
@@ -36,6 +74,11 @@ func (s *Service) SetSecret(ctx context.Context, key string, value interface{})
 		return fmt.Errorf("failed to marshal value for key %s: %w", key, err)
 	}
 
+	data, err = s.seal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secret %s: %w", key, err)
+	}
+
 	if err := s.repo.UpsertSecret(ctx, key, data); err != nil {
 		return fmt.Errorf("failed to set secret %s: %w", key, err)
 	}
@@ -43,6 +86,14 @@ func (s *Service) SetSecret(ctx context.Context, key string, value interface{})
 	return nil
 }
 
+// DeleteSecret 删除密钥，不存在时不报错
+func (s *Service) DeleteSecret(ctx context.Context, key string) error {
+	if err := s.repo.DeleteSecret(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete secret %s: %w", key, err)
+	}
+	return nil
+}
+
 // GetSecretOrThrow 如果不存在则返回错误 (兼容 trigger.dev 接口)
 func (s *Service) GetSecretOrThrow(ctx context.Context, key string, target interface{}) error {
 	if err := s.GetSecret(ctx, key, target); err != nil {
@@ -53,3 +104,34 @@ func (s *Service) GetSecretOrThrow(ctx context.Context, key string, target inter
 	}
 	return nil
 }
+
+// seal 加密明文，密文格式为 nonce || ciphertext
+func (s *Service) seal(plaintext []byte) ([]byte, error) {
+	if s.aead == nil {
+		return plaintext, nil
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open 解密 seal 生成的密文
+func (s *Service) open(data []byte) ([]byte, error) {
+	if s.aead == nil {
+		return data, nil
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, ErrDecryptionFailed
+	}
+
+	plaintext, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
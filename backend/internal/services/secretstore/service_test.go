@@ -26,6 +26,11 @@ func (m *MockRepository) UpsertSecret(ctx context.Context, key string, value []b
 	return args.Error(0)
 }
 
+func (m *MockRepository) DeleteSecret(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
 func TestService_SetAndGetSecret(t *testing.T) {
 	mockRepo := new(MockRepository)
 	service := NewService(mockRepo)
@@ -160,3 +165,40 @@ func TestService_GetSecretOrThrow(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestService_EncryptedRoundTrip(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service, err := NewEncryptedService(mockRepo, []byte("0123456789abcdef0123456789abcdef"))
+	assert.NoError(t, err)
+	assert.True(t, service.Encrypted())
+	ctx := context.Background()
+
+	// 捕获写入的密文，再由 GetSecret 读回
+	var stored []byte
+	mockRepo.On("UpsertSecret", ctx, "encrypted-key", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(2).([]byte)
+	}).Return(nil)
+
+	err = service.SetSecret(ctx, "encrypted-key", map[string]string{"token": "secret-123"})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(stored), "secret-123")
+
+	mockRepo.On("GetSecret", ctx, "encrypted-key").Return(&SecretStore{Key: "encrypted-key", Value: stored}, nil)
+
+	var result map[string]string
+	err = service.GetSecret(ctx, "encrypted-key", &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-123", result["token"])
+
+	// 篡改密文后解密失败
+	tampered := append([]byte{}, stored...)
+	tampered[len(tampered)-1] ^= 0xff
+	mockRepo.On("GetSecret", ctx, "tampered-key").Return(&SecretStore{Key: "tampered-key", Value: tampered}, nil)
+	err = service.GetSecret(ctx, "tampered-key", &result)
+	assert.ErrorIs(t, err, ErrDecryptionFailed)
+}
+
+func TestNewEncryptedService_InvalidKey(t *testing.T) {
+	_, err := NewEncryptedService(new(MockRepository), []byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidEncryptionKey)
+}
@@ -0,0 +1,80 @@
+// Package tracing configures OpenTelemetry tracing for KongFlow services
+// Services create spans through the global tracer provider and propagate W3C trace context,
+// so tracing stays a no-op until Setup is called
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// Config configures the tracer provider
+type Config struct {
+	// ServiceName is reported as the service.name resource attribute
+	ServiceName string
+
+	// OTLPEndpoint is the host:port of an OTLP/HTTP collector; spans are not exported when empty
+	OTLPEndpoint string
+
+	// OTLPInsecure sends spans over plain HTTP instead of HTTPS
+	OTLPInsecure bool
+
+	// SampleRatio is the fraction of new traces sampled; 0 samples every trace
+	// Spans continuing a remote trace follow the caller's sampling decision
+	SampleRatio float64
+
+	// SpanProcessors are added to the provider, e.g. a tracetest.SpanRecorder in tests
+	SpanProcessors []sdktrace.SpanProcessor
+}
+
+// Setup installs a tracer provider and the W3C trace context propagator as the otel globals
+// The returned provider must be shut down to flush spans before the process exits
+func Setup(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "kongflow"
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	}
+
+	if cfg.OTLPEndpoint != "" {
+		exporterOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	for _, processor := range cfg.SpanProcessors {
+		opts = append(opts, sdktrace.WithSpanProcessor(processor))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider, nil
+}
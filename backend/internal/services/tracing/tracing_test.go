@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider, err := Setup(ctx, Config{
+		ServiceName:    "kongflow-test",
+		SpanProcessors: []sdktrace.SpanProcessor{recorder},
+	})
+	require.NoError(t, err)
+
+	spanCtx, span := otel.Tracer("test").Start(ctx, "events.IngestSendEvent")
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(spanCtx, carrier)
+	span.End()
+
+	assert.Contains(t, carrier.Get("traceparent"), span.SpanContext().TraceID().String())
+	require.Len(t, recorder.Ended(), 1)
+	serviceName, ok := recorder.Ended()[0].Resource().Set().Value(semconv.ServiceNameKey)
+	assert.True(t, ok)
+	assert.Equal(t, "kongflow-test", serviceName.AsString())
+	require.NoError(t, provider.Shutdown(ctx))
+}
+
+func TestSetup_OTLPExporter(t *testing.T) {
+	ctx := context.Background()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	// The exporter connects lazily, so creating it does not require a collector
+	provider, err := Setup(ctx, Config{OTLPEndpoint: "localhost:4318", OTLPInsecure: true, SampleRatio: 0.5})
+	require.NoError(t, err)
+	assert.Same(t, provider, otel.GetTracerProvider())
+
+	shutdownCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_ = provider.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,250 @@
+// Package worker 组装 worker queue 管理器与业务服务，对齐 trigger.dev worker.server.ts
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"kongflow/backend/internal/services/email"
+	"kongflow/backend/internal/services/events"
+	eventsqueue "kongflow/backend/internal/services/events/queue"
+	"kongflow/backend/internal/services/integrations"
+	"kongflow/backend/internal/services/metrics"
+	"kongflow/backend/internal/services/runs"
+	runsqueue "kongflow/backend/internal/services/runs/queue"
+	"kongflow/backend/internal/services/secretstore"
+	"kongflow/backend/internal/services/workerqueue"
+	"kongflow/backend/internal/shared"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Options worker 启动所需的依赖
+type Options struct {
+	Config workerqueue.Config
+	Pool   *pgxpool.Pool
+	Logger *slog.Logger
+
+	// ManagerOptions 其余可选依赖（邮件、端点索引、连接刷新、失败通知等）
+	// 运行执行器、超时巡检器和事件处理器未设置时由启动流程注入 runs 与 events 服务
+	ManagerOptions workerqueue.ManagerOptions
+
+	// DeadLetters 为 true 时将耗尽重试次数的作业复制到死信表
+	DeadLetters bool
+
+	// Metrics 设置时记录作业结果、队列深度、事件摄取分发、运行结束和端点调用指标
+	Metrics *metrics.Metrics
+
+	// runs 服务依赖，均可为 nil；ConnectionAuth 未设置时使用启动流程创建的集成服务，
+	// ConnectionNotifier 未设置且配置了邮件服务时通过邮件提醒组织成员
+	ClientFactory      runs.EndpointClientFactory
+	ConnectionNotifier runs.ConnectionNotifier
+	ConnectionAuth     runs.ConnectionAuthProvider
+
+	// SecretKey 集成连接凭证的 SecretStore 加密密钥（16、24 或 32 字节）
+	// 未设置时集成服务无法读写连接凭证，需要连接的运行会执行失败
+	SecretKey []byte
+
+	// EmailService 设置时向组织成员发送生产环境运行失败通知和缺失连接提醒，AppOrigin 用于生成链接
+	EmailService email.EmailService
+	AppOrigin    string
+}
+
+// Worker 已组装的 worker 进程
+type Worker struct {
+	Manager *workerqueue.Manager
+	Runs    runs.Service
+	Events  events.Service
+
+	// Integrations 集成服务，提供运行的连接凭证并轮换即将过期的 OAuth 令牌
+	Integrations integrations.Service
+
+	// DeadLetters 死信存储，未启用时为 nil
+	DeadLetters *workerqueue.DeadLetterStore
+
+	// FailureNotifier 运行失败通知器，未配置邮件服务时为 nil
+	FailureNotifier *runs.FailureNotifier
+
+	// ConnectionNotifier 缺失连接的邮件通知器，未配置邮件服务或已显式提供通知器时为 nil
+	ConnectionNotifier *integrations.EmailNotifier
+}
+
+// Bootstrap 创建 worker queue 管理器以及通过它入队的 runs 与 events 服务
+// 服务依赖管理器入队，而管理器在创建时就要注册 worker，因此 worker 先绑定到延迟解析的服务上
+func Bootstrap(opts Options) (*Worker, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	bound := &boundServices{}
+	managerOpts := opts.ManagerOptions
+	if managerOpts.RunExecutor == nil {
+		managerOpts.RunExecutor = bound
+	}
+	if managerOpts.RunTimeoutEnforcer == nil {
+		managerOpts.RunTimeoutEnforcer = bound
+	}
+	if managerOpts.EventProcessor == nil {
+		managerOpts.EventProcessor = bound
+	}
+	if managerOpts.ConnectionRefresher == nil {
+		managerOpts.ConnectionRefresher = bound
+	}
+	connectionAuth := opts.ConnectionAuth
+	if connectionAuth == nil {
+		connectionAuth = bound
+	}
+	// 未显式配置时使用任务目录声明的限流，传入空 map 可关闭限流
+	if managerOpts.RateLimits == nil {
+		managerOpts.RateLimits = workerqueue.DefaultRateLimits()
+	}
+
+	var deadLetters *workerqueue.DeadLetterStore
+	if opts.DeadLetters {
+		deadLetters = workerqueue.NewDeadLetterStore(opts.Pool, logger)
+		managerOpts.DeadLetterHook = deadLetters
+	}
+
+	secrets, err := newSecretStore(opts.Pool, opts.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	runsRepo := runs.NewRepository(runs.New(opts.Pool), opts.Pool)
+	integrationsRepo := integrations.NewRepository(opts.Pool)
+
+	var failureNotifier *runs.FailureNotifier
+	if opts.EmailService != nil && managerOpts.RunFailureNotifier == nil {
+		failureNotifier = runs.NewFailureNotifier(runsRepo, opts.EmailService, opts.AppOrigin, logger)
+		managerOpts.RunFailureNotifier = failureNotifier
+	}
+
+	var emailConnectionNotifier *integrations.EmailNotifier
+	connectionNotifier := opts.ConnectionNotifier
+	if opts.EmailService != nil && connectionNotifier == nil {
+		emailConnectionNotifier = integrations.NewEmailNotifier(opts.EmailService, integrations.MemberRecipients(integrationsRepo), opts.AppOrigin)
+		connectionNotifier = emailConnectionNotifier
+	}
+
+	clientFactory := opts.ClientFactory
+	var eventMetrics events.Metrics
+	var runMetrics runs.Metrics
+	if opts.Metrics != nil {
+		if managerOpts.JobMetrics == nil {
+			managerOpts.JobMetrics = opts.Metrics
+		}
+		if clientFactory == nil {
+			clientFactory = runs.NewEndpointClientFactoryWithMetrics(logger, opts.Metrics)
+		}
+		eventMetrics = opts.Metrics
+		runMetrics = opts.Metrics
+	}
+
+	manager, err := workerqueue.NewManagerWithOptions(opts.Config, opts.Pool, logger, managerOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worker queue manager: %w", err)
+	}
+
+	if opts.Metrics != nil {
+		if err := opts.Metrics.RegisterQueueStats(manager); err != nil {
+			return nil, fmt.Errorf("failed to register queue metrics: %w", err)
+		}
+	}
+
+	runsSvc := runs.NewServiceWithMetrics(
+		runsRepo,
+		runsqueue.NewRiverQueueService(manager),
+		clientFactory,
+		connectionNotifier,
+		connectionAuth,
+		runMetrics,
+		logger,
+	)
+	eventsSvc := events.NewServiceWithMetrics(
+		events.NewRepository(events.New(opts.Pool), opts.Pool),
+		shared.New(opts.Pool),
+		eventsqueue.NewRiverQueueService(manager),
+		runsSvc,
+		eventMetrics,
+		logger,
+	)
+
+	integrationsSvc := integrations.NewService(integrationsRepo, secrets, runsSvc, logger)
+
+	bound.runs = runsSvc
+	bound.events = eventsSvc
+	bound.integrations = integrationsSvc
+
+	return &Worker{
+		Manager:            manager,
+		Runs:               runsSvc,
+		Events:             eventsSvc,
+		Integrations:       integrationsSvc,
+		DeadLetters:        deadLetters,
+		FailureNotifier:    failureNotifier,
+		ConnectionNotifier: emailConnectionNotifier,
+	}, nil
+}
+
+// newSecretStore 创建保存连接凭证的 SecretStore，未提供密钥时不加密
+func newSecretStore(pool *pgxpool.Pool, key []byte) (*secretstore.Service, error) {
+	repo := secretstore.NewRepository(pool)
+	if len(key) == 0 {
+		return secretstore.NewService(repo), nil
+	}
+	secrets, err := secretstore.NewEncryptedService(repo, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret store: %w", err)
+	}
+	return secrets, nil
+}
+
+// boundServices 在服务创建后才绑定的 worker 依赖
+type boundServices struct {
+	runs         runs.Service
+	events       events.Service
+	integrations integrations.Service
+}
+
+var (
+	_ workerqueue.RunExecutor         = (*boundServices)(nil)
+	_ workerqueue.RunTimeoutEnforcer  = (*boundServices)(nil)
+	_ workerqueue.EventProcessor      = (*boundServices)(nil)
+	_ workerqueue.ConnectionRefresher = (*boundServices)(nil)
+	_ runs.ConnectionAuthProvider     = (*boundServices)(nil)
+)
+
+func (b *boundServices) StartRun(ctx context.Context, runID string) error {
+	return b.runs.StartRun(ctx, runID)
+}
+
+func (b *boundServices) PerformRunExecution(ctx context.Context, req *workerqueue.RunExecutionRequest) error {
+	return b.runs.PerformRunExecution(ctx, req)
+}
+
+func (b *boundServices) StartQueuedRuns(ctx context.Context, req *workerqueue.StartQueuedRunsRequest) error {
+	return b.runs.StartQueuedRuns(ctx, req)
+}
+
+func (b *boundServices) TimeoutRuns(ctx context.Context) (int, error) {
+	return b.runs.TimeoutRuns(ctx)
+}
+
+func (b *boundServices) DeliverEvent(ctx context.Context, eventID string) error {
+	return b.events.DeliverEvent(ctx, eventID)
+}
+
+func (b *boundServices) InvokeDispatcher(ctx context.Context, dispatcherID string, eventRecordID string) error {
+	return b.events.InvokeDispatcher(ctx, dispatcherID, eventRecordID)
+}
+
+func (b *boundServices) RefreshExpiringConnections(ctx context.Context, window time.Duration) (int, error) {
+	return b.integrations.RefreshExpiringConnections(ctx, window)
+}
+
+func (b *boundServices) ConnectionAuth(ctx context.Context, integrationID string) (map[string]interface{}, error) {
+	return b.integrations.ConnectionAuth(ctx, integrationID)
+}
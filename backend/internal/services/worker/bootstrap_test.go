@@ -0,0 +1,176 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	authtestutil "kongflow/backend/internal/services/auth/testutil"
+	"kongflow/backend/internal/services/events"
+	eventsqueue "kongflow/backend/internal/services/events/queue"
+	"kongflow/backend/internal/services/integrations"
+	"kongflow/backend/internal/services/workerqueue"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockEventsService 模拟事件服务，只实现 worker 调用的方法
+type MockEventsService struct {
+	events.Service
+	mock.Mock
+}
+
+func (m *MockEventsService) DeliverEvent(ctx context.Context, eventID string) error {
+	return m.Called(ctx, eventID).Error(0)
+}
+
+func (m *MockEventsService) InvokeDispatcher(ctx context.Context, dispatcherID string, eventRecordID string) error {
+	return m.Called(ctx, dispatcherID, eventRecordID).Error(0)
+}
+
+// MockIntegrationsService 模拟集成服务，只实现 worker 调用的方法
+type MockIntegrationsService struct {
+	integrations.Service
+	mock.Mock
+}
+
+func (m *MockIntegrationsService) RefreshExpiringConnections(ctx context.Context, window time.Duration) (int, error) {
+	args := m.Called(ctx, window)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockIntegrationsService) ConnectionAuth(ctx context.Context, integrationID string) (map[string]interface{}, error) {
+	args := m.Called(ctx, integrationID)
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+// recordingManager 记录入队时使用的标识符
+type recordingManager struct {
+	identifiers []string
+}
+
+func (m *recordingManager) EnqueueJob(ctx context.Context, identifier string, payload interface{}, opts *workerqueue.JobOptions) (*rivertype.JobInsertResult, error) {
+	m.identifiers = append(m.identifiers, identifier)
+	return &rivertype.JobInsertResult{Job: &rivertype.JobRow{Kind: identifier}}, nil
+}
+
+func (m *recordingManager) EnqueueJobTx(ctx context.Context, tx pgx.Tx, identifier string, payload interface{}, opts *workerqueue.JobOptions) (*rivertype.JobInsertResult, error) {
+	return m.EnqueueJob(ctx, identifier, payload, opts)
+}
+
+func TestBoundServices_EventWorkersCallEventsService(t *testing.T) {
+	eventsSvc := &MockEventsService{}
+	bound := &boundServices{events: eventsSvc}
+	logger := slog.Default()
+
+	eventsSvc.On("DeliverEvent", mock.Anything, "event-1").Return(nil)
+	eventsSvc.On("InvokeDispatcher", mock.Anything, "dispatcher-1", "event-1").Return(errors.New("dispatcher disabled"))
+
+	err := workerqueue.NewDeliverEventWorker(bound, logger).Work(context.Background(), &river.Job[workerqueue.DeliverEventArgs]{
+		JobRow: &rivertype.JobRow{ID: 1},
+		Args:   workerqueue.DeliverEventArgs{ID: "event-1"},
+	})
+	require.NoError(t, err)
+
+	err = workerqueue.NewInvokeDispatcherWorker(bound, logger).Work(context.Background(), &river.Job[workerqueue.InvokeDispatcherArgs]{
+		JobRow: &rivertype.JobRow{ID: 2},
+		Args:   workerqueue.InvokeDispatcherArgs{ID: "dispatcher-1", EventRecordID: "event-1"},
+	})
+	assert.ErrorContains(t, err, "dispatcher disabled")
+
+	eventsSvc.AssertExpectations(t)
+}
+
+func TestEventsQueue_IdentifiersMatchRegisteredTasks(t *testing.T) {
+	manager := &recordingManager{}
+	queueSvc := eventsqueue.NewRiverQueueService(manager)
+	ctx := context.Background()
+
+	_, err := queueSvc.EnqueueDeliverEvent(ctx, &eventsqueue.EnqueueDeliverEventRequest{EventID: "event-1"})
+	require.NoError(t, err)
+	_, err = queueSvc.EnqueueInvokeDispatcher(ctx, &eventsqueue.EnqueueInvokeDispatcherRequest{DispatcherID: "dispatcher-1", EventID: "event-1"})
+	require.NoError(t, err)
+
+	registry := workerqueue.NewTaskRegistry()
+	require.NoError(t, workerqueue.RegisterTask(registry, workerqueue.NewDeliverEventWorker(nil, nil), workerqueue.JobOptions{}))
+	require.NoError(t, workerqueue.RegisterTask(registry, workerqueue.NewInvokeDispatcherWorker(nil, nil), workerqueue.JobOptions{}))
+
+	require.Len(t, manager.identifiers, 2)
+	for _, identifier := range manager.identifiers {
+		_, err := registry.Lookup(identifier)
+		assert.NoError(t, err, identifier)
+	}
+}
+
+func TestBootstrap_WiresRunFailureNotifier(t *testing.T) {
+	// 连接池惰性建立连接，组装过程不访问数据库
+	pool, err := pgxpool.New(context.Background(), "postgres://kongflow@localhost:1/kongflow")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	w, err := Bootstrap(Options{
+		Config:       workerqueue.DefaultConfig(),
+		Pool:         pool,
+		Logger:       slog.Default(),
+		EmailService: &authtestutil.MockEmailService{},
+		AppOrigin:    "https://app.kongflow.dev",
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, w.FailureNotifier)
+	assert.NotNil(t, w.ConnectionNotifier)
+
+	w, err = Bootstrap(Options{Config: workerqueue.DefaultConfig(), Pool: pool, Logger: slog.Default()})
+	require.NoError(t, err)
+	assert.Nil(t, w.FailureNotifier)
+	assert.Nil(t, w.ConnectionNotifier)
+}
+
+func TestBoundServices_ConnectionsUseIntegrationsService(t *testing.T) {
+	integrationsSvc := &MockIntegrationsService{}
+	bound := &boundServices{integrations: integrationsSvc}
+	ctx := context.Background()
+
+	integrationsSvc.On("ConnectionAuth", mock.Anything, "integration-1").Return(map[string]interface{}{"accessToken": "token"}, nil)
+	integrationsSvc.On("RefreshExpiringConnections", mock.Anything, 10*time.Minute).Return(2, nil)
+
+	auth, err := bound.ConnectionAuth(ctx, "integration-1")
+	require.NoError(t, err)
+	assert.Equal(t, "token", auth["accessToken"])
+
+	refreshed, err := workerqueue.ConnectionRefresher(bound).RefreshExpiringConnections(ctx, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, refreshed)
+
+	integrationsSvc.AssertExpectations(t)
+}
+
+func TestBootstrap_WiresIntegrations(t *testing.T) {
+	pool, err := pgxpool.New(context.Background(), "postgres://kongflow@localhost:1/kongflow")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	w, err := Bootstrap(Options{
+		Config:    workerqueue.DefaultConfig(),
+		Pool:      pool,
+		Logger:    slog.Default(),
+		SecretKey: make([]byte, 32),
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, w.Integrations)
+
+	_, err = Bootstrap(Options{
+		Config:    workerqueue.DefaultConfig(),
+		Pool:      pool,
+		Logger:    slog.Default(),
+		SecretKey: []byte("short"),
+	})
+	assert.ErrorContains(t, err, "failed to create secret store")
+}
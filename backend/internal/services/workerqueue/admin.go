@@ -0,0 +1,152 @@
+// Package workerqueue provides admin operations for inspecting and recovering failed jobs
+package workerqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// defaultFailedJobsLimit caps ListFailedJobs when no limit is given
+const defaultFailedJobsLimit = 100
+
+// FailedJobFilter selects failed jobs to inspect
+// Empty slices match everything; zero times leave the range open
+type FailedJobFilter struct {
+	Kinds  []string
+	Queues []string
+
+	// States defaults to discarded and retryable jobs
+	States []rivertype.JobState
+
+	// Since and Until bound the time of the job's last failure
+	Since time.Time
+	Until time.Time
+
+	Limit int
+}
+
+// FailedJob is a failed River job with its error history
+type FailedJob struct {
+	ID          int64                    `json:"id"`
+	Kind        string                   `json:"kind"`
+	Queue       string                   `json:"queue"`
+	State       rivertype.JobState       `json:"state"`
+	Attempt     int                      `json:"attempt"`
+	MaxAttempts int                      `json:"maxAttempts"`
+	Args        json.RawMessage          `json:"args"`
+	Errors      []rivertype.AttemptError `json:"errors"`
+	CreatedAt   time.Time                `json:"createdAt"`
+	AttemptedAt *time.Time               `json:"attemptedAt,omitempty"`
+	FinalizedAt *time.Time               `json:"finalizedAt,omitempty"`
+}
+
+// ListFailedJobs lists failed jobs matching filter, most recent first
+func (m *Manager) ListFailedJobs(ctx context.Context, filter FailedJobFilter) ([]FailedJob, error) {
+	states := filter.States
+	if len(states) == 0 {
+		states = []rivertype.JobState{rivertype.JobStateDiscarded, rivertype.JobStateRetryable}
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultFailedJobsLimit
+	}
+
+	params := river.NewJobListParams().
+		States(states...).
+		OrderBy(river.JobListOrderByID, river.SortOrderDesc).
+		First(limit)
+	if len(filter.Kinds) > 0 {
+		params = params.Kinds(filter.Kinds...)
+	}
+	if len(filter.Queues) > 0 {
+		params = params.Queues(filter.Queues...)
+	}
+	if !filter.Since.IsZero() {
+		params = params.Where("COALESCE(finalized_at, attempted_at, created_at) >= @since", river.NamedArgs{"since": filter.Since})
+	}
+	if !filter.Until.IsZero() {
+		params = params.Where("COALESCE(finalized_at, attempted_at, created_at) < @until", river.NamedArgs{"until": filter.Until})
+	}
+
+	result, err := m.riverClient.JobList(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed jobs: %w", err)
+	}
+
+	jobs := make([]FailedJob, 0, len(result.Jobs))
+	for _, job := range result.Jobs {
+		jobs = append(jobs, failedJobFromRow(job))
+	}
+	return jobs, nil
+}
+
+// RetryJob makes a failed job available to run again immediately
+func (m *Manager) RetryJob(ctx context.Context, id int64) (*FailedJob, error) {
+	job, err := m.riverClient.JobRetry(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry job %d: %w", id, err)
+	}
+
+	m.logger.Info("Retried job", "job_id", id, "kind", job.Kind)
+	failed := failedJobFromRow(job)
+	return &failed, nil
+}
+
+// RetryJobs retries each job in ids, returning the number retried
+// Jobs that no longer exist are skipped
+func (m *Manager) RetryJobs(ctx context.Context, ids []int64) (int, error) {
+	retried := 0
+	for _, id := range ids {
+		if _, err := m.riverClient.JobRetry(ctx, id); err != nil {
+			if errors.Is(err, river.ErrNotFound) {
+				continue
+			}
+			return retried, fmt.Errorf("failed to retry job %d: %w", id, err)
+		}
+		retried++
+	}
+
+	m.logger.Info("Retried jobs", "requested", len(ids), "retried", retried)
+	return retried, nil
+}
+
+// DeleteJobs deletes the jobs in ids, returning the number deleted
+// Running jobs cannot be deleted; jobs that no longer exist are skipped
+func (m *Manager) DeleteJobs(ctx context.Context, ids []int64) (int, error) {
+	deleted := 0
+	for _, id := range ids {
+		if _, err := m.riverClient.JobDelete(ctx, id); err != nil {
+			if errors.Is(err, river.ErrNotFound) {
+				continue
+			}
+			return deleted, fmt.Errorf("failed to delete job %d: %w", id, err)
+		}
+		deleted++
+	}
+
+	m.logger.Info("Deleted jobs", "requested", len(ids), "deleted", deleted)
+	return deleted, nil
+}
+
+// failedJobFromRow converts a River job row to a FailedJob
+func failedJobFromRow(job *rivertype.JobRow) FailedJob {
+	return FailedJob{
+		ID:          job.ID,
+		Kind:        job.Kind,
+		Queue:       job.Queue,
+		State:       job.State,
+		Attempt:     job.Attempt,
+		MaxAttempts: job.MaxAttempts,
+		Args:        json.RawMessage(job.EncodedArgs),
+		Errors:      job.Errors,
+		CreatedAt:   job.CreatedAt,
+		AttemptedAt: job.AttemptedAt,
+		FinalizedAt: job.FinalizedAt,
+	}
+}
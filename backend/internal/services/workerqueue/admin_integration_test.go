@@ -0,0 +1,159 @@
+package workerqueue_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kongflow/backend/internal/database"
+	"kongflow/backend/internal/services/workerqueue"
+	"kongflow/backend/internal/services/workerqueue/testutil"
+)
+
+// failingArgs is a job that always fails on its only attempt
+type failingArgs struct {
+	Value string `json:"value"`
+}
+
+func (failingArgs) Kind() string { return "test_failing_job" }
+
+func (failingArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{Queue: string(workerqueue.QueueDefault), MaxAttempts: 1}
+}
+
+type failingWorker struct {
+	river.WorkerDefaults[failingArgs]
+}
+
+func (w *failingWorker) Work(ctx context.Context, job *river.Job[failingArgs]) error {
+	return errors.New("boom: " + job.Args.Value)
+}
+
+func TestFailedJobAdminAndDeadLetterQueue(t *testing.T) {
+	ctx := context.Background()
+
+	testDB := database.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	deadLetters := workerqueue.NewDeadLetterStore(testDB.Pool, logger)
+
+	config := workerqueue.DefaultConfig()
+	config.TestMode = true
+
+	manager, err := workerqueue.NewManagerWithOptions(config, testDB.Pool, logger, workerqueue.ManagerOptions{
+		DeadLetterHook: deadLetters,
+		RegisterTasks: func(registry *workerqueue.TaskRegistry) error {
+			return workerqueue.RegisterTask(registry, &failingWorker{}, workerqueue.JobOptions{})
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, manager.EnsureRiverTables(ctx))
+	require.NoError(t, manager.Start(ctx))
+	defer func() {
+		assert.NoError(t, manager.Stop(ctx))
+	}()
+
+	_, err = manager.EnqueueJob(ctx, "test_failing_job", map[string]interface{}{"value": "first"}, nil)
+	require.NoError(t, err)
+
+	discarded := func() []workerqueue.FailedJob {
+		jobs, err := manager.ListFailedJobs(ctx, workerqueue.FailedJobFilter{
+			Kinds:  []string{"test_failing_job"},
+			States: []rivertype.JobState{rivertype.JobStateDiscarded},
+		})
+		require.NoError(t, err)
+		return jobs
+	}
+	pendingDeadLetters := func() []workerqueue.DeadLetterJob {
+		jobs, err := deadLetters.List(ctx, "test_failing_job", 10)
+		require.NoError(t, err)
+		return jobs
+	}
+
+	testutil.WaitForJobCompletion(t, func() int { return len(discarded()) }, 1, 15*time.Second)
+	testutil.WaitForJobCompletion(t, func() int { return len(pendingDeadLetters()) }, 1, 5*time.Second)
+
+	failed := discarded()[0]
+	require.NotEmpty(t, failed.Errors)
+	assert.Contains(t, failed.Errors[0].Error, "boom: first")
+	assert.JSONEq(t, `{"value":"first"}`, string(failed.Args))
+
+	// Time filters exclude jobs outside the range
+	future, err := manager.ListFailedJobs(ctx, workerqueue.FailedJobFilter{
+		Kinds: []string{"test_failing_job"},
+		Since: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, future)
+
+	t.Run("Sweep copies jobs missed by the hook", func(t *testing.T) {
+		// 模拟丢弃事件发生时没有进程订阅
+		_, err := testDB.Pool.Exec(ctx, `DELETE FROM worker_dead_letter_jobs WHERE job_id = $1`, failed.ID)
+		require.NoError(t, err)
+		require.Empty(t, pendingDeadLetters())
+
+		swept, err := deadLetters.SweepDiscardedJobs(ctx, config.Schema)
+		require.NoError(t, err)
+		assert.Equal(t, 1, swept)
+
+		jobs := pendingDeadLetters()
+		require.Len(t, jobs, 1)
+		assert.Equal(t, failed.ID, jobs[0].JobID)
+		require.NotEmpty(t, jobs[0].Errors)
+		assert.Contains(t, jobs[0].Errors[0].Error, "boom: first")
+
+		swept, err = deadLetters.SweepDiscardedJobs(ctx, config.Schema)
+		require.NoError(t, err)
+		assert.Zero(t, swept)
+	})
+
+	t.Run("Replay dead-letter job", func(t *testing.T) {
+		deadLetter := pendingDeadLetters()[0]
+		assert.Equal(t, failed.ID, deadLetter.JobID)
+		assert.JSONEq(t, `{"value":"first"}`, string(deadLetter.Args))
+
+		result, err := deadLetters.Replay(ctx, manager, deadLetter.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, failed.ID, result.Job.ID)
+		assert.Empty(t, pendingDeadLetters())
+
+		_, err = deadLetters.Replay(ctx, manager, deadLetter.ID)
+		assert.ErrorIs(t, err, workerqueue.ErrDeadLetterJobNotFound)
+
+		testutil.WaitForJobCompletion(t, func() int { return len(discarded()) }, 2, 15*time.Second)
+	})
+
+	t.Run("Retry and delete failed jobs", func(t *testing.T) {
+		retried, err := manager.RetryJobs(ctx, []int64{failed.ID, -1})
+		require.NoError(t, err)
+		assert.Equal(t, 1, retried)
+
+		// The retried job fails again and is discarded with a second error
+		testutil.WaitForJobCompletion(t, func() int {
+			for _, job := range discarded() {
+				if job.ID == failed.ID {
+					return len(job.Errors)
+				}
+			}
+			return 0
+		}, 2, 15*time.Second)
+
+		var ids []int64
+		for _, job := range discarded() {
+			ids = append(ids, job.ID)
+		}
+		deleted, err := manager.DeleteJobs(ctx, ids)
+		require.NoError(t, err)
+		assert.Equal(t, len(ids), deleted)
+		assert.Empty(t, discarded())
+	})
+}
@@ -3,9 +3,9 @@ package workerqueue
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river/rivertype"
@@ -92,7 +92,8 @@ func (c *Client) Initialize(ctx context.Context) error {
 	return c.triggerWorker.Initialize(ctx)
 }
 
-// Stop gracefully stops the worker client (mirrors trigger.dev's stop())
+// Stop drains and stops the worker client (mirrors trigger.dev's stop())
+// Running jobs finish up to Config.DrainTimeout; jobs that have not started are snoozed
 func (c *Client) Stop(ctx context.Context) error {
 	c.logger.Info("Stopping worker queue client")
 	return c.triggerWorker.Stop(ctx)
@@ -124,170 +125,55 @@ func (c *Client) EnqueueWithBusinessLogic(ctx context.Context, identifier string
 }
 
 // createDefaultTaskCatalog creates a task catalog that aligns with trigger.dev's workerCatalog
+// Each identifier enqueues the River worker registered for its kind; queues come from the
+// registered task so jobs land on queues the manager actually works
 func createDefaultTaskCatalog() TaskCatalog {
 	return TaskCatalog{
 		// Core tasks from trigger.dev workerCatalog
 		"indexEndpoint": TaskDefinition{
-			QueueName:   StaticQueueName("internal-queue"), // ✅ Convert to StaticQueueName
-			MaxAttempts: 7,                                 // matches trigger.dev
-			Handler:     handleIndexEndpoint,
+			Kind:        IndexEndpointArgs{}.Kind(),
+			MaxAttempts: 7, // matches trigger.dev
 		},
 		"scheduleEmail": TaskDefinition{
-			QueueName:   StaticQueueName("internal-queue"), // ✅ Convert to StaticQueueName
-			Priority:    100,                               // matches trigger.dev
-			MaxAttempts: 3,                                 // matches trigger.dev
-			Handler:     handleScheduleEmail,
+			Kind:        ScheduleEmailArgs{}.Kind(),
+			Priority:    100, // matches trigger.dev
+			MaxAttempts: 3,   // matches trigger.dev
+			// Stays under Resend's default API rate limit
+			RateLimit: &RateLimit{Limit: 2, Interval: time.Second},
 		},
 		"startRun": TaskDefinition{
-			QueueName:   StaticQueueName("executions"), // ✅ Convert to StaticQueueName
-			MaxAttempts: 13,                            // matches trigger.dev
-			Handler:     handleStartRun,
+			Kind:        StartRunArgs{}.Kind(),
+			MaxAttempts: 13, // matches trigger.dev
 		},
 		"performRunExecution": TaskDefinition{
-			// ✅ Phase 2: Dynamic queue - run-level isolation
-			QueueName: DynamicQueueName(func(payload interface{}) string {
-				// Try to cast to PerformRunExecutionV2Args (our enhanced structure)
-				if runArgs, ok := payload.(PerformRunExecutionV2Args); ok {
-					return fmt.Sprintf("runs_%s", runArgs.ID) // runs_run-12345 (using underscores)
-				}
-				// Fallback for other payload types
-				if payloadMap, ok := payload.(map[string]interface{}); ok {
-					if id, exists := payloadMap["id"]; exists {
-						return fmt.Sprintf("runs_%v", id)
-					}
-				}
-				return "runs_default" // Safe fallback
-			}),
+			Kind:        PerformRunExecutionV2Args{}.Kind(),
 			MaxAttempts: 1, // matches trigger.dev
-			Handler:     handlePerformRunExecution,
-		},
-		"performTaskOperation": TaskDefinition{
-			QueueName:   StaticQueueName("tasks"), // ✅ Convert to StaticQueueName
-			MaxAttempts: 3,                        // matches trigger.dev
-			Handler:     handlePerformTaskOperation,
+			// Each endpoint gets its own bucket so one busy endpoint cannot flood the others
+			RateLimit: &RateLimit{Limit: 60, Interval: time.Minute, Burst: 20, KeyField: "endpointId"},
 		},
 		"deliverEvent": TaskDefinition{
-			// ✅ Phase 2: Dynamic queue - project-level event routing
-			QueueName: DynamicQueueName(func(payload interface{}) string {
-				// Try to cast to DeliverEventArgs (our enhanced structure)
-				if eventArgs, ok := payload.(DeliverEventArgs); ok {
-					if eventArgs.ProjectID != "" {
-						// Project-specific event queue
-						return fmt.Sprintf("events_project_%s", eventArgs.ProjectID)
-					}
-					if eventArgs.EventType != "" {
-						// Event type-specific queue
-						return fmt.Sprintf("events_type_%s", eventArgs.EventType)
-					}
-				}
-				// Fallback for map payload
-				if payloadMap, ok := payload.(map[string]interface{}); ok {
-					if projectId, exists := payloadMap["projectId"]; exists {
-						return fmt.Sprintf("events_project_%v", projectId)
-					}
-					if eventType, exists := payloadMap["eventType"]; exists {
-						return fmt.Sprintf("events_type_%v", eventType)
-					}
-				}
-				return "events_default" // Safe fallback to default event queue
-			}),
+			Kind:        DeliverEventArgs{}.Kind(),
 			MaxAttempts: 5, // matches trigger.dev
-			Handler:     handleDeliverEvent,
 		},
 		"events.invokeDispatcher": TaskDefinition{
-			QueueName:   StaticQueueName("event-dispatcher"), // ✅ Add missing queue name
-			MaxAttempts: 3,                                   // matches trigger.dev
-			Handler:     handleInvokeDispatcher,
-		},
-		"runFinished": TaskDefinition{
-			QueueName:   StaticQueueName("executions"), // ✅ Add missing queue name
-			MaxAttempts: 3,                             // matches trigger.dev
-			Handler:     handleRunFinished,
+			Kind:        InvokeDispatcherArgs{}.Kind(),
+			MaxAttempts: 3, // matches trigger.dev
 		},
 		"startQueuedRuns": TaskDefinition{
-			// ✅ Phase 2: Dynamic queue - project-level isolation
-			QueueName: DynamicQueueName(func(payload interface{}) string {
-				// Try to cast to StartQueuedRunsArgs (our new structure)
-				if runArgs, ok := payload.(StartQueuedRunsArgs); ok {
-					return fmt.Sprintf("project_%s_runs", runArgs.ProjectID) // project_proj-123_runs
-				}
-				// Fallback for map payload
-				if payloadMap, ok := payload.(map[string]interface{}); ok {
-					if projectId, exists := payloadMap["projectId"]; exists {
-						return fmt.Sprintf("project_%v_runs", projectId)
-					}
-				}
-				return "project_default_runs" // Safe fallback
-			}),
+			Kind:        StartQueuedRunsArgs{}.Kind(),
 			MaxAttempts: 3, // matches trigger.dev
-			Handler:     handleStartQueuedRuns,
-		},
-		// ✅ Phase 2: User-level task routing with plan and geographic isolation
-		"processUserTask": TaskDefinition{
-			QueueName: DynamicQueueName(func(payload interface{}) string {
-				// Try to cast to UserTaskArgs
-				if userArgs, ok := payload.(UserTaskArgs); ok {
-					// Multi-dimensional routing: plan + region
-					switch userArgs.UserPlan {
-					case "enterprise":
-						return fmt.Sprintf("enterprise_%s_high-priority", userArgs.Region)
-					case "pro":
-						return fmt.Sprintf("pro_%s_medium-priority", userArgs.Region)
-					case "free":
-						return fmt.Sprintf("free_%s_standard", userArgs.Region)
-					default:
-						return fmt.Sprintf("standard_%s_normal", userArgs.Region)
-					}
-				}
-				// Fallback for map payload
-				if payloadMap, ok := payload.(map[string]interface{}); ok {
-					userPlan := "standard"
-					region := "default"
-					if plan, exists := payloadMap["userPlan"]; exists {
-						userPlan = fmt.Sprintf("%v", plan)
-					}
-					if reg, exists := payloadMap["region"]; exists {
-						region = fmt.Sprintf("%v", reg)
-					}
-					return fmt.Sprintf("%s_%s_tasks", userPlan, region)
-				}
-				return "standard_default_tasks" // Safe fallback
-			}),
-			MaxAttempts: 5,
-			Handler:     handleUserTask,
 		},
 	}
 }
 
-// Additional handlers for completeness (placeholders for now)
-func handleScheduleEmail(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	return fmt.Errorf("scheduleEmail handler not implemented")
-}
-
-func handlePerformRunExecution(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	return fmt.Errorf("performRunExecution handler not implemented")
-}
-
-func handlePerformTaskOperation(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	return fmt.Errorf("performTaskOperation handler not implemented")
-}
-
-func handleRunFinished(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	return fmt.Errorf("runFinished handler not implemented")
-}
-
-func handleStartQueuedRuns(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	return fmt.Errorf("startQueuedRuns handler not implemented")
-}
-
-// ✅ Phase 2: User task handler with dynamic queue routing context
-func handleUserTask(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	// Parse the payload to get user context
-	var userArgs UserTaskArgs
-	if err := json.Unmarshal(payload, &userArgs); err == nil {
-		// Log the dynamic queue routing for demonstration
-		fmt.Printf("Processing user task for user %s (plan: %s, region: %s) in queue: %s\n",
-			userArgs.UserID, userArgs.UserPlan, userArgs.Region, job.Queue)
+// DefaultRateLimits returns the rate limits declared by the default task catalog, keyed by kind
+// Bootstrap applies them through ManagerOptions.RateLimits
+func DefaultRateLimits() map[string]RateLimit {
+	limits := make(map[string]RateLimit)
+	for identifier, taskDef := range createDefaultTaskCatalog() {
+		if taskDef.RateLimit != nil {
+			limits[taskDef.kind(identifier)] = *taskDef.RateLimit
+		}
 	}
-	return fmt.Errorf("userTask handler not implemented - processed in queue: %s", job.Queue)
+	return limits
 }
@@ -22,6 +22,16 @@ type Config struct {
 	// MaintenanceMaxWorkers is the maximum number of workers for maintenance tasks
 	MaintenanceMaxWorkers int
 
+	// ConnectionRefreshInterval is how often expiring integration connections are refreshed
+	// Connections expiring within twice this interval are refreshed on each run
+	ConnectionRefreshInterval time.Duration
+
+	// RunTimeoutCheckInterval is how often runs exceeding their maximum duration are timed out
+	RunTimeoutCheckInterval time.Duration
+
+	// DeadLetterSweepInterval is how often discarded jobs missed by the dead-letter hook are copied
+	DeadLetterSweepInterval time.Duration
+
 	// FetchCooldown is the minimum time between job fetches
 	FetchCooldown time.Duration
 
@@ -31,6 +41,34 @@ type Config struct {
 	// FetchPollInterval is the interval for polling new jobs
 	FetchPollInterval time.Duration
 
+	// HeartbeatInterval is how often the River client checks in while running
+	HeartbeatInterval time.Duration
+
+	// HealthCheckInStaleAfter is how long after its last check-in the client is reported unhealthy
+	HealthCheckInStaleAfter time.Duration
+
+	// DrainTimeout is how long Drain waits for running jobs before cancelling them
+	DrainTimeout time.Duration
+
+	// DrainSnoozeDelay is how long jobs snoozed by a draining manager wait before becoming available
+	DrainSnoozeDelay time.Duration
+
+	// TenantConcurrency is how many jobs of one tenant may run at once in a queue
+	// Jobs over the quota are snoozed so other tenants' jobs are worked first; 0 disables the quota
+	// It is disabled by default so operators opt in with a quota sized for their queues
+	TenantConcurrency int
+
+	// TenantConcurrencyOverrides sets the quota of individual tenants by fair-share key, e.g. an
+	// environment ID; an override of 0 lets the tenant use every worker of the queue
+	TenantConcurrencyOverrides map[string]int
+
+	// FairShareSnoozeDelay is how long a job over its tenant's quota waits before becoming available
+	FairShareSnoozeDelay time.Duration
+
+	// QueueLimitSnoozeDelay is how long a job fetched over its queue's runtime MaxWorkers limit
+	// waits before becoming available again
+	QueueLimitSnoozeDelay time.Duration
+
 	// Schema is the database schema to use for River tables
 	Schema string
 
@@ -41,15 +79,25 @@ type Config struct {
 // DefaultConfig returns a configuration with sensible defaults
 func DefaultConfig() Config {
 	return Config{
-		MaxWorkers:            10,
-		ExecutionMaxWorkers:   5,
-		EventsMaxWorkers:      20,
-		MaintenanceMaxWorkers: 2,
-		FetchCooldown:         100 * time.Millisecond,
-		JobTimeout:            1 * time.Minute,
-		FetchPollInterval:     1 * time.Second,
-		Schema:                "public",
-		TestMode:              false,
+		MaxWorkers:                10,
+		ExecutionMaxWorkers:       5,
+		EventsMaxWorkers:          20,
+		MaintenanceMaxWorkers:     2,
+		ConnectionRefreshInterval: 5 * time.Minute,
+		RunTimeoutCheckInterval:   1 * time.Minute,
+		DeadLetterSweepInterval:   5 * time.Minute,
+		FetchCooldown:             100 * time.Millisecond,
+		JobTimeout:                1 * time.Minute,
+		FetchPollInterval:         1 * time.Second,
+		HeartbeatInterval:         15 * time.Second,
+		HealthCheckInStaleAfter:   1 * time.Minute,
+		DrainTimeout:              30 * time.Second,
+		DrainSnoozeDelay:          5 * time.Second,
+		TenantConcurrency:         0,
+		FairShareSnoozeDelay:      1 * time.Second,
+		QueueLimitSnoozeDelay:     1 * time.Second,
+		Schema:                    "public",
+		TestMode:                  false,
 	}
 }
 
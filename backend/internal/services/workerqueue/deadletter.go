@@ -0,0 +1,236 @@
+// Package workerqueue provides the dead-letter store for jobs discarded after exhausting their attempts
+package workerqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// deadLetterSweepBatch limits how many discarded jobs one sweep copies
+const deadLetterSweepBatch = 1000
+
+// ErrDeadLetterJobNotFound is returned when a dead-letter job does not exist or was already replayed
+var ErrDeadLetterJobNotFound = errors.New("dead-letter job not found")
+
+// DeadLetterHook receives jobs discarded after exhausting MaxAttempts
+// Set ManagerOptions.DeadLetterHook to enable it
+type DeadLetterHook interface {
+	JobDiscarded(ctx context.Context, job *rivertype.JobRow) error
+}
+
+// DeadLetterSweeper copies discarded jobs the hook missed, e.g. while no manager was subscribed
+// A DeadLetterHook implementing it is swept periodically on the maintenance queue
+type DeadLetterSweeper interface {
+	SweepDiscardedJobs(ctx context.Context, schema string) (int, error)
+}
+
+// DeadLetterJob is a discarded job copied into worker_dead_letter_jobs
+type DeadLetterJob struct {
+	ID            int64                    `json:"id"`
+	JobID         int64                    `json:"jobId"`
+	Kind          string                   `json:"kind"`
+	Queue         string                   `json:"queue"`
+	Args          json.RawMessage          `json:"args"`
+	Errors        []rivertype.AttemptError `json:"errors"`
+	Attempt       int                      `json:"attempt"`
+	MaxAttempts   int                      `json:"maxAttempts"`
+	DiscardedAt   time.Time                `json:"discardedAt"`
+	ReplayedAt    *time.Time               `json:"replayedAt,omitempty"`
+	ReplayedJobID *int64                   `json:"replayedJobId,omitempty"`
+}
+
+// DeadLetterStore copies discarded jobs into the worker_dead_letter_jobs table and replays them
+type DeadLetterStore struct {
+	dbPool *pgxpool.Pool
+	logger *slog.Logger
+}
+
+var (
+	_ DeadLetterHook    = (*DeadLetterStore)(nil)
+	_ DeadLetterSweeper = (*DeadLetterStore)(nil)
+)
+
+// NewDeadLetterStore creates a new dead-letter store
+func NewDeadLetterStore(dbPool *pgxpool.Pool, logger *slog.Logger) *DeadLetterStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DeadLetterStore{
+		dbPool: dbPool,
+		logger: logger,
+	}
+}
+
+// JobDiscarded copies a discarded job with its original args; copying the same job twice is a no-op
+func (s *DeadLetterStore) JobDiscarded(ctx context.Context, job *rivertype.JobRow) error {
+	errorsJSON, err := json.Marshal(job.Errors)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job errors: %w", err)
+	}
+
+	discardedAt := time.Now()
+	if job.FinalizedAt != nil {
+		discardedAt = *job.FinalizedAt
+	}
+
+	_, err = s.dbPool.Exec(ctx, `
+		INSERT INTO worker_dead_letter_jobs (job_id, kind, queue, args, errors, attempt, max_attempts, discarded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (job_id) DO NOTHING`,
+		job.ID, job.Kind, job.Queue, job.EncodedArgs, errorsJSON, job.Attempt, job.MaxAttempts, discardedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store dead-letter job %d: %w", job.ID, err)
+	}
+
+	s.logger.Warn("Job moved to dead-letter queue", "job_id", job.ID, "kind", job.Kind, "attempt", job.Attempt)
+	return nil
+}
+
+// SweepDiscardedJobs copies discarded River jobs in schema that are not in the dead-letter table yet
+// Discard events are only delivered to subscribed processes, so the sweep catches jobs discarded
+// while the hook was not listening; at most deadLetterSweepBatch jobs are copied per call
+func (s *DeadLetterStore) SweepDiscardedJobs(ctx context.Context, schema string) (int, error) {
+	tag, err := s.dbPool.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO worker_dead_letter_jobs (job_id, kind, queue, args, errors, attempt, max_attempts, discarded_at)
+		SELECT j.id, j.kind, j.queue, j.args, COALESCE(array_to_json(j.errors)::jsonb, '[]'::jsonb),
+			j.attempt, j.max_attempts, COALESCE(j.finalized_at, NOW())
+		FROM %s AS j
+		WHERE j.state = 'discarded'
+			AND NOT EXISTS (SELECT 1 FROM worker_dead_letter_jobs AS d WHERE d.job_id = j.id)
+		ORDER BY j.id
+		LIMIT $1
+		ON CONFLICT (job_id) DO NOTHING`, riverTable(schema, "river_job")),
+		deadLetterSweepBatch,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sweep discarded jobs: %w", err)
+	}
+
+	swept := int(tag.RowsAffected())
+	if swept > 0 {
+		s.logger.Warn("Swept discarded jobs into dead-letter queue", "count", swept)
+	}
+	return swept, nil
+}
+
+// List lists dead-letter jobs not yet replayed, most recently discarded first
+// kind filters by job kind when not empty
+func (s *DeadLetterStore) List(ctx context.Context, kind string, limit int) ([]DeadLetterJob, error) {
+	if limit <= 0 {
+		limit = defaultFailedJobsLimit
+	}
+
+	rows, err := s.dbPool.Query(ctx, `
+		SELECT id, job_id, kind, queue, args, errors, attempt, max_attempts, discarded_at, replayed_at, replayed_job_id
+		FROM worker_dead_letter_jobs
+		WHERE replayed_at IS NULL AND ($1 = '' OR kind = $1)
+		ORDER BY discarded_at DESC
+		LIMIT $2`,
+		kind, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-letter jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []DeadLetterJob
+	for rows.Next() {
+		job, err := scanDeadLetterJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list dead-letter jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Replay enqueues a dead-letter job again with its original kind, queue and args
+// The new job and the replay marker are written in one transaction so a job is only replayed once
+func (s *DeadLetterStore) Replay(ctx context.Context, manager *Manager, id int64) (*rivertype.JobInsertResult, error) {
+	tx, err := s.dbPool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	job, err := scanDeadLetterJob(tx.QueryRow(ctx, `
+		SELECT id, job_id, kind, queue, args, errors, attempt, max_attempts, discarded_at, replayed_at, replayed_job_id
+		FROM worker_dead_letter_jobs
+		WHERE id = $1 AND replayed_at IS NULL
+		FOR UPDATE`,
+		id,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %d", ErrDeadLetterJobNotFound, id)
+		}
+		return nil, err
+	}
+
+	result, err := manager.EnqueueJobTx(ctx, tx, job.Kind, job.Args, &JobOptions{
+		QueueName:   job.Queue,
+		MaxAttempts: job.MaxAttempts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay dead-letter job %d: %w", id, err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE worker_dead_letter_jobs SET replayed_at = NOW(), replayed_job_id = $2 WHERE id = $1`,
+		id, result.Job.ID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to mark dead-letter job %d replayed: %w", id, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit replay: %w", err)
+	}
+
+	s.logger.Info("Replayed dead-letter job", "id", id, "kind", job.Kind, "job_id", result.Job.ID)
+	return result, nil
+}
+
+// scanDeadLetterJob scans a worker_dead_letter_jobs row
+func scanDeadLetterJob(row pgx.Row) (DeadLetterJob, error) {
+	var job DeadLetterJob
+	var errorsJSON []byte
+	if err := row.Scan(
+		&job.ID, &job.JobID, &job.Kind, &job.Queue, &job.Args, &errorsJSON,
+		&job.Attempt, &job.MaxAttempts, &job.DiscardedAt, &job.ReplayedAt, &job.ReplayedJobID,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return job, err
+		}
+		return job, fmt.Errorf("failed to scan dead-letter job: %w", err)
+	}
+	if err := json.Unmarshal(errorsJSON, &job.Errors); err != nil {
+		return job, fmt.Errorf("failed to unmarshal dead-letter job errors: %w", err)
+	}
+	return job, nil
+}
+
+// forwardDiscardedJobs passes jobs discarded after their last attempt to hook until events closes
+func forwardDiscardedJobs(events <-chan *river.Event, hook DeadLetterHook, logger *slog.Logger) {
+	for event := range events {
+		if event.Job == nil || event.Job.State != rivertype.JobStateDiscarded {
+			continue
+		}
+		if err := hook.JobDiscarded(context.Background(), event.Job); err != nil {
+			logger.Error("Failed to copy discarded job to dead-letter queue", "job_id", event.Job.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package workerqueue_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kongflow/backend/internal/services/workerqueue"
+)
+
+// recordingSweeper records the schemas it was asked to sweep
+type recordingSweeper struct {
+	schemas []string
+	err     error
+}
+
+func (s *recordingSweeper) SweepDiscardedJobs(ctx context.Context, schema string) (int, error) {
+	s.schemas = append(s.schemas, schema)
+	return len(s.schemas), s.err
+}
+
+func TestSweepDeadLetterJobsWorker(t *testing.T) {
+	job := &river.Job[workerqueue.SweepDeadLetterJobsArgs]{JobRow: &rivertype.JobRow{ID: 1}}
+
+	sweeper := &recordingSweeper{}
+	require.NoError(t, workerqueue.NewSweepDeadLetterJobsWorker(sweeper, "kongflow", nil).Work(context.Background(), job))
+	assert.Equal(t, []string{"kongflow"}, sweeper.schemas)
+
+	sweeper.err = errors.New("connection refused")
+	err := workerqueue.NewSweepDeadLetterJobsWorker(sweeper, "kongflow", nil).Work(context.Background(), job)
+	assert.ErrorContains(t, err, "connection refused")
+
+	assert.NoError(t, workerqueue.NewSweepDeadLetterJobsWorker(nil, "kongflow", nil).Work(context.Background(), job))
+}
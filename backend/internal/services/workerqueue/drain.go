@@ -0,0 +1,122 @@
+// Package workerqueue coordinates draining the worker manager on shutdown
+package workerqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// drainCancelWait bounds how long Drain waits for cancelled jobs to return once the drain
+// deadline has passed, so interrupted runs can record themselves before the process exits
+const drainCancelWait = 10 * time.Second
+
+// ErrWorkerDraining is the cause of job contexts cancelled because the drain deadline passed
+// Workers tell a drain from a job timeout with errors.Is(context.Cause(ctx), ErrWorkerDraining)
+var ErrWorkerDraining = errors.New("worker draining")
+
+// drainMiddleware snoozes jobs that have not started once the manager is draining
+type drainMiddleware struct {
+	river.MiddlewareDefaults
+
+	snoozeDelay time.Duration
+	draining    atomic.Bool
+
+	mu      sync.Mutex
+	running map[int64]context.CancelCauseFunc
+}
+
+var _ rivertype.WorkerMiddleware = (*drainMiddleware)(nil)
+
+// Work snoozes the job instead of working it while draining
+// Jobs cancelled by the drain deadline are snoozed too, so shutdown does not consume an attempt
+func (d *drainMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(context.Context) error) error {
+	if d.draining.Load() {
+		return river.JobSnooze(d.snoozeDelay)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	d.track(job.ID, cancel)
+	defer d.untrack(job.ID)
+
+	err := doInner(ctx)
+	if err != nil && errors.Is(context.Cause(ctx), ErrWorkerDraining) {
+		return river.JobSnooze(d.snoozeDelay)
+	}
+	return err
+}
+
+// track registers the cancel function of a running job
+func (d *drainMiddleware) track(jobID int64, cancel context.CancelCauseFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running == nil {
+		d.running = make(map[int64]context.CancelCauseFunc)
+	}
+	d.running[jobID] = cancel
+}
+
+// untrack removes a finished job
+func (d *drainMiddleware) untrack(jobID int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.running, jobID)
+}
+
+// cancelRunning cancels every running job with ErrWorkerDraining as the cause
+func (d *drainMiddleware) cancelRunning() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, cancel := range d.running {
+		cancel(ErrWorkerDraining)
+	}
+}
+
+// Drain stops the manager gracefully: it stops fetching new jobs, snoozes fetched jobs that have
+// not started and waits up to DrainTimeout for running jobs to finish
+// Jobs still running at the deadline have their contexts cancelled and are snoozed
+func (m *Manager) Drain(ctx context.Context) error {
+	m.logger.Info("Draining worker manager", "timeout", m.config.DrainTimeout)
+	m.drainMiddleware.draining.Store(true)
+
+	if m.cancelCheckIns != nil {
+		m.cancelCheckIns()
+		m.cancelCheckIns = nil
+	}
+	m.checkIn.Store(0)
+
+	drainCtx := ctx
+	if m.config.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		drainCtx, cancel = context.WithTimeout(ctx, m.config.DrainTimeout)
+		defer cancel()
+	}
+
+	err := m.riverClient.Stop(drainCtx)
+	if err != nil && (errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)) {
+		m.logger.Warn("Drain deadline reached, cancelling running jobs")
+		// Cancel with the drain cause first so jobs can tell the drain from a job timeout
+		m.drainMiddleware.cancelRunning()
+		cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drainCancelWait)
+		defer cancel()
+		err = m.riverClient.StopAndCancel(cancelCtx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stop river client: %w", err)
+	}
+
+	if m.cancelDeadLetterHooks != nil {
+		m.cancelDeadLetterHooks()
+		m.cancelDeadLetterHooks = nil
+	}
+
+	m.logger.Info("Worker manager drained successfully")
+	return nil
+}
@@ -0,0 +1,82 @@
+package workerqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainMiddleware_WorksJobsUntilDraining(t *testing.T) {
+	middleware := &drainMiddleware{snoozeDelay: time.Second}
+	job := &rivertype.JobRow{ID: 1, Kind: "perform_run_execution_v2"}
+
+	worked := false
+	err := middleware.Work(context.Background(), job, func(ctx context.Context) error {
+		worked = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, worked)
+
+	// Jobs fetched after draining begins are snoozed without being worked
+	middleware.draining.Store(true)
+	worked = false
+	err = middleware.Work(context.Background(), job, func(ctx context.Context) error {
+		worked = true
+		return nil
+	})
+
+	var snooze *rivertype.JobSnoozeError
+	require.ErrorAs(t, err, &snooze)
+	assert.Equal(t, time.Second, snooze.Duration)
+	assert.False(t, worked)
+}
+
+func TestDrainMiddleware_SnoozesJobsCancelledByDrain(t *testing.T) {
+	middleware := &drainMiddleware{snoozeDelay: time.Second}
+	job := &rivertype.JobRow{ID: 1, Kind: "perform_run_execution_v2"}
+
+	err := middleware.Work(context.Background(), job, func(ctx context.Context) error {
+		// The drain deadline passes while the job is running
+		middleware.draining.Store(true)
+		middleware.cancelRunning()
+		assert.ErrorIs(t, context.Cause(ctx), ErrWorkerDraining)
+		return ctx.Err()
+	})
+
+	var snooze *rivertype.JobSnoozeError
+	assert.ErrorAs(t, err, &snooze)
+	assert.Empty(t, middleware.running)
+}
+
+func TestDrainMiddleware_KeepsJobTimeouts(t *testing.T) {
+	middleware := &drainMiddleware{snoozeDelay: time.Second}
+	job := &rivertype.JobRow{ID: 1, Kind: "perform_run_execution_v2"}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err := middleware.Work(ctx, job, func(ctx context.Context) error {
+		// The job timeout fires while the manager happens to be draining
+		<-ctx.Done()
+		middleware.draining.Store(true)
+		return ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDrainMiddleware_KeepsJobErrors(t *testing.T) {
+	middleware := &drainMiddleware{snoozeDelay: time.Second}
+	jobErr := errors.New("endpoint unavailable")
+
+	err := middleware.Work(context.Background(), &rivertype.JobRow{ID: 1}, func(ctx context.Context) error {
+		middleware.draining.Store(true)
+		return jobErr
+	})
+	assert.ErrorIs(t, err, jobErr)
+}
@@ -0,0 +1,116 @@
+// Package workerqueue schedules jobs fairly between tenants sharing a queue
+package workerqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// metadataKeyFairShare is the job metadata key holding the tenant a job is scheduled for
+const metadataKeyFairShare = "fair_share_key"
+
+// FairShareArgs is implemented by job args scheduled fairly between tenants
+// Jobs with the same key share one concurrency quota per queue
+type FairShareArgs interface {
+	FairShareKey() string
+}
+
+// fairShareMiddleware enforces per-tenant concurrency quotas within a queue
+// The tenant is recorded in job metadata at insertion; a job whose tenant already has its quota of
+// jobs running is snoozed, which moves it behind other tenants' available jobs
+type fairShareMiddleware struct {
+	river.MiddlewareDefaults
+
+	concurrency int
+	overrides   map[string]int
+	snoozeDelay time.Duration
+
+	// runningAhead counts the tenant's jobs running in the job's queue that were fetched before it
+	// Jobs fetched in one batch are ordered by ID, so a batch never snoozes all of its own jobs
+	runningAhead func(ctx context.Context, job *rivertype.JobRow, key string) (int, error)
+}
+
+var (
+	_ rivertype.JobInsertMiddleware = (*fairShareMiddleware)(nil)
+	_ rivertype.WorkerMiddleware    = (*fairShareMiddleware)(nil)
+)
+
+// newFairShareMiddleware creates a fair-share middleware counting running jobs in the River job table
+func newFairShareMiddleware(config Config, dbPool *pgxpool.Pool) *fairShareMiddleware {
+	table := riverTable(config.Schema, "river_job")
+	return &fairShareMiddleware{
+		concurrency: config.TenantConcurrency,
+		overrides:   config.TenantConcurrencyOverrides,
+		snoozeDelay: config.FairShareSnoozeDelay,
+		runningAhead: func(ctx context.Context, job *rivertype.JobRow, key string) (int, error) {
+			var count int
+			err := dbPool.QueryRow(ctx, fmt.Sprintf(`
+				SELECT COUNT(*) FROM %s
+				WHERE state = 'running' AND queue = $1 AND metadata->>'fair_share_key' = $2
+					AND (attempted_at, id) < ($3, $4)`, table),
+				job.Queue, key, job.AttemptedAt, job.ID,
+			).Scan(&count)
+			return count, err
+		},
+	}
+}
+
+// InsertMany records the fair-share key of each job whose args implement FairShareArgs
+func (f *fairShareMiddleware) InsertMany(ctx context.Context, manyParams []*rivertype.JobInsertParams, doInner func(context.Context) ([]*rivertype.JobInsertResult, error)) ([]*rivertype.JobInsertResult, error) {
+	for _, params := range manyParams {
+		args, ok := params.Args.(FairShareArgs)
+		if !ok || args.FairShareKey() == "" {
+			continue
+		}
+
+		metadata, err := setMetadataValue(params.Metadata, metadataKeyFairShare, args.FairShareKey())
+		if err != nil {
+			return nil, err
+		}
+		params.Metadata = metadata
+	}
+	return doInner(ctx)
+}
+
+// Work snoozes the job if its tenant already has its quota of jobs running in the queue
+func (f *fairShareMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(context.Context) error) error {
+	key := jobFairShareKey(job)
+	quota := f.quota(key)
+	if key == "" || quota <= 0 {
+		return doInner(ctx)
+	}
+
+	running, err := f.runningAhead(ctx, job, key)
+	if err != nil {
+		return fmt.Errorf("failed to count running jobs for %s: %w", key, err)
+	}
+	if running >= quota {
+		return river.JobSnooze(f.snoozeDelay)
+	}
+	return doInner(ctx)
+}
+
+// quota returns the concurrency quota of a tenant; 0 means unlimited
+func (f *fairShareMiddleware) quota(key string) int {
+	if quota, ok := f.overrides[key]; ok {
+		return quota
+	}
+	return f.concurrency
+}
+
+// jobFairShareKey returns the fair-share key recorded in a job's metadata
+func jobFairShareKey(job *rivertype.JobRow) string {
+	var metadata struct {
+		FairShareKey string `json:"fair_share_key"`
+	}
+	if len(job.Metadata) == 0 || json.Unmarshal(job.Metadata, &metadata) != nil {
+		return ""
+	}
+	return metadata.FairShareKey
+}
@@ -0,0 +1,139 @@
+package workerqueue_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kongflow/backend/internal/database"
+	"kongflow/backend/internal/services/workerqueue"
+	"kongflow/backend/internal/services/workerqueue/testutil"
+)
+
+// tenantArgs is an execution queue job scheduled fairly by tenant
+type tenantArgs struct {
+	Tenant string `json:"tenant"`
+}
+
+func (tenantArgs) Kind() string { return "test_tenant_job" }
+
+func (tenantArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{Queue: string(workerqueue.QueueExecution)}
+}
+
+func (a tenantArgs) FairShareKey() string { return a.Tenant }
+
+// tenantWorker records the jobs running per tenant and blocks until released
+type tenantWorker struct {
+	river.WorkerDefaults[tenantArgs]
+	release chan struct{}
+
+	mu         sync.Mutex
+	running    map[string]int
+	maxRunning map[string]int
+	started    map[string]int
+}
+
+func newTenantWorker() *tenantWorker {
+	return &tenantWorker{
+		release:    make(chan struct{}),
+		running:    make(map[string]int),
+		maxRunning: make(map[string]int),
+		started:    make(map[string]int),
+	}
+}
+
+func (w *tenantWorker) Work(ctx context.Context, job *river.Job[tenantArgs]) error {
+	tenant := job.Args.Tenant
+
+	w.mu.Lock()
+	w.started[tenant]++
+	w.running[tenant]++
+	if w.running[tenant] > w.maxRunning[tenant] {
+		w.maxRunning[tenant] = w.running[tenant]
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.release:
+	case <-ctx.Done():
+	}
+
+	w.mu.Lock()
+	w.running[tenant]--
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *tenantWorker) startedCount(tenant string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.started[tenant]
+}
+
+func (w *tenantWorker) maxRunningCount(tenant string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.maxRunning[tenant]
+}
+
+func TestFairShareScheduling_BurstDoesNotStarveOtherTenants(t *testing.T) {
+	ctx := context.Background()
+
+	testDB := database.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	worker := newTenantWorker()
+
+	config := workerqueue.DefaultConfig()
+	config.TestMode = true
+	config.ExecutionMaxWorkers = 3
+	config.TenantConcurrency = 1
+	config.TenantConcurrencyOverrides = map[string]int{"env_enterprise": 2}
+	config.FairShareSnoozeDelay = 200 * time.Millisecond
+
+	manager, err := workerqueue.NewManagerWithOptions(config, testDB.Pool, logger, workerqueue.ManagerOptions{
+		RegisterTasks: func(registry *workerqueue.TaskRegistry) error {
+			return workerqueue.RegisterTask(registry, worker, workerqueue.JobOptions{})
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, manager.EnsureRiverTables(ctx))
+
+	// A burst from one environment is enqueued ahead of the other environments' jobs
+	for i := 0; i < 20; i++ {
+		_, err := manager.EnqueueJob(ctx, "test_tenant_job", tenantArgs{Tenant: "env_burst"}, nil)
+		require.NoError(t, err)
+	}
+	for _, tenant := range []string{"env_quiet", "env_enterprise", "env_enterprise"} {
+		_, err := manager.EnqueueJob(ctx, "test_tenant_job", tenantArgs{Tenant: tenant}, nil)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, manager.Start(ctx))
+	defer func() {
+		close(worker.release)
+		assert.NoError(t, manager.Stop(ctx))
+	}()
+
+	testutil.WaitForJobCompletion(t, func() int {
+		return worker.startedCount("env_quiet")
+	}, 1, 10*time.Second)
+	testutil.WaitForJobCompletion(t, func() int {
+		return worker.startedCount("env_enterprise")
+	}, 1, 10*time.Second)
+
+	// The bursting environment never holds more than its quota of execution workers
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, 1, worker.maxRunningCount("env_burst"))
+	assert.Equal(t, 1, worker.startedCount("env_burst"))
+	assert.LessOrEqual(t, worker.maxRunningCount("env_enterprise"), 2)
+}
@@ -0,0 +1,102 @@
+package workerqueue
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFairShareMiddleware creates a middleware reporting fixed counts of running jobs ahead per tenant
+func newTestFairShareMiddleware(concurrency int, running map[string]int) *fairShareMiddleware {
+	return &fairShareMiddleware{
+		concurrency: concurrency,
+		overrides:   map[string]int{"env_enterprise": 5, "env_unlimited": 0},
+		snoozeDelay: time.Second,
+		runningAhead: func(ctx context.Context, job *rivertype.JobRow, key string) (int, error) {
+			return running[key], nil
+		},
+	}
+}
+
+func TestFairShareMiddleware_RecordsKeyOnInsert(t *testing.T) {
+	middleware := newTestFairShareMiddleware(1, nil)
+
+	execution := &rivertype.JobInsertParams{
+		Args:     PerformRunExecutionV2Args{ID: "run_1", EnvironmentID: "env_1"},
+		Metadata: []byte(`{"otel":{"traceparent":"00-1"}}`),
+	}
+	email := &rivertype.JobInsertParams{Args: ScheduleEmailArgs{To: "user@example.com"}}
+	_, err := middleware.InsertMany(context.Background(), []*rivertype.JobInsertParams{execution, email}, func(ctx context.Context) ([]*rivertype.JobInsertResult, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(execution.Metadata, &metadata))
+	assert.Equal(t, "env_1", metadata[metadataKeyFairShare])
+	assert.NotNil(t, metadata[metadataKeyTraceContext])
+	assert.Nil(t, email.Metadata)
+}
+
+func TestFairShareMiddleware_SnoozesTenantsOverQuota(t *testing.T) {
+	middleware := newTestFairShareMiddleware(2, map[string]int{
+		"env_busy":       2,
+		"env_quiet":      1,
+		"env_enterprise": 4,
+		"env_unlimited":  50,
+	})
+
+	tests := []struct {
+		key     string
+		snoozed bool
+	}{
+		{key: "env_busy", snoozed: true},
+		{key: "env_quiet", snoozed: false},
+		{key: "env_enterprise", snoozed: false},
+		{key: "env_unlimited", snoozed: false},
+		{key: "", snoozed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			job := &rivertype.JobRow{ID: 1, Queue: string(QueueExecution)}
+			if tt.key != "" {
+				job.Metadata = []byte(`{"fair_share_key":"` + tt.key + `"}`)
+			}
+
+			worked := false
+			err := middleware.Work(context.Background(), job, func(ctx context.Context) error {
+				worked = true
+				return nil
+			})
+
+			if tt.snoozed {
+				var snooze *rivertype.JobSnoozeError
+				require.ErrorAs(t, err, &snooze)
+				assert.Equal(t, time.Second, snooze.Duration)
+				assert.False(t, worked)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, worked)
+		})
+	}
+}
+
+func TestFairShareMiddleware_DisabledByDefault(t *testing.T) {
+	middleware := newFairShareMiddleware(DefaultConfig(), nil)
+	job := &rivertype.JobRow{ID: 1, Metadata: []byte(`{"fair_share_key":"env_busy"}`)}
+
+	worked := false
+	err := middleware.Work(context.Background(), job, func(ctx context.Context) error {
+		worked = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, worked)
+}
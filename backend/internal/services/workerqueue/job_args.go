@@ -44,6 +44,12 @@ func (IndexEndpointArgs) InsertOpts() river.InsertOpts {
 	}
 }
 
+// withJobKey sets the job key used for uniqueness checks
+func (a IndexEndpointArgs) withJobKey(jobKey string) JobArgs {
+	a.JobKey = jobKey
+	return a
+}
+
 // StartRunArgs represents arguments for starting a job run
 // This corresponds to trigger.dev's startRun worker task
 type StartRunArgs struct {
@@ -142,6 +148,12 @@ type PerformRunExecutionV2Args struct {
 	// UserID for user-level routing context (Phase 2)
 	UserID string `json:"userId,omitempty"`
 
+	// EnvironmentID is the run's environment; executions are scheduled fairly between environments
+	EnvironmentID string `json:"environmentId,omitempty"`
+
+	// EndpointID is the endpoint executing the run, usable as a rate limit key
+	EndpointID string `json:"endpointId,omitempty"`
+
 	// Reason indicates why this execution is happening
 	Reason ExecutionReason `json:"reason"`
 
@@ -150,6 +162,10 @@ type PerformRunExecutionV2Args struct {
 
 	// IsRetry indicates if this is a retry attempt
 	IsRetry bool `json:"is_retry"`
+
+	// ExecutionCount is the number of executions already performed for the run
+	// 区分同一运行的多次执行，避免被唯一性约束去重
+	ExecutionCount int `json:"execution_count,omitempty"`
 }
 
 // Kind returns the unique identifier for this job type
@@ -157,6 +173,11 @@ func (PerformRunExecutionV2Args) Kind() string {
 	return "perform_run_execution_v2"
 }
 
+// FairShareKey schedules executions fairly between environments
+func (a PerformRunExecutionV2Args) FairShareKey() string {
+	return a.EnvironmentID
+}
+
 // InsertOpts provides default insertion options for run execution jobs
 func (PerformRunExecutionV2Args) InsertOpts() river.InsertOpts {
 	return river.InsertOpts{
@@ -207,6 +228,12 @@ func (ScheduleEmailArgs) InsertOpts() river.InsertOpts {
 	}
 }
 
+// withJobKey sets the job key used for uniqueness checks
+func (a ScheduleEmailArgs) withJobKey(jobKey string) JobArgs {
+	a.JobKey = jobKey
+	return a
+}
+
 // StartQueuedRunsArgs represents arguments for starting queued runs
 // This corresponds to trigger.dev's startQueuedRuns worker task
 // ✅ Phase 2 implementation for project-level queue isolation
@@ -214,6 +241,12 @@ type StartQueuedRunsArgs struct {
 	// ProjectID enables project-level queue isolation
 	ProjectID string `json:"projectId"`
 
+	// QueueID is the job queue (job_queues.id) whose waiting runs should be started
+	QueueID string `json:"queueId,omitempty"`
+
+	// FinishedRunID is the run whose concurrency slot is released before starting
+	FinishedRunID string `json:"finishedRunId,omitempty"`
+
 	// UserID for additional routing context
 	UserID string `json:"userId,omitempty"`
 
@@ -239,7 +272,7 @@ func (StartQueuedRunsArgs) InsertOpts() river.InsertOpts {
 		Priority:    int(PriorityNormal),
 		MaxAttempts: 3, // matches trigger.dev
 		UniqueOpts: river.UniqueOpts{
-			ByArgs:   true,             // 防止相同项目的重复调度
+			ByArgs:   true,             // 同一运行结束只释放并调度一次
 			ByPeriod: 10 * time.Minute, // 10分钟内相同项目不重复
 		},
 	}
@@ -411,3 +444,90 @@ func (RegisterDynamicScheduleArgs) InsertOpts() river.InsertOpts {
 		},
 	}
 }
+
+// RefreshIntegrationConnectionsArgs represents arguments for the periodic OAuth token refresh
+// Connections expiring within Window are refreshed before they lapse
+type RefreshIntegrationConnectionsArgs struct {
+	// Window is how far ahead of expiry connections are refreshed
+	Window time.Duration `json:"window"`
+}
+
+// Kind returns the unique identifier for this job type
+func (RefreshIntegrationConnectionsArgs) Kind() string {
+	return "refresh_integration_connections"
+}
+
+// InsertOpts provides default insertion options for connection refresh jobs
+func (RefreshIntegrationConnectionsArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue:       string(QueueMaintenance),
+		Priority:    int(PriorityLow),
+		MaxAttempts: 3,
+		UniqueOpts: river.UniqueOpts{
+			ByPeriod: time.Minute, // 同一分钟内只刷新一次
+		},
+	}
+}
+
+// NotifyRunFailedArgs represents arguments for notifying organization members about a failed run
+type NotifyRunFailedArgs struct {
+	// RunID is the failed run
+	RunID string `json:"runId"`
+}
+
+// Kind returns the unique identifier for this job type
+func (NotifyRunFailedArgs) Kind() string {
+	return "notify_run_failed"
+}
+
+// InsertOpts provides default insertion options for run failure notifications
+func (NotifyRunFailedArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue:       string(QueueDefault),
+		Priority:    int(PriorityLow),
+		MaxAttempts: 3,
+		UniqueOpts: river.UniqueOpts{
+			ByArgs: true, // 同一运行只通知一次
+		},
+	}
+}
+
+// TimeoutRunsArgs represents arguments for the periodic run timeout watchdog
+type TimeoutRunsArgs struct{}
+
+// Kind returns the unique identifier for this job type
+func (TimeoutRunsArgs) Kind() string {
+	return "timeout_runs"
+}
+
+// InsertOpts provides default insertion options for run timeout jobs
+func (TimeoutRunsArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue:       string(QueueMaintenance),
+		Priority:    int(PriorityLow),
+		MaxAttempts: 3,
+		UniqueOpts: river.UniqueOpts{
+			ByPeriod: time.Minute, // 同一分钟内只巡检一次
+		},
+	}
+}
+
+// SweepDeadLetterJobsArgs represents arguments for the periodic dead-letter sweep
+type SweepDeadLetterJobsArgs struct{}
+
+// Kind returns the unique identifier for this job type
+func (SweepDeadLetterJobsArgs) Kind() string {
+	return "sweep_dead_letter_jobs"
+}
+
+// InsertOpts provides default insertion options for dead-letter sweep jobs
+func (SweepDeadLetterJobsArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue:       string(QueueMaintenance),
+		Priority:    int(PriorityLow),
+		MaxAttempts: 3,
+		UniqueOpts: river.UniqueOpts{
+			ByPeriod: time.Minute, // 多个 worker 进程同一分钟内只清扫一次
+		},
+	}
+}
@@ -3,9 +3,10 @@ package workerqueue
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -27,42 +28,6 @@ func (w *TestWorker) Work(ctx context.Context, job *river.Job[IndexEndpointArgs]
 	return nil
 }
 
-// StartRunWorker handles start run jobs
-type StartRunWorker struct {
-	river.WorkerDefaults[StartRunArgs]
-	logger *slog.Logger
-}
-
-// Work processes start run jobs
-func (w *StartRunWorker) Work(ctx context.Context, job *river.Job[StartRunArgs]) error {
-	w.logger.Info("Processing start run job", "job_id", job.ID, "args", job.Args)
-	return nil
-}
-
-// DeliverEventWorker handles event delivery jobs
-type DeliverEventWorker struct {
-	river.WorkerDefaults[DeliverEventArgs]
-	logger *slog.Logger
-}
-
-// Work processes event delivery jobs
-func (w *DeliverEventWorker) Work(ctx context.Context, job *river.Job[DeliverEventArgs]) error {
-	w.logger.Info("Processing deliver event job", "job_id", job.ID, "args", job.Args)
-	return nil
-}
-
-// InvokeDispatcherWorker handles dispatcher invocation jobs
-type InvokeDispatcherWorker struct {
-	river.WorkerDefaults[InvokeDispatcherArgs]
-	logger *slog.Logger
-}
-
-// Work processes dispatcher invocation jobs
-func (w *InvokeDispatcherWorker) Work(ctx context.Context, job *river.Job[InvokeDispatcherArgs]) error {
-	w.logger.Info("Processing invoke dispatcher job", "job_id", job.ID, "args", job.Args)
-	return nil
-}
-
 // EmailSender defines the interface for sending emails
 // This avoids circular dependencies with the email package
 type EmailSender interface {
@@ -123,11 +88,23 @@ func (w *ScheduleEmailWorker) Work(ctx context.Context, job *river.Job[ScheduleE
 type Manager struct {
 	riverClient *river.Client[pgx.Tx]
 	dbPool      *pgxpool.Pool
-	workers     *river.Workers
+	registry    *TaskRegistry
 	config      Config
 	logger      *slog.Logger
 	emailSender EmailSender
 
+	deadLetterHook        DeadLetterHook
+	cancelDeadLetterHooks func()
+
+	// workerMiddleware counts active jobs and reports JobMetrics; checkIn backs Health
+	workerMiddleware *workerMiddleware
+	drainMiddleware  *drainMiddleware
+	checkIn          atomic.Int64
+	cancelCheckIns   func()
+
+	// rateLimitMiddleware enforces the rate limits set through SetRateLimit
+	rateLimitMiddleware *rateLimitMiddleware
+
 	// Future: can add SQLC support when needed
 	// sqlcQueries *database.Queries
 }
@@ -140,28 +117,95 @@ func NewManager(config Config, dbPool *pgxpool.Pool, logger *slog.Logger, emailS
 
 // NewManagerWithIndexer creates a new worker manager with an optional EndpointIndexer for testing
 func NewManagerWithIndexer(config Config, dbPool *pgxpool.Pool, logger *slog.Logger, emailSender EmailSender, indexer EndpointIndexer) (*Manager, error) {
+	return NewManagerWithOptions(config, dbPool, logger, ManagerOptions{
+		EmailSender: emailSender,
+		Indexer:     indexer,
+	})
+}
+
+// ManagerOptions holds the optional service dependencies injected into workers
+// All fields can be nil; workers without a dependency fall back to logging only
+type ManagerOptions struct {
+	EmailSender         EmailSender
+	Indexer             EndpointIndexer
+	RunExecutor         RunExecutor
+	ConnectionRefresher ConnectionRefresher
+	RunTimeoutEnforcer  RunTimeoutEnforcer
+	RunFailureNotifier  RunFailureNotifier
+	EventProcessor      EventProcessor
+
+	// JobMetrics records the outcome and duration of every job worked by this manager
+	JobMetrics JobMetrics
+
+	// DeadLetterHook receives jobs discarded after exhausting their attempts, e.g. a DeadLetterStore
+	DeadLetterHook DeadLetterHook
+
+	// RegisterTasks registers service-owned tasks on top of the built-in ones
+	RegisterTasks func(registry *TaskRegistry) error
+
+	// RateLimits rate-limits jobs by kind or alias once the manager is created, e.g. DefaultRateLimits()
+	RateLimits map[string]RateLimit
+}
+
+// NewManagerWithOptions creates a new worker manager with the given service dependencies
+func NewManagerWithOptions(config Config, dbPool *pgxpool.Pool, logger *slog.Logger, opts ManagerOptions) (*Manager, error) {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	emailSender := opts.EmailSender
 
-	workers := river.NewWorkers()
+	registry := NewTaskRegistry()
+	if err := registerBuiltinTasks(registry, config, opts, logger); err != nil {
+		return nil, err
+	}
+	if opts.RegisterTasks != nil {
+		if err := opts.RegisterTasks(registry); err != nil {
+			return nil, fmt.Errorf("failed to register tasks: %w", err)
+		}
+	}
 
-	// Register all workers to satisfy River's requirement
-	if indexer != nil {
-		// Use real IndexEndpointWorker if indexer is provided (for testing)
-		river.AddWorker(workers, NewIndexEndpointWorker(indexer, logger))
-	} else {
-		// Use TestWorker for production/default case
-		river.AddWorker(workers, &TestWorker{logger: logger})
+	// Integration connections are refreshed periodically on the maintenance queue
+	var periodicJobs []*river.PeriodicJob
+	if opts.ConnectionRefresher != nil && config.ConnectionRefreshInterval > 0 {
+		window := 2 * config.ConnectionRefreshInterval
+		periodicJobs = append(periodicJobs, river.NewPeriodicJob(
+			river.PeriodicInterval(config.ConnectionRefreshInterval),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return RefreshIntegrationConnectionsArgs{Window: window}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		))
 	}
 
-	river.AddWorker(workers, &StartRunWorker{logger: logger})
-	river.AddWorker(workers, &DeliverEventWorker{logger: logger})
-	river.AddWorker(workers, &InvokeDispatcherWorker{logger: logger})
-	river.AddWorker(workers, &ScheduleEmailWorker{logger: logger, emailSender: emailSender})
+	// Runs exceeding their maximum duration are timed out by a periodic watchdog
+	if opts.RunTimeoutEnforcer != nil && config.RunTimeoutCheckInterval > 0 {
+		periodicJobs = append(periodicJobs, river.NewPeriodicJob(
+			river.PeriodicInterval(config.RunTimeoutCheckInterval),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return TimeoutRunsArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		))
+	}
+
+	// Discarded jobs missed by the hook's subscription are copied by a periodic sweep
+	if _, ok := opts.DeadLetterHook.(DeadLetterSweeper); ok && config.DeadLetterSweepInterval > 0 {
+		periodicJobs = append(periodicJobs, river.NewPeriodicJob(
+			river.PeriodicInterval(config.DeadLetterSweepInterval),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return SweepDeadLetterJobsArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		))
+	}
+
+	middleware := newWorkerMiddleware(opts.JobMetrics, config.QueueLimitSnoozeDelay)
+	drain := &drainMiddleware{snoozeDelay: config.DrainSnoozeDelay}
+	rateLimits := newRateLimitMiddleware(dbPool)
 
 	riverConfig := &river.Config{
-		Logger: logger,
+		Logger:     logger,
+		Middleware: []rivertype.Middleware{&traceMiddleware{}, middleware, drain, newFairShareMiddleware(config, dbPool), rateLimits},
 		Queues: map[string]river.QueueConfig{
 			string(QueueDefault): {
 				MaxWorkers: config.MaxWorkers,
@@ -176,7 +220,8 @@ func NewManagerWithIndexer(config Config, dbPool *pgxpool.Pool, logger *slog.Log
 				MaxWorkers: config.MaintenanceMaxWorkers,
 			},
 		},
-		Workers:           workers,
+		Workers:           registry.Workers(),
+		PeriodicJobs:      periodicJobs,
 		JobTimeout:        config.JobTimeout,
 		FetchCooldown:     config.FetchCooldown,
 		FetchPollInterval: config.FetchPollInterval,
@@ -189,14 +234,49 @@ func NewManagerWithIndexer(config Config, dbPool *pgxpool.Pool, logger *slog.Log
 		return nil, fmt.Errorf("failed to create river client: %w", err)
 	}
 
-	return &Manager{
-		riverClient: riverClient,
-		dbPool:      dbPool,
-		workers:     workers,
-		config:      config,
-		logger:      logger,
-		emailSender: emailSender,
-	}, nil
+	m := &Manager{
+		riverClient:         riverClient,
+		dbPool:              dbPool,
+		registry:            registry,
+		config:              config,
+		logger:              logger,
+		emailSender:         emailSender,
+		deadLetterHook:      opts.DeadLetterHook,
+		workerMiddleware:    middleware,
+		drainMiddleware:     drain,
+		rateLimitMiddleware: rateLimits,
+	}
+
+	for kind, limit := range opts.RateLimits {
+		if err := m.SetRateLimit(kind, limit); err != nil {
+			return nil, fmt.Errorf("failed to set rate limit: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// registerBuiltinTasks registers the workers owned by the worker queue itself
+// trigger.dev style camelCase identifiers are kept as aliases of the River kinds
+func registerBuiltinTasks(registry *TaskRegistry, config Config, opts ManagerOptions, logger *slog.Logger) error {
+	var indexWorker river.Worker[IndexEndpointArgs] = &TestWorker{logger: logger}
+	if opts.Indexer != nil {
+		indexWorker = NewIndexEndpointWorker(opts.Indexer, logger)
+	}
+	sweeper, _ := opts.DeadLetterHook.(DeadLetterSweeper)
+
+	return errors.Join(
+		RegisterTask(registry, indexWorker, JobOptions{}, "indexEndpoint"),
+		RegisterTask(registry, NewStartRunWorker(opts.RunExecutor, logger), JobOptions{}, "startRun"),
+		RegisterTask(registry, NewPerformRunExecutionV2Worker(opts.RunExecutor, logger), JobOptions{}, "performRunExecutionV2"),
+		RegisterTask(registry, NewStartQueuedRunsWorker(opts.RunExecutor, logger), JobOptions{}, "startQueuedRuns"),
+		RegisterTask(registry, NewDeliverEventWorker(opts.EventProcessor, logger), JobOptions{}, "deliverEvent"),
+		RegisterTask(registry, NewInvokeDispatcherWorker(opts.EventProcessor, logger), JobOptions{}, "invokeDispatcher", "events.invokeDispatcher"),
+		RegisterTask(registry, &ScheduleEmailWorker{logger: logger, emailSender: opts.EmailSender}, JobOptions{}, "scheduleEmail"),
+		RegisterTask(registry, NewRefreshIntegrationConnectionsWorker(opts.ConnectionRefresher, logger), JobOptions{}),
+		RegisterTask(registry, NewTimeoutRunsWorker(opts.RunTimeoutEnforcer, logger), JobOptions{}),
+		RegisterTask(registry, NewNotifyRunFailedWorker(opts.RunFailureNotifier, logger), JobOptions{}),
+		RegisterTask(registry, NewSweepDeadLetterJobsWorker(sweeper, config.Schema, logger), JobOptions{}),
+	)
 }
 
 // SetEmailSender sets the email sender for the manager after creation
@@ -215,24 +295,32 @@ func (m *Manager) Start(ctx context.Context) error {
 		"events_workers", m.config.EventsMaxWorkers,
 	)
 
+	// Discarded jobs are forwarded to the dead-letter hook as they happen
+	if m.deadLetterHook != nil && m.cancelDeadLetterHooks == nil {
+		events, cancel := m.riverClient.Subscribe(river.EventKindJobFailed)
+		m.cancelDeadLetterHooks = cancel
+		go forwardDiscardedJobs(events, m.deadLetterHook, m.logger)
+	}
+
+	m.drainMiddleware.draining.Store(false)
 	if err := m.riverClient.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start river client: %w", err)
 	}
 
+	// The client checks in periodically so Health can detect a stalled process
+	if m.cancelCheckIns == nil && m.config.HeartbeatInterval > 0 {
+		checkInCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		m.cancelCheckIns = cancel
+		go m.runCheckIns(checkInCtx)
+	}
+
 	m.logger.Info("Worker manager started successfully")
 	return nil
 }
 
-// Stop gracefully stops the worker manager
+// Stop gracefully stops the worker manager, draining running jobs as described by Drain
 func (m *Manager) Stop(ctx context.Context) error {
-	m.logger.Info("Stopping worker manager")
-
-	if err := m.riverClient.Stop(ctx); err != nil {
-		return fmt.Errorf("failed to stop river client: %w", err)
-	}
-
-	m.logger.Info("Worker manager stopped successfully")
-	return nil
+	return m.Drain(ctx)
 }
 
 // WithTransaction provides SQLC + River transaction support
@@ -260,13 +348,11 @@ func (m *Manager) WithTransaction(ctx context.Context, fn BusinessLogicFunc) err
 // EnqueueInTransaction inserts a job within an existing transaction
 // This aligns with trigger.dev's transaction semantics
 func (m *Manager) EnqueueInTransaction(ctx context.Context, txCtx *TransactionContext, identifier string, payload interface{}, opts *JobOptions) (*rivertype.JobInsertResult, error) {
-	args, err := m.createJobArgsWithJobKey(identifier, payload, opts)
+	args, riverOpts, err := m.prepareJob(identifier, payload, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	riverOpts := m.convertToRiverOpts(opts)
-
 	// Use River's transaction support
 	return m.riverClient.InsertTx(ctx, txCtx.Tx, args, riverOpts)
 }
@@ -288,26 +374,44 @@ func (m *Manager) InsertJobTx(ctx context.Context, tx pgx.Tx, args JobArgs, opts
 
 // EnqueueJob inserts a job into the queue using string identifier (trigger.dev compatible)
 func (m *Manager) EnqueueJob(ctx context.Context, identifier string, payload interface{}, opts *JobOptions) (*rivertype.JobInsertResult, error) {
-	args, err := m.createJobArgsWithJobKey(identifier, payload, opts)
+	args, riverOpts, err := m.prepareJob(identifier, payload, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create job args: %w", err)
+		return nil, err
 	}
-
-	riverOpts := m.convertToRiverOpts(opts)
 	return m.riverClient.Insert(ctx, args, riverOpts)
 }
 
 // EnqueueJobTx inserts a job into the queue within a transaction using string identifier
 func (m *Manager) EnqueueJobTx(ctx context.Context, tx pgx.Tx, identifier string, payload interface{}, opts *JobOptions) (*rivertype.JobInsertResult, error) {
-	args, err := m.createJobArgsWithJobKey(identifier, payload, opts)
+	args, riverOpts, err := m.prepareJob(identifier, payload, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create job args: %w", err)
+		return nil, err
 	}
-
-	riverOpts := m.convertToRiverOpts(opts)
 	return m.riverClient.InsertTx(ctx, tx, args, riverOpts)
 }
 
+// CancelJobsByArgTx cancels jobs of the given kind that have not started yet and whose
+// args field matches value, returning the number of cancelled jobs
+func (m *Manager) CancelJobsByArgTx(ctx context.Context, tx pgx.Tx, kind, field, value string) (int, error) {
+	params := river.NewJobListParams().
+		Kinds(kind).
+		States(rivertype.JobStateAvailable, rivertype.JobStatePending, rivertype.JobStateRetryable, rivertype.JobStateScheduled).
+		Where("args->>@field = @value", river.NamedArgs{"field": field, "value": value}).
+		First(1000)
+
+	result, err := m.riverClient.JobListTx(ctx, tx, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list %s jobs: %w", kind, err)
+	}
+
+	for _, job := range result.Jobs {
+		if _, err := m.riverClient.JobCancelTx(ctx, tx, job.ID); err != nil {
+			return 0, fmt.Errorf("failed to cancel job %d: %w", job.ID, err)
+		}
+	}
+	return len(result.Jobs), nil
+}
+
 // DequeueJob cancels a job by job key (placeholder implementation)
 func (m *Manager) DequeueJob(ctx context.Context, jobKey string) error {
 	// River doesn't have direct dequeue functionality
@@ -316,125 +420,36 @@ func (m *Manager) DequeueJob(ctx context.Context, jobKey string) error {
 	return fmt.Errorf("dequeue functionality not yet implemented for job key: %s", jobKey)
 }
 
-// Health returns the health status of the worker manager
-func (m *Manager) Health() bool {
-	// For now, we'll consider the manager healthy if the client exists
-	return m.riverClient != nil
-}
+// Helper methods for job insertion
 
-// Stats returns basic statistics about the worker manager
-func (m *Manager) Stats() map[string]interface{} {
-	return map[string]interface{}{
-		"config": map[string]interface{}{
-			"max_workers":             m.config.MaxWorkers,
-			"execution_max_workers":   m.config.ExecutionMaxWorkers,
-			"events_max_workers":      m.config.EventsMaxWorkers,
-			"maintenance_max_workers": m.config.MaintenanceMaxWorkers,
-			"job_timeout":             m.config.JobTimeout.String(),
-			"fetch_cooldown":          m.config.FetchCooldown.String(),
-		},
+// prepareJob validates identifier against the task registry, decodes payload into the task's
+// args type and merges opts over the task's default options
+func (m *Manager) prepareJob(identifier string, payload interface{}, opts *JobOptions) (JobArgs, *river.InsertOpts, error) {
+	task, err := m.registry.Lookup(identifier)
+	if err != nil {
+		return nil, nil, err
 	}
-}
-
-// Helper methods for job insertion
 
-// createJobArgsWithJobKey converts identifier and payload to appropriate job args and sets JobKey if provided
-func (m *Manager) createJobArgsWithJobKey(identifier string, payload interface{}, opts *JobOptions) (JobArgs, error) {
-	args, err := m.createJobArgs(identifier, payload)
+	args, err := task.NewArgs(payload)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("failed to create job args for %s: %w", task.Kind, err)
 	}
 
-	// Set JobKey for IndexEndpointArgs if provided in options
-	if opts != nil && opts.JobKey != "" {
-		if indexArgs, ok := args.(IndexEndpointArgs); ok {
-			indexArgs.JobKey = opts.JobKey
-			return indexArgs, nil
+	merged := mergeJobOptions(task.Defaults, opts)
+
+	// Args carrying a job key use it for uniqueness (similar to trigger.dev's jobKey)
+	if merged.JobKey != "" {
+		if keyed, ok := args.(jobKeyArgs); ok {
+			args = keyed.withJobKey(merged.JobKey)
 		}
-		// For other job types, JobKey might not be supported yet
-		// This is similar to trigger.dev where not all job types support uniqueness
 	}
 
-	return args, nil
+	return args, m.convertToRiverOpts(merged), nil
 }
 
-// createJobArgs converts identifier and payload to appropriate job args
-func (m *Manager) createJobArgs(identifier string, payload interface{}) (JobArgs, error) {
-	switch identifier {
-	case "indexEndpoint", "index_endpoint":
-		// Convert payload to IndexEndpointArgs
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		var args IndexEndpointArgs
-		if err := json.Unmarshal(data, &args); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal to IndexEndpointArgs: %w", err)
-		}
-		return args, nil
-	case "startRun", "start_run":
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		var args StartRunArgs
-		if err := json.Unmarshal(data, &args); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal to StartRunArgs: %w", err)
-		}
-		return args, nil
-	case "invokeDispatcher", "invoke_dispatcher":
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		var args InvokeDispatcherArgs
-		if err := json.Unmarshal(data, &args); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal to InvokeDispatcherArgs: %w", err)
-		}
-		return args, nil
-	case "deliverEvent":
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		var args DeliverEventArgs
-		if err := json.Unmarshal(data, &args); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal to DeliverEventArgs: %w", err)
-		}
-		return args, nil
-	case "performRunExecutionV2":
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		var args PerformRunExecutionV2Args
-		if err := json.Unmarshal(data, &args); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal to PerformRunExecutionV2Args: %w", err)
-		}
-		return args, nil
-	case "scheduleEmail":
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		var args ScheduleEmailArgs
-		if err := json.Unmarshal(data, &args); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal to ScheduleEmailArgs: %w", err)
-		}
-		return args, nil
-	case "events.invokeDispatcher":
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		var args InvokeDispatcherArgs
-		if err := json.Unmarshal(data, &args); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal to InvokeDispatcherArgs: %w", err)
-		}
-		return args, nil
-	default:
-		return nil, fmt.Errorf("unknown job identifier: %s", identifier)
-	}
+// Registry returns the task registry backing identifier-based enqueueing
+func (m *Manager) Registry() *TaskRegistry {
+	return m.registry
 }
 
 // convertToRiverOpts converts JobOptions to River InsertOpts
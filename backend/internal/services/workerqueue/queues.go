@@ -0,0 +1,182 @@
+// Package workerqueue provides admin operations for pausing and reconfiguring queues at runtime
+package workerqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// queueMetadataMaxWorkers is the River queue metadata key holding a runtime MaxWorkers limit
+const queueMetadataMaxWorkers = "max_workers"
+
+var (
+	// ErrUnknownQueue is returned when controlling a queue the manager does not work
+	ErrUnknownQueue = errors.New("unknown queue")
+
+	// ErrInvalidMaxWorkers is returned when a runtime MaxWorkers limit is out of range
+	ErrInvalidMaxWorkers = errors.New("invalid max workers")
+)
+
+// QueueControl is the runtime state of a queue shared by every worker process
+type QueueControl struct {
+	Queue    string     `json:"queue"`
+	Paused   bool       `json:"paused"`
+	PausedAt *time.Time `json:"pausedAt,omitempty"`
+
+	// ConfiguredMaxWorkers is the MaxWorkers the manager was started with
+	ConfiguredMaxWorkers int `json:"configuredMaxWorkers"`
+
+	// MaxWorkers is the effective limit: the runtime limit when set, otherwise ConfiguredMaxWorkers
+	MaxWorkers int `json:"maxWorkers"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// queueMetadata is the part of River queue metadata owned by the manager
+type queueMetadata struct {
+	MaxWorkers int `json:"max_workers,omitempty"`
+}
+
+// PauseQueue stops every worker process from fetching jobs from queue
+// The pause is persisted in River's queue table, so it survives restarts until ResumeQueue
+func (m *Manager) PauseQueue(ctx context.Context, queue string) error {
+	if err := m.ensureQueue(ctx, queue); err != nil {
+		return err
+	}
+	if err := m.riverClient.QueuePause(ctx, queue, nil); err != nil {
+		return fmt.Errorf("failed to pause queue %s: %w", queue, err)
+	}
+
+	m.logger.Info("Queue paused", "queue", queue)
+	return nil
+}
+
+// ResumeQueue lets worker processes fetch jobs from a paused queue again
+func (m *Manager) ResumeQueue(ctx context.Context, queue string) error {
+	if err := m.ensureQueue(ctx, queue); err != nil {
+		return err
+	}
+	if err := m.riverClient.QueueResume(ctx, queue, nil); err != nil {
+		return fmt.Errorf("failed to resume queue %s: %w", queue, err)
+	}
+
+	m.logger.Info("Queue resumed", "queue", queue)
+	return nil
+}
+
+// SetQueueMaxWorkers limits how many jobs each worker process works at once from queue
+// River fixes a queue's worker pool when the client starts, so the limit can only lower the
+// configured MaxWorkers; 0 removes the limit. Other processes apply it on their next check-in
+func (m *Manager) SetQueueMaxWorkers(ctx context.Context, queue string, maxWorkers int) (*QueueControl, error) {
+	configured, ok := m.queueMaxWorkers()[queue]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownQueue, queue)
+	}
+	if maxWorkers < 0 || maxWorkers > configured {
+		return nil, fmt.Errorf("%w: %d is outside 0-%d for queue %s", ErrInvalidMaxWorkers, maxWorkers, configured, queue)
+	}
+	if err := m.ensureQueue(ctx, queue); err != nil {
+		return nil, err
+	}
+
+	current, err := m.riverClient.QueueGet(ctx, queue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue %s: %w", queue, err)
+	}
+	metadata, err := setMetadataValue(current.Metadata, queueMetadataMaxWorkers, maxWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := m.riverClient.QueueUpdate(ctx, queue, &river.QueueUpdateParams{Metadata: metadata})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update queue %s: %w", queue, err)
+	}
+	m.applyQueueLimits([]*rivertype.Queue{updated})
+
+	m.logger.Info("Queue max workers updated", "queue", queue, "max_workers", maxWorkers)
+	control := m.queueControl(updated)
+	return &control, nil
+}
+
+// ListQueueControls returns the runtime state of every queue the manager works
+func (m *Manager) ListQueueControls(ctx context.Context) ([]QueueControl, error) {
+	result, err := m.riverClient.QueueList(ctx, river.NewQueueListParams().First(len(m.queueMaxWorkers())+100))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queues: %w", err)
+	}
+
+	known := make(map[string]*rivertype.Queue, len(result.Queues))
+	for _, queue := range result.Queues {
+		known[queue.Name] = queue
+	}
+
+	controls := make([]QueueControl, 0, len(m.queueMaxWorkers()))
+	for _, name := range []JobQueue{QueueDefault, QueueExecution, QueueEvents, QueueMaintenance} {
+		queue, ok := known[string(name)]
+		if !ok {
+			// Queues are recorded once a worker process starts working them
+			queue = &rivertype.Queue{Name: string(name)}
+		}
+		controls = append(controls, m.queueControl(queue))
+	}
+	return controls, nil
+}
+
+// ensureQueue records queue in River's queue table so it can be controlled before any worker
+// process has started working it
+func (m *Manager) ensureQueue(ctx context.Context, queue string) error {
+	if _, ok := m.queueMaxWorkers()[queue]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, queue)
+	}
+
+	_, err := m.dbPool.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s (name, metadata, created_at, updated_at)
+		VALUES ($1, '{}', NOW(), NOW())
+		ON CONFLICT (name) DO NOTHING`, m.riverTable("river_queue")),
+		queue,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record queue %s: %w", queue, err)
+	}
+	return nil
+}
+
+// applyQueueLimits applies the runtime MaxWorkers limits recorded in queue metadata
+func (m *Manager) applyQueueLimits(queues []*rivertype.Queue) {
+	for _, queue := range queues {
+		m.workerMiddleware.setLimit(queue.Name, queueMaxWorkersLimit(queue))
+	}
+}
+
+// queueControl describes queue using the manager's configured worker counts
+func (m *Manager) queueControl(queue *rivertype.Queue) QueueControl {
+	configured := m.queueMaxWorkers()[queue.Name]
+	control := QueueControl{
+		Queue:                queue.Name,
+		Paused:               queue.PausedAt != nil,
+		PausedAt:             queue.PausedAt,
+		ConfiguredMaxWorkers: configured,
+		MaxWorkers:           configured,
+		UpdatedAt:            queue.UpdatedAt,
+	}
+	if limit := queueMaxWorkersLimit(queue); limit > 0 && limit < configured {
+		control.MaxWorkers = limit
+	}
+	return control
+}
+
+// queueMaxWorkersLimit returns the runtime MaxWorkers limit recorded in queue metadata, or 0
+func queueMaxWorkersLimit(queue *rivertype.Queue) int {
+	var metadata queueMetadata
+	if len(queue.Metadata) == 0 || json.Unmarshal(queue.Metadata, &metadata) != nil {
+		return 0
+	}
+	return metadata.MaxWorkers
+}
@@ -0,0 +1,128 @@
+package workerqueue_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kongflow/backend/internal/database"
+	"kongflow/backend/internal/services/workerqueue"
+	"kongflow/backend/internal/services/workerqueue/testutil"
+)
+
+// eventsQueueArgs is a job worked on the events queue
+type eventsQueueArgs struct{}
+
+func (eventsQueueArgs) Kind() string { return "test_events_queue_job" }
+
+func (eventsQueueArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{Queue: string(workerqueue.QueueEvents)}
+}
+
+type countingWorker struct {
+	river.WorkerDefaults[eventsQueueArgs]
+	worked atomic.Int64
+}
+
+func (w *countingWorker) Work(ctx context.Context, job *river.Job[eventsQueueArgs]) error {
+	w.worked.Add(1)
+	return nil
+}
+
+func TestQueueControls(t *testing.T) {
+	ctx := context.Background()
+
+	testDB := database.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	config := workerqueue.DefaultConfig()
+	config.TestMode = true
+	config.HeartbeatInterval = 100 * time.Millisecond
+
+	newManager := func(worker *countingWorker) *workerqueue.Manager {
+		manager, err := workerqueue.NewManagerWithOptions(config, testDB.Pool, logger, workerqueue.ManagerOptions{
+			RegisterTasks: func(registry *workerqueue.TaskRegistry) error {
+				return workerqueue.RegisterTask(registry, worker, workerqueue.JobOptions{})
+			},
+		})
+		require.NoError(t, err)
+		return manager
+	}
+
+	// The admin manager only controls queues; the worker manager is a separate process working them
+	admin := newManager(&countingWorker{})
+	require.NoError(t, admin.EnsureRiverTables(ctx))
+	worker := &countingWorker{}
+	workerManager := newManager(worker)
+
+	t.Run("Unknown queue", func(t *testing.T) {
+		assert.ErrorIs(t, admin.PauseQueue(ctx, "reports"), workerqueue.ErrUnknownQueue)
+		_, err := admin.SetQueueMaxWorkers(ctx, "reports", 1)
+		assert.ErrorIs(t, err, workerqueue.ErrUnknownQueue)
+	})
+
+	t.Run("Pause is persisted before workers start", func(t *testing.T) {
+		require.NoError(t, admin.PauseQueue(ctx, string(workerqueue.QueueEvents)))
+
+		_, err := admin.EnqueueJob(ctx, "test_events_queue_job", eventsQueueArgs{}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, workerManager.Start(ctx))
+		time.Sleep(500 * time.Millisecond)
+		assert.Zero(t, worker.worked.Load())
+
+		controls, err := admin.ListQueueControls(ctx)
+		require.NoError(t, err)
+		require.Len(t, controls, 4)
+		for _, control := range controls {
+			assert.Equal(t, control.Queue == string(workerqueue.QueueEvents), control.Paused, control.Queue)
+		}
+	})
+	defer func() {
+		assert.NoError(t, workerManager.Stop(ctx))
+	}()
+
+	t.Run("Resume lets workers fetch again", func(t *testing.T) {
+		require.NoError(t, admin.ResumeQueue(ctx, string(workerqueue.QueueEvents)))
+
+		testutil.WaitForJobCompletion(t, func() int {
+			return int(worker.worked.Load())
+		}, 1, 10*time.Second)
+	})
+
+	t.Run("Runtime max workers", func(t *testing.T) {
+		_, err := admin.SetQueueMaxWorkers(ctx, string(workerqueue.QueueEvents), config.EventsMaxWorkers+1)
+		assert.ErrorIs(t, err, workerqueue.ErrInvalidMaxWorkers)
+
+		control, err := admin.SetQueueMaxWorkers(ctx, string(workerqueue.QueueEvents), 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, control.MaxWorkers)
+		assert.Equal(t, config.EventsMaxWorkers, control.ConfiguredMaxWorkers)
+		assert.False(t, control.Paused)
+
+		// The worker process picks up the limit on its next check-in
+		assert.Eventually(t, func() bool {
+			stats, err := workerManager.Stats(ctx)
+			require.NoError(t, err)
+			for _, queueStats := range stats.Queues {
+				if queueStats.Queue == string(workerqueue.QueueEvents) {
+					return queueStats.MaxWorkers == 2
+				}
+			}
+			return false
+		}, 5*time.Second, 100*time.Millisecond)
+
+		control, err = admin.SetQueueMaxWorkers(ctx, string(workerqueue.QueueEvents), 0)
+		require.NoError(t, err)
+		assert.Equal(t, config.EventsMaxWorkers, control.MaxWorkers)
+	})
+}
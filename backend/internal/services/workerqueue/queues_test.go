@@ -0,0 +1,56 @@
+package workerqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerMiddleware_RuntimeMaxWorkers(t *testing.T) {
+	middleware := newWorkerMiddleware(nil, time.Second)
+	middleware.setLimit(string(QueueEvents), 1)
+	job := &rivertype.JobRow{ID: 1, Kind: "deliver_event", Queue: string(QueueEvents)}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- middleware.Work(context.Background(), job, func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	// The queue is at its limit, so the next job is snoozed without being worked
+	err := middleware.Work(context.Background(), job, func(ctx context.Context) error {
+		t.Fatal("job worked over the queue limit")
+		return nil
+	})
+	var snooze *rivertype.JobSnoozeError
+	require.ErrorAs(t, err, &snooze)
+	assert.Equal(t, time.Second, snooze.Duration)
+
+	// Other queues are not limited
+	other := &rivertype.JobRow{ID: 2, Kind: "start_run", Queue: string(QueueExecution)}
+	require.NoError(t, middleware.Work(context.Background(), other, func(ctx context.Context) error { return nil }))
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Zero(t, middleware.count(string(QueueEvents)))
+
+	// Removing the limit lets jobs run again
+	middleware.setLimit(string(QueueEvents), 0)
+	require.NoError(t, middleware.Work(context.Background(), job, func(ctx context.Context) error { return nil }))
+}
+
+func TestQueueMaxWorkersLimit(t *testing.T) {
+	assert.Equal(t, 2, queueMaxWorkersLimit(&rivertype.Queue{Metadata: []byte(`{"max_workers":2,"owner":"ops"}`)}))
+	assert.Zero(t, queueMaxWorkersLimit(&rivertype.Queue{Metadata: []byte(`{}`)}))
+	assert.Zero(t, queueMaxWorkersLimit(&rivertype.Queue{}))
+}
@@ -0,0 +1,219 @@
+// Package workerqueue rate-limits jobs per kind and payload key with token buckets stored in Postgres
+package workerqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// minRateLimitSnooze is the shortest delay before a rate-limited job is tried again
+const minRateLimitSnooze = 100 * time.Millisecond
+
+// RateLimit caps how often jobs of a kind run, using one token bucket per payload key
+// Buckets are stored in worker_rate_limit_buckets, so the limit is shared by every worker process
+type RateLimit struct {
+	// Limit is how many jobs may run per Interval once the burst is used up
+	Limit    int
+	Interval time.Duration
+
+	// Burst is how many jobs may run back to back; defaults to Limit
+	Burst int
+
+	// KeyField is the JSON field of the job args the bucket is keyed by, e.g. "endpointId" for
+	// PerformRunExecutionV2Args; it must be a field of the kind's args
+	// Jobs without the field, or all jobs when empty, share one bucket per kind
+	KeyField string
+}
+
+// validate checks the limit can be enforced
+func (r RateLimit) validate() error {
+	if r.Limit <= 0 || r.Interval <= 0 || r.Burst < 0 {
+		return fmt.Errorf("rate limit needs a positive limit and interval, got %d per %s (burst %d)", r.Limit, r.Interval, r.Burst)
+	}
+	return nil
+}
+
+// burst returns the bucket capacity
+func (r RateLimit) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Limit
+}
+
+// tokensPerSecond returns how fast the bucket refills
+func (r RateLimit) tokensPerSecond() float64 {
+	return float64(r.Limit) / r.Interval.Seconds()
+}
+
+// rateLimitMiddleware snoozes jobs whose kind and key have no tokens left
+type rateLimitMiddleware struct {
+	river.MiddlewareDefaults
+
+	mu     sync.RWMutex
+	limits map[string]RateLimit
+
+	// take removes a token from the bucket of kind and key, returning how long to wait when it is empty
+	take func(ctx context.Context, kind, key string, limit RateLimit) (time.Duration, error)
+}
+
+var _ rivertype.WorkerMiddleware = (*rateLimitMiddleware)(nil)
+
+// newRateLimitMiddleware creates a rate-limit middleware taking tokens from worker_rate_limit_buckets
+func newRateLimitMiddleware(dbPool *pgxpool.Pool) *rateLimitMiddleware {
+	return &rateLimitMiddleware{
+		limits: make(map[string]RateLimit),
+		take: func(ctx context.Context, kind, key string, limit RateLimit) (time.Duration, error) {
+			return takeRateLimitToken(ctx, dbPool, kind, key, limit)
+		},
+	}
+}
+
+// Work snoozes the job until its bucket refills when no token is left
+func (r *rateLimitMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(context.Context) error) error {
+	r.mu.RLock()
+	limit, ok := r.limits[job.Kind]
+	r.mu.RUnlock()
+	if !ok {
+		return doInner(ctx)
+	}
+
+	wait, err := r.take(ctx, job.Kind, jobArgsField(job.EncodedArgs, limit.KeyField), limit)
+	if err != nil {
+		return fmt.Errorf("failed to take rate limit token for %s: %w", job.Kind, err)
+	}
+	if wait > 0 {
+		return river.JobSnooze(max(wait, minRateLimitSnooze))
+	}
+	return doInner(ctx)
+}
+
+// setLimit sets the rate limit of kind
+func (r *rateLimitMiddleware) setLimit(kind string, limit RateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limits[kind] = limit
+}
+
+// SetRateLimit rate-limits jobs of a registered kind or alias in every worker process sharing the database
+// ManagerOptions.RateLimits and TaskDefinition.RateLimit are applied through this
+func (m *Manager) SetRateLimit(kind string, limit RateLimit) error {
+	task, err := m.registry.Lookup(kind)
+	if err != nil {
+		return err
+	}
+	if err := limit.validate(); err != nil {
+		return fmt.Errorf("task %s: %w", kind, err)
+	}
+	if limit.KeyField != "" && !argsTypeHasField(task.argsType, limit.KeyField) {
+		return fmt.Errorf("task %s: rate limit key field %q is not a field of %s", kind, limit.KeyField, task.argsType)
+	}
+
+	m.rateLimitMiddleware.setLimit(task.Kind, limit)
+	return nil
+}
+
+// argsTypeHasField reports whether args of type t encode a JSON field named field
+// Types that are not structs, such as maps, may hold any field
+func argsTypeHasField(t reflect.Type, field string) bool {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			if argsTypeHasField(f.Type, field) {
+				return true
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// takeRateLimitToken refills the bucket for the time since its last update and takes one token
+// The bucket row is locked by the upsert, so concurrent workers never take the same token
+func takeRateLimitToken(ctx context.Context, dbPool *pgxpool.Pool, kind, key string, limit RateLimit) (time.Duration, error) {
+	burst, rate := float64(limit.burst()), limit.tokensPerSecond()
+
+	var tokens float64
+	err := dbPool.QueryRow(ctx, `
+		INSERT INTO worker_rate_limit_buckets AS b (kind, key, tokens, updated_at)
+		VALUES ($1, $2, $3 - 1, NOW())
+		ON CONFLICT (kind, key) DO UPDATE
+		SET tokens = LEAST($3, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at) * $4) - 1,
+			updated_at = NOW()
+		WHERE LEAST($3, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at) * $4) >= 1
+		RETURNING tokens`,
+		kind, key, burst, rate,
+	).Scan(&tokens)
+	if err == nil {
+		return 0, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	// The bucket is empty: wait until the next token is due
+	err = dbPool.QueryRow(ctx, `
+		SELECT LEAST($3, tokens + EXTRACT(EPOCH FROM NOW() - updated_at) * $4)
+		FROM worker_rate_limit_buckets
+		WHERE kind = $1 AND key = $2`,
+		kind, key, burst, rate,
+	).Scan(&tokens)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(math.Max(1-tokens, 0) / rate * float64(time.Second)), nil
+}
+
+// jobArgsField returns the value of field in encoded job args as a string, or "" when missing
+func jobArgsField(encodedArgs []byte, field string) string {
+	if field == "" {
+		return ""
+	}
+
+	var args map[string]json.RawMessage
+	if json.Unmarshal(encodedArgs, &args) != nil {
+		return ""
+	}
+	raw, ok := args[field]
+	if !ok || string(raw) == "null" {
+		return ""
+	}
+
+	var value string
+	if json.Unmarshal(raw, &value) == nil {
+		return value
+	}
+	return string(raw)
+}
@@ -0,0 +1,123 @@
+package workerqueue_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kongflow/backend/internal/database"
+	"kongflow/backend/internal/services/workerqueue"
+	"kongflow/backend/internal/services/workerqueue/testutil"
+)
+
+// endpointCallArgs is a job calling one endpoint
+type endpointCallArgs struct {
+	EndpointID string `json:"endpoint_id"`
+}
+
+func (endpointCallArgs) Kind() string { return "test_endpoint_call" }
+
+type endpointCallWorker struct {
+	river.WorkerDefaults[endpointCallArgs]
+
+	mu     sync.Mutex
+	worked map[string]int
+}
+
+func (w *endpointCallWorker) Work(ctx context.Context, job *river.Job[endpointCallArgs]) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.worked[job.Args.EndpointID]++
+	return nil
+}
+
+func (w *endpointCallWorker) workedCount(endpointID string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.worked[endpointID]
+}
+
+func TestRateLimitedTasks(t *testing.T) {
+	ctx := context.Background()
+
+	testDB := database.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	worker := &endpointCallWorker{worked: make(map[string]int)}
+
+	config := workerqueue.DefaultConfig()
+	config.TestMode = true
+
+	manager, err := workerqueue.NewManagerWithOptions(config, testDB.Pool, logger, workerqueue.ManagerOptions{
+		RegisterTasks: func(registry *workerqueue.TaskRegistry) error {
+			return workerqueue.RegisterTask(registry, worker, workerqueue.JobOptions{})
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, manager.EnsureRiverTables(ctx))
+
+	// Each endpoint may be called twice back to back, then once an hour
+	triggerWorker := workerqueue.NewTriggerCompatibleWorker(workerqueue.TriggerWorkerOptions{
+		Manager: manager,
+		Catalog: workerqueue.TaskCatalog{
+			"callEndpoint": workerqueue.TaskDefinition{
+				Kind: "test_endpoint_call",
+				RateLimit: &workerqueue.RateLimit{
+					Limit:    1,
+					Interval: time.Hour,
+					Burst:    2,
+					KeyField: "endpoint_id",
+				},
+			},
+		},
+		Logger: logger,
+	})
+
+	for i := 0; i < 4; i++ {
+		_, err := triggerWorker.Enqueue(ctx, "callEndpoint", endpointCallArgs{EndpointID: "ep_busy"}, nil)
+		require.NoError(t, err)
+	}
+	_, err = triggerWorker.Enqueue(ctx, "callEndpoint", endpointCallArgs{EndpointID: "ep_quiet"}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, triggerWorker.Initialize(ctx))
+	defer func() {
+		assert.NoError(t, triggerWorker.Stop(ctx))
+	}()
+
+	testutil.WaitForJobCompletion(t, func() int {
+		return worker.workedCount("ep_busy") + worker.workedCount("ep_quiet")
+	}, 3, 10*time.Second)
+
+	// Jobs over the limit are snoozed until the bucket refills instead of failing
+	assert.Eventually(t, func() bool {
+		result, err := manager.Client().JobList(ctx, river.NewJobListParams().
+			Kinds("test_endpoint_call").
+			States(rivertype.JobStateScheduled))
+		require.NoError(t, err)
+		return len(result.Jobs) == 2
+	}, 5*time.Second, 100*time.Millisecond)
+
+	assert.Equal(t, 2, worker.workedCount("ep_busy"))
+	assert.Equal(t, 1, worker.workedCount("ep_quiet"))
+
+	result, err := manager.Client().JobList(ctx, river.NewJobListParams().
+		Kinds("test_endpoint_call").
+		States(rivertype.JobStateRetryable, rivertype.JobStateDiscarded))
+	require.NoError(t, err)
+	assert.Empty(t, result.Jobs)
+
+	t.Run("Rejects invalid limits", func(t *testing.T) {
+		assert.Error(t, manager.SetRateLimit("test_endpoint_call", workerqueue.RateLimit{Limit: 1}))
+		assert.ErrorIs(t, manager.SetRateLimit("unknown_kind", workerqueue.RateLimit{Limit: 1, Interval: time.Second}), workerqueue.ErrUnknownTask)
+	})
+}
@@ -0,0 +1,109 @@
+package workerqueue
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware_SnoozesEmptyBuckets(t *testing.T) {
+	tokens := map[string]int{"endpoint_1": 1}
+	middleware := &rateLimitMiddleware{
+		limits: map[string]RateLimit{
+			"perform_run_execution_v2": {Limit: 1, Interval: time.Minute, KeyField: "endpointId"},
+		},
+		take: func(ctx context.Context, kind, key string, limit RateLimit) (time.Duration, error) {
+			if tokens[key] == 0 {
+				return 30 * time.Second, nil
+			}
+			tokens[key]--
+			return 0, nil
+		},
+	}
+	job := &rivertype.JobRow{
+		Kind:        "perform_run_execution_v2",
+		EncodedArgs: []byte(`{"id":"run_1","endpointId":"endpoint_1"}`),
+	}
+
+	worked := 0
+	work := func(ctx context.Context) error {
+		worked++
+		return nil
+	}
+
+	require.NoError(t, middleware.Work(context.Background(), job, work))
+
+	err := middleware.Work(context.Background(), job, work)
+	var snooze *rivertype.JobSnoozeError
+	require.ErrorAs(t, err, &snooze)
+	assert.Equal(t, 30*time.Second, snooze.Duration)
+	assert.Equal(t, 1, worked)
+
+	// Kinds without a rate limit are not throttled
+	require.NoError(t, middleware.Work(context.Background(), &rivertype.JobRow{Kind: "start_run"}, work))
+	assert.Equal(t, 2, worked)
+}
+
+func TestRateLimit_Validate(t *testing.T) {
+	assert.NoError(t, RateLimit{Limit: 10, Interval: time.Minute}.validate())
+	assert.Error(t, RateLimit{Limit: 0, Interval: time.Minute}.validate())
+	assert.Error(t, RateLimit{Limit: 10}.validate())
+	assert.Error(t, RateLimit{Limit: 10, Interval: time.Minute, Burst: -1}.validate())
+
+	limit := RateLimit{Limit: 10, Interval: time.Minute}
+	assert.Equal(t, 10, limit.burst())
+	assert.InDelta(t, 10.0/60, limit.tokensPerSecond(), 1e-9)
+}
+
+func TestJobArgsField(t *testing.T) {
+	args := []byte(`{"endpoint_id":"ep_1","organizationId":42,"empty":null}`)
+
+	assert.Equal(t, "ep_1", jobArgsField(args, "endpoint_id"))
+	assert.Equal(t, "42", jobArgsField(args, "organizationId"))
+	assert.Empty(t, jobArgsField(args, "empty"))
+	assert.Empty(t, jobArgsField(args, "missing"))
+	assert.Empty(t, jobArgsField(args, ""))
+	assert.Empty(t, jobArgsField([]byte(`not json`), "endpoint_id"))
+}
+
+func TestManager_SetRateLimit(t *testing.T) {
+	registry := NewTaskRegistry()
+	require.NoError(t, registerBuiltinTasks(registry, DefaultConfig(), ManagerOptions{}, nil))
+	manager := &Manager{registry: registry, rateLimitMiddleware: newRateLimitMiddleware(nil)}
+
+	// Aliases are stored under the kind the middleware sees on jobs
+	require.NoError(t, manager.SetRateLimit("performRunExecutionV2", RateLimit{Limit: 1, Interval: time.Second, KeyField: "endpointId"}))
+	assert.Contains(t, manager.rateLimitMiddleware.limits, PerformRunExecutionV2Args{}.Kind())
+
+	// The execution args use camelCase JSON fields, unlike the snake_case endpoint args
+	err := manager.SetRateLimit("performRunExecutionV2", RateLimit{Limit: 1, Interval: time.Second, KeyField: "endpoint_id"})
+	assert.ErrorContains(t, err, `rate limit key field "endpoint_id"`)
+
+	assert.ErrorIs(t, manager.SetRateLimit("missing", RateLimit{Limit: 1, Interval: time.Second}), ErrUnknownTask)
+}
+
+func TestDefaultRateLimits(t *testing.T) {
+	registry := NewTaskRegistry()
+	require.NoError(t, registerBuiltinTasks(registry, DefaultConfig(), ManagerOptions{}, nil))
+	manager := &Manager{registry: registry, rateLimitMiddleware: newRateLimitMiddleware(nil)}
+
+	limits := DefaultRateLimits()
+	assert.Contains(t, limits, ScheduleEmailArgs{}.Kind())
+	assert.Equal(t, "endpointId", limits[PerformRunExecutionV2Args{}.Kind()].KeyField)
+	for kind, limit := range limits {
+		assert.NoError(t, manager.SetRateLimit(kind, limit), kind)
+	}
+}
+
+func TestArgsTypeHasField(t *testing.T) {
+	executionArgs := reflect.TypeOf(PerformRunExecutionV2Args{})
+	assert.True(t, argsTypeHasField(executionArgs, "endpointId"))
+	assert.False(t, argsTypeHasField(executionArgs, "EndpointID"))
+	assert.True(t, argsTypeHasField(reflect.TypeOf(RegisterJobArgs{}), "endpoint_id"))
+	assert.True(t, argsTypeHasField(reflect.TypeOf(map[string]interface{}{}), "anything"))
+}
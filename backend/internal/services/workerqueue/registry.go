@@ -0,0 +1,180 @@
+// Package workerqueue provides the task registry that maps task identifiers to River workers
+package workerqueue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/riverqueue/river"
+)
+
+// ErrUnknownTask is returned when enqueueing an identifier that has no registered task
+var ErrUnknownTask = errors.New("unknown task")
+
+// TaskRegistry maps task kinds (and their aliases) to the River worker that processes them,
+// the args type used to decode payloads and the default JobOptions applied on enqueue.
+// Each service registers its tasks before the River client is created.
+type TaskRegistry struct {
+	workers     *river.Workers
+	tasks       map[string]*TaskRegistration
+	identifiers map[string]string // identifier or alias -> kind
+}
+
+// TaskRegistration describes a registered task
+type TaskRegistration struct {
+	// Kind is the River job kind, as returned by the args type's Kind()
+	Kind string
+
+	// Aliases are additional identifiers accepted when enqueueing (e.g. trigger.dev camelCase names)
+	Aliases []string
+
+	// Defaults are merged under the options passed when enqueueing
+	Defaults JobOptions
+
+	decode   func(payload interface{}) (JobArgs, error)
+	argsType reflect.Type
+}
+
+// NewTaskRegistry creates an empty task registry
+func NewTaskRegistry() *TaskRegistry {
+	return &TaskRegistry{
+		workers:     river.NewWorkers(),
+		tasks:       make(map[string]*TaskRegistration),
+		identifiers: make(map[string]string),
+	}
+}
+
+// RegisterTask registers worker for the args type T under T's Kind and the given aliases.
+// Zero fields in defaults fall back to T's InsertOpts when T provides them.
+func RegisterTask[T river.JobArgs](r *TaskRegistry, worker river.Worker[T], defaults JobOptions, aliases ...string) error {
+	var zero T
+	kind := zero.Kind()
+
+	identifiers := append([]string{kind}, aliases...)
+	for _, identifier := range identifiers {
+		if existing, ok := r.identifiers[identifier]; ok {
+			return fmt.Errorf("task identifier %q is already registered for kind %q", identifier, existing)
+		}
+	}
+
+	if err := river.AddWorkerSafely(r.workers, worker); err != nil {
+		return fmt.Errorf("failed to register worker for %q: %w", kind, err)
+	}
+
+	if withOpts, ok := any(zero).(river.JobArgsWithInsertOpts); ok {
+		insertOpts := withOpts.InsertOpts()
+		if defaults.QueueName == "" {
+			defaults.QueueName = insertOpts.Queue
+		}
+		if defaults.Priority == 0 {
+			defaults.Priority = insertOpts.Priority
+		}
+		if defaults.MaxAttempts == 0 {
+			defaults.MaxAttempts = insertOpts.MaxAttempts
+		}
+	}
+
+	r.tasks[kind] = &TaskRegistration{
+		Kind:     kind,
+		Aliases:  aliases,
+		Defaults: defaults,
+		decode:   decodeTaskArgs[T],
+		argsType: reflect.TypeOf(zero),
+	}
+	for _, identifier := range identifiers {
+		r.identifiers[identifier] = kind
+	}
+	return nil
+}
+
+// Lookup returns the task registered for an identifier, which may be a kind or an alias
+func (r *TaskRegistry) Lookup(identifier string) (*TaskRegistration, error) {
+	kind, ok := r.identifiers[identifier]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTask, identifier)
+	}
+	return r.tasks[kind], nil
+}
+
+// Kinds returns the registered task kinds in sorted order
+func (r *TaskRegistry) Kinds() []string {
+	kinds := make([]string, 0, len(r.tasks))
+	for kind := range r.tasks {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Workers returns the River workers bundle holding every registered worker
+func (r *TaskRegistry) Workers() *river.Workers {
+	return r.workers
+}
+
+// NewArgs converts payload into the task's args type
+// Payloads that already have the args type are used as is; others are converted through JSON
+func (t *TaskRegistration) NewArgs(payload interface{}) (JobArgs, error) {
+	return t.decode(payload)
+}
+
+// decodeTaskArgs converts payload into T
+func decodeTaskArgs[T river.JobArgs](payload interface{}) (JobArgs, error) {
+	switch typed := payload.(type) {
+	case T:
+		return typed, nil
+	case *T:
+		if typed != nil {
+			return *typed, nil
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	var args T
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal to %T: %w", args, err)
+	}
+	return args, nil
+}
+
+// mergeJobOptions applies opts over defaults; zero-valued fields in opts keep the default
+func mergeJobOptions(defaults JobOptions, opts *JobOptions) *JobOptions {
+	merged := defaults
+
+	if opts != nil {
+		if opts.QueueName != "" {
+			merged.QueueName = opts.QueueName
+		}
+		if opts.Priority > 0 {
+			merged.Priority = opts.Priority
+		}
+		if opts.MaxAttempts > 0 {
+			merged.MaxAttempts = opts.MaxAttempts
+		}
+		if opts.RunAt != nil {
+			merged.RunAt = opts.RunAt
+		}
+		if opts.JobKey != "" {
+			merged.JobKey = opts.JobKey
+		}
+		if opts.JobKeyMode != "" {
+			merged.JobKeyMode = opts.JobKeyMode
+		}
+		if opts.Tags != nil {
+			merged.Tags = opts.Tags
+		}
+		if len(opts.Flags) > 0 {
+			merged.Flags = opts.Flags
+		}
+		if opts.UniqueOpts != nil {
+			merged.UniqueOpts = opts.UniqueOpts
+		}
+	}
+
+	return &merged
+}
@@ -0,0 +1,78 @@
+package workerqueue_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kongflow/backend/internal/services/workerqueue"
+)
+
+// TestTaskRegistry_LookupByKindAndAlias tests that tasks resolve by kind and trigger.dev aliases
+func TestTaskRegistry_LookupByKindAndAlias(t *testing.T) {
+	registry := workerqueue.NewTaskRegistry()
+	err := workerqueue.RegisterTask(registry, workerqueue.NewStartRunWorker(nil, slog.Default()), workerqueue.JobOptions{}, "startRun")
+	require.NoError(t, err)
+
+	byKind, err := registry.Lookup("start_run")
+	require.NoError(t, err)
+	byAlias, err := registry.Lookup("startRun")
+	require.NoError(t, err)
+
+	assert.Same(t, byKind, byAlias)
+	assert.Equal(t, "start_run", byKind.Kind)
+	assert.Equal(t, []string{"start_run"}, registry.Kinds())
+
+	// Zero defaults fall back to the args' InsertOpts
+	assert.Equal(t, string(workerqueue.QueueExecution), byKind.Defaults.QueueName)
+	assert.Equal(t, 4, byKind.Defaults.MaxAttempts)
+}
+
+// TestTaskRegistry_UnknownTask tests that unregistered identifiers are rejected
+func TestTaskRegistry_UnknownTask(t *testing.T) {
+	registry := workerqueue.NewTaskRegistry()
+
+	_, err := registry.Lookup("invoke_dispatcher")
+	assert.ErrorIs(t, err, workerqueue.ErrUnknownTask)
+}
+
+// TestTaskRegistry_DuplicateRegistration tests that kinds and aliases can only be registered once
+func TestTaskRegistry_DuplicateRegistration(t *testing.T) {
+	registry := workerqueue.NewTaskRegistry()
+	logger := slog.Default()
+
+	require.NoError(t, workerqueue.RegisterTask(registry, workerqueue.NewStartRunWorker(nil, logger), workerqueue.JobOptions{}, "run"))
+	assert.Error(t, workerqueue.RegisterTask(registry, workerqueue.NewStartRunWorker(nil, logger), workerqueue.JobOptions{}))
+	assert.Error(t, workerqueue.RegisterTask(registry, workerqueue.NewStartQueuedRunsWorker(nil, logger), workerqueue.JobOptions{}, "run"))
+
+	// A rejected registration does not leave the kind behind
+	_, err := registry.Lookup("start_queued_runs")
+	assert.ErrorIs(t, err, workerqueue.ErrUnknownTask)
+}
+
+// TestTaskRegistration_NewArgs tests decoding payloads into the registered args type
+func TestTaskRegistration_NewArgs(t *testing.T) {
+	registry := workerqueue.NewTaskRegistry()
+	err := workerqueue.RegisterTask(registry, workerqueue.NewNotifyRunFailedWorker(nil, slog.Default()), workerqueue.JobOptions{
+		QueueName: "notifications",
+	})
+	require.NoError(t, err)
+
+	task, err := registry.Lookup("notify_run_failed")
+	require.NoError(t, err)
+	assert.Equal(t, "notifications", task.Defaults.QueueName)
+
+	args, err := task.NewArgs(map[string]interface{}{"runId": "run_123"})
+	require.NoError(t, err)
+	assert.Equal(t, workerqueue.NotifyRunFailedArgs{RunID: "run_123"}, args)
+
+	typed := workerqueue.NotifyRunFailedArgs{RunID: "run_456"}
+	args, err = task.NewArgs(&typed)
+	require.NoError(t, err)
+	assert.Equal(t, typed, args)
+
+	_, err = task.NewArgs(map[string]interface{}{"runId": 42})
+	assert.Error(t, err)
+}
@@ -0,0 +1,393 @@
+// Package workerqueue provides live queue metrics and health checks for the worker manager
+package workerqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// statsJobStates are the job states counted by Stats
+var statsJobStates = []rivertype.JobState{
+	rivertype.JobStateAvailable,
+	rivertype.JobStateRunning,
+	rivertype.JobStateScheduled,
+	rivertype.JobStateRetryable,
+	rivertype.JobStateDiscarded,
+}
+
+// JobStateCounts counts jobs by state
+type JobStateCounts struct {
+	Available int64 `json:"available"`
+	Running   int64 `json:"running"`
+	Scheduled int64 `json:"scheduled"`
+	Retryable int64 `json:"retryable"`
+	Discarded int64 `json:"discarded"`
+}
+
+// add adds count jobs in state
+func (c *JobStateCounts) add(state rivertype.JobState, count int64) {
+	switch state {
+	case rivertype.JobStateAvailable:
+		c.Available += count
+	case rivertype.JobStateRunning:
+		c.Running += count
+	case rivertype.JobStateScheduled:
+		c.Scheduled += count
+	case rivertype.JobStateRetryable:
+		c.Retryable += count
+	case rivertype.JobStateDiscarded:
+		c.Discarded += count
+	}
+}
+
+// QueueStats holds live metrics for a queue
+// Running counts jobs across all processes; ActiveWorkers and Utilization only cover this process
+type QueueStats struct {
+	Queue string `json:"queue"`
+	JobStateCounts
+
+	// OldestAvailableAge is how long the oldest available job has been waiting to run
+	OldestAvailableAge time.Duration `json:"oldestAvailableAge"`
+
+	MaxWorkers    int     `json:"maxWorkers"`
+	ActiveWorkers int64   `json:"activeWorkers"`
+	Utilization   float64 `json:"utilization"`
+}
+
+// KindStats holds live job counts for a job kind
+type KindStats struct {
+	Kind string `json:"kind"`
+	JobStateCounts
+}
+
+// ManagerStats is a snapshot of the worker manager's queues
+type ManagerStats struct {
+	ClientID    string       `json:"clientId"`
+	CollectedAt time.Time    `json:"collectedAt"`
+	Queues      []QueueStats `json:"queues"`
+	Kinds       []KindStats  `json:"kinds"`
+	Config      Config       `json:"config"`
+}
+
+// HealthStatus is the result of a manager health check
+type HealthStatus struct {
+	Healthy     bool      `json:"healthy"`
+	Database    bool      `json:"database"`
+	Client      bool      `json:"client"`
+	ClientID    string    `json:"clientId"`
+	LastCheckIn time.Time `json:"lastCheckIn"`
+	Errors      []string  `json:"errors,omitempty"`
+}
+
+// Stats collects per-queue and per-kind job counts, the age of each queue's oldest available
+// job and this process's worker utilisation
+func (m *Manager) Stats(ctx context.Context) (*ManagerStats, error) {
+	states := make([]string, 0, len(statsJobStates))
+	for _, state := range statsJobStates {
+		states = append(states, string(state))
+	}
+
+	rows, err := m.dbPool.Query(ctx, fmt.Sprintf(`
+		SELECT queue, kind, state, COUNT(*),
+			EXTRACT(EPOCH FROM NOW() - MIN(scheduled_at) FILTER (WHERE state = 'available'))::float8
+		FROM %s
+		WHERE state::text = ANY($1::text[])
+		GROUP BY queue, kind, state`, m.riverTable("river_job")),
+		states,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect job stats: %w", err)
+	}
+	defer rows.Close()
+
+	queues := make(map[string]*QueueStats)
+	kinds := make(map[string]*KindStats)
+	for name, maxWorkers := range m.queueMaxWorkers() {
+		if limit := m.workerMiddleware.limit(name); limit > 0 && limit < maxWorkers {
+			maxWorkers = limit
+		}
+		queues[name] = &QueueStats{Queue: name, MaxWorkers: maxWorkers}
+	}
+
+	for rows.Next() {
+		var (
+			queue, kind, state string
+			count              int64
+			oldestAge          *float64
+		)
+		if err := rows.Scan(&queue, &kind, &state, &count, &oldestAge); err != nil {
+			return nil, fmt.Errorf("failed to scan job stats: %w", err)
+		}
+
+		queueStats, ok := queues[queue]
+		if !ok {
+			queueStats = &QueueStats{Queue: queue}
+			queues[queue] = queueStats
+		}
+		queueStats.add(rivertype.JobState(state), count)
+		if oldestAge != nil {
+			age := time.Duration(*oldestAge * float64(time.Second))
+			if age > queueStats.OldestAvailableAge {
+				queueStats.OldestAvailableAge = age
+			}
+		}
+
+		kindStats, ok := kinds[kind]
+		if !ok {
+			kindStats = &KindStats{Kind: kind}
+			kinds[kind] = kindStats
+		}
+		kindStats.add(rivertype.JobState(state), count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to collect job stats: %w", err)
+	}
+
+	stats := &ManagerStats{
+		ClientID:    m.riverClient.ID(),
+		CollectedAt: time.Now(),
+		Config:      m.config,
+	}
+	for _, queueStats := range queues {
+		queueStats.ActiveWorkers = m.workerMiddleware.count(queueStats.Queue)
+		if queueStats.MaxWorkers > 0 {
+			queueStats.Utilization = float64(queueStats.ActiveWorkers) / float64(queueStats.MaxWorkers)
+		}
+		stats.Queues = append(stats.Queues, *queueStats)
+	}
+	for _, kindStats := range kinds {
+		stats.Kinds = append(stats.Kinds, *kindStats)
+	}
+	sort.Slice(stats.Queues, func(i, j int) bool { return stats.Queues[i].Queue < stats.Queues[j].Queue })
+	sort.Slice(stats.Kinds, func(i, j int) bool { return stats.Kinds[i].Kind < stats.Kinds[j].Kind })
+
+	return stats, nil
+}
+
+// Health checks database connectivity and that this process's River client checked in within
+// HealthCheckInStaleAfter
+func (m *Manager) Health(ctx context.Context) HealthStatus {
+	status := HealthStatus{
+		ClientID:    m.riverClient.ID(),
+		LastCheckIn: m.lastCheckIn(),
+	}
+
+	if err := m.dbPool.Ping(ctx); err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("database: %v", err))
+	} else {
+		status.Database = true
+	}
+
+	switch {
+	case status.LastCheckIn.IsZero():
+		status.Errors = append(status.Errors, "client: not started")
+	case time.Since(status.LastCheckIn) > m.config.HealthCheckInStaleAfter:
+		status.Errors = append(status.Errors, fmt.Sprintf("client: last checked in %s ago", time.Since(status.LastCheckIn).Round(time.Second)))
+	default:
+		status.Client = true
+	}
+
+	status.Healthy = status.Database && status.Client
+	return status
+}
+
+// queueMaxWorkers returns the configured worker limit of each queue
+func (m *Manager) queueMaxWorkers() map[string]int {
+	return map[string]int{
+		string(QueueDefault):     m.config.MaxWorkers,
+		string(QueueExecution):   m.config.ExecutionMaxWorkers,
+		string(QueueEvents):      m.config.EventsMaxWorkers,
+		string(QueueMaintenance): m.config.MaintenanceMaxWorkers,
+	}
+}
+
+// riverTable returns a River table name qualified with the configured schema
+func (m *Manager) riverTable(name string) string {
+	return riverTable(m.config.Schema, name)
+}
+
+// riverTable returns the quoted name of a River table in schema
+func riverTable(schema, name string) string {
+	if schema == "" {
+		return pgx.Identifier{name}.Sanitize()
+	}
+	return pgx.Identifier{schema, name}.Sanitize()
+}
+
+// lastCheckIn returns when the River client last checked in, or zero if it never did
+func (m *Manager) lastCheckIn() time.Time {
+	nanos := m.checkIn.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// runCheckIns records a check-in every HeartbeatInterval while the River client is running and
+// can reach the database; it stops when ctx is cancelled or the client stops
+func (m *Manager) runCheckIns(ctx context.Context) {
+	ticker := time.NewTicker(m.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		m.checkInOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.riverClient.Stopped():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkInOnce records a check-in when a round trip through the River client succeeds and
+// applies the runtime MaxWorkers limits it returns
+func (m *Manager) checkInOnce(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.config.HeartbeatInterval)
+	defer cancel()
+
+	result, err := m.riverClient.QueueList(ctx, river.NewQueueListParams().First(len(m.queueMaxWorkers())+100))
+	if err != nil {
+		if ctx.Err() == nil {
+			m.logger.Warn("Worker manager check-in failed", "client_id", m.riverClient.ID(), "error", err)
+		}
+		return
+	}
+	m.checkIn.Store(time.Now().UnixNano())
+
+	// Runtime MaxWorkers limits set by other processes are picked up on check-in
+	m.applyQueueLimits(result.Queues)
+}
+
+// Job run outcomes passed to JobMetrics
+const (
+	JobOutcomeSuccess   = "success"
+	JobOutcomeError     = "error"
+	JobOutcomeCancelled = "cancelled"
+	JobOutcomeSnoozed   = "snoozed"
+)
+
+// JobMetrics records job run outcomes
+// Set ManagerOptions.JobMetrics to enable it; the metrics package provides an implementation
+type JobMetrics interface {
+	JobFinished(kind, queue, outcome string, duration time.Duration)
+}
+
+// jobOutcome classifies the error returned by a worker
+func jobOutcome(err error) string {
+	var cancelErr *rivertype.JobCancelError
+	var snoozeErr *rivertype.JobSnoozeError
+	switch {
+	case err == nil:
+		return JobOutcomeSuccess
+	case errors.As(err, &cancelErr):
+		return JobOutcomeCancelled
+	case errors.As(err, &snoozeErr):
+		return JobOutcomeSnoozed
+	default:
+		return JobOutcomeError
+	}
+}
+
+// workerMiddleware counts the jobs this process is working per queue, enforces runtime
+// MaxWorkers limits and reports job outcomes
+type workerMiddleware struct {
+	river.MiddlewareDefaults
+
+	metrics          JobMetrics
+	limitSnoozeDelay time.Duration
+
+	mu     sync.Mutex
+	queues map[string]*atomic.Int64
+	limits map[string]int
+}
+
+var _ rivertype.WorkerMiddleware = (*workerMiddleware)(nil)
+
+// newWorkerMiddleware creates a new worker middleware; metrics may be nil
+func newWorkerMiddleware(metrics JobMetrics, limitSnoozeDelay time.Duration) *workerMiddleware {
+	return &workerMiddleware{
+		metrics:          metrics,
+		limitSnoozeDelay: limitSnoozeDelay,
+		queues:           make(map[string]*atomic.Int64),
+		limits:           make(map[string]int),
+	}
+}
+
+// Work counts the job as active while it is being worked
+// Jobs fetched while their queue is at its runtime MaxWorkers limit are snoozed without being worked
+func (w *workerMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(context.Context) error) error {
+	counter := w.counter(job.Queue)
+	if !acquireSlot(counter, w.limit(job.Queue)) {
+		return river.JobSnooze(w.limitSnoozeDelay)
+	}
+	defer counter.Add(-1)
+
+	start := time.Now()
+	err := doInner(ctx)
+	if w.metrics != nil {
+		w.metrics.JobFinished(job.Kind, job.Queue, jobOutcome(err), time.Since(start))
+	}
+	return err
+}
+
+// setLimit sets the runtime MaxWorkers limit of queue; 0 removes it
+func (w *workerMiddleware) setLimit(queue string, limit int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if limit <= 0 {
+		delete(w.limits, queue)
+		return
+	}
+	w.limits[queue] = limit
+}
+
+// limit returns the runtime MaxWorkers limit of queue, or 0 when unlimited
+func (w *workerMiddleware) limit(queue string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.limits[queue]
+}
+
+// acquireSlot increments counter unless it has reached limit; a limit of 0 never blocks
+func acquireSlot(counter *atomic.Int64, limit int) bool {
+	for {
+		active := counter.Load()
+		if limit > 0 && active >= int64(limit) {
+			return false
+		}
+		if counter.CompareAndSwap(active, active+1) {
+			return true
+		}
+	}
+}
+
+// count returns the number of jobs being worked on queue
+func (w *workerMiddleware) count(queue string) int64 {
+	return w.counter(queue).Load()
+}
+
+// counter returns the counter for queue, creating it if needed
+func (w *workerMiddleware) counter(queue string) *atomic.Int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	counter, ok := w.queues[queue]
+	if !ok {
+		counter = &atomic.Int64{}
+		w.queues[queue] = counter
+	}
+	return counter
+}
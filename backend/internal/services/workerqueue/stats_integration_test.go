@@ -0,0 +1,135 @@
+package workerqueue_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kongflow/backend/internal/database"
+	"kongflow/backend/internal/services/workerqueue"
+	"kongflow/backend/internal/services/workerqueue/testutil"
+)
+
+// blockingArgs is a job that runs until its worker is released
+type blockingArgs struct{}
+
+func (blockingArgs) Kind() string { return "test_blocking_job" }
+
+func (blockingArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{Queue: string(workerqueue.QueueDefault)}
+}
+
+type blockingWorker struct {
+	river.WorkerDefaults[blockingArgs]
+	release chan struct{}
+}
+
+func (w *blockingWorker) Work(ctx context.Context, job *river.Job[blockingArgs]) error {
+	select {
+	case <-w.release:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func TestManagerStatsAndHealth(t *testing.T) {
+	ctx := context.Background()
+
+	testDB := database.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	worker := &blockingWorker{release: make(chan struct{})}
+
+	config := workerqueue.DefaultConfig()
+	config.TestMode = true
+	config.HeartbeatInterval = 100 * time.Millisecond
+
+	manager, err := workerqueue.NewManagerWithOptions(config, testDB.Pool, logger, workerqueue.ManagerOptions{
+		RegisterTasks: func(registry *workerqueue.TaskRegistry) error {
+			return workerqueue.RegisterTask(registry, worker, workerqueue.JobOptions{})
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, manager.EnsureRiverTables(ctx))
+
+	queueStats := func(stats *workerqueue.ManagerStats, queue string) workerqueue.QueueStats {
+		for _, queueStats := range stats.Queues {
+			if queueStats.Queue == queue {
+				return queueStats
+			}
+		}
+		t.Fatalf("queue %s missing from stats", queue)
+		return workerqueue.QueueStats{}
+	}
+
+	t.Run("Health before start", func(t *testing.T) {
+		status := manager.Health(ctx)
+		assert.False(t, status.Healthy)
+		assert.True(t, status.Database)
+		assert.False(t, status.Client)
+		assert.NotEmpty(t, status.Errors)
+	})
+
+	_, err = manager.EnqueueJob(ctx, "test_blocking_job", blockingArgs{}, nil)
+	require.NoError(t, err)
+	runAt := time.Now().Add(time.Hour)
+	_, err = manager.EnqueueJob(ctx, "test_blocking_job", blockingArgs{}, &workerqueue.JobOptions{
+		RunAt: &runAt,
+	})
+	require.NoError(t, err)
+
+	t.Run("Stats before start", func(t *testing.T) {
+		time.Sleep(50 * time.Millisecond)
+
+		stats, err := manager.Stats(ctx)
+		require.NoError(t, err)
+
+		defaultQueue := queueStats(stats, string(workerqueue.QueueDefault))
+		assert.Equal(t, int64(1), defaultQueue.Available)
+		assert.Equal(t, int64(1), defaultQueue.Scheduled)
+		assert.Equal(t, config.MaxWorkers, defaultQueue.MaxWorkers)
+		assert.Greater(t, defaultQueue.OldestAvailableAge, time.Duration(0))
+		assert.Zero(t, defaultQueue.ActiveWorkers)
+
+		require.Len(t, stats.Kinds, 1)
+		assert.Equal(t, "test_blocking_job", stats.Kinds[0].Kind)
+		assert.Equal(t, int64(1), stats.Kinds[0].Available)
+		assert.Equal(t, int64(1), stats.Kinds[0].Scheduled)
+	})
+
+	require.NoError(t, manager.Start(ctx))
+	defer func() {
+		assert.NoError(t, manager.Stop(ctx))
+	}()
+
+	t.Run("Stats while working", func(t *testing.T) {
+		testutil.WaitForJobCompletion(t, func() int {
+			stats, err := manager.Stats(ctx)
+			require.NoError(t, err)
+			return int(queueStats(stats, string(workerqueue.QueueDefault)).ActiveWorkers)
+		}, 1, 10*time.Second)
+
+		stats, err := manager.Stats(ctx)
+		require.NoError(t, err)
+		defaultQueue := queueStats(stats, string(workerqueue.QueueDefault))
+		assert.Equal(t, int64(1), defaultQueue.Running)
+		assert.Zero(t, defaultQueue.Available)
+		assert.InDelta(t, 1/float64(config.MaxWorkers), defaultQueue.Utilization, 0.0001)
+
+		close(worker.release)
+	})
+
+	t.Run("Health after start", func(t *testing.T) {
+		status := manager.Health(ctx)
+		assert.True(t, status.Healthy, status.Errors)
+		assert.Equal(t, manager.Client().ID(), status.ClientID)
+		assert.WithinDuration(t, time.Now(), status.LastCheckIn, config.HealthCheckInStaleAfter)
+	})
+}
@@ -0,0 +1,104 @@
+// Package workerqueue propagates OpenTelemetry trace context through River job metadata
+package workerqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// metadataKeyTraceContext is the job metadata key holding the inserting span's trace context
+const metadataKeyTraceContext = "otel"
+
+// tracerName identifies spans created by the worker queue
+const tracerName = "kongflow/backend/internal/services/workerqueue"
+
+// traceMiddleware records the inserting span's trace context in job metadata and works each
+// job in a span continuing that trace
+type traceMiddleware struct {
+	river.MiddlewareDefaults
+}
+
+var (
+	_ rivertype.JobInsertMiddleware = (*traceMiddleware)(nil)
+	_ rivertype.WorkerMiddleware    = (*traceMiddleware)(nil)
+)
+
+// InsertMany injects the trace context of ctx into the metadata of each inserted job
+func (t *traceMiddleware) InsertMany(ctx context.Context, manyParams []*rivertype.JobInsertParams, doInner func(context.Context) ([]*rivertype.JobInsertResult, error)) ([]*rivertype.JobInsertResult, error) {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return doInner(ctx)
+	}
+
+	for _, params := range manyParams {
+		metadata, err := setMetadataValue(params.Metadata, metadataKeyTraceContext, carrier)
+		if err != nil {
+			return nil, err
+		}
+		params.Metadata = metadata
+	}
+	return doInner(ctx)
+}
+
+// Work works the job in a consumer span continuing the trace recorded at insertion
+func (t *traceMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(context.Context) error) error {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, jobTraceCarrier(job))
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "workerqueue.work "+job.Kind,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.Int64("job.id", job.ID),
+			attribute.String("job.kind", job.Kind),
+			attribute.String("job.queue", job.Queue),
+			attribute.Int("job.attempt", job.Attempt),
+		),
+	)
+	defer span.End()
+
+	err := doInner(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attribute.String("job.outcome", jobOutcome(err)))
+	return err
+}
+
+// jobTraceCarrier returns the trace context recorded in a job's metadata
+func jobTraceCarrier(job *rivertype.JobRow) propagation.MapCarrier {
+	var metadata struct {
+		TraceContext propagation.MapCarrier `json:"otel"`
+	}
+	if len(job.Metadata) == 0 || json.Unmarshal(job.Metadata, &metadata) != nil || metadata.TraceContext == nil {
+		return propagation.MapCarrier{}
+	}
+	return metadata.TraceContext
+}
+
+// setMetadataValue sets key in a job's JSON metadata, keeping existing keys
+func setMetadataValue(metadata []byte, key string, value interface{}) ([]byte, error) {
+	values := map[string]json.RawMessage{}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &values); err != nil {
+			return nil, fmt.Errorf("failed to decode job metadata: %w", err)
+		}
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job metadata %s: %w", key, err)
+	}
+	values[key] = encoded
+
+	return json.Marshal(values)
+}
@@ -0,0 +1,87 @@
+package workerqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setupSpanRecorder installs an in-memory span recorder as the global tracer provider
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+// TestTraceMiddleware_PropagatesThroughMetadata tests that a job is worked in the trace it was inserted from
+func TestTraceMiddleware_PropagatesThroughMetadata(t *testing.T) {
+	recorder := setupSpanRecorder(t)
+	middleware := &traceMiddleware{}
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "events.IngestSendEvent")
+	params := &rivertype.JobInsertParams{Kind: "deliver_event", Metadata: []byte(`{"existing":true}`)}
+	_, err := middleware.InsertMany(ctx, []*rivertype.JobInsertParams{params}, func(ctx context.Context) ([]*rivertype.JobInsertResult, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	parent.End()
+
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(params.Metadata, &metadata))
+	assert.Equal(t, true, metadata["existing"])
+	assert.Contains(t, metadata[metadataKeyTraceContext], "traceparent")
+
+	job := &rivertype.JobRow{ID: 42, Kind: "deliver_event", Queue: string(QueueEvents), Attempt: 1, Metadata: params.Metadata}
+	err = middleware.Work(context.Background(), job, func(ctx context.Context) error {
+		return errors.New("endpoint unavailable")
+	})
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	work := spans[1]
+	assert.Equal(t, "workerqueue.work deliver_event", work.Name())
+	assert.Equal(t, parent.SpanContext().TraceID(), work.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), work.Parent().SpanID())
+	assert.Equal(t, codes.Error, work.Status().Code)
+}
+
+// TestTraceMiddleware_NoActiveSpan tests that jobs inserted outside a trace keep their metadata untouched
+func TestTraceMiddleware_NoActiveSpan(t *testing.T) {
+	recorder := setupSpanRecorder(t)
+	middleware := &traceMiddleware{}
+
+	params := &rivertype.JobInsertParams{Kind: "start_run"}
+	_, err := middleware.InsertMany(context.Background(), []*rivertype.JobInsertParams{params}, func(ctx context.Context) ([]*rivertype.JobInsertResult, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Nil(t, params.Metadata)
+
+	err = middleware.Work(context.Background(), &rivertype.JobRow{Kind: "start_run"}, func(ctx context.Context) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.False(t, spans[0].Parent().IsValid())
+}
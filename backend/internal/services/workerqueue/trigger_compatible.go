@@ -3,7 +3,6 @@ package workerqueue
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"time"
@@ -40,28 +39,28 @@ func (d DynamicQueueName) ResolveQueueName(payload interface{}) string {
 type TaskCatalog map[string]TaskDefinition
 
 // TaskDefinition represents a task configuration
+// Jobs are processed by the River worker registered for Kind in the manager's TaskRegistry
 type TaskDefinition struct {
-	// Task configuration
-	QueueName   QueueNameResolver // ✅ Now supports both static and dynamic queue names
+	// Kind is the registered task the identifier enqueues; defaults to the identifier itself
+	Kind string
+
+	// Task configuration, applied over the registered task's defaults
+	QueueName   QueueNameResolver // ✅ Now supports both static and dynamic queue names; nil keeps the task default
 	Priority    int
 	MaxAttempts int
 	JobKeyMode  string // "replace", "preserve_run_at", "unsafe_dedupe"
 	Flags       []string
 
-	// Handler function
-	Handler TaskHandler
+	// RateLimit caps how often jobs of Kind run per payload key; jobs over it are snoozed
+	RateLimit *RateLimit
 }
 
-// TaskHandler defines the interface for task handlers
-type TaskHandler func(ctx context.Context, payload json.RawMessage, job JobContext) error
-
-// JobContext provides context information about the executing job
-type JobContext struct {
-	ID        int64
-	Attempt   int
-	Queue     string
-	CreatedAt time.Time
-	Metadata  map[string]string
+// kind returns the registered task kind for identifier
+func (d TaskDefinition) kind(identifier string) string {
+	if d.Kind != "" {
+		return d.Kind
+	}
+	return identifier
 }
 
 // RecurringTaskHandler defines the interface for recurring task handlers
@@ -121,6 +120,18 @@ func NewTriggerCompatibleWorker(opts TriggerWorkerOptions) *TriggerCompatibleWor
 func (w *TriggerCompatibleWorker) Initialize(ctx context.Context) error {
 	w.logger.Info("Initializing trigger-compatible worker")
 
+	// Every catalog entry must be backed by a registered River worker
+	for identifier, taskDef := range w.catalog {
+		if _, err := w.manager.Registry().Lookup(taskDef.kind(identifier)); err != nil {
+			return fmt.Errorf("task %s: %w", identifier, err)
+		}
+		if taskDef.RateLimit != nil {
+			if err := w.manager.SetRateLimit(taskDef.kind(identifier), *taskDef.RateLimit); err != nil {
+				return fmt.Errorf("task %s: %w", identifier, err)
+			}
+		}
+	}
+
 	// Register recurring tasks if any
 	for identifier, config := range w.recurring {
 		if err := w.registerRecurringTask(identifier, config); err != nil {
@@ -148,13 +159,13 @@ func (w *TriggerCompatibleWorker) Enqueue(ctx context.Context, identifier string
 	// Get task definition from catalog
 	taskDef, exists := w.catalog[identifier]
 	if !exists {
-		return nil, fmt.Errorf("unknown task identifier: %s", identifier)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTask, identifier)
 	}
 
 	// Merge task definition with provided options
 	finalOpts := w.mergeJobOptions(taskDef, opts, payload)
 
-	result, err := w.manager.EnqueueJob(ctx, identifier, payload, finalOpts)
+	result, err := w.manager.EnqueueJob(ctx, taskDef.kind(identifier), payload, finalOpts)
 	if err != nil {
 		w.logger.Error("Failed to enqueue job",
 			"identifier", identifier,
@@ -175,12 +186,12 @@ func (w *TriggerCompatibleWorker) Enqueue(ctx context.Context, identifier string
 func (w *TriggerCompatibleWorker) EnqueueTx(ctx context.Context, tx pgx.Tx, identifier string, payload interface{}, opts *JobOptions) (*rivertype.JobInsertResult, error) {
 	taskDef, exists := w.catalog[identifier]
 	if !exists {
-		return nil, fmt.Errorf("unknown task identifier: %s", identifier)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTask, identifier)
 	}
 
 	finalOpts := w.mergeJobOptions(taskDef, opts, payload)
 
-	return w.manager.EnqueueJobTx(ctx, tx, identifier, payload, finalOpts)
+	return w.manager.EnqueueJobTx(ctx, tx, taskDef.kind(identifier), payload, finalOpts)
 }
 
 // Dequeue removes a job from the queue by job key (mirrors trigger.dev's dequeue())
@@ -191,43 +202,18 @@ func (w *TriggerCompatibleWorker) Dequeue(ctx context.Context, jobKey string) er
 // Helper methods
 
 func (w *TriggerCompatibleWorker) mergeJobOptions(taskDef TaskDefinition, opts *JobOptions, payload interface{}) *JobOptions {
-	merged := &JobOptions{
-		QueueName:   taskDef.QueueName.ResolveQueueName(payload), // ✅ Resolve queue name from payload
+	defaults := JobOptions{
 		Priority:    taskDef.Priority,
 		MaxAttempts: taskDef.MaxAttempts,
 		JobKeyMode:  taskDef.JobKeyMode,
 		Flags:       taskDef.Flags,
 	}
-
-	// Override with provided options
-	if opts != nil {
-		if opts.QueueName != "" {
-			merged.QueueName = opts.QueueName
-		}
-		if opts.Priority > 0 {
-			merged.Priority = opts.Priority
-		}
-		if opts.MaxAttempts > 0 {
-			merged.MaxAttempts = opts.MaxAttempts
-		}
-		if opts.RunAt != nil {
-			merged.RunAt = opts.RunAt
-		}
-		if opts.JobKey != "" {
-			merged.JobKey = opts.JobKey
-		}
-		if opts.JobKeyMode != "" {
-			merged.JobKeyMode = opts.JobKeyMode
-		}
-		if opts.Tags != nil {
-			merged.Tags = opts.Tags
-		}
-		if len(opts.Flags) > 0 {
-			merged.Flags = opts.Flags
-		}
+	if taskDef.QueueName != nil {
+		defaults.QueueName = taskDef.QueueName.ResolveQueueName(payload) // ✅ Resolve queue name from payload
 	}
 
-	return merged
+	// Provided options override the catalog, and the manager applies the registered task defaults underneath
+	return mergeJobOptions(defaults, opts)
 }
 
 func (w *TriggerCompatibleWorker) registerRecurringTask(identifier string, config RecurringTaskConfig) error {
@@ -249,34 +235,34 @@ func CreateKongFlowWorker(manager *Manager, logger *slog.Logger) *TriggerCompati
 	// Define task catalog similar to trigger.dev's workerCatalog
 	catalog := TaskCatalog{
 		"indexEndpoint": TaskDefinition{
+			Kind:        IndexEndpointArgs{}.Kind(),
 			QueueName:   StaticQueueName(QueueDefault), // ✅ Convert to StaticQueueName
 			Priority:    1,
 			MaxAttempts: 7,
-			Handler:     handleIndexEndpoint,
 		},
 		"startRun": TaskDefinition{
+			Kind:        StartRunArgs{}.Kind(),
 			QueueName:   StaticQueueName(QueueExecution), // ✅ Convert to StaticQueueName
 			Priority:    0,
 			MaxAttempts: 4,
-			Handler:     handleStartRun,
 		},
 		"invokeDispatcher": TaskDefinition{
+			Kind:        InvokeDispatcherArgs{}.Kind(),
 			QueueName:   StaticQueueName(QueueEvents), // ✅ Convert to StaticQueueName
 			Priority:    0,
 			MaxAttempts: 3,
-			Handler:     handleInvokeDispatcher,
 		},
 		"deliverEvent": TaskDefinition{
+			Kind:        DeliverEventArgs{}.Kind(),
 			QueueName:   StaticQueueName(QueueEvents), // ✅ Convert to StaticQueueName
 			Priority:    0,
 			MaxAttempts: 5,
-			Handler:     handleDeliverEvent,
 		},
 		"performRunExecutionV2": TaskDefinition{
+			Kind:        PerformRunExecutionV2Args{}.Kind(),
 			QueueName:   StaticQueueName(QueueExecution), // ✅ Convert to StaticQueueName
 			Priority:    0,
 			MaxAttempts: 12,
-			Handler:     handlePerformRunExecutionV2,
 		},
 	}
 
@@ -301,67 +287,7 @@ func CreateKongFlowWorker(manager *Manager, logger *slog.Logger) *TriggerCompati
 	})
 }
 
-// Task handlers (to be implemented)
-func handleIndexEndpoint(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	// Implementation would go here
-	return fmt.Errorf("handleIndexEndpoint not yet implemented")
-}
-
-func handleStartRun(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	return fmt.Errorf("handleStartRun not yet implemented")
-}
-
-func handleInvokeDispatcher(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	var args InvokeDispatcherArgs
-	if err := json.Unmarshal(payload, &args); err != nil {
-		return fmt.Errorf("failed to unmarshal invoke dispatcher args: %w", err)
-	}
-
-	// TODO: Get dependencies from the context or implement dependency injection
-	// For now, return a placeholder implementation
-	// In a real implementation, we would:
-	// 1. Get the InvokeDispatcherService from a service container
-	// 2. Call service.InvokeDispatcher(ctx, args.ID, args.EventRecordID)
-
-	// Log the invocation for now
-	slog.InfoContext(ctx, "Processing invoke dispatcher job",
-		"dispatcherId", args.ID,
-		"eventRecordId", args.EventRecordID,
-		"jobId", job.ID,
-		"attempt", job.Attempt)
-
-	// Return success for placeholder
-	// TODO: Replace with actual service call
-	return nil
-}
-
-func handleDeliverEvent(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	var args DeliverEventArgs
-	if err := json.Unmarshal(payload, &args); err != nil {
-		return fmt.Errorf("failed to unmarshal deliver event args: %w", err)
-	}
-
-	// TODO: Get dependencies from the context or implement dependency injection
-	// For now, return a placeholder implementation
-	// In a real implementation, we would:
-	// 1. Get the DeliverEventService from a service container
-	// 2. Call service.DeliverEvent(ctx, args.ID)
-
-	// Log the delivery for now
-	slog.InfoContext(ctx, "Processing deliver event job",
-		"eventId", args.ID,
-		"jobId", job.ID,
-		"attempt", job.Attempt)
-
-	// Return success for placeholder
-	// TODO: Replace with actual service call
-	return nil
-}
-
-func handlePerformRunExecutionV2(ctx context.Context, payload json.RawMessage, job JobContext) error {
-	return fmt.Errorf("handlePerformRunExecutionV2 not yet implemented")
-}
-
+// Recurring task handlers (to be implemented)
 func handleAutoIndexEndpoints(ctx context.Context, payload RecurringTaskPayload) error {
 	return fmt.Errorf("handleAutoIndexEndpoints not yet implemented")
 }
@@ -8,6 +8,11 @@ type JobArgs interface {
 	Kind() string
 }
 
+// jobKeyArgs is implemented by job args that carry a trigger.dev style job key
+type jobKeyArgs interface {
+	withJobKey(jobKey string) JobArgs
+}
+
 // JobPriority represents job priority levels (lower number = higher priority)
 // River Queue uses 1-4 range where 1=highest, 4=lowest
 type JobPriority int
@@ -104,3 +104,410 @@ func (w *IndexEndpointWorker) Timeout(job *river.Job[IndexEndpointArgs]) time.Du
 	// Index operations should complete within 2 minutes
 	return 2 * time.Minute
 }
+
+// RunExecutor 运行执行器接口 (避免循环导入)
+// 由 runs 服务实现，对齐 trigger.dev startRun / performRunExecutionV2 / startQueuedRuns 任务
+type RunExecutor interface {
+	StartRun(ctx context.Context, runID string) error
+	PerformRunExecution(ctx context.Context, req *RunExecutionRequest) error
+	StartQueuedRuns(ctx context.Context, req *StartQueuedRunsRequest) error
+}
+
+// RunExecutionRequest 运行执行请求
+type RunExecutionRequest struct {
+	RunID        string          `json:"runId"`
+	Reason       ExecutionReason `json:"reason"`
+	ResumeTaskID string          `json:"resumeTaskId,omitempty"`
+	IsRetry      bool            `json:"isRetry"`
+	Attempt      int             `json:"attempt"`
+	// ExecutionCount 入队时运行已执行的次数
+	ExecutionCount int `json:"executionCount"`
+	// FinalAttempt 本次为队列任务的最后一次尝试，失败后任务将被丢弃
+	FinalAttempt bool `json:"finalAttempt"`
+}
+
+// StartQueuedRunsRequest 启动排队运行请求
+type StartQueuedRunsRequest struct {
+	QueueID       string `json:"queueId"`
+	FinishedRunID string `json:"finishedRunId,omitempty"`
+}
+
+// StartRunWorker handles start run jobs
+type StartRunWorker struct {
+	river.WorkerDefaults[StartRunArgs]
+	executor RunExecutor
+	logger   *slog.Logger
+}
+
+// NewStartRunWorker creates a new StartRunWorker
+// executor can be nil, in which case jobs are only logged
+func NewStartRunWorker(executor RunExecutor, logger *slog.Logger) *StartRunWorker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &StartRunWorker{
+		executor: executor,
+		logger:   logger,
+	}
+}
+
+// Work processes start run jobs
+func (w *StartRunWorker) Work(ctx context.Context, job *river.Job[StartRunArgs]) error {
+	w.logger.Info("Processing start run job", "job_id", job.ID, "args", job.Args)
+
+	if w.executor == nil {
+		return nil
+	}
+
+	if err := w.executor.StartRun(ctx, job.Args.ID); err != nil {
+		return fmt.Errorf("failed to start run %s: %w", job.Args.ID, err)
+	}
+
+	return nil
+}
+
+// PerformRunExecutionV2Worker handles run execution jobs
+type PerformRunExecutionV2Worker struct {
+	river.WorkerDefaults[PerformRunExecutionV2Args]
+	executor RunExecutor
+	logger   *slog.Logger
+}
+
+// NewPerformRunExecutionV2Worker creates a new PerformRunExecutionV2Worker
+// executor can be nil, in which case jobs are only logged
+func NewPerformRunExecutionV2Worker(executor RunExecutor, logger *slog.Logger) *PerformRunExecutionV2Worker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PerformRunExecutionV2Worker{
+		executor: executor,
+		logger:   logger,
+	}
+}
+
+// Work processes run execution jobs
+func (w *PerformRunExecutionV2Worker) Work(ctx context.Context, job *river.Job[PerformRunExecutionV2Args]) error {
+	w.logger.Info("Processing run execution job",
+		"job_id", job.ID,
+		"run_id", job.Args.ID,
+		"reason", job.Args.Reason,
+		"attempt", job.Attempt,
+	)
+
+	if w.executor == nil {
+		return nil
+	}
+
+	req := &RunExecutionRequest{
+		RunID:          job.Args.ID,
+		Reason:         job.Args.Reason,
+		ResumeTaskID:   job.Args.ResumeTaskID,
+		IsRetry:        job.Args.IsRetry,
+		Attempt:        job.Attempt,
+		ExecutionCount: job.Args.ExecutionCount,
+		FinalAttempt:   job.Attempt >= job.MaxAttempts,
+	}
+
+	if err := w.executor.PerformRunExecution(ctx, req); err != nil {
+		return fmt.Errorf("failed to perform run execution %s: %w", job.Args.ID, err)
+	}
+
+	return nil
+}
+
+// StartQueuedRunsWorker releases finished runs' queue slots and starts waiting runs
+type StartQueuedRunsWorker struct {
+	river.WorkerDefaults[StartQueuedRunsArgs]
+	executor RunExecutor
+	logger   *slog.Logger
+}
+
+// NewStartQueuedRunsWorker creates a new StartQueuedRunsWorker
+// executor can be nil, in which case jobs are only logged
+func NewStartQueuedRunsWorker(executor RunExecutor, logger *slog.Logger) *StartQueuedRunsWorker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &StartQueuedRunsWorker{
+		executor: executor,
+		logger:   logger,
+	}
+}
+
+// Work processes start queued runs jobs
+func (w *StartQueuedRunsWorker) Work(ctx context.Context, job *river.Job[StartQueuedRunsArgs]) error {
+	w.logger.Info("Processing start queued runs job",
+		"job_id", job.ID,
+		"queue_id", job.Args.QueueID,
+		"finished_run_id", job.Args.FinishedRunID,
+	)
+
+	if w.executor == nil {
+		return nil
+	}
+
+	req := &StartQueuedRunsRequest{
+		QueueID:       job.Args.QueueID,
+		FinishedRunID: job.Args.FinishedRunID,
+	}
+
+	if err := w.executor.StartQueuedRuns(ctx, req); err != nil {
+		return fmt.Errorf("failed to start queued runs for queue %s: %w", job.Args.QueueID, err)
+	}
+
+	return nil
+}
+
+// ConnectionRefresher refreshes integration connections before they expire (避免循环导入)
+type ConnectionRefresher interface {
+	RefreshExpiringConnections(ctx context.Context, window time.Duration) (int, error)
+}
+
+// RefreshIntegrationConnectionsWorker rotates OAuth tokens of expiring integration connections
+type RefreshIntegrationConnectionsWorker struct {
+	river.WorkerDefaults[RefreshIntegrationConnectionsArgs]
+	refresher ConnectionRefresher
+	logger    *slog.Logger
+}
+
+// NewRefreshIntegrationConnectionsWorker creates a new RefreshIntegrationConnectionsWorker
+// refresher can be nil, in which case jobs are only logged
+func NewRefreshIntegrationConnectionsWorker(refresher ConnectionRefresher, logger *slog.Logger) *RefreshIntegrationConnectionsWorker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RefreshIntegrationConnectionsWorker{
+		refresher: refresher,
+		logger:    logger,
+	}
+}
+
+// Work processes connection refresh jobs
+func (w *RefreshIntegrationConnectionsWorker) Work(ctx context.Context, job *river.Job[RefreshIntegrationConnectionsArgs]) error {
+	w.logger.Info("Processing refresh integration connections job",
+		"job_id", job.ID,
+		"window", job.Args.Window,
+	)
+
+	if w.refresher == nil {
+		return nil
+	}
+
+	refreshed, err := w.refresher.RefreshExpiringConnections(ctx, job.Args.Window)
+	if err != nil {
+		return fmt.Errorf("failed to refresh integration connections: %w", err)
+	}
+
+	w.logger.Info("Refreshed integration connections", "job_id", job.ID, "count", refreshed)
+	return nil
+}
+
+// RunTimeoutEnforcer times out runs that exceeded their maximum duration (避免循环导入)
+type RunTimeoutEnforcer interface {
+	TimeoutRuns(ctx context.Context) (int, error)
+}
+
+// TimeoutRunsWorker fails runs stuck in EXECUTING or WAITING beyond their maximum duration
+type TimeoutRunsWorker struct {
+	river.WorkerDefaults[TimeoutRunsArgs]
+	enforcer RunTimeoutEnforcer
+	logger   *slog.Logger
+}
+
+// NewTimeoutRunsWorker creates a new TimeoutRunsWorker
+// enforcer can be nil, in which case jobs are only logged
+func NewTimeoutRunsWorker(enforcer RunTimeoutEnforcer, logger *slog.Logger) *TimeoutRunsWorker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &TimeoutRunsWorker{
+		enforcer: enforcer,
+		logger:   logger,
+	}
+}
+
+// Work processes run timeout jobs
+func (w *TimeoutRunsWorker) Work(ctx context.Context, job *river.Job[TimeoutRunsArgs]) error {
+	w.logger.Debug("Processing timeout runs job", "job_id", job.ID)
+
+	if w.enforcer == nil {
+		return nil
+	}
+
+	timedOut, err := w.enforcer.TimeoutRuns(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to time out runs: %w", err)
+	}
+
+	if timedOut > 0 {
+		w.logger.Info("Timed out runs", "job_id", job.ID, "count", timedOut)
+	}
+	return nil
+}
+
+// SweepDeadLetterJobsWorker copies discarded jobs missed by the dead-letter hook
+type SweepDeadLetterJobsWorker struct {
+	river.WorkerDefaults[SweepDeadLetterJobsArgs]
+	sweeper DeadLetterSweeper
+	schema  string
+	logger  *slog.Logger
+}
+
+// NewSweepDeadLetterJobsWorker creates a new SweepDeadLetterJobsWorker sweeping River tables in schema
+// sweeper can be nil, in which case jobs are only logged
+func NewSweepDeadLetterJobsWorker(sweeper DeadLetterSweeper, schema string, logger *slog.Logger) *SweepDeadLetterJobsWorker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SweepDeadLetterJobsWorker{
+		sweeper: sweeper,
+		schema:  schema,
+		logger:  logger,
+	}
+}
+
+// Work processes dead-letter sweep jobs
+func (w *SweepDeadLetterJobsWorker) Work(ctx context.Context, job *river.Job[SweepDeadLetterJobsArgs]) error {
+	w.logger.Debug("Processing dead-letter sweep job", "job_id", job.ID)
+
+	if w.sweeper == nil {
+		return nil
+	}
+
+	if _, err := w.sweeper.SweepDiscardedJobs(ctx, w.schema); err != nil {
+		return fmt.Errorf("failed to sweep dead-letter jobs: %w", err)
+	}
+	return nil
+}
+
+// RunFailureNotifier notifies organization members about a failed run (避免循环导入)
+type RunFailureNotifier interface {
+	NotifyRunFailed(ctx context.Context, runID string) error
+}
+
+// NotifyRunFailedWorker sends workflow failure notifications for failed runs
+type NotifyRunFailedWorker struct {
+	river.WorkerDefaults[NotifyRunFailedArgs]
+	notifier RunFailureNotifier
+	logger   *slog.Logger
+}
+
+// NewNotifyRunFailedWorker creates a new NotifyRunFailedWorker
+// notifier can be nil, in which case jobs are only logged
+func NewNotifyRunFailedWorker(notifier RunFailureNotifier, logger *slog.Logger) *NotifyRunFailedWorker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &NotifyRunFailedWorker{
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// Work processes run failure notification jobs
+func (w *NotifyRunFailedWorker) Work(ctx context.Context, job *river.Job[NotifyRunFailedArgs]) error {
+	w.logger.Info("Processing notify run failed job",
+		"job_id", job.ID,
+		"run_id", job.Args.RunID,
+	)
+
+	if w.notifier == nil {
+		return nil
+	}
+
+	if err := w.notifier.NotifyRunFailed(ctx, job.Args.RunID); err != nil {
+		return fmt.Errorf("failed to notify run %s failure: %w", job.Args.RunID, err)
+	}
+	return nil
+}
+
+// EventProcessor 事件处理器接口 (避免循环导入)
+// 由 events 服务实现，对齐 trigger.dev deliverEvent / events.invokeDispatcher 任务
+type EventProcessor interface {
+	DeliverEvent(ctx context.Context, eventID string) error
+	InvokeDispatcher(ctx context.Context, dispatcherID string, eventRecordID string) error
+}
+
+// DeliverEventWorker handles event delivery jobs
+type DeliverEventWorker struct {
+	river.WorkerDefaults[DeliverEventArgs]
+	processor EventProcessor
+	logger    *slog.Logger
+}
+
+// NewDeliverEventWorker creates a new DeliverEventWorker
+// processor can be nil, in which case jobs are only logged
+func NewDeliverEventWorker(processor EventProcessor, logger *slog.Logger) *DeliverEventWorker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DeliverEventWorker{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+// Work processes event delivery jobs
+func (w *DeliverEventWorker) Work(ctx context.Context, job *river.Job[DeliverEventArgs]) error {
+	w.logger.Info("Processing deliver event job",
+		"job_id", job.ID,
+		"event_id", job.Args.ID,
+		"attempt", job.Attempt,
+	)
+
+	if w.processor == nil {
+		return nil
+	}
+
+	if err := w.processor.DeliverEvent(ctx, job.Args.ID); err != nil {
+		return fmt.Errorf("failed to deliver event %s: %w", job.Args.ID, err)
+	}
+	return nil
+}
+
+// InvokeDispatcherWorker handles dispatcher invocation jobs
+type InvokeDispatcherWorker struct {
+	river.WorkerDefaults[InvokeDispatcherArgs]
+	processor EventProcessor
+	logger    *slog.Logger
+}
+
+// NewInvokeDispatcherWorker creates a new InvokeDispatcherWorker
+// processor can be nil, in which case jobs are only logged
+func NewInvokeDispatcherWorker(processor EventProcessor, logger *slog.Logger) *InvokeDispatcherWorker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &InvokeDispatcherWorker{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+// Work processes dispatcher invocation jobs
+func (w *InvokeDispatcherWorker) Work(ctx context.Context, job *river.Job[InvokeDispatcherArgs]) error {
+	w.logger.Info("Processing invoke dispatcher job",
+		"job_id", job.ID,
+		"dispatcher_id", job.Args.ID,
+		"event_record_id", job.Args.EventRecordID,
+		"attempt", job.Attempt,
+	)
+
+	if w.processor == nil {
+		return nil
+	}
+
+	if err := w.processor.InvokeDispatcher(ctx, job.Args.ID, job.Args.EventRecordID); err != nil {
+		return fmt.Errorf("failed to invoke dispatcher %s: %w", job.Args.ID, err)
+	}
+	return nil
+}
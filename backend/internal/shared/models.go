@@ -21,6 +21,15 @@ type ExternalAccounts struct {
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
+type OrgMembers struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	UserID         pgtype.UUID        `json:"user_id"`
+	Role           string             `json:"role"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Organizations struct {
 	ID        pgtype.UUID        `json:"id"`
 	Title     string             `json:"title"`
@@ -48,6 +57,16 @@ type RuntimeEnvironments struct {
 	OrgMemberID    pgtype.UUID        `json:"org_member_id"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	// 作业未声明时使用的运行最长持续时间（秒）
+	DefaultMaxRunDurationSeconds pgtype.Int4 `json:"default_max_run_duration_seconds"`
+}
+
+type UserNotificationPreferences struct {
+	UserID pgtype.UUID `json:"user_id"`
+	// 是否接收生产环境运行失败邮件
+	RunFailureEmails bool               `json:"run_failure_emails"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
 
 type Users struct {
@@ -16,6 +16,7 @@ type Querier interface {
 	CreateProject(ctx context.Context, arg CreateProjectParams) (Projects, error)
 	CreateRuntimeEnvironment(ctx context.Context, arg CreateRuntimeEnvironmentParams) (RuntimeEnvironments, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (Users, error)
+	DeleteOrgMember(ctx context.Context, arg DeleteOrgMemberParams) error
 	// external_accounts.sql
 	// External Accounts 共享查询，对齐 trigger.dev 访问模式
 	FindExternalAccountByEnvAndIdentifier(ctx context.Context, arg FindExternalAccountByEnvAndIdentifierParams) (ExternalAccounts, error)
@@ -34,7 +35,9 @@ type Querier interface {
 	GetRuntimeEnvironment(ctx context.Context, id pgtype.UUID) (RuntimeEnvironments, error)
 	// Users queries - trigger.dev User entity alignment
 	GetUser(ctx context.Context, id pgtype.UUID) (Users, error)
+	GetUserNotificationPreferences(ctx context.Context, userID pgtype.UUID) (UserNotificationPreferences, error)
 	ListExternalAccountsByEnvironment(ctx context.Context, arg ListExternalAccountsByEnvironmentParams) ([]ExternalAccounts, error)
+	ListOrgMembersByOrganization(ctx context.Context, organizationID pgtype.UUID) ([]OrgMembers, error)
 	ListOrganizations(ctx context.Context) ([]Organizations, error)
 	ListProjectsByOrganization(ctx context.Context, organizationID pgtype.UUID) ([]Projects, error)
 	ListRuntimeEnvironmentsByProject(ctx context.Context, projectID pgtype.UUID) ([]RuntimeEnvironments, error)
@@ -43,7 +46,13 @@ type Querier interface {
 	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organizations, error)
 	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Projects, error)
 	UpdateRuntimeEnvironment(ctx context.Context, arg UpdateRuntimeEnvironmentParams) (RuntimeEnvironments, error)
+	// 设置环境级默认运行最长持续时间，NULL 表示不限制
+	UpdateRuntimeEnvironmentMaxRunDuration(ctx context.Context, arg UpdateRuntimeEnvironmentMaxRunDurationParams) (RuntimeEnvironments, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (Users, error)
+	// Org members queries - trigger.dev OrgMember entity alignment
+	// 用户加入组织或变更角色，成员据此接收运行失败等组织通知
+	UpsertOrgMember(ctx context.Context, arg UpsertOrgMemberParams) (OrgMembers, error)
+	UpsertUserNotificationPreferences(ctx context.Context, arg UpsertUserNotificationPreferencesParams) (UserNotificationPreferences, error)
 }
 
 var _ Querier = (*Queries)(nil)
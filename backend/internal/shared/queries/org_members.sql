@@ -0,0 +1,16 @@
+-- Org members queries - trigger.dev OrgMember entity alignment
+-- name: UpsertOrgMember :one
+-- 用户加入组织或变更角色，成员据此接收运行失败等组织通知
+INSERT INTO org_members (organization_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, user_id) DO UPDATE
+SET role = EXCLUDED.role, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteOrgMember :exec
+DELETE FROM org_members WHERE organization_id = $1 AND user_id = $2;
+
+-- name: ListOrgMembersByOrganization :many
+SELECT * FROM org_members
+WHERE organization_id = $1
+ORDER BY created_at;
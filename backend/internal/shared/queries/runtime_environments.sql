@@ -33,4 +33,11 @@ RETURNING *;
 -- name: ListRuntimeEnvironmentsByProject :many
 SELECT * FROM runtime_environments 
 WHERE project_id = $1
-ORDER BY created_at DESC;
\ No newline at end of file
+ORDER BY created_at DESC;
+
+-- name: UpdateRuntimeEnvironmentMaxRunDuration :one
+-- 设置环境级默认运行最长持续时间，NULL 表示不限制
+UPDATE runtime_environments
+SET default_max_run_duration_seconds = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
@@ -19,4 +19,14 @@ RETURNING *;
 -- name: ListUsers :many
 SELECT * FROM users
 ORDER BY created_at DESC
-LIMIT $1 OFFSET $2;
\ No newline at end of file
+LIMIT $1 OFFSET $2;
+
+-- name: GetUserNotificationPreferences :one
+SELECT * FROM user_notification_preferences WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertUserNotificationPreferences :one
+INSERT INTO user_notification_preferences (user_id, run_failure_emails)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET run_failure_emails = EXCLUDED.run_failure_emails, updated_at = NOW()
+RETURNING *;
@@ -14,7 +14,7 @@ import (
 const createRuntimeEnvironment = `-- name: CreateRuntimeEnvironment :one
 INSERT INTO runtime_environments (slug, api_key, type, organization_id, project_id, org_member_id)
 VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at
+RETURNING id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at, default_max_run_duration_seconds
 `
 
 type CreateRuntimeEnvironmentParams struct {
@@ -46,12 +46,13 @@ func (q *Queries) CreateRuntimeEnvironment(ctx context.Context, arg CreateRuntim
 		&i.OrgMemberID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultMaxRunDurationSeconds,
 	)
 	return i, err
 }
 
 const findRuntimeEnvironmentByAPIKey = `-- name: FindRuntimeEnvironmentByAPIKey :one
-SELECT id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at FROM runtime_environments WHERE api_key = $1 LIMIT 1
+SELECT id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at, default_max_run_duration_seconds FROM runtime_environments WHERE api_key = $1 LIMIT 1
 `
 
 func (q *Queries) FindRuntimeEnvironmentByAPIKey(ctx context.Context, apiKey string) (RuntimeEnvironments, error) {
@@ -67,12 +68,13 @@ func (q *Queries) FindRuntimeEnvironmentByAPIKey(ctx context.Context, apiKey str
 		&i.OrgMemberID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultMaxRunDurationSeconds,
 	)
 	return i, err
 }
 
 const findRuntimeEnvironmentByPublicAPIKey = `-- name: FindRuntimeEnvironmentByPublicAPIKey :one
-SELECT id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at FROM runtime_environments WHERE api_key = $1 AND type != 'PRODUCTION' LIMIT 1
+SELECT id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at, default_max_run_duration_seconds FROM runtime_environments WHERE api_key = $1 AND type != 'PRODUCTION' LIMIT 1
 `
 
 func (q *Queries) FindRuntimeEnvironmentByPublicAPIKey(ctx context.Context, apiKey string) (RuntimeEnvironments, error) {
@@ -88,6 +90,7 @@ func (q *Queries) FindRuntimeEnvironmentByPublicAPIKey(ctx context.Context, apiK
 		&i.OrgMemberID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultMaxRunDurationSeconds,
 	)
 	return i, err
 }
@@ -146,7 +149,7 @@ func (q *Queries) GetEnvironmentWithProjectAndOrg(ctx context.Context, id pgtype
 }
 
 const getRuntimeEnvironment = `-- name: GetRuntimeEnvironment :one
-SELECT id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at FROM runtime_environments WHERE id = $1 LIMIT 1
+SELECT id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at, default_max_run_duration_seconds FROM runtime_environments WHERE id = $1 LIMIT 1
 `
 
 // Runtime Environments queries - trigger.dev RuntimeEnvironment alignment
@@ -163,12 +166,13 @@ func (q *Queries) GetRuntimeEnvironment(ctx context.Context, id pgtype.UUID) (Ru
 		&i.OrgMemberID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultMaxRunDurationSeconds,
 	)
 	return i, err
 }
 
 const listRuntimeEnvironmentsByProject = `-- name: ListRuntimeEnvironmentsByProject :many
-SELECT id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at FROM runtime_environments 
+SELECT id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at, default_max_run_duration_seconds FROM runtime_environments 
 WHERE project_id = $1
 ORDER BY created_at DESC
 `
@@ -192,6 +196,7 @@ func (q *Queries) ListRuntimeEnvironmentsByProject(ctx context.Context, projectI
 			&i.OrgMemberID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DefaultMaxRunDurationSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -207,7 +212,7 @@ const updateRuntimeEnvironment = `-- name: UpdateRuntimeEnvironment :one
 UPDATE runtime_environments 
 SET slug = $2, type = $3, updated_at = NOW()
 WHERE id = $1
-RETURNING id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at
+RETURNING id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at, default_max_run_duration_seconds
 `
 
 type UpdateRuntimeEnvironmentParams struct {
@@ -229,6 +234,38 @@ func (q *Queries) UpdateRuntimeEnvironment(ctx context.Context, arg UpdateRuntim
 		&i.OrgMemberID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultMaxRunDurationSeconds,
+	)
+	return i, err
+}
+
+const updateRuntimeEnvironmentMaxRunDuration = `-- name: UpdateRuntimeEnvironmentMaxRunDuration :one
+UPDATE runtime_environments
+SET default_max_run_duration_seconds = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, slug, api_key, type, organization_id, project_id, org_member_id, created_at, updated_at, default_max_run_duration_seconds
+`
+
+type UpdateRuntimeEnvironmentMaxRunDurationParams struct {
+	ID                           pgtype.UUID `json:"id"`
+	DefaultMaxRunDurationSeconds pgtype.Int4 `json:"default_max_run_duration_seconds"`
+}
+
+// 设置环境级默认运行最长持续时间，NULL 表示不限制
+func (q *Queries) UpdateRuntimeEnvironmentMaxRunDuration(ctx context.Context, arg UpdateRuntimeEnvironmentMaxRunDurationParams) (RuntimeEnvironments, error) {
+	row := q.db.QueryRow(ctx, updateRuntimeEnvironmentMaxRunDuration, arg.ID, arg.DefaultMaxRunDurationSeconds)
+	var i RuntimeEnvironments
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.ApiKey,
+		&i.Type,
+		&i.OrganizationID,
+		&i.ProjectID,
+		&i.OrgMemberID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DefaultMaxRunDurationSeconds,
 	)
 	return i, err
 }
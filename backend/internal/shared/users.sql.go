@@ -74,6 +74,22 @@ func (q *Queries) GetUser(ctx context.Context, id pgtype.UUID) (Users, error) {
 	return i, err
 }
 
+const getUserNotificationPreferences = `-- name: GetUserNotificationPreferences :one
+SELECT user_id, run_failure_emails, created_at, updated_at FROM user_notification_preferences WHERE user_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserNotificationPreferences(ctx context.Context, userID pgtype.UUID) (UserNotificationPreferences, error) {
+	row := q.db.QueryRow(ctx, getUserNotificationPreferences, userID)
+	var i UserNotificationPreferences
+	err := row.Scan(
+		&i.UserID,
+		&i.RunFailureEmails,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const listUsers = `-- name: ListUsers :many
 SELECT id, email, name, avatar_url, created_at, updated_at FROM users
 ORDER BY created_at DESC
@@ -138,3 +154,28 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (Users,
 	)
 	return i, err
 }
+
+const upsertUserNotificationPreferences = `-- name: UpsertUserNotificationPreferences :one
+INSERT INTO user_notification_preferences (user_id, run_failure_emails)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET run_failure_emails = EXCLUDED.run_failure_emails, updated_at = NOW()
+RETURNING user_id, run_failure_emails, created_at, updated_at
+`
+
+type UpsertUserNotificationPreferencesParams struct {
+	UserID           pgtype.UUID `json:"user_id"`
+	RunFailureEmails bool        `json:"run_failure_emails"`
+}
+
+func (q *Queries) UpsertUserNotificationPreferences(ctx context.Context, arg UpsertUserNotificationPreferencesParams) (UserNotificationPreferences, error) {
+	row := q.db.QueryRow(ctx, upsertUserNotificationPreferences, arg.UserID, arg.RunFailureEmails)
+	var i UserNotificationPreferences
+	err := row.Scan(
+		&i.UserID,
+		&i.RunFailureEmails,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}